@@ -0,0 +1,3 @@
+ALTER TABLE "api_keys" DROP CONSTRAINT IF EXISTS api_keys_user_id_fkey;
+
+DROP TABLE IF EXISTS "api_keys";
@@ -0,0 +1,15 @@
+CREATE TABLE "api_keys" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "prefix" varchar(16) UNIQUE NOT NULL,
+  "secret_hash" varchar(64) NOT NULL,
+  "permissions" text[] NOT NULL DEFAULT '{}',
+  "last_used_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "revoked_at" timestamp
+);
+
+CREATE INDEX ON "api_keys" ("user_id");
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
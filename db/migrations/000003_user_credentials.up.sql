@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "password_hash" varchar(255) NOT NULL DEFAULT '';
+
+ALTER TABLE "users" ADD COLUMN "email_verified_at" timestamp;
@@ -0,0 +1,10 @@
+ALTER TABLE "notifications" DROP CONSTRAINT IF EXISTS notifications_order_id_fkey;
+ALTER TABLE "notifications" DROP CONSTRAINT IF EXISTS notifications_user_id_fkey;
+
+DROP TABLE IF EXISTS "notifications";
+
+DROP TYPE IF EXISTS "notification_status";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "locale";
+
+-- Enum values cannot be dropped; 'shipped' and 'refunded' remain in order_status and payment_status
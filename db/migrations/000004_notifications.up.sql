@@ -0,0 +1,38 @@
+ALTER TYPE "order_status" ADD VALUE IF NOT EXISTS 'shipped' AFTER 'processing';
+
+ALTER TYPE "payment_status" ADD VALUE IF NOT EXISTS 'refunded';
+
+CREATE TYPE "notification_status" AS ENUM (
+  'pending',
+  'sent',
+  'failed'
+);
+
+ALTER TABLE "users" ADD COLUMN "locale" varchar(8) NOT NULL DEFAULT 'ru';
+
+CREATE TABLE "notifications" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "order_id" BIGINT,
+  "event" varchar(64) NOT NULL,
+  "channel" varchar(32) NOT NULL DEFAULT 'email',
+  "recipient" varchar(320) NOT NULL,
+  "locale" varchar(8) NOT NULL,
+  "subject" varchar(255) NOT NULL,
+  "body_text" text NOT NULL,
+  "body_html" text NOT NULL,
+  "status" notification_status NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" text,
+  "next_attempt_at" timestamp NOT NULL DEFAULT NOW(),
+  "sent_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "notifications" ("status", "next_attempt_at");
+
+CREATE INDEX ON "notifications" ("user_id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id");
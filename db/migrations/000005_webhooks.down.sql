@@ -0,0 +1,6 @@
+ALTER TABLE "webhook_deliveries" DROP CONSTRAINT IF EXISTS webhook_deliveries_subscription_id_fkey;
+
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhook_subscriptions";
+
+DROP TYPE IF EXISTS "webhook_delivery_status";
@@ -0,0 +1,36 @@
+CREATE TYPE "webhook_delivery_status" AS ENUM (
+  'pending',
+  'succeeded',
+  'failed'
+);
+
+CREATE TABLE "webhook_subscriptions" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "url" text NOT NULL,
+  "event_types" text[] NOT NULL,
+  "secret" varchar(255) NOT NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "subscription_id" BIGINT NOT NULL,
+  "event_type" varchar(64) NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" webhook_delivery_status NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "response_status" int,
+  "response_body" text,
+  "last_error" text,
+  "next_attempt_at" timestamp NOT NULL DEFAULT NOW(),
+  "delivered_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "webhook_deliveries" ("status", "next_attempt_at");
+
+CREATE INDEX ON "webhook_deliveries" ("subscription_id");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE;
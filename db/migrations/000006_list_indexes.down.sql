@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "users_registration_date_id_idx";
+DROP INDEX IF EXISTS "products_addition_date_id_idx";
+DROP INDEX IF EXISTS "products_category_addition_date_id_idx";
+DROP INDEX IF EXISTS "orders_order_date_id_idx";
+DROP INDEX IF EXISTS "orders_user_id_order_date_id_idx";
+DROP INDEX IF EXISTS "payments_payment_date_id_idx";
+DROP INDEX IF EXISTS "payments_user_id_payment_date_id_idx";
+DROP INDEX IF EXISTS "order_items_order_id_id_idx";
@@ -0,0 +1,15 @@
+CREATE INDEX ON "users" ("registration_date", "id");
+
+CREATE INDEX ON "products" ("addition_date", "id");
+
+CREATE INDEX ON "products" ("category", "addition_date", "id");
+
+CREATE INDEX ON "orders" ("order_date", "id");
+
+CREATE INDEX ON "orders" ("user_id", "order_date", "id");
+
+CREATE INDEX ON "payments" ("payment_date", "id");
+
+CREATE INDEX ON "payments" ("user_id", "payment_date", "id");
+
+CREATE INDEX ON "order_items" ("order_id", "id");
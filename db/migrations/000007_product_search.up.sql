@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX "products_search_idx" ON "products" USING GIN ((
+  setweight(to_tsvector('russian', "name"), 'A') || setweight(to_tsvector('russian', "description"), 'B') ||
+  setweight(to_tsvector('english', "name"), 'A') || setweight(to_tsvector('english', "description"), 'B')
+));
+
+CREATE INDEX "products_name_trgm_idx" ON "products" USING GIN ("name" gin_trgm_ops);
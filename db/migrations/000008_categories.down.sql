@@ -0,0 +1,15 @@
+ALTER TABLE "products" ADD COLUMN "category" varchar(255);
+
+UPDATE "products" SET "category" = "categories"."name"
+FROM "categories"
+WHERE "categories"."id" = "products"."category_id";
+
+ALTER TABLE "products" ALTER COLUMN "category" SET NOT NULL;
+
+CREATE INDEX ON "products" ("category", "addition_date", "id");
+
+ALTER TABLE "products" DROP CONSTRAINT IF EXISTS products_category_id_fkey;
+
+ALTER TABLE "products" DROP COLUMN "category_id";
+
+DROP TABLE IF EXISTS "categories";
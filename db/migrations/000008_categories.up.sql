@@ -0,0 +1,37 @@
+CREATE TABLE "categories" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "parent_id" BIGINT,
+  "name" varchar(255) NOT NULL,
+  "slug" varchar(255) UNIQUE NOT NULL,
+  "position" int NOT NULL DEFAULT 0,
+  "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "categories" ("parent_id", "position");
+
+ALTER TABLE "categories" ADD FOREIGN KEY ("parent_id") REFERENCES "categories" ("id");
+
+-- Existing category strings become top-level categories; strings that only differ in case or punctuation are merged
+INSERT INTO "categories" ("name", "slug")
+SELECT min(trim("category")), "slug"
+FROM (
+  SELECT "category", coalesce(nullif(trim(BOTH '-' FROM lower(regexp_replace("category", '[^[:alnum:]]+', '-', 'g'))), ''), 'uncategorized') AS "slug"
+  FROM "products"
+) AS "existing"
+GROUP BY "slug";
+
+ALTER TABLE "products" ADD COLUMN "category_id" BIGINT;
+
+UPDATE "products" SET "category_id" = "categories"."id"
+FROM "categories"
+WHERE "categories"."slug" = coalesce(nullif(trim(BOTH '-' FROM lower(regexp_replace("products"."category", '[^[:alnum:]]+', '-', 'g'))), ''), 'uncategorized');
+
+ALTER TABLE "products" ALTER COLUMN "category_id" SET NOT NULL;
+
+ALTER TABLE "products" ADD FOREIGN KEY ("category_id") REFERENCES "categories" ("id");
+
+CREATE INDEX ON "products" ("category_id", "addition_date", "id");
+
+DROP INDEX IF EXISTS "products_category_addition_date_id_idx";
+
+ALTER TABLE "products" DROP COLUMN "category";
@@ -0,0 +1,5 @@
+ALTER TABLE "order_items" DROP CONSTRAINT IF EXISTS order_items_variant_id_fkey;
+
+ALTER TABLE "order_items" DROP COLUMN "variant_id";
+
+DROP TABLE IF EXISTS "product_variants";
@@ -0,0 +1,20 @@
+CREATE TABLE "product_variants" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "product_id" BIGINT NOT NULL,
+  "sku" varchar(64) UNIQUE NOT NULL,
+  "attributes" jsonb NOT NULL DEFAULT '{}',
+  "price" numeric(10, 2),
+  "stock_quantity" int NOT NULL DEFAULT 0,
+  "position" int NOT NULL DEFAULT 0,
+  "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "product_variants" ("product_id", "position");
+
+ALTER TABLE "product_variants" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "product_variants" ADD CONSTRAINT "product_variants_stock_quantity_check" CHECK ("stock_quantity" >= 0);
+
+ALTER TABLE "order_items" ADD COLUMN "variant_id" BIGINT;
+
+ALTER TABLE "order_items" ADD FOREIGN KEY ("variant_id") REFERENCES "product_variants" ("id");
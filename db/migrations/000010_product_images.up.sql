@@ -0,0 +1,20 @@
+CREATE TABLE "product_images" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "product_id" BIGINT NOT NULL,
+  "storage_key" varchar(512) NOT NULL,
+  "url" text NOT NULL,
+  "thumbnails" jsonb NOT NULL DEFAULT '{}',
+  "content_type" varchar(64) NOT NULL,
+  "width" int NOT NULL,
+  "height" int NOT NULL,
+  "size_bytes" BIGINT NOT NULL,
+  "position" int NOT NULL DEFAULT 0,
+  "is_primary" boolean NOT NULL DEFAULT false,
+  "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "product_images" ("product_id", "position");
+
+CREATE UNIQUE INDEX "product_images_primary_idx" ON "product_images" ("product_id") WHERE "is_primary";
+
+ALTER TABLE "product_images" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
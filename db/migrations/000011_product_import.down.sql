@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "product_import_jobs";
+
+DROP TYPE IF EXISTS "import_job_status";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "sku";
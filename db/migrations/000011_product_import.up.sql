@@ -0,0 +1,30 @@
+ALTER TABLE "products" ADD COLUMN "sku" varchar(64);
+
+UPDATE "products" SET "sku" = 'P-' || lpad("id"::text, 6, '0');
+
+ALTER TABLE "products" ALTER COLUMN "sku" SET NOT NULL;
+
+ALTER TABLE "products" ADD CONSTRAINT "products_sku_key" UNIQUE ("sku");
+
+CREATE TYPE "import_job_status" AS ENUM (
+  'pending',
+  'running',
+  'completed',
+  'failed'
+);
+
+CREATE TABLE "product_import_jobs" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "format" varchar(16) NOT NULL,
+  "status" import_job_status NOT NULL DEFAULT 'pending',
+  "dry_run" boolean NOT NULL DEFAULT false,
+  "total_rows" int NOT NULL DEFAULT 0,
+  "created_rows" int NOT NULL DEFAULT 0,
+  "updated_rows" int NOT NULL DEFAULT 0,
+  "failed_rows" int NOT NULL DEFAULT 0,
+  "errors" jsonb NOT NULL DEFAULT '[]',
+  "last_error" text,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "started_at" timestamp,
+  "finished_at" timestamp
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "order_adjustments";
+
+DROP TABLE IF EXISTS "promotion_redemptions";
+
+DROP TABLE IF EXISTS "promotions";
+
+DROP TYPE IF EXISTS "promotion_type";
@@ -0,0 +1,64 @@
+CREATE TYPE "promotion_type" AS ENUM (
+  'percentage',
+  'fixed',
+  'free_shipping',
+  'buy_x_get_y'
+);
+
+CREATE TABLE "promotions" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" varchar(255) NOT NULL,
+  "code" varchar(64) UNIQUE,
+  "type" promotion_type NOT NULL,
+  "value" numeric(10,2) NOT NULL DEFAULT 0,
+  "buy_quantity" int NOT NULL DEFAULT 0,
+  "get_quantity" int NOT NULL DEFAULT 0,
+  "product_id" BIGINT,
+  "category_id" BIGINT,
+  "min_order_amount" numeric(10,2),
+  "starts_at" timestamp,
+  "ends_at" timestamp,
+  "usage_limit" int,
+  "per_user_limit" int,
+  "times_used" int NOT NULL DEFAULT 0,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE "promotion_redemptions" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "promotion_id" BIGINT NOT NULL,
+  "order_id" BIGINT NOT NULL,
+  "user_id" BIGINT NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE "order_adjustments" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "order_id" BIGINT NOT NULL,
+  "promotion_id" BIGINT,
+  "kind" varchar(32) NOT NULL,
+  "code" varchar(64),
+  "description" text NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "promotions" ("active") WHERE "code" IS NULL;
+
+CREATE INDEX ON "promotion_redemptions" ("promotion_id", "user_id");
+
+CREATE INDEX ON "order_adjustments" ("order_id");
+
+ALTER TABLE "promotions" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "promotions" ADD FOREIGN KEY ("category_id") REFERENCES "categories" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "promotion_redemptions" ADD FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "promotion_redemptions" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "order_adjustments" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "order_adjustments" ADD FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id") ON DELETE SET NULL;
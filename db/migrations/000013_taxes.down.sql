@@ -0,0 +1,15 @@
+ALTER TABLE "order_items"
+  DROP COLUMN IF EXISTS "gross_amount",
+  DROP COLUMN IF EXISTS "tax_amount",
+  DROP COLUMN IF EXISTS "net_amount",
+  DROP COLUMN IF EXISTS "tax_rate";
+
+ALTER TABLE "orders"
+  DROP COLUMN IF EXISTS "destination_country",
+  DROP COLUMN IF EXISTS "prices_include_tax",
+  DROP COLUMN IF EXISTS "tax_amount",
+  DROP COLUMN IF EXISTS "net_amount";
+
+DROP TABLE IF EXISTS "tax_rates";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "tax_class";
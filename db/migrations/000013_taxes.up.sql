@@ -0,0 +1,32 @@
+ALTER TABLE "products" ADD COLUMN "tax_class" varchar(32) NOT NULL DEFAULT 'standard';
+
+CREATE TABLE "tax_rates" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "tax_class" varchar(32) NOT NULL,
+  "country" char(2) NOT NULL,
+  "rate" numeric(6,4) NOT NULL,
+  "name" varchar(64) NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  UNIQUE ("tax_class", "country")
+);
+
+ALTER TABLE "orders"
+  ADD COLUMN "net_amount" numeric(10,2) NOT NULL DEFAULT 0,
+  ADD COLUMN "tax_amount" numeric(10,2) NOT NULL DEFAULT 0,
+  ADD COLUMN "prices_include_tax" boolean NOT NULL DEFAULT true,
+  ADD COLUMN "destination_country" char(2) NOT NULL DEFAULT 'KZ';
+
+ALTER TABLE "order_items"
+  ADD COLUMN "tax_rate" numeric(6,4) NOT NULL DEFAULT 0,
+  ADD COLUMN "net_amount" numeric(10,2) NOT NULL DEFAULT 0,
+  ADD COLUMN "tax_amount" numeric(10,2) NOT NULL DEFAULT 0,
+  ADD COLUMN "gross_amount" numeric(10,2) NOT NULL DEFAULT 0;
+
+-- Orders placed before taxes were tracked carry no tax
+UPDATE "orders" SET "net_amount" = "total_amount";
+
+UPDATE "order_items" SET "net_amount" = "price", "gross_amount" = "price";
+
+INSERT INTO "tax_rates" ("tax_class", "country", "rate", "name") VALUES
+  ('standard', 'KZ', 0.12, 'VAT 12%'),
+  ('exempt', 'KZ', 0, 'VAT exempt');
@@ -0,0 +1,17 @@
+ALTER TABLE "orders"
+  DROP COLUMN IF EXISTS "shipping_amount",
+  DROP COLUMN IF EXISTS "shipping_method_id";
+
+DROP TABLE IF EXISTS "shipments";
+
+DROP TABLE IF EXISTS "shipping_rates";
+
+DROP TABLE IF EXISTS "shipping_methods";
+
+ALTER TABLE "products" DROP COLUMN IF EXISTS "weight_grams";
+
+DROP TYPE IF EXISTS "shipment_status";
+
+DROP TYPE IF EXISTS "shipping_rate_type";
+
+-- Enum values cannot be dropped; 'delivered' remains in order_status
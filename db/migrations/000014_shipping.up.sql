@@ -0,0 +1,61 @@
+ALTER TYPE "order_status" ADD VALUE IF NOT EXISTS 'delivered' AFTER 'shipped';
+
+CREATE TYPE "shipping_rate_type" AS ENUM (
+  'flat',
+  'weight'
+);
+
+CREATE TYPE "shipment_status" AS ENUM (
+  'pending',
+  'shipped',
+  'delivered'
+);
+
+ALTER TABLE "products" ADD COLUMN "weight_grams" int NOT NULL DEFAULT 0;
+
+CREATE TABLE "shipping_methods" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" varchar(255) NOT NULL,
+  "carrier" varchar(64) NOT NULL,
+  "rate_type" shipping_rate_type NOT NULL,
+  "price" numeric(10,2) NOT NULL DEFAULT 0,
+  "free_over" numeric(10,2),
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE "shipping_rates" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "method_id" BIGINT NOT NULL,
+  "max_weight_grams" int NOT NULL,
+  "price" numeric(10,2) NOT NULL,
+  UNIQUE ("method_id", "max_weight_grams")
+);
+
+CREATE TABLE "shipments" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "order_id" BIGINT NOT NULL,
+  "shipping_method_id" BIGINT,
+  "carrier" varchar(64) NOT NULL,
+  "tracking_number" varchar(128),
+  "status" shipment_status NOT NULL DEFAULT 'pending',
+  "shipped_at" timestamp,
+  "delivered_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE "orders"
+  ADD COLUMN "shipping_method_id" BIGINT,
+  ADD COLUMN "shipping_amount" numeric(10,2) NOT NULL DEFAULT 0;
+
+CREATE INDEX ON "shipments" ("order_id");
+
+ALTER TABLE "shipping_rates" ADD FOREIGN KEY ("method_id") REFERENCES "shipping_methods" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "shipments" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "shipments" ADD FOREIGN KEY ("shipping_method_id") REFERENCES "shipping_methods" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("shipping_method_id") REFERENCES "shipping_methods" ("id") ON DELETE SET NULL;
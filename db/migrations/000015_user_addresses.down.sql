@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "order_addresses";
+
+DROP TABLE IF EXISTS "user_addresses";
+
+DROP TYPE IF EXISTS "address_type";
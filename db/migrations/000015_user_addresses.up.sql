@@ -0,0 +1,52 @@
+CREATE TYPE "address_type" AS ENUM (
+  'shipping',
+  'billing'
+);
+
+CREATE TABLE "user_addresses" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "type" address_type NOT NULL,
+  "full_name" varchar(255) NOT NULL,
+  "phone" varchar(32),
+  "line1" varchar(255) NOT NULL,
+  "line2" varchar(255),
+  "city" varchar(128) NOT NULL,
+  "region" varchar(128),
+  "postal_code" varchar(16),
+  "country" char(2) NOT NULL,
+  "is_default" boolean NOT NULL DEFAULT false,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+-- Copies of the addresses an order was placed with; they are never updated
+CREATE TABLE "order_addresses" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "order_id" BIGINT NOT NULL,
+  "type" address_type NOT NULL,
+  "full_name" varchar(255) NOT NULL,
+  "phone" varchar(32),
+  "line1" varchar(255) NOT NULL,
+  "line2" varchar(255),
+  "city" varchar(128) NOT NULL,
+  "region" varchar(128),
+  "postal_code" varchar(16),
+  "country" char(2) NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  UNIQUE ("order_id", "type")
+);
+
+CREATE INDEX ON "user_addresses" ("user_id");
+
+CREATE UNIQUE INDEX "user_addresses_default_idx" ON "user_addresses" ("user_id", "type") WHERE "is_default";
+
+ALTER TABLE "user_addresses" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "order_addresses" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+
+-- The free-text users.address becomes each user's default shipping address; the city is not known
+INSERT INTO "user_addresses" ("user_id", "type", "full_name", "line1", "city", "country", "is_default")
+SELECT "id", 'shipping', "full_name", "address", '', 'KZ', true
+FROM "users"
+WHERE btrim("address") <> '';
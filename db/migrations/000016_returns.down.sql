@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "return_items";
+
+DROP TABLE IF EXISTS "returns";
+
+ALTER TABLE "payments"
+  DROP COLUMN IF EXISTS "refunded_amount",
+  DROP COLUMN IF EXISTS "invoice_id";
+
+DROP TYPE IF EXISTS "return_status";
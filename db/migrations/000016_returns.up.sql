@@ -0,0 +1,49 @@
+CREATE TYPE "return_status" AS ENUM (
+  'requested',
+  'approved',
+  'rejected',
+  'received',
+  'refunded'
+);
+
+-- The ePay invoice of a payment, needed to find its transaction for refunds
+ALTER TABLE "payments"
+  ADD COLUMN "invoice_id" varchar(32),
+  ADD COLUMN "refunded_amount" numeric(10, 2) NOT NULL DEFAULT 0;
+
+CREATE TABLE "returns" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "order_id" BIGINT NOT NULL,
+  "status" return_status NOT NULL DEFAULT 'requested',
+  "reason" text NOT NULL DEFAULT '',
+  "note" text NOT NULL DEFAULT '',
+  "refund_amount" numeric(10, 2) NOT NULL,
+  "payment_id" BIGINT,
+  "received_at" timestamp,
+  "refunded_at" timestamp,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE "return_items" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "return_id" BIGINT NOT NULL,
+  "order_item_id" BIGINT NOT NULL,
+  "quantity" int NOT NULL,
+  "amount" numeric(10, 2) NOT NULL,
+  UNIQUE ("return_id", "order_item_id")
+);
+
+CREATE INDEX ON "returns" ("order_id");
+
+CREATE INDEX ON "returns" ("status", "created_at");
+
+CREATE INDEX ON "return_items" ("order_item_id");
+
+ALTER TABLE "returns" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "returns" ADD FOREIGN KEY ("payment_id") REFERENCES "payments" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "return_items" ADD FOREIGN KEY ("return_id") REFERENCES "returns" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "return_items" ADD FOREIGN KEY ("order_item_id") REFERENCES "order_items" ("id") ON DELETE CASCADE;
@@ -0,0 +1,16 @@
+-- Responses of mutating requests sent with an Idempotency-Key header, replayed when the request is retried.
+-- owner scopes keys to the API key or user that sent them; status_code is NULL while the request is in progress
+CREATE TABLE "idempotency_keys" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "owner" varchar(255) NOT NULL,
+  "key" varchar(255) NOT NULL,
+  "fingerprint" varchar(64) NOT NULL,
+  "status_code" integer,
+  "content_type" varchar(255) NOT NULL DEFAULT '',
+  "response_body" bytea,
+  "created_at" timestamp NOT NULL DEFAULT NOW(),
+  "completed_at" timestamp,
+  UNIQUE ("owner", "key")
+);
+
+CREATE INDEX ON "idempotency_keys" ("created_at");
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS "payments_bump_version" ON "payments";
+DROP TRIGGER IF EXISTS "orders_bump_version" ON "orders";
+DROP TRIGGER IF EXISTS "products_bump_version" ON "products";
+DROP TRIGGER IF EXISTS "users_bump_version" ON "users";
+
+DROP FUNCTION IF EXISTS "bump_version"();
+
+ALTER TABLE "payments" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "version";
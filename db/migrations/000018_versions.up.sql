@@ -0,0 +1,19 @@
+-- Row versions for optimistic concurrency control, exposed as ETags.
+-- The trigger bumps the version on every update, so writes such as stock reservations
+-- and order repricing invalidate ETags handed out before them as well
+ALTER TABLE "users" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "products" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "orders" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "payments" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+
+CREATE FUNCTION "bump_version"() RETURNS trigger AS $$
+BEGIN
+  NEW.version := OLD.version + 1;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "users_bump_version" BEFORE UPDATE ON "users" FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER "products_bump_version" BEFORE UPDATE ON "products" FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER "orders_bump_version" BEFORE UPDATE ON "orders" FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER "payments_bump_version" BEFORE UPDATE ON "payments" FOR EACH ROW EXECUTE FUNCTION bump_version();
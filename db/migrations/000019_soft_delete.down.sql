@@ -0,0 +1,4 @@
+ALTER TABLE "payments" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "deleted_at";
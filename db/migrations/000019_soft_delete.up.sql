@@ -0,0 +1,10 @@
+-- Deleted rows are kept, hidden from lists and searches, until the retention job purges them
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamp;
+ALTER TABLE "products" ADD COLUMN "deleted_at" timestamp;
+ALTER TABLE "orders" ADD COLUMN "deleted_at" timestamp;
+ALTER TABLE "payments" ADD COLUMN "deleted_at" timestamp;
+
+CREATE INDEX ON "users" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+CREATE INDEX ON "products" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+CREATE INDEX ON "orders" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+CREATE INDEX ON "payments" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
@@ -0,0 +1,19 @@
+-- Who changed what through the API. before and after hold only the fields that changed;
+-- entity_id has no foreign key so that events outlive the records they describe
+CREATE TABLE "audit_events" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "actor_type" varchar(32) NOT NULL,
+  "actor_id" varchar(255) NOT NULL DEFAULT '',
+  "action" varchar(32) NOT NULL,
+  "entity_type" varchar(32) NOT NULL,
+  "entity_id" bigint NOT NULL,
+  "before" jsonb NOT NULL DEFAULT '{}',
+  "after" jsonb NOT NULL DEFAULT '{}',
+  "request_id" varchar(255) NOT NULL DEFAULT '',
+  "ip" varchar(64) NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ON "audit_events" ("entity_type", "entity_id");
+CREATE INDEX ON "audit_events" ("actor_type", "actor_id");
+CREATE INDEX ON "audit_events" ("created_at");
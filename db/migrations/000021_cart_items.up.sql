@@ -0,0 +1,18 @@
+-- The products a user is about to order; checkout turns them into an order and empties the cart
+CREATE TABLE "cart_items" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" BIGINT NOT NULL,
+  "product_id" BIGINT NOT NULL,
+  "variant_id" BIGINT,
+  "quantity" INT NOT NULL CHECK ("quantity" > 0),
+  "added_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+-- Adding a product already in the cart increases its quantity instead of adding a line
+CREATE UNIQUE INDEX "cart_items_line_idx" ON "cart_items" ("user_id", "product_id", (COALESCE("variant_id", 0)));
+
+ALTER TABLE "cart_items" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "cart_items" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "cart_items" ADD FOREIGN KEY ("variant_id") REFERENCES "product_variants" ("id") ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+UPDATE "returns" SET "status" = 'received' WHERE "status" = 'refunding';
+
+-- Enum values cannot be dropped; 'refunding' remains in return_status
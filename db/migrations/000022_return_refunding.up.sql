@@ -0,0 +1,2 @@
+-- A return is refunding while its money is being given back through ePay; only the request that moved it there calls ePay
+ALTER TYPE "return_status" ADD VALUE IF NOT EXISTS 'refunding' AFTER 'received';
@@ -0,0 +1 @@
+-- The slugs derived by migration 000008 dropped non-Latin letters; the rederived ones are kept
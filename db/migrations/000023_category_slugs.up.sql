@@ -0,0 +1,40 @@
+-- Migration 000008 derived the slugs of the former category strings with the database locale, which drops non-Latin
+-- letters in the C locale and maps such names to "uncategorized". Those slugs are rederived with the category.Slugify
+-- rules: letters and digits of any script, lowercased and joined with dashes. Only top-level categories whose slug is
+-- still the 000008 one are touched, so slugs set through the API are kept. Collisions get a numbered suffix.
+-- The ICU root collation gives Unicode character classes whatever the database locale is; without ICU the default
+-- collation is used. Products already merged into one category by 000008 stay merged.
+DO $$
+DECLARE
+  collation_name text := 'default';
+  existing record;
+  base text;
+  candidate text;
+  n int;
+BEGIN
+  IF EXISTS (SELECT 1 FROM pg_collation WHERE collname = 'und-x-icu') THEN
+    collation_name := 'und-x-icu';
+  END IF;
+
+  FOR existing IN
+    SELECT "id", "name"
+    FROM "categories"
+    WHERE "parent_id" IS NULL
+      AND "slug" = coalesce(nullif(trim(BOTH '-' FROM lower(regexp_replace("name", '[^[:alnum:]]+', '-', 'g'))), ''), 'uncategorized')
+    ORDER BY "id"
+  LOOP
+    EXECUTE format(
+      'SELECT coalesce(nullif(trim(BOTH ''-'' FROM regexp_replace(lower(replace($1, ''İ'', ''i'') COLLATE %I), ''[^[:alnum:]]+'', ''-'', ''g'')), ''''), ''uncategorized'')',
+      collation_name
+    ) INTO base USING existing."name";
+
+    candidate := rtrim(left(base, 255), '-');
+    n := 1;
+    WHILE EXISTS (SELECT 1 FROM "categories" WHERE "slug" = candidate AND "id" <> existing."id") LOOP
+      n := n + 1;
+      candidate := rtrim(left(base, 254 - length(n::text)), '-') || '-' || n;
+    END LOOP;
+
+    UPDATE "categories" SET "slug" = candidate WHERE "id" = existing."id" AND "slug" <> candidate;
+  END LOOP;
+END $$;
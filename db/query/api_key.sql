@@ -0,0 +1,22 @@
+-- name: GetAPIKeyByPrefix :one
+-- Keys of deleted users are not found, so they stop authenticating
+SELECT * FROM api_keys
+WHERE prefix = $1 AND EXISTS (SELECT 1 FROM users WHERE users.id = api_keys.user_id AND users.deleted_at IS NULL)
+LIMIT 1;
+
+-- name: ListAPIKeysByUser :many
+SELECT * FROM api_keys WHERE user_id = $1 ORDER BY created_at ASC;
+
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, name, prefix, secret_hash, permissions, created_at) 
+VALUES ($1, $2, $3, $4, $5, NOW()) 
+RETURNING *;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys SET 
+    revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL 
+RETURNING *;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys SET last_used_at = NOW() WHERE id = $1;
@@ -0,0 +1,6 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (actor_type, actor_id, action, entity_type, entity_id, before, after, request_id, ip, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW());
+
+-- name: GetAuditEvent :one
+SELECT * FROM audit_events WHERE id = $1 LIMIT 1;
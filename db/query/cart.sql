@@ -0,0 +1,19 @@
+-- name: ListCartItems :many
+SELECT * FROM cart_items WHERE user_id = $1 ORDER BY id ASC;
+
+-- name: AddCartItem :one
+-- Adding a product already in the cart increases the quantity of its line
+INSERT INTO cart_items (user_id, product_id, variant_id, quantity)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, product_id, (COALESCE(variant_id, 0)))
+DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity
+RETURNING *;
+
+-- name: UpdateCartItemQuantity :one
+UPDATE cart_items SET quantity = $3 WHERE id = $1 AND user_id = $2 RETURNING *;
+
+-- name: DeleteCartItem :execrows
+DELETE FROM cart_items WHERE id = $1 AND user_id = $2;
+
+-- name: ClearCart :exec
+DELETE FROM cart_items WHERE user_id = $1;
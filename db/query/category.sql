@@ -0,0 +1,39 @@
+-- name: GetCategory :one
+SELECT * FROM categories WHERE id = $1 LIMIT 1;
+
+-- name: GetCategoryBySlug :one
+SELECT * FROM categories WHERE slug = $1 LIMIT 1;
+
+-- name: ListCategories :many
+SELECT * FROM categories ORDER BY parent_id NULLS FIRST, position ASC, name ASC;
+
+-- name: ListCategoryChildren :many
+SELECT * FROM categories WHERE parent_id = $1 ORDER BY position ASC, name ASC;
+
+-- name: ListCategoryDescendantIDs :many
+WITH RECURSIVE tree AS (
+    SELECT categories.id FROM categories WHERE categories.id = $1
+    UNION ALL
+    SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+)
+SELECT tree.id::bigint AS id FROM tree;
+
+-- name: CreateCategory :one
+INSERT INTO categories (parent_id, name, slug, position) 
+VALUES ($1, $2, $3, $4) 
+RETURNING *;
+
+-- name: UpdateCategory :one
+UPDATE categories SET 
+    parent_id = $2,
+    name = $3,
+    slug = $4,
+    position = $5
+WHERE id = $1 
+RETURNING *;
+
+-- name: DeleteCategory :exec
+DELETE FROM categories WHERE id = $1;
+
+-- name: CountProductsByCategory :one
+SELECT count(*) FROM products WHERE category_id = $1;
@@ -0,0 +1,31 @@
+-- name: ClaimIdempotencyKey :one
+-- Claims a key for a new request. A key whose claim is older than expires_before is taken over;
+-- no row is returned while the key is held by an earlier request.
+INSERT INTO idempotency_keys (owner, key, fingerprint, created_at)
+VALUES (sqlc.arg(owner), sqlc.arg(key), sqlc.arg(fingerprint), NOW())
+ON CONFLICT (owner, key) DO UPDATE SET
+    fingerprint = EXCLUDED.fingerprint,
+    status_code = NULL,
+    content_type = '',
+    response_body = NULL,
+    created_at = NOW(),
+    completed_at = NULL
+WHERE idempotency_keys.created_at < sqlc.arg(expires_before)
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE owner = $1 AND key = $2 LIMIT 1;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys SET
+    status_code = $2,
+    content_type = $3,
+    response_body = $4,
+    completed_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE id = $1;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE created_at < $1;
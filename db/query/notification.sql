@@ -0,0 +1,40 @@
+-- name: GetNotification :one
+SELECT * FROM notifications WHERE id = $1 LIMIT 1;
+
+-- name: ListNotificationsByUser :many
+SELECT * FROM notifications WHERE user_id = $1 ORDER BY created_at DESC;
+
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, order_id, event, recipient, locale, subject, body_text, body_html, next_attempt_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+RETURNING *;
+
+-- name: ClaimDueNotifications :many
+-- Claims due notifications by moving next_attempt_at to the end of a lease, so that other workers skip them;
+-- a worker that stops before recording the outcome leaves them to be retried once the lease runs out
+UPDATE notifications SET 
+    next_attempt_at = $1
+WHERE id IN (
+    SELECT id FROM notifications 
+    WHERE status = 'pending' AND next_attempt_at <= NOW() 
+    ORDER BY next_attempt_at ASC 
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkNotificationSent :exec
+UPDATE notifications SET 
+    status = 'sent',
+    attempts = attempts + 1,
+    last_error = NULL,
+    sent_at = NOW()
+WHERE id = $1;
+
+-- name: MarkNotificationFailed :exec
+UPDATE notifications SET 
+    status = $2,
+    attempts = attempts + 1,
+    last_error = $3,
+    next_attempt_at = $4
+WHERE id = $1;
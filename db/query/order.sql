@@ -2,26 +2,57 @@
 SELECT * FROM orders WHERE id = $1 LIMIT 1;
 
 -- name: ListOrders :many
-SELECT * FROM orders ORDER BY order_date ASC;
+SELECT * FROM orders WHERE deleted_at IS NULL ORDER BY order_date ASC;
 
 -- name: CreateOrder :one
-INSERT INTO orders (user_id, total_amount, order_date) 
-VALUES ($1, $2, NOW()) 
-RETURNING id, user_id, total_amount, order_date, status;
+INSERT INTO orders (user_id, total_amount, prices_include_tax, destination_country, shipping_method_id, order_date) 
+VALUES ($1, $2, $3, $4, $5, NOW()) 
+RETURNING *;
 
 -- name: UpdateOrder :one
 UPDATE orders SET 
     user_id = $2,
     total_amount = $3,
     status = $4
-WHERE id = $1 
+WHERE id = $1 AND version = $5
 RETURNING *;
 
--- name: DeleteOrder :exec
-DELETE FROM orders WHERE id = $1;
+-- name: DeleteOrder :one
+UPDATE orders SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING *;
+
+-- name: RestoreOrder :one
+UPDATE orders SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: PurgeDeletedOrders :execrows
+-- Orders with payments are kept until those are purged; items and notifications go with the order
+WITH expired AS (
+    SELECT o.id FROM orders o
+    WHERE o.deleted_at < $1
+        AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.order_id = o.id)
+), items_deleted AS (
+    DELETE FROM order_items WHERE order_id IN (SELECT id FROM expired)
+), notifications_deleted AS (
+    DELETE FROM notifications WHERE order_id IN (SELECT id FROM expired)
+)
+DELETE FROM orders WHERE id IN (SELECT id FROM expired);
 
 -- name: SearchOrdersByUser :many
-SELECT * FROM orders WHERE user_id = $1 ORDER BY order_date ASC;
+SELECT * FROM orders WHERE user_id = $1 AND deleted_at IS NULL ORDER BY order_date ASC;
 
 -- name: SearchOrdersByStatus :many
-SELECT * FROM orders WHERE status = $1 ORDER BY order_date ASC;
+SELECT * FROM orders WHERE status = $1 AND deleted_at IS NULL ORDER BY order_date ASC;
+
+-- name: SetOrderTotals :one
+UPDATE orders SET
+    net_amount = $2,
+    tax_amount = $3,
+    shipping_amount = $4,
+    total_amount = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: SetOrderStatus :one
+UPDATE orders SET status = $2 WHERE id = $1 RETURNING *;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders WHERE id = $1 LIMIT 1 FOR UPDATE;
@@ -0,0 +1,10 @@
+-- name: CreateOrderAddress :one
+-- Copies a user address onto an order; type is given as the billing address may be a shipping one
+INSERT INTO order_addresses (order_id, type, full_name, phone, line1, line2, city, region, postal_code, country)
+SELECT sqlc.arg(order_id)::bigint, sqlc.arg(type)::address_type, a.full_name, a.phone, a.line1, a.line2, a.city, a.region, a.postal_code, a.country
+FROM user_addresses a
+WHERE a.id = sqlc.arg(address_id)::bigint
+RETURNING *;
+
+-- name: ListOrderAddresses :many
+SELECT * FROM order_addresses WHERE order_id = $1 ORDER BY type ASC;
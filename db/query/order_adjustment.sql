@@ -0,0 +1,10 @@
+-- name: CreateOrderAdjustment :one
+INSERT INTO order_adjustments (order_id, promotion_id, kind, code, description, amount)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListOrderAdjustmentsByOrder :many
+SELECT * FROM order_adjustments WHERE order_id = $1 ORDER BY id ASC;
+
+-- name: DeleteOrderAdjustmentsByOrder :exec
+DELETE FROM order_adjustments WHERE order_id = $1;
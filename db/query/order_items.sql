@@ -5,8 +5,8 @@ SELECT * FROM order_items WHERE id = $1 LIMIT 1;
 SELECT * FROM order_items ORDER BY id ASC;
 
 -- name: CreateOrderItem :one
-INSERT INTO order_items (order_id, product_id, quantity, price) 
-VALUES ($1, $2, $3, $4) 
+INSERT INTO order_items (order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
 RETURNING *;
 
 -- name: UpdateOrderItem :one
@@ -26,3 +26,27 @@ SELECT * FROM order_items WHERE order_id = $1 ORDER BY id ASC;
 
 -- name: ListOrderItemsByProduct :many
 SELECT * FROM order_items WHERE product_id = $1 ORDER BY id ASC;
+
+-- name: SummarizeOrderItemTaxes :many
+SELECT tax_rate,
+    sum(net_amount)::numeric AS net_amount,
+    sum(tax_amount)::numeric AS tax_amount,
+    sum(gross_amount)::numeric AS gross_amount
+FROM order_items
+WHERE order_id = $1
+GROUP BY tax_rate
+ORDER BY tax_rate DESC;
+
+-- name: SetOrderItemAmounts :one
+UPDATE order_items SET
+    quantity = $2,
+    price = $3,
+    tax_rate = $4,
+    net_amount = $5,
+    tax_amount = $6,
+    gross_amount = $7
+WHERE id = $1
+RETURNING *;
+
+-- name: ListOrderItemsByOrders :many
+SELECT * FROM order_items WHERE order_id = ANY(sqlc.arg(order_ids)::bigint[]) ORDER BY order_id ASC, id ASC;
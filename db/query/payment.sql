@@ -2,11 +2,11 @@
 SELECT * FROM payments WHERE id = $1 LIMIT 1;
 
 -- name: ListPayments :many
-SELECT * FROM payments ORDER BY payment_date ASC;
+SELECT * FROM payments WHERE deleted_at IS NULL ORDER BY payment_date ASC;
 
 -- name: CreatePayment :one
-INSERT INTO payments (user_id, order_id, amount, payment_date, status) 
-VALUES ($1, $2, $3, NOW(), $4) 
+INSERT INTO payments (user_id, order_id, amount, payment_date, status, invoice_id) 
+VALUES ($1, $2, $3, NOW(), $4, $5) 
 RETURNING *;
 
 -- name: UpdatePayment :one
@@ -15,17 +15,45 @@ UPDATE payments SET
     order_id = $3,
     amount = $4,
     status = $5
-WHERE id = $1 
+WHERE id = $1 AND version = $6
 RETURNING *;
 
--- name: DeletePayment :exec
-DELETE FROM payments WHERE id = $1;
+-- name: DeletePayment :one
+UPDATE payments SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING *;
+
+-- name: RestorePayment :one
+UPDATE payments SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: PurgeDeletedPayments :execrows
+DELETE FROM payments WHERE deleted_at < $1;
 
 -- name: SearchPaymentsByUser :many
-SELECT * FROM payments WHERE user_id = $1 ORDER BY payment_date ASC;
+SELECT * FROM payments WHERE user_id = $1 AND deleted_at IS NULL ORDER BY payment_date ASC;
 
 -- name: SearchPaymentsByOrder :many
-SELECT * FROM payments WHERE order_id = $1 ORDER BY payment_date ASC;
+SELECT * FROM payments WHERE order_id = $1 AND deleted_at IS NULL ORDER BY payment_date ASC;
 
 -- name: SearchPaymentsByStatus :many
-SELECT * FROM payments WHERE status = $1 ORDER BY payment_date ASC;
+SELECT * FROM payments WHERE status = $1 AND deleted_at IS NULL ORDER BY payment_date ASC;
+
+-- name: GetRefundablePayment :one
+-- The latest successful ePay payment of an order that has not been refunded in full
+SELECT * FROM payments
+WHERE order_id = $1 AND status = 'successful' AND invoice_id IS NOT NULL AND refunded_amount < amount
+ORDER BY payment_date DESC, id DESC
+LIMIT 1;
+
+-- name: AddPaymentRefund :one
+-- A payment becomes refunded once its whole amount has been returned
+UPDATE payments SET
+    refunded_amount = refunded_amount + sqlc.arg(amount)::numeric,
+    status = CASE WHEN refunded_amount + sqlc.arg(amount)::numeric >= amount THEN 'refunded'::payment_status ELSE status END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CountPaidPayments :one
+-- Payments that took money from the customer, including ones refunded since
+SELECT count(*) FROM payments WHERE order_id = $1 AND status <> 'unsuccessful';
+
+-- name: ListPaymentsByOrders :many
+SELECT * FROM payments WHERE order_id = ANY(sqlc.arg(order_ids)::bigint[]) AND deleted_at IS NULL ORDER BY order_id ASC, payment_date ASC;
@@ -2,34 +2,162 @@
 SELECT * FROM products WHERE id = $1 LIMIT 1;
 
 -- name: ListProducts :many
-SELECT * FROM products ORDER BY addition_date ASC;
+SELECT * FROM products WHERE deleted_at IS NULL ORDER BY addition_date ASC;
+
+-- name: GetProductBySku :one
+SELECT * FROM products WHERE sku = $1 LIMIT 1;
 
 -- name: CreateProduct :one
-INSERT INTO products (name, description, price, category, stock_quantity, addition_date) 
-VALUES ($1, $2, $3, $4, $5, NOW()) 
+INSERT INTO products (sku, name, description, price, category_id, stock_quantity, tax_class, weight_grams, addition_date) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW()) 
 RETURNING *;
 
 -- name: UpdateProduct :one
 UPDATE products SET 
-    name = $2,
-    description = $3,
-    price = $4,
-    category = $5,
-    stock_quantity = $6
-WHERE id = $1 
+    sku = $2,
+    name = $3,
+    description = $4,
+    price = $5,
+    category_id = $6,
+    stock_quantity = $7,
+    tax_class = $8,
+    weight_grams = $9
+WHERE id = $1 AND version = $10
 RETURNING *;
 
--- name: DeleteProduct :exec
-DELETE FROM products WHERE id = $1;
+-- name: DeleteProduct :one
+UPDATE products SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING *;
+
+-- name: RestoreProduct :one
+UPDATE products SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: ListPurgeableProductImages :many
+SELECT i.* FROM product_images i
+JOIN products p ON p.id = i.product_id
+WHERE p.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = p.id);
+
+-- name: PurgeDeletedProducts :execrows
+-- Products on orders are kept for the invoices; variants, images and promotions are removed by the cascade
+DELETE FROM products p
+WHERE p.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = p.id);
 
 -- name: SearchProductsByName :many
-SELECT * FROM products WHERE name ILIKE '%' || $1 || '%' ORDER BY addition_date ASC;
+SELECT * FROM products WHERE name ILIKE '%' || $1 || '%' AND deleted_at IS NULL ORDER BY addition_date ASC;
 
 -- name: SearchProductsByCategory :many
-SELECT * FROM products WHERE category = $1 ORDER BY addition_date ASC;
+SELECT p.* FROM products p 
+JOIN categories c ON c.id = p.category_id 
+WHERE c.name = $1 AND p.deleted_at IS NULL
+ORDER BY p.addition_date ASC;
 
 -- name: UpdateProductStock :one
 UPDATE products
 SET stock_quantity = stock_quantity - $1
 WHERE id = $2
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+-- name: SearchProducts :many
+SELECT p.*,
+    (ts_rank(
+        setweight(to_tsvector('russian', p.name), 'A') || setweight(to_tsvector('russian', p.description), 'B') ||
+        setweight(to_tsvector('english', p.name), 'A') || setweight(to_tsvector('english', p.description), 'B'),
+        websearch_to_tsquery('russian', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text)
+    ) + similarity(p.name, sqlc.arg(query)::text))::real AS rank,
+    count(*) OVER ()::bigint AS total
+FROM products p
+WHERE (
+        setweight(to_tsvector('russian', p.name), 'A') || setweight(to_tsvector('russian', p.description), 'B') ||
+        setweight(to_tsvector('english', p.name), 'A') || setweight(to_tsvector('english', p.description), 'B')
+        @@ (websearch_to_tsquery('russian', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text))
+        OR p.name % sqlc.arg(query)::text
+    )
+    AND p.deleted_at IS NULL
+    AND (sqlc.narg(category_ids)::bigint[] IS NULL OR p.category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+    AND (sqlc.narg(min_price)::numeric IS NULL OR p.price >= sqlc.narg(min_price)::numeric)
+    AND (sqlc.narg(max_price)::numeric IS NULL OR p.price <= sqlc.narg(max_price)::numeric)
+    AND (NOT sqlc.arg(in_stock)::boolean OR p.stock_quantity > 0)
+ORDER BY rank DESC, p.id ASC
+LIMIT sqlc.arg(page_limit)::int OFFSET sqlc.arg(page_offset)::int;
+
+-- name: SearchProductCategoryFacets :many
+SELECT p.category_id, c.name AS category, count(*)::bigint AS count
+FROM products p
+JOIN categories c ON c.id = p.category_id
+WHERE (
+        setweight(to_tsvector('russian', p.name), 'A') || setweight(to_tsvector('russian', p.description), 'B') ||
+        setweight(to_tsvector('english', p.name), 'A') || setweight(to_tsvector('english', p.description), 'B')
+        @@ (websearch_to_tsquery('russian', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text))
+        OR p.name % sqlc.arg(query)::text
+    )
+    AND p.deleted_at IS NULL
+    AND (sqlc.narg(min_price)::numeric IS NULL OR p.price >= sqlc.narg(min_price)::numeric)
+    AND (sqlc.narg(max_price)::numeric IS NULL OR p.price <= sqlc.narg(max_price)::numeric)
+    AND (NOT sqlc.arg(in_stock)::boolean OR p.stock_quantity > 0)
+GROUP BY p.category_id, c.name
+ORDER BY count DESC, c.name ASC;
+
+-- name: SearchProductPriceFacets :many
+SELECT width_bucket(p.price, sqlc.arg(bounds)::numeric[])::int AS bucket, count(*)::bigint AS count
+FROM products p
+WHERE (
+        setweight(to_tsvector('russian', p.name), 'A') || setweight(to_tsvector('russian', p.description), 'B') ||
+        setweight(to_tsvector('english', p.name), 'A') || setweight(to_tsvector('english', p.description), 'B')
+        @@ (websearch_to_tsquery('russian', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text))
+        OR p.name % sqlc.arg(query)::text
+    )
+    AND p.deleted_at IS NULL
+    AND (sqlc.narg(category_ids)::bigint[] IS NULL OR p.category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+    AND (NOT sqlc.arg(in_stock)::boolean OR p.stock_quantity > 0)
+GROUP BY bucket
+ORDER BY bucket ASC;
+
+-- name: ReserveProductStock :one
+UPDATE products
+SET stock_quantity = stock_quantity - sqlc.arg(quantity)::int
+WHERE id = sqlc.arg(id) AND stock_quantity >= sqlc.arg(quantity)::int
+RETURNING *;
+
+-- name: UpsertProductBySku :one
+-- Fields passed as NULL keep their value on an existing product and take the column default on a new one.
+-- A deleted product stays deleted unless restore is set.
+INSERT INTO products (sku, name, description, price, category_id, stock_quantity, tax_class, weight_grams, addition_date)
+VALUES (
+    sqlc.arg(sku),
+    sqlc.narg(name)::varchar,
+    coalesce(sqlc.narg(description)::text, ''),
+    sqlc.narg(price)::numeric,
+    sqlc.narg(category_id)::bigint,
+    coalesce(sqlc.narg(stock_quantity)::int, 0),
+    coalesce(sqlc.narg(tax_class)::varchar, 'standard'),
+    coalesce(sqlc.narg(weight_grams)::int, 0),
+    NOW()
+)
+ON CONFLICT (sku) DO UPDATE SET
+    name = coalesce(sqlc.narg(name)::varchar, products.name),
+    description = coalesce(sqlc.narg(description)::text, products.description),
+    price = coalesce(sqlc.narg(price)::numeric, products.price),
+    category_id = coalesce(sqlc.narg(category_id)::bigint, products.category_id),
+    stock_quantity = coalesce(sqlc.narg(stock_quantity)::int, products.stock_quantity),
+    tax_class = coalesce(sqlc.narg(tax_class)::varchar, products.tax_class),
+    weight_grams = coalesce(sqlc.narg(weight_grams)::int, products.weight_grams),
+    deleted_at = CASE WHEN sqlc.arg(restore)::boolean THEN NULL ELSE products.deleted_at END
+RETURNING *, (xmax = 0)::boolean AS inserted;
+
+-- name: ListProductsForExport :many
+SELECT p.*, c.slug AS category
+FROM products p
+JOIN categories c ON c.id = p.category_id
+WHERE p.id > sqlc.arg(after_id)::bigint AND p.deleted_at IS NULL
+ORDER BY p.id ASC
+LIMIT sqlc.arg(page_limit)::int;
+
+-- name: RestockProduct :one
+UPDATE products
+SET stock_quantity = stock_quantity + sqlc.arg(quantity)::int
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListProductsByIDs :many
+-- Deleted products are included so that the orders they are on can still show them
+SELECT * FROM products WHERE id = ANY(sqlc.arg(ids)::bigint[]) ORDER BY id ASC;
@@ -0,0 +1,25 @@
+-- name: GetProductImage :one
+SELECT * FROM product_images WHERE id = $1 AND product_id = $2 LIMIT 1;
+
+-- name: ListProductImagesByProduct :many
+SELECT * FROM product_images WHERE product_id = $1 ORDER BY is_primary DESC, position ASC, id ASC;
+
+-- name: CountProductImagesByProduct :one
+SELECT count(*) FROM product_images WHERE product_id = $1;
+
+-- name: CreateProductImage :one
+INSERT INTO product_images (product_id, storage_key, url, thumbnails, content_type, width, height, size_bytes, position, is_primary) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
+RETURNING *;
+
+-- name: UpdateProductImagePosition :one
+UPDATE product_images SET position = $3 WHERE id = $1 AND product_id = $2 RETURNING *;
+
+-- name: ClearPrimaryProductImage :exec
+UPDATE product_images SET is_primary = false WHERE product_id = $1 AND is_primary;
+
+-- name: SetPrimaryProductImage :one
+UPDATE product_images SET is_primary = true WHERE id = $1 AND product_id = $2 RETURNING *;
+
+-- name: DeleteProductImage :exec
+DELETE FROM product_images WHERE id = $1 AND product_id = $2;
@@ -0,0 +1,23 @@
+-- name: GetProductImportJob :one
+SELECT * FROM product_import_jobs WHERE id = $1 LIMIT 1;
+
+-- name: CreateProductImportJob :one
+INSERT INTO product_import_jobs (format, dry_run)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: StartProductImportJob :exec
+UPDATE product_import_jobs SET status = 'running', started_at = NOW() WHERE id = $1;
+
+-- name: FinishProductImportJob :one
+UPDATE product_import_jobs SET
+    status = $2,
+    total_rows = $3,
+    created_rows = $4,
+    updated_rows = $5,
+    failed_rows = $6,
+    errors = $7,
+    last_error = $8,
+    finished_at = NOW()
+WHERE id = $1
+RETURNING *;
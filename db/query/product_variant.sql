@@ -0,0 +1,41 @@
+-- name: GetProductVariant :one
+SELECT * FROM product_variants WHERE id = $1 LIMIT 1;
+
+-- name: GetProductVariantBySku :one
+SELECT * FROM product_variants WHERE sku = $1 LIMIT 1;
+
+-- name: ListProductVariantsByProduct :many
+SELECT * FROM product_variants WHERE product_id = $1 ORDER BY position ASC, id ASC;
+
+-- name: CountProductVariantsByProduct :one
+SELECT count(*) FROM product_variants WHERE product_id = $1;
+
+-- name: CreateProductVariant :one
+INSERT INTO product_variants (product_id, sku, attributes, price, stock_quantity, position) 
+VALUES ($1, $2, $3, $4, $5, $6) 
+RETURNING *;
+
+-- name: UpdateProductVariant :one
+UPDATE product_variants SET 
+    sku = $3,
+    attributes = $4,
+    price = $5,
+    stock_quantity = $6,
+    position = $7
+WHERE id = $1 AND product_id = $2 
+RETURNING *;
+
+-- name: DeleteProductVariant :exec
+DELETE FROM product_variants WHERE id = $1 AND product_id = $2;
+
+-- name: ReserveProductVariantStock :one
+UPDATE product_variants
+SET stock_quantity = stock_quantity - sqlc.arg(quantity)::int
+WHERE id = sqlc.arg(id) AND stock_quantity >= sqlc.arg(quantity)::int
+RETURNING *;
+
+-- name: RestockProductVariant :one
+UPDATE product_variants
+SET stock_quantity = stock_quantity + sqlc.arg(quantity)::int
+WHERE id = sqlc.arg(id)
+RETURNING *;
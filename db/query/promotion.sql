@@ -0,0 +1,73 @@
+-- name: GetPromotion :one
+SELECT * FROM promotions WHERE id = $1 LIMIT 1;
+
+-- name: GetPromotionByCode :one
+SELECT * FROM promotions WHERE code = $1 LIMIT 1;
+
+-- name: ListPromotions :many
+SELECT * FROM promotions ORDER BY id ASC;
+
+-- name: ListActiveAutomaticPromotions :many
+SELECT * FROM promotions
+WHERE code IS NULL
+    AND active
+    AND (starts_at IS NULL OR starts_at <= sqlc.arg(now)::timestamp)
+    AND (ends_at IS NULL OR ends_at > sqlc.arg(now)::timestamp)
+ORDER BY id ASC;
+
+-- name: CreatePromotion :one
+INSERT INTO promotions (
+    name, code, type, value, buy_quantity, get_quantity, product_id, category_id,
+    min_order_amount, starts_at, ends_at, usage_limit, per_user_limit, active
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING *;
+
+-- name: UpdatePromotion :one
+UPDATE promotions SET
+    name = $2,
+    code = $3,
+    type = $4,
+    value = $5,
+    buy_quantity = $6,
+    get_quantity = $7,
+    product_id = $8,
+    category_id = $9,
+    min_order_amount = $10,
+    starts_at = $11,
+    ends_at = $12,
+    usage_limit = $13,
+    per_user_limit = $14,
+    active = $15,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePromotion :exec
+DELETE FROM promotions WHERE id = $1;
+
+-- name: RedeemPromotion :one
+UPDATE promotions SET times_used = times_used + 1
+WHERE id = $1 AND (usage_limit IS NULL OR times_used < usage_limit)
+RETURNING *;
+
+-- name: CreatePromotionRedemption :exec
+INSERT INTO promotion_redemptions (promotion_id, order_id, user_id)
+VALUES ($1, $2, $3);
+
+-- name: CountPromotionRedemptionsByUser :one
+SELECT count(*) FROM promotion_redemptions WHERE promotion_id = $1 AND user_id = $2;
+
+-- name: ReleaseOrderPromotions :exec
+-- Gives back the uses an order took from its promotions, before the order is priced again
+UPDATE promotions p SET times_used = GREATEST(p.times_used - r.uses, 0)
+FROM (
+    SELECT promotion_id, count(*)::int AS uses
+    FROM promotion_redemptions
+    WHERE order_id = $1
+    GROUP BY promotion_id
+) r
+WHERE p.id = r.promotion_id;
+
+-- name: DeletePromotionRedemptionsByOrder :exec
+DELETE FROM promotion_redemptions WHERE order_id = $1;
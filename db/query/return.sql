@@ -0,0 +1,73 @@
+-- name: GetReturn :one
+SELECT * FROM returns WHERE id = $1 LIMIT 1;
+
+-- name: GetReturnForUpdate :one
+SELECT * FROM returns WHERE id = $1 LIMIT 1 FOR UPDATE;
+
+-- name: ListReturns :many
+SELECT * FROM returns ORDER BY created_at DESC, id DESC;
+
+-- name: ListReturnsByStatus :many
+SELECT * FROM returns WHERE status = $1 ORDER BY created_at ASC, id ASC;
+
+-- name: ListReturnsByOrder :many
+SELECT * FROM returns WHERE order_id = $1 ORDER BY created_at ASC, id ASC;
+
+-- name: CreateReturn :one
+INSERT INTO returns (order_id, reason, refund_amount)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: UpdateReturn :one
+UPDATE returns SET
+    status = $2,
+    note = $3,
+    payment_id = $4,
+    received_at = $5,
+    refunded_at = $6,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ClaimReturnRefund :one
+-- Only one request can move a received return to refunding, so only that request refunds it
+UPDATE returns SET
+    status = 'refunding',
+    payment_id = $2,
+    updated_at = NOW()
+WHERE id = $1 AND status = 'received'
+RETURNING *;
+
+-- name: CompleteReturnRefund :one
+UPDATE returns SET
+    status = 'refunded',
+    refunded_at = $2,
+    updated_at = NOW()
+WHERE id = $1 AND status = 'refunding'
+RETURNING *;
+
+-- name: ReleaseReturnRefund :one
+-- Puts a return back to received when its refund was not made, so that it can be retried
+UPDATE returns SET
+    status = 'received',
+    updated_at = NOW()
+WHERE id = $1 AND status = 'refunding'
+RETURNING *;
+
+-- name: DeleteReturn :one
+DELETE FROM returns WHERE id = $1 RETURNING *;
+
+-- name: CreateReturnItem :one
+INSERT INTO return_items (return_id, order_item_id, quantity, amount)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListReturnItems :many
+SELECT * FROM return_items WHERE return_id = $1 ORDER BY id ASC;
+
+-- name: SumReturnedQuantity :one
+-- Units of an order item already in a return that was not rejected
+SELECT COALESCE(SUM(ri.quantity), 0)::int AS quantity
+FROM return_items ri
+JOIN returns r ON r.id = ri.return_id
+WHERE ri.order_item_id = $1 AND r.status <> 'rejected';
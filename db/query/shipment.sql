@@ -0,0 +1,27 @@
+-- name: GetShipment :one
+SELECT * FROM shipments WHERE id = $1 LIMIT 1;
+
+-- name: ListShipmentsByOrder :many
+SELECT * FROM shipments WHERE order_id = $1 ORDER BY id ASC;
+
+-- name: CreateShipment :one
+INSERT INTO shipments (order_id, shipping_method_id, carrier, tracking_number)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UpdateShipment :one
+UPDATE shipments SET
+    carrier = $2,
+    tracking_number = $3,
+    status = $4,
+    shipped_at = $5,
+    delivered_at = $6,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteShipment :exec
+DELETE FROM shipments WHERE id = $1;
+
+-- name: CountUndeliveredShipments :one
+SELECT count(*) FROM shipments WHERE order_id = $1 AND status <> 'delivered';
@@ -0,0 +1,42 @@
+-- name: GetShippingMethod :one
+SELECT * FROM shipping_methods WHERE id = $1 LIMIT 1;
+
+-- name: ListShippingMethods :many
+SELECT * FROM shipping_methods ORDER BY id ASC;
+
+-- name: CreateShippingMethod :one
+INSERT INTO shipping_methods (name, carrier, rate_type, price, free_over, active)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: UpdateShippingMethod :one
+UPDATE shipping_methods SET
+    name = $2,
+    carrier = $3,
+    rate_type = $4,
+    price = $5,
+    free_over = $6,
+    active = $7,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteShippingMethod :exec
+DELETE FROM shipping_methods WHERE id = $1;
+
+-- name: ListShippingRatesByMethod :many
+SELECT * FROM shipping_rates WHERE method_id = $1 ORDER BY max_weight_grams ASC;
+
+-- name: CreateShippingRate :one
+INSERT INTO shipping_rates (method_id, max_weight_grams, price)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: DeleteShippingRatesByMethod :exec
+DELETE FROM shipping_rates WHERE method_id = $1;
+
+-- name: GetShippingRateForWeight :one
+SELECT * FROM shipping_rates
+WHERE method_id = sqlc.arg(method_id) AND max_weight_grams >= sqlc.arg(weight_grams)::int
+ORDER BY max_weight_grams ASC
+LIMIT 1;
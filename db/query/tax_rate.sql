@@ -0,0 +1,25 @@
+-- name: GetTaxRate :one
+SELECT * FROM tax_rates WHERE id = $1 LIMIT 1;
+
+-- name: GetTaxRateByClass :one
+SELECT * FROM tax_rates WHERE tax_class = $1 AND country = $2 LIMIT 1;
+
+-- name: ListTaxRates :many
+SELECT * FROM tax_rates ORDER BY country ASC, tax_class ASC;
+
+-- name: CreateTaxRate :one
+INSERT INTO tax_rates (tax_class, country, rate, name)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UpdateTaxRate :one
+UPDATE tax_rates SET
+    tax_class = $2,
+    country = $3,
+    rate = $4,
+    name = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteTaxRate :exec
+DELETE FROM tax_rates WHERE id = $1;
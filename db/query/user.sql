@@ -2,11 +2,11 @@
 SELECT * FROM users WHERE id = $1 LIMIT 1;
 
 -- name: ListUsers :many
-SELECT * FROM users ORDER BY registration_date ASC;
+SELECT * FROM users WHERE deleted_at IS NULL ORDER BY registration_date ASC;
 
 -- name: CreateUser :one
-INSERT INTO users (full_name, email, address, registration_date, role) 
-VALUES ($1, $2, $3, NOW(), $4) 
+INSERT INTO users (full_name, email, address, registration_date, role, password_hash, locale) 
+VALUES ($1, $2, $3, NOW(), $4, $5, $6) 
 RETURNING *;
 
 -- name: UpdateUser :one
@@ -14,15 +14,45 @@ UPDATE users SET
     full_name = $2,
     email = $3,
     address = $4,
-    role = $5
-WHERE id = $1 
+    role = $5,
+    email_verified_at = CASE WHEN email = $3 THEN email_verified_at ELSE NULL END
+WHERE id = $1 AND version = $6
 RETURNING *;
 
--- name: DeleteUser :exec
-DELETE FROM users WHERE id = $1;
+-- name: DeleteUser :one
+UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING *;
+
+-- name: RestoreUser :one
+UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING *;
+
+-- name: PurgeDeletedUsers :execrows
+-- Users who still have orders or payments are kept; their API keys and notifications go with them
+WITH expired AS (
+    SELECT u.id FROM users u
+    WHERE u.deleted_at < $1
+        AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id)
+        AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.user_id = u.id)
+), api_keys_deleted AS (
+    DELETE FROM api_keys WHERE user_id IN (SELECT id FROM expired)
+), notifications_deleted AS (
+    DELETE FROM notifications WHERE user_id IN (SELECT id FROM expired)
+)
+DELETE FROM users WHERE id IN (SELECT id FROM expired);
 
 -- name: SearchUsersByName :many
-SELECT * FROM users WHERE full_name ILIKE '%' || $1 || '%' ORDER BY registration_date ASC;
+SELECT * FROM users WHERE full_name ILIKE '%' || $1 || '%' AND deleted_at IS NULL ORDER BY registration_date ASC;
 
 -- name: SearchUsersByEmail :many
-SELECT * FROM users WHERE email = $1 ORDER BY registration_date ASC;
+SELECT * FROM users WHERE email = $1 AND deleted_at IS NULL ORDER BY registration_date ASC;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users WHERE email = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2 WHERE id = $1;
+
+-- name: VerifyUserEmail :one
+UPDATE users SET email_verified_at = NOW() WHERE id = $1 RETURNING *;
+
+-- name: ListUsersByIDs :many
+SELECT * FROM users WHERE id = ANY(sqlc.arg(ids)::bigint[]) ORDER BY id ASC;
@@ -0,0 +1,52 @@
+-- name: GetUserAddress :one
+SELECT * FROM user_addresses WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: GetDefaultUserAddress :one
+SELECT * FROM user_addresses WHERE user_id = $1 AND type = $2 AND is_default LIMIT 1;
+
+-- name: ListUserAddresses :many
+SELECT * FROM user_addresses WHERE user_id = $1 ORDER BY type ASC, is_default DESC, id ASC;
+
+-- name: CreateUserAddress :one
+INSERT INTO user_addresses (user_id, type, full_name, phone, line1, line2, city, region, postal_code, country)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: UpdateUserAddress :one
+-- An address moved to another type stops being the default of its old type
+UPDATE user_addresses SET
+    is_default = is_default AND type = sqlc.arg(type),
+    type = sqlc.arg(type),
+    full_name = sqlc.arg(full_name),
+    phone = sqlc.arg(phone),
+    line1 = sqlc.arg(line1),
+    line2 = sqlc.arg(line2),
+    city = sqlc.arg(city),
+    region = sqlc.arg(region),
+    postal_code = sqlc.arg(postal_code),
+    country = sqlc.arg(country),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: ClearDefaultUserAddress :exec
+UPDATE user_addresses SET is_default = false WHERE user_id = $1 AND type = $2 AND is_default;
+
+-- name: SetDefaultUserAddress :one
+UPDATE user_addresses SET is_default = true WHERE id = $1 RETURNING *;
+
+-- name: EnsureDefaultUserAddress :exec
+-- Makes the oldest address of a type the default when the type has none
+UPDATE user_addresses SET is_default = true
+WHERE id = (
+    SELECT a.id FROM user_addresses a
+    WHERE a.user_id = $1 AND a.type = $2
+    ORDER BY a.id ASC
+    LIMIT 1
+) AND NOT EXISTS (
+    SELECT 1 FROM user_addresses d
+    WHERE d.user_id = $1 AND d.type = $2 AND d.is_default
+);
+
+-- name: DeleteUserAddress :one
+DELETE FROM user_addresses WHERE id = $1 AND user_id = $2 RETURNING *;
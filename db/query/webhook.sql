@@ -0,0 +1,84 @@
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions ORDER BY created_at ASC;
+
+-- name: ListActiveWebhookSubscriptionsByEvent :many
+SELECT * FROM webhook_subscriptions 
+WHERE active = true AND sqlc.arg(event_type)::text = ANY(event_types) 
+ORDER BY id ASC;
+
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (url, event_types, secret, active) 
+VALUES ($1, $2, $3, $4) 
+RETURNING *;
+
+-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions SET 
+    url = $2,
+    event_types = $3,
+    active = $4,
+    updated_at = NOW()
+WHERE id = $1 
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscriptions WHERE id = $1;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveriesBySubscription :many
+SELECT * FROM webhook_deliveries WHERE subscription_id = $1 ORDER BY created_at DESC;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (subscription_id, event_type, payload, next_attempt_at) 
+VALUES ($1, $2, $3, $4) 
+RETURNING *;
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Claims due deliveries by moving next_attempt_at to the end of a lease, so that other workers skip them;
+-- a worker that stops before recording the outcome leaves them to be retried once the lease runs out
+UPDATE webhook_deliveries SET 
+    next_attempt_at = $1
+WHERE id IN (
+    SELECT id FROM webhook_deliveries 
+    WHERE status = 'pending' AND next_attempt_at <= NOW() 
+    ORDER BY next_attempt_at ASC 
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE webhook_deliveries SET 
+    status = 'succeeded',
+    attempts = attempts + 1,
+    response_status = $2,
+    response_body = $3,
+    last_error = NULL,
+    delivered_at = NOW()
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries SET 
+    status = $2,
+    attempts = attempts + 1,
+    response_status = $3,
+    response_body = $4,
+    last_error = $5,
+    next_attempt_at = $6
+WHERE id = $1;
+
+-- name: RequeueWebhookDelivery :one
+-- The delivery starts over with a full set of attempts
+UPDATE webhook_deliveries SET 
+    status = 'pending',
+    attempts = 0,
+    response_status = NULL,
+    response_body = NULL,
+    last_error = NULL,
+    next_attempt_at = NOW()
+WHERE id = $1 AND status = 'failed' 
+RETURNING *;
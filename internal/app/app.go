@@ -5,12 +5,33 @@ import (
 	"ecommerce_management/internal/config"
 	"ecommerce_management/internal/database"
 	"ecommerce_management/internal/handlers"
+	"ecommerce_management/internal/provider/blob"
+	"ecommerce_management/internal/provider/cache"
 	"ecommerce_management/internal/provider/epay"
+	"ecommerce_management/internal/provider/mail"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/internal/service/catalog"
+	"ecommerce_management/internal/service/idempotency"
 	"ecommerce_management/internal/service/kafka"
+	"ecommerce_management/internal/service/media"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/order"
+	"ecommerce_management/internal/service/payment"
+	"ecommerce_management/internal/service/promotion"
+	"ecommerce_management/internal/service/retention"
+	"ecommerce_management/internal/service/returns"
+	"ecommerce_management/internal/service/shipping"
+	"ecommerce_management/internal/service/tax"
+	"ecommerce_management/internal/service/user"
+	"ecommerce_management/internal/service/webhook"
 	"ecommerce_management/pkg/log"
 	"ecommerce_management/pkg/server"
+	"ecommerce_management/pkg/store"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -29,8 +50,39 @@ func Run() {
 		return
 	}
 
-	// Call InitDB multiple times to test the singleton pattern
-	database.InitDB()
+	// Initialize the repository, keeping data in memory when DB_DRIVER is memory
+	var repoConfigs []repository.Configuration
+	if configs.DBDriver == repository.DriverMemory {
+		repoConfigs = append(repoConfigs, repository.WithMemoryStore())
+	} else {
+		database.InitDB()
+		repoConfigs = append(repoConfigs, repository.WithPostgresStore(database.DB))
+	}
+
+	// Cache products and categories in Redis when REDIS_URL is set; queries fall back to the store while it is unavailable
+	if configs.RedisURL != "" {
+		redis, err := store.NewRedis(configs.RedisURL)
+		if err != nil {
+			logger.Error("ERR_INIT_REDIS", zap.Error(err))
+			return
+		}
+		defer redis.Connection.Close()
+
+		if err = redis.Connection.Ping(context.Background()).Err(); err != nil {
+			logger.Warn("redis is unavailable, reading from the store until it is back", zap.Error(err))
+		}
+		repoConfigs = append(repoConfigs, repository.WithCache(cache.NewRedis(redis.Connection), repository.CacheTTL{
+			Products:   configs.CacheProductTTL,
+			Categories: configs.CacheCategoryTTL,
+		}))
+	}
+
+	repo, err := repository.New(repoConfigs...)
+	if err != nil {
+		logger.Error("ERR_INIT_REPOSITORY", zap.Error(err))
+		return
+	}
+	defer repo.Close()
 
 	// Initialize the ePay client
 	epayClient, err := epay.New(epay.Credentials{
@@ -55,21 +107,252 @@ func Run() {
 		SchemaURL:     configs.SchemaURL,
 	})
 
+	// Initialize the mailer, capturing messages locally when no SMTP server is configured
+	var mailer mail.Sender = mail.NewCapture()
+	if configs.SMTPServer != "" {
+		mailer, err = mail.New(mail.Credentials{
+			Host:     configs.SMTPServer,
+			Port:     configs.SMTPPort,
+			Username: configs.SMTPUsername,
+			Password: configs.SMTPPassword,
+		})
+		if err != nil {
+			logger.Error("ERR_INIT_MAILER", zap.Error(err))
+			return
+		}
+	}
+
+	// Initialize the audit service recording changes made through the API
+	auditService, err := audit.New(
+		audit.WithRepository(repo))
+	if err != nil {
+		logger.Error("ERR_INIT_AUDIT_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the notification service and its retry worker
+	notificationService, err := notification.New(
+		notification.WithRepository(repo),
+		notification.WithMailer(mailer))
+	if err != nil {
+		logger.Error("ERR_INIT_NOTIFICATION_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the webhook service delivering events to subscribed endpoints
+	webhookService, err := webhook.New(
+		webhook.WithRepository(repo))
+	if err != nil {
+		logger.Error("ERR_INIT_WEBHOOK_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the promotion service pricing orders with coupons and automatic discounts
+	promotionService, err := promotion.New(
+		promotion.WithRepository(repo))
+	if err != nil {
+		logger.Error("ERR_INIT_PROMOTION_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the tax service computing per-line tax from the configured rates
+	taxService, err := tax.New(
+		tax.WithRepository(repo),
+		tax.WithPricesIncludeTax(configs.PricesIncludeTax),
+		tax.WithDefaultCountry(configs.TaxDefaultCountry))
+	if err != nil {
+		logger.Error("ERR_INIT_TAX_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the shipping service pricing shipping methods and tracking shipments
+	shippingService, err := shipping.New(
+		shipping.WithRepository(repo),
+		shipping.WithWebhooks(webhookService),
+		shipping.WithNotifications(notificationService))
+	if err != nil {
+		logger.Error("ERR_INIT_SHIPPING_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the returns service refunding returned items through ePay
+	returnsService, err := returns.New(
+		returns.WithRepository(repo),
+		returns.WithRefunder(epayClient),
+		returns.WithAudit(auditService),
+		returns.WithWebhooks(webhookService),
+		returns.WithNotifications(notificationService))
+	if err != nil {
+		logger.Error("ERR_INIT_RETURNS_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the blob store for uploaded media, keeping files on local disk unless S3 is configured
+	var (
+		mediaStore blob.Store
+		mediaFiles http.Handler
+	)
+	if configs.MediaStorage == "s3" {
+		mediaStore, err = blob.NewS3(blob.Credentials{
+			Endpoint:  configs.S3Endpoint,
+			Region:    configs.S3Region,
+			Bucket:    configs.S3Bucket,
+			AccessKey: configs.S3AccessKey,
+			SecretKey: configs.S3SecretKey,
+			PublicURL: configs.S3PublicURL,
+		})
+	} else {
+		if configs.MediaDir == "" {
+			configs.MediaDir = "uploads"
+		}
+		if configs.MediaURL == "" {
+			configs.MediaURL = configs.AppURL + "/media"
+		}
+
+		var local *blob.Local
+		if local, err = blob.NewLocal(configs.MediaDir, configs.MediaURL); err == nil {
+			mediaStore, mediaFiles = local, local.Handler()
+		}
+	}
+	if err != nil {
+		logger.Error("ERR_INIT_MEDIA_STORE", zap.Error(err))
+		return
+	}
+
+	// Initialize the media service generating thumbnails for uploaded images
+	mediaService, err := media.New(
+		media.WithStore(mediaStore))
+	if err != nil {
+		logger.Error("ERR_INIT_MEDIA_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the catalog service importing and exporting products in bulk
+	catalogService, err := catalog.New(
+		catalog.WithRepository(repo),
+		catalog.WithImages(mediaService),
+		catalog.WithWebhooks(webhookService),
+		catalog.WithAudit(auditService))
+	if err != nil {
+		logger.Error("ERR_INIT_CATALOG_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the order service checking out, pricing and tracking orders
+	orderService, err := order.New(
+		order.WithRepository(repo),
+		order.WithPricing(promotionService, taxService, shippingService),
+		order.WithAudit(auditService),
+		order.WithWebhooks(webhookService),
+		order.WithNotifications(notificationService))
+	if err != nil {
+		logger.Error("ERR_INIT_ORDER_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the payment service charging orders through ePay
+	paymentService, err := payment.New(
+		payment.WithRepository(repo),
+		payment.WithGateway(epayClient, configs.TerminalID),
+		payment.WithAudit(auditService),
+		payment.WithWebhooks(webhookService),
+		payment.WithNotifications(notificationService))
+	if err != nil {
+		logger.Error("ERR_INIT_PAYMENT_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the auth service accepting both bearer tokens and API keys
+	authService, err := auth.New(
+		auth.WithAPIKeyRepository(repo),
+		auth.WithUserRepository(repo),
+		auth.WithBearerAuthentication(configs.TokenSymmetricKey),
+		auth.WithTokenSecret(configs.TokenSymmetricKey))
+	if err != nil {
+		logger.Error("ERR_INIT_AUTH_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the user service registering users and sending their account emails
+	userService, err := user.New(
+		user.WithRepository(repo),
+		user.WithTokens(authService),
+		user.WithMailer(mailer),
+		user.WithProducer(kafkaService),
+		user.WithAudit(auditService),
+		user.WithAppURL(configs.AppURL))
+	if err != nil {
+		logger.Error("ERR_INIT_USER_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the idempotency service replaying responses of retried requests
+	idempotencyService, err := idempotency.New(
+		idempotency.WithRepository(repo),
+		idempotency.WithTTL(configs.IdempotencyKeyTTL))
+	if err != nil {
+		logger.Error("ERR_INIT_IDEMPOTENCY_SERVICE", zap.Error(err))
+		return
+	}
+
+	// Initialize the retention service purging records deleted longer ago than the retention period
+	retentionService, err := retention.New(
+		retention.WithRepository(repo),
+		retention.WithImageRemover(mediaService),
+		retention.WithRetention(configs.DeleteRetention))
+	if err != nil {
+		logger.Error("ERR_INIT_RETENTION_SERVICE", zap.Error(err))
+		return
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	notificationService.Start(workerCtx)
+	webhookService.Start(workerCtx)
+	idempotencyService.Start(workerCtx)
+	retentionService.Start(workerCtx)
+
+	// Serve the API over gRPC as well when GRPC_ADDRESS is set
+	handlerConfigs := []handlers.Configuration{handlers.WithHTTPHandler()}
+	if configs.GRPCAddress != "" {
+		handlerConfigs = append(handlerConfigs, handlers.WithGRPCHandler())
+	}
+
 	handlers, err := handlers.New(
 		handlers.Dependencies{
-			DB:           database.DB,
+			Repository:   repo,
 			Configs:      configs,
 			EpayClient:   epayClient,
 			KafkaService: kafkaService,
+			Mailer:       mailer,
+			Notification: notificationService,
+			Webhook:      webhookService,
+			Promotion:    promotionService,
+			Tax:          taxService,
+			Shipping:     shippingService,
+			Returns:      returnsService,
+			Catalog:      catalogService,
+			Orders:       orderService,
+			Payments:     paymentService,
+			Users:        userService,
+			Auth:         authService,
+			Media:        mediaService,
+			Idempotency:  idempotencyService,
+			Audit:        auditService,
+			MediaFiles:   mediaFiles,
 		},
-		handlers.WithHTTPHandler())
+		handlerConfigs...)
 	if err != nil {
 		logger.Error("ERR_INIT_HANDLERS", zap.Error(err))
 		return
 	}
 
-	servers, err := server.New(
-		server.WithHTTPServer(handlers.HTTP, configs.ServerAddress))
+	serverConfigs := []server.Configuration{server.WithHTTPServer(handlers.HTTP, configs.ServerAddress)}
+	if handlers.GRPC != nil {
+		serverConfigs = append(serverConfigs, server.WithGRPCServer(handlers.GRPC, configs.GRPCAddress))
+	}
+
+	servers, err := server.New(serverConfigs...)
 	if err != nil {
 		logger.Error("ERR_INIT_SERVERS", zap.Error(err))
 		return
@@ -81,6 +364,9 @@ func Run() {
 		return
 	}
 	logger.Info("http server started on http://localhost" + configs.ServerAddress + "/swagger/index.html")
+	if handlers.GRPC != nil {
+		logger.Info("grpc server started on localhost:" + configs.GRPCAddress)
+	}
 
 	// Graceful Shutdown
 	var wait time.Duration
@@ -107,6 +393,7 @@ func Run() {
 
 	fmt.Println("running cleanup tasks...")
 	// Your cleanup tasks go here
+	stopWorkers()
 
 	fmt.Println("server was successfully shutdown.")
 }
@@ -11,6 +11,7 @@ type Config struct {
 	DBDriver            string        `mapstructure:"DB_DRIVER"`
 	DBSource            string        `mapstructure:"DB_SOURCE"`
 	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
+	GRPCAddress         string        `mapstructure:"GRPC_ADDRESS"`
 	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	BaseURL             string        `mapstructure:"BASE_URL"`
@@ -30,7 +31,26 @@ type Config struct {
 	KafkaPassword       string        `mapstructure:"UPSTASH_KAFKA_REST_PASSWORD"`
 	SMTPServer          string        `mapstructure:"SMTP_SERVER"`
 	SMTPPort            int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername        string        `mapstructure:"EMAIL"`
+	SMTPPassword        string        `mapstructure:"EMAIL_PASSWORD"`
+	AppURL              string        `mapstructure:"APP_URL"`
 	SchemaURL           string        `mapstructure:"SCHEMA_URL"`
+	MediaStorage        string        `mapstructure:"MEDIA_STORAGE"`
+	MediaDir            string        `mapstructure:"MEDIA_DIR"`
+	MediaURL            string        `mapstructure:"MEDIA_URL"`
+	S3Endpoint          string        `mapstructure:"S3_ENDPOINT"`
+	S3Region            string        `mapstructure:"S3_REGION"`
+	S3Bucket            string        `mapstructure:"S3_BUCKET"`
+	S3AccessKey         string        `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey         string        `mapstructure:"S3_SECRET_KEY"`
+	S3PublicURL         string        `mapstructure:"S3_PUBLIC_URL"`
+	PricesIncludeTax    bool          `mapstructure:"PRICES_INCLUDE_TAX"`
+	TaxDefaultCountry   string        `mapstructure:"TAX_DEFAULT_COUNTRY"`
+	IdempotencyKeyTTL   time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	DeleteRetention     time.Duration `mapstructure:"DELETE_RETENTION"`
+	RedisURL            string        `mapstructure:"REDIS_URL"`
+	CacheProductTTL     time.Duration `mapstructure:"CACHE_PRODUCT_TTL"`
+	CacheCategoryTTL    time.Duration `mapstructure:"CACHE_CATEGORY_TTL"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -40,6 +60,14 @@ func LoadConfig(path string) (config Config, err error) {
 
 	viper.AutomaticEnv()
 
+	// Catalogue prices are gross unless configured otherwise
+	viper.SetDefault("PRICES_INCLUDE_TAX", true)
+	viper.SetDefault("TAX_DEFAULT_COUNTRY", "KZ")
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", "24h")
+	viper.SetDefault("DELETE_RETENTION", "720h")
+	viper.SetDefault("CACHE_PRODUCT_TTL", "5m")
+	viper.SetDefault("CACHE_CATEGORY_TTL", "1h")
+
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
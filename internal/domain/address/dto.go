@@ -0,0 +1,142 @@
+package address
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Request represents the request payload for creating or updating an address of a user.
+// The first address of a type becomes its default; IsDefault moves the default to this address.
+type Request struct {
+	Type       postgres.AddressType `json:"type"`
+	FullName   string               `json:"full_name"`
+	Phone      *string              `json:"phone"`
+	Line1      string               `json:"line1"`
+	Line2      *string              `json:"line2"`
+	City       string               `json:"city"`
+	Region     *string              `json:"region"`
+	PostalCode *string              `json:"postal_code"`
+	Country    string               `json:"country"`
+	IsDefault  bool                 `json:"is_default"`
+}
+
+// Address is the public representation of a stored address of a user
+type Address struct {
+	ID         int64                `json:"id"`
+	UserID     int64                `json:"user_id"`
+	Type       postgres.AddressType `json:"type"`
+	FullName   string               `json:"full_name"`
+	Phone      *string              `json:"phone"`
+	Line1      string               `json:"line1"`
+	Line2      *string              `json:"line2"`
+	City       string               `json:"city"`
+	Region     *string              `json:"region"`
+	PostalCode *string              `json:"postal_code"`
+	Country    string               `json:"country"`
+	IsDefault  bool                 `json:"is_default"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+// ParseFrom converts a stored address into its public representation
+func ParseFrom(src postgres.UserAddress) (dst Address) {
+	dst = Address{
+		ID:         src.ID,
+		UserID:     src.UserID,
+		Type:       src.Type,
+		FullName:   src.FullName,
+		Phone:      nullable(src.Phone),
+		Line1:      src.Line1,
+		Line2:      nullable(src.Line2),
+		City:       src.City,
+		Region:     nullable(src.Region),
+		PostalCode: nullable(src.PostalCode),
+		Country:    src.Country,
+		IsDefault:  src.IsDefault,
+		CreatedAt:  src.CreatedAt,
+		UpdatedAt:  src.UpdatedAt,
+	}
+
+	return
+}
+
+// ParseFromList converts a list of stored addresses into their public representation
+func ParseFromList(src []postgres.UserAddress) (dst []Address) {
+	dst = make([]Address, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseFrom(data))
+	}
+
+	return
+}
+
+// Snapshot is the copy of an address an order was placed with; it does not change with the user's addresses
+type Snapshot struct {
+	Type       postgres.AddressType `json:"type"`
+	FullName   string               `json:"full_name"`
+	Phone      *string              `json:"phone"`
+	Line1      string               `json:"line1"`
+	Line2      *string              `json:"line2"`
+	City       string               `json:"city"`
+	Region     *string              `json:"region"`
+	PostalCode *string              `json:"postal_code"`
+	Country    string               `json:"country"`
+}
+
+// String formats the snapshot on one line, such as for the customer address of an invoice
+func (s Snapshot) String() string {
+	parts := []string{s.Line1}
+	if s.Line2 != nil && *s.Line2 != "" {
+		parts = append(parts, *s.Line2)
+	}
+	if s.City != "" {
+		parts = append(parts, s.City)
+	}
+	if s.Region != nil && *s.Region != "" {
+		parts = append(parts, *s.Region)
+	}
+	if s.PostalCode != nil && *s.PostalCode != "" {
+		parts = append(parts, *s.PostalCode)
+	}
+	parts = append(parts, s.Country)
+
+	return strings.Join(parts, ", ")
+}
+
+// ParseSnapshot converts a stored order address into its public representation
+func ParseSnapshot(src postgres.OrderAddress) (dst Snapshot) {
+	dst = Snapshot{
+		Type:       src.Type,
+		FullName:   src.FullName,
+		Phone:      nullable(src.Phone),
+		Line1:      src.Line1,
+		Line2:      nullable(src.Line2),
+		City:       src.City,
+		Region:     nullable(src.Region),
+		PostalCode: nullable(src.PostalCode),
+		Country:    src.Country,
+	}
+
+	return
+}
+
+// ParseSnapshots converts the stored addresses of an order into their public representation
+func ParseSnapshots(src []postgres.OrderAddress) (dst []Snapshot) {
+	dst = make([]Snapshot, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseSnapshot(data))
+	}
+
+	return
+}
+
+// nullable returns a pointer to the value of a nullable column, or nil when it is NULL
+func nullable(src sql.NullString) *string {
+	if !src.Valid {
+		return nil
+	}
+	return &src.String
+}
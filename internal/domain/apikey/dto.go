@@ -0,0 +1,61 @@
+package apikey
+
+import (
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// CreateAPIKeyRequest represents the request payload for issuing a new API key.
+type CreateAPIKeyRequest struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// APIKey is the public representation of a stored key; the secret hash is never exposed.
+type APIKey struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Permissions []string   `json:"permissions"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyResponse is returned once on creation and is the only time the key is shown.
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// ParseFrom converts a stored key into its public representation
+func ParseFrom(src postgres.ApiKey) (dst APIKey) {
+	dst = APIKey{
+		ID:          src.ID,
+		UserID:      src.UserID,
+		Name:        src.Name,
+		Prefix:      src.Prefix,
+		Permissions: src.Permissions,
+		CreatedAt:   src.CreatedAt,
+	}
+	if src.LastUsedAt.Valid {
+		dst.LastUsedAt = &src.LastUsedAt.Time
+	}
+	if src.RevokedAt.Valid {
+		dst.RevokedAt = &src.RevokedAt.Time
+	}
+
+	return
+}
+
+// ParseFromList converts a list of stored keys into their public representation
+func ParseFromList(src []postgres.ApiKey) (dst []APIKey) {
+	dst = make([]APIKey, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseFrom(data))
+	}
+
+	return
+}
@@ -0,0 +1,106 @@
+package category
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Request represents the request payload for creating or updating a category.
+// Slug is derived from the name when empty.
+type Request struct {
+	ParentID *int64 `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Position int32  `json:"position"`
+}
+
+// Category is the public representation of a stored category
+type Category struct {
+	ID        int64     `json:"id"`
+	ParentID  *int64    `json:"parent_id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Position  int32     `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Node is a category together with its subcategories
+type Node struct {
+	Category
+	Children []Node `json:"children"`
+}
+
+// ParseFrom converts a stored category into its public representation
+func ParseFrom(src postgres.Category) (dst Category) {
+	dst = Category{
+		ID:        src.ID,
+		Name:      src.Name,
+		Slug:      src.Slug,
+		Position:  src.Position,
+		CreatedAt: src.CreatedAt,
+	}
+	if src.ParentID.Valid {
+		dst.ParentID = &src.ParentID.Int64
+	}
+
+	return
+}
+
+// ParseFromList converts a list of stored categories into their public representation
+func ParseFromList(src []postgres.Category) (dst []Category) {
+	dst = make([]Category, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseFrom(data))
+	}
+
+	return
+}
+
+// BuildTree nests categories under their parents, keeping the order of src within each level
+func BuildTree(src []postgres.Category) []Node {
+	children := make(map[int64][]postgres.Category)
+	var roots []postgres.Category
+	for _, data := range src {
+		if data.ParentID.Valid {
+			children[data.ParentID.Int64] = append(children[data.ParentID.Int64], data)
+		} else {
+			roots = append(roots, data)
+		}
+	}
+
+	var build func(level []postgres.Category) []Node
+	build = func(level []postgres.Category) []Node {
+		nodes := make([]Node, 0, len(level))
+		for _, data := range level {
+			nodes = append(nodes, Node{
+				Category: ParseFrom(data),
+				Children: build(children[data.ID]),
+			})
+		}
+		return nodes
+	}
+
+	return build(roots)
+}
+
+// Slugify lowercases name and joins its letters and digits with dashes, keeping non-Latin letters
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return b.String()
+}
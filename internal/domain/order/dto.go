@@ -1,15 +1,168 @@
 package order
 
+import (
+	"time"
+
+	"ecommerce_management/internal/domain/address"
+	"ecommerce_management/internal/repository/postgres"
+)
+
 // CreateOrderRequest represents the request payload for creating a new order with items.
 type CreateOrderRequest struct {
-    UserID int64       `json:"user_id"`
-    Items  []OrderItem `json:"items"`
+	UserID int64       `json:"user_id"`
+	Items  []OrderItem `json:"items"`
+	// CouponCode is optional; automatic promotions apply without one
+	CouponCode string `json:"coupon_code,omitempty"`
+	// Country is the ISO 3166-1 alpha-2 destination that selects the tax rates; defaults to the country of the shipping address, then TAX_DEFAULT_COUNTRY
+	Country string `json:"country,omitempty"`
+	// ShippingMethodID is optional; orders without one are not shipped, such as store pickups
+	ShippingMethodID *int64 `json:"shipping_method_id,omitempty"`
+	// ShippingAddressID is one of the user's shipping addresses; defaults to the user's default shipping address
+	ShippingAddressID *int64 `json:"shipping_address_id,omitempty"`
+	// BillingAddressID is one of the user's addresses; defaults to the default billing address, then to the shipping address
+	BillingAddressID *int64 `json:"billing_address_id,omitempty"`
 }
 
 // OrderItem represents an item in the order.
 type OrderItem struct {
-	ProductID int64 `json:"product_id"` // The ID of the product being ordered
-	Quantity  int32 `json:"quantity"`   // The quantity of the product
+	ProductID int64  `json:"product_id"`           // The ID of the product being ordered
+	VariantID *int64 `json:"variant_id,omitempty"` // The variant being ordered; required when the product has variants
+	Quantity  int32  `json:"quantity"`             // The quantity of the product
+}
+
+// UpdateOrderItemRequest represents the request payload for changing the quantity of an order item
+type UpdateOrderItemRequest struct {
+	Quantity int32 `json:"quantity"`
+}
+
+// Adjustment is a line that changes the order total, such as a discount; Amount is negative for discounts.
+type Adjustment struct {
+	ID          int64     `json:"id"`
+	OrderID     int64     `json:"order_id"`
+	PromotionID *int64    `json:"promotion_id"`
+	Kind        string    `json:"kind"`
+	Code        *string   `json:"code"`
+	Description string    `json:"description"`
+	Amount      string    `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ParseAdjustments converts stored adjustments into their public representation
+func ParseAdjustments(src []postgres.OrderAdjustment) (dst []Adjustment) {
+	dst = make([]Adjustment, 0, len(src))
+	for _, data := range src {
+		adjustment := Adjustment{
+			ID:          data.ID,
+			OrderID:     data.OrderID,
+			Kind:        data.Kind,
+			Description: data.Description,
+			Amount:      data.Amount,
+			CreatedAt:   data.CreatedAt,
+		}
+		if data.PromotionID.Valid {
+			adjustment.PromotionID = &data.PromotionID.Int64
+		}
+		if data.Code.Valid {
+			adjustment.Code = &data.Code.String
+		}
+		dst = append(dst, adjustment)
+	}
+
+	return
 }
 
+// Invoice is an order as billed to the customer, with the tax of every line.
+// Line amounts include the line's share of the order discounts; Price is the amount before them.
+type Invoice struct {
+	OrderID          int64                `json:"order_id"`
+	OrderDate        time.Time            `json:"order_date"`
+	Status           postgres.OrderStatus `json:"status"`
+	Customer         InvoiceCustomer      `json:"customer"`
+	ShippingAddress  *address.Snapshot    `json:"shipping_address"`
+	BillingAddress   *address.Snapshot    `json:"billing_address"`
+	Country          string               `json:"country"`
+	PricesIncludeTax bool                 `json:"prices_include_tax"`
+	Lines            []InvoiceLine        `json:"lines"`
+	Adjustments      []Adjustment         `json:"adjustments"`
+	Taxes            []InvoiceTax         `json:"taxes"`
+	NetAmount        string               `json:"net_amount"`
+	TaxAmount        string               `json:"tax_amount"`
+	ShippingAmount   string               `json:"shipping_amount"`
+	TotalAmount      string               `json:"total_amount"`
+}
+
+// InvoiceCustomer is the buyer named on an invoice; Address is the billing address of the order,
+// or the user's address for orders placed before addresses were recorded
+type InvoiceCustomer struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Address string `json:"address"`
+}
+
+// InvoiceLine is an order item on an invoice
+type InvoiceLine struct {
+	ProductID   int64  `json:"product_id"`
+	VariantID   *int64 `json:"variant_id"`
+	Sku         string `json:"sku"`
+	Name        string `json:"name"`
+	Quantity    int32  `json:"quantity"`
+	Price       string `json:"price"`
+	TaxRate     string `json:"tax_rate"`
+	NetAmount   string `json:"net_amount"`
+	TaxAmount   string `json:"tax_amount"`
+	GrossAmount string `json:"gross_amount"`
+}
 
+// InvoiceTax totals the lines taxed at one rate
+type InvoiceTax struct {
+	Rate        string `json:"rate"`
+	NetAmount   string `json:"net_amount"`
+	TaxAmount   string `json:"tax_amount"`
+	GrossAmount string `json:"gross_amount"`
+}
+
+// ParseInvoice assembles the invoice of an order from its customer, addresses, lines, adjustments and per-rate totals
+func ParseInvoice(order postgres.Order, user postgres.User, addresses []postgres.OrderAddress, lines []InvoiceLine, adjustments []postgres.OrderAdjustment, taxes []postgres.SummarizeOrderItemTaxesRow) (dst Invoice) {
+	dst = Invoice{
+		OrderID:   order.ID,
+		OrderDate: order.OrderDate,
+		Status:    order.Status,
+		Customer: InvoiceCustomer{
+			ID:      user.ID,
+			Name:    user.FullName,
+			Email:   user.Email,
+			Address: user.Address,
+		},
+		Country:          order.DestinationCountry,
+		PricesIncludeTax: order.PricesIncludeTax,
+		Lines:            lines,
+		Adjustments:      ParseAdjustments(adjustments),
+		Taxes:            make([]InvoiceTax, 0, len(taxes)),
+		NetAmount:        order.NetAmount,
+		TaxAmount:        order.TaxAmount,
+		ShippingAmount:   order.ShippingAmount,
+		TotalAmount:      order.TotalAmount,
+	}
+	for _, data := range addresses {
+		snapshot := address.ParseSnapshot(data)
+		switch data.Type {
+		case postgres.AddressTypeShipping:
+			dst.ShippingAddress = &snapshot
+		case postgres.AddressTypeBilling:
+			dst.BillingAddress = &snapshot
+			dst.Customer.Name = snapshot.FullName
+			dst.Customer.Address = snapshot.String()
+		}
+	}
+	for _, data := range taxes {
+		dst.Taxes = append(dst.Taxes, InvoiceTax{
+			Rate:        data.TaxRate,
+			NetAmount:   data.NetAmount,
+			TaxAmount:   data.TaxAmount,
+			GrossAmount: data.GrossAmount,
+		})
+	}
+
+	return
+}
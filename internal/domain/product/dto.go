@@ -0,0 +1,262 @@
+package product
+
+import (
+	"encoding/json"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// PriceBuckets are the upper bounds of the price facet buckets
+var PriceBuckets = []string{"1000", "5000", "10000", "50000", "100000"}
+
+// SearchResponse represents ranked search results together with their facets.
+type SearchResponse struct {
+	Items  []postgres.SearchProductsRow `json:"items"`
+	Facets Facets                       `json:"facets"`
+}
+
+// Facets summarise how the matching products are distributed.
+type Facets struct {
+	Categories []CategoryFacet `json:"categories"`
+	Prices     []PriceFacet    `json:"prices"`
+}
+
+// CategoryFacet is the number of matching products in a category.
+type CategoryFacet struct {
+	CategoryID int64  `json:"category_id"`
+	Category   string `json:"category"`
+	Count      int64  `json:"count"`
+}
+
+// PriceFacet is the number of matching products priced in [Min, Max); an open end is omitted.
+type PriceFacet struct {
+	Min   string `json:"min,omitempty"`
+	Max   string `json:"max,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// ParseCategoryFacets converts facet rows into their public representation
+func ParseCategoryFacets(src []postgres.SearchProductCategoryFacetsRow) (dst []CategoryFacet) {
+	dst = make([]CategoryFacet, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, CategoryFacet{
+			CategoryID: data.CategoryID,
+			Category:   data.Category,
+			Count:      data.Count,
+		})
+	}
+
+	return
+}
+
+// ParsePriceFacets converts width_bucket rows into price ranges; bucket 0 is below the first bound
+func ParsePriceFacets(bounds []string, src []postgres.SearchProductPriceFacetsRow) (dst []PriceFacet) {
+	dst = make([]PriceFacet, 0, len(src))
+	for _, data := range src {
+		facet := PriceFacet{Count: data.Count}
+		if data.Bucket > 0 {
+			facet.Min = bounds[data.Bucket-1]
+		}
+		if int(data.Bucket) < len(bounds) {
+			facet.Max = bounds[data.Bucket]
+		}
+		dst = append(dst, facet)
+	}
+
+	return
+}
+
+// VariantRequest represents the request payload for creating or updating a product variant.
+// Price overrides the product price when set.
+type VariantRequest struct {
+	Sku           string            `json:"sku"`
+	Attributes    map[string]string `json:"attributes"`
+	Price         *string           `json:"price"`
+	StockQuantity int32             `json:"stock_quantity"`
+	Position      int32             `json:"position"`
+}
+
+// Variant is the public representation of a stored product variant
+type Variant struct {
+	ID            int64           `json:"id"`
+	ProductID     int64           `json:"product_id"`
+	Sku           string          `json:"sku"`
+	Attributes    json.RawMessage `json:"attributes"`
+	Price         *string         `json:"price"`
+	StockQuantity int32           `json:"stock_quantity"`
+	Position      int32           `json:"position"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// ParseVariant converts a stored variant into its public representation
+func ParseVariant(src postgres.ProductVariant) (dst Variant) {
+	dst = Variant{
+		ID:            src.ID,
+		ProductID:     src.ProductID,
+		Sku:           src.Sku,
+		Attributes:    src.Attributes,
+		StockQuantity: src.StockQuantity,
+		Position:      src.Position,
+		CreatedAt:     src.CreatedAt,
+	}
+	if src.Price.Valid {
+		dst.Price = &src.Price.String
+	}
+
+	return
+}
+
+// ParseVariants converts a list of stored variants into their public representation
+func ParseVariants(src []postgres.ProductVariant) (dst []Variant) {
+	dst = make([]Variant, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseVariant(data))
+	}
+
+	return
+}
+
+// ImageUpdateRequest represents the request payload for reordering an image or making it primary.
+type ImageUpdateRequest struct {
+	Position  *int32 `json:"position"`
+	IsPrimary *bool  `json:"is_primary"`
+}
+
+// Image is the public representation of a stored product image
+type Image struct {
+	ID          int64                     `json:"id"`
+	ProductID   int64                     `json:"product_id"`
+	URL         string                    `json:"url"`
+	Thumbnails  map[string]ImageThumbnail `json:"thumbnails"`
+	ContentType string                    `json:"content_type"`
+	Width       int32                     `json:"width"`
+	Height      int32                     `json:"height"`
+	SizeBytes   int64                     `json:"size_bytes"`
+	Position    int32                     `json:"position"`
+	IsPrimary   bool                      `json:"is_primary"`
+	CreatedAt   time.Time                 `json:"created_at"`
+}
+
+// ImageThumbnail is a resized copy of a product image
+type ImageThumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// ParseImage converts a stored image into its public representation
+func ParseImage(src postgres.ProductImage) (dst Image) {
+	dst = Image{
+		ID:          src.ID,
+		ProductID:   src.ProductID,
+		URL:         src.Url,
+		Thumbnails:  map[string]ImageThumbnail{},
+		ContentType: src.ContentType,
+		Width:       src.Width,
+		Height:      src.Height,
+		SizeBytes:   src.SizeBytes,
+		Position:    src.Position,
+		IsPrimary:   src.IsPrimary,
+		CreatedAt:   src.CreatedAt,
+	}
+	json.Unmarshal(src.Thumbnails, &dst.Thumbnails)
+
+	return
+}
+
+// ParseImages converts a list of stored images into their public representation
+func ParseImages(src []postgres.ProductImage) (dst []Image) {
+	dst = make([]Image, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseImage(data))
+	}
+
+	return
+}
+
+// ImportRow is one product of a catalogue import or export; rows are matched to products by SKU.
+// CategoryID takes precedence over the Category slug when both are set; an empty TaxClass means the standard one.
+// Restore brings back a deleted product and is never exported.
+type ImportRow struct {
+	Sku           string      `json:"sku"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	Price         json.Number `json:"price"`
+	StockQuantity int32       `json:"stock_quantity"`
+	CategoryID    int64       `json:"category_id,omitempty"`
+	Category      string      `json:"category,omitempty"`
+	TaxClass      string      `json:"tax_class,omitempty"`
+	WeightGrams   int32       `json:"weight_grams,omitempty"`
+	Restore       bool        `json:"restore,omitempty"`
+}
+
+// ImportReport summarises an import; Errors lists at most the first MaxReportedErrors failed rows.
+type ImportReport struct {
+	DryRun  bool       `json:"dry_run"`
+	Total   int        `json:"total"`
+	Created int        `json:"created"`
+	Updated int        `json:"updated"`
+	Failed  int        `json:"failed"`
+	Errors  []RowError `json:"errors"`
+}
+
+// MaxReportedErrors bounds the size of an import report
+const MaxReportedErrors = 1000
+
+// RowError explains why a row was rejected; Line is the line number in the uploaded file.
+type RowError struct {
+	Line  int    `json:"line"`
+	Sku   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
+
+// AddError records a rejected row
+func (r *ImportReport) AddError(line int, sku string, err error) {
+	r.Failed++
+	if len(r.Errors) < MaxReportedErrors {
+		r.Errors = append(r.Errors, RowError{Line: line, Sku: sku, Error: err.Error()})
+	}
+}
+
+// ImportJob is the public representation of an asynchronous import
+type ImportJob struct {
+	ID         int64                    `json:"id"`
+	Format     string                   `json:"format"`
+	Status     postgres.ImportJobStatus `json:"status"`
+	Report     ImportReport             `json:"report"`
+	LastError  *string                  `json:"last_error"`
+	CreatedAt  time.Time                `json:"created_at"`
+	StartedAt  *time.Time               `json:"started_at"`
+	FinishedAt *time.Time               `json:"finished_at"`
+}
+
+// ParseImportJob converts a stored import job into its public representation
+func ParseImportJob(src postgres.ProductImportJob) (dst ImportJob) {
+	dst = ImportJob{
+		ID:     src.ID,
+		Format: src.Format,
+		Status: src.Status,
+		Report: ImportReport{
+			DryRun:  src.DryRun,
+			Total:   int(src.TotalRows),
+			Created: int(src.CreatedRows),
+			Updated: int(src.UpdatedRows),
+			Failed:  int(src.FailedRows),
+			Errors:  []RowError{},
+		},
+		CreatedAt: src.CreatedAt,
+	}
+	json.Unmarshal(src.Errors, &dst.Report.Errors)
+	if src.LastError.Valid {
+		dst.LastError = &src.LastError.String
+	}
+	if src.StartedAt.Valid {
+		dst.StartedAt = &src.StartedAt.Time
+	}
+	if src.FinishedAt.Valid {
+		dst.FinishedAt = &src.FinishedAt.Time
+	}
+
+	return
+}
@@ -0,0 +1,103 @@
+package promotion
+
+import (
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Request represents the request payload for creating or updating a promotion.
+// A promotion with a code is a coupon; without one it applies automatically to every matching order.
+// Value is a percentage for percentage promotions and an amount for fixed ones.
+// Buy X get Y promotions give away the GetQuantity cheapest of every BuyQuantity+GetQuantity matching units.
+// ProductID or CategoryID limit the discount to matching items; a category includes its subcategories.
+type Request struct {
+	Name           string                 `json:"name"`
+	Code           *string                `json:"code"`
+	Type           postgres.PromotionType `json:"type"`
+	Value          string                 `json:"value"`
+	BuyQuantity    int32                  `json:"buy_quantity"`
+	GetQuantity    int32                  `json:"get_quantity"`
+	ProductID      *int64                 `json:"product_id"`
+	CategoryID     *int64                 `json:"category_id"`
+	MinOrderAmount *string                `json:"min_order_amount"`
+	StartsAt       *time.Time             `json:"starts_at"`
+	EndsAt         *time.Time             `json:"ends_at"`
+	UsageLimit     *int32                 `json:"usage_limit"`
+	PerUserLimit   *int32                 `json:"per_user_limit"`
+	Active         *bool                  `json:"active"`
+}
+
+// Promotion is the public representation of a stored promotion
+type Promotion struct {
+	ID             int64                  `json:"id"`
+	Name           string                 `json:"name"`
+	Code           *string                `json:"code"`
+	Type           postgres.PromotionType `json:"type"`
+	Value          string                 `json:"value"`
+	BuyQuantity    int32                  `json:"buy_quantity"`
+	GetQuantity    int32                  `json:"get_quantity"`
+	ProductID      *int64                 `json:"product_id"`
+	CategoryID     *int64                 `json:"category_id"`
+	MinOrderAmount *string                `json:"min_order_amount"`
+	StartsAt       *time.Time             `json:"starts_at"`
+	EndsAt         *time.Time             `json:"ends_at"`
+	UsageLimit     *int32                 `json:"usage_limit"`
+	PerUserLimit   *int32                 `json:"per_user_limit"`
+	TimesUsed      int32                  `json:"times_used"`
+	Active         bool                   `json:"active"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// ParseFrom converts a stored promotion into its public representation
+func ParseFrom(src postgres.Promotion) (dst Promotion) {
+	dst = Promotion{
+		ID:          src.ID,
+		Name:        src.Name,
+		Type:        src.Type,
+		Value:       src.Value,
+		BuyQuantity: src.BuyQuantity,
+		GetQuantity: src.GetQuantity,
+		TimesUsed:   src.TimesUsed,
+		Active:      src.Active,
+		CreatedAt:   src.CreatedAt,
+		UpdatedAt:   src.UpdatedAt,
+	}
+	if src.Code.Valid {
+		dst.Code = &src.Code.String
+	}
+	if src.ProductID.Valid {
+		dst.ProductID = &src.ProductID.Int64
+	}
+	if src.CategoryID.Valid {
+		dst.CategoryID = &src.CategoryID.Int64
+	}
+	if src.MinOrderAmount.Valid {
+		dst.MinOrderAmount = &src.MinOrderAmount.String
+	}
+	if src.StartsAt.Valid {
+		dst.StartsAt = &src.StartsAt.Time
+	}
+	if src.EndsAt.Valid {
+		dst.EndsAt = &src.EndsAt.Time
+	}
+	if src.UsageLimit.Valid {
+		dst.UsageLimit = &src.UsageLimit.Int32
+	}
+	if src.PerUserLimit.Valid {
+		dst.PerUserLimit = &src.PerUserLimit.Int32
+	}
+
+	return
+}
+
+// ParseFromList converts a list of stored promotions into their public representation
+func ParseFromList(src []postgres.Promotion) (dst []Promotion) {
+	dst = make([]Promotion, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseFrom(data))
+	}
+
+	return
+}
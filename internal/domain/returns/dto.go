@@ -0,0 +1,85 @@
+package returns
+
+import (
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Request represents the request payload for returning items of an order
+type Request struct {
+	Reason string `json:"reason"`
+	Items  []Item `json:"items"`
+}
+
+// Item is a quantity of an order item to return
+type Item struct {
+	OrderItemID int64 `json:"order_item_id"`
+	Quantity    int32 `json:"quantity"`
+}
+
+// UpdateRequest represents the request payload for moving a return through its workflow.
+// Status goes from requested to approved or rejected, then received, then refunded; empty keeps the status.
+// Note is shown to staff; nil keeps the current note.
+type UpdateRequest struct {
+	Status postgres.ReturnStatus `json:"status"`
+	Note   *string               `json:"note"`
+}
+
+// Return is the public representation of a stored return with its items
+type Return struct {
+	ID           int64                 `json:"id"`
+	OrderID      int64                 `json:"order_id"`
+	Status       postgres.ReturnStatus `json:"status"`
+	Reason       string                `json:"reason"`
+	Note         string                `json:"note"`
+	RefundAmount string                `json:"refund_amount"`
+	PaymentID    *int64                `json:"payment_id"`
+	Items        []ReturnItem          `json:"items"`
+	ReceivedAt   *time.Time            `json:"received_at"`
+	RefundedAt   *time.Time            `json:"refunded_at"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+// ReturnItem is a returned quantity of an order item with the amount refunded for it
+type ReturnItem struct {
+	ID          int64  `json:"id"`
+	OrderItemID int64  `json:"order_item_id"`
+	Quantity    int32  `json:"quantity"`
+	Amount      string `json:"amount"`
+}
+
+// ParseReturn converts a stored return and its items into their public representation
+func ParseReturn(src postgres.Return, items []postgres.ReturnItem) (dst Return) {
+	dst = Return{
+		ID:           src.ID,
+		OrderID:      src.OrderID,
+		Status:       src.Status,
+		Reason:       src.Reason,
+		Note:         src.Note,
+		RefundAmount: src.RefundAmount,
+		Items:        make([]ReturnItem, 0, len(items)),
+		CreatedAt:    src.CreatedAt,
+		UpdatedAt:    src.UpdatedAt,
+	}
+	if src.PaymentID.Valid {
+		dst.PaymentID = &src.PaymentID.Int64
+	}
+	if src.ReceivedAt.Valid {
+		dst.ReceivedAt = &src.ReceivedAt.Time
+	}
+	if src.RefundedAt.Valid {
+		dst.RefundedAt = &src.RefundedAt.Time
+	}
+	for _, item := range items {
+		dst.Items = append(dst.Items, ReturnItem{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+			Amount:      item.Amount,
+		})
+	}
+
+	return
+}
@@ -0,0 +1,131 @@
+package shipping
+
+import (
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// MethodRequest represents the request payload for creating or updating a shipping method.
+// Flat methods cost Price; weight methods add Price to the lightest of Rates that fits the order weight.
+// Either is free once the order subtotal reaches FreeOver. Rates replace the existing ones on update.
+type MethodRequest struct {
+	Name     string                    `json:"name"`
+	Carrier  string                    `json:"carrier"`
+	RateType postgres.ShippingRateType `json:"rate_type"`
+	Price    string                    `json:"price"`
+	FreeOver *string                   `json:"free_over"`
+	Active   *bool                     `json:"active"`
+	Rates    []Rate                    `json:"rates"`
+}
+
+// Rate is a weight bracket of a shipping method
+type Rate struct {
+	MaxWeightGrams int32  `json:"max_weight_grams"`
+	Price          string `json:"price"`
+}
+
+// Method is the public representation of a stored shipping method with its weight brackets
+type Method struct {
+	ID        int64                     `json:"id"`
+	Name      string                    `json:"name"`
+	Carrier   string                    `json:"carrier"`
+	RateType  postgres.ShippingRateType `json:"rate_type"`
+	Price     string                    `json:"price"`
+	FreeOver  *string                   `json:"free_over"`
+	Active    bool                      `json:"active"`
+	Rates     []Rate                    `json:"rates"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+// ParseMethod converts a stored shipping method and its rates into their public representation
+func ParseMethod(src postgres.ShippingMethod, rates []postgres.ShippingRate) (dst Method) {
+	dst = Method{
+		ID:        src.ID,
+		Name:      src.Name,
+		Carrier:   src.Carrier,
+		RateType:  src.RateType,
+		Price:     src.Price,
+		Active:    src.Active,
+		Rates:     make([]Rate, 0, len(rates)),
+		CreatedAt: src.CreatedAt,
+		UpdatedAt: src.UpdatedAt,
+	}
+	if src.FreeOver.Valid {
+		dst.FreeOver = &src.FreeOver.String
+	}
+	for _, rate := range rates {
+		dst.Rates = append(dst.Rates, Rate{
+			MaxWeightGrams: rate.MaxWeightGrams,
+			Price:          rate.Price,
+		})
+	}
+
+	return
+}
+
+// ShipmentRequest represents the request payload for creating a shipment.
+// Carrier defaults to the carrier of the order's shipping method.
+type ShipmentRequest struct {
+	Carrier        string  `json:"carrier"`
+	TrackingNumber *string `json:"tracking_number"`
+}
+
+// ShipmentUpdateRequest represents the request payload for updating a shipment.
+// Empty fields keep their value; Status only moves forward, from pending to shipped to delivered.
+type ShipmentUpdateRequest struct {
+	Carrier        string                  `json:"carrier"`
+	TrackingNumber *string                 `json:"tracking_number"`
+	Status         postgres.ShipmentStatus `json:"status"`
+}
+
+// Shipment is the public representation of a stored shipment
+type Shipment struct {
+	ID               int64                   `json:"id"`
+	OrderID          int64                   `json:"order_id"`
+	ShippingMethodID *int64                  `json:"shipping_method_id"`
+	Carrier          string                  `json:"carrier"`
+	TrackingNumber   *string                 `json:"tracking_number"`
+	Status           postgres.ShipmentStatus `json:"status"`
+	ShippedAt        *time.Time              `json:"shipped_at"`
+	DeliveredAt      *time.Time              `json:"delivered_at"`
+	CreatedAt        time.Time               `json:"created_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+}
+
+// ParseShipment converts a stored shipment into its public representation
+func ParseShipment(src postgres.Shipment) (dst Shipment) {
+	dst = Shipment{
+		ID:        src.ID,
+		OrderID:   src.OrderID,
+		Carrier:   src.Carrier,
+		Status:    src.Status,
+		CreatedAt: src.CreatedAt,
+		UpdatedAt: src.UpdatedAt,
+	}
+	if src.ShippingMethodID.Valid {
+		dst.ShippingMethodID = &src.ShippingMethodID.Int64
+	}
+	if src.TrackingNumber.Valid {
+		dst.TrackingNumber = &src.TrackingNumber.String
+	}
+	if src.ShippedAt.Valid {
+		dst.ShippedAt = &src.ShippedAt.Time
+	}
+	if src.DeliveredAt.Valid {
+		dst.DeliveredAt = &src.DeliveredAt.Time
+	}
+
+	return
+}
+
+// ParseShipments converts stored shipments into their public representation
+func ParseShipments(src []postgres.Shipment) (dst []Shipment) {
+	dst = make([]Shipment, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseShipment(data))
+	}
+
+	return
+}
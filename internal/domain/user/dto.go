@@ -0,0 +1,27 @@
+package user
+
+// CreateUserRequest represents the request payload for registering a new user.
+type CreateUserRequest struct {
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Address  string `json:"address"`
+	Role     string `json:"role"`
+	Password string `json:"password"`
+	Locale   string `json:"locale"` // Language of emails sent to the user, "ru" by default
+}
+
+// EmailRequest asks for a verification or password reset email to be sent.
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+// VerifyEmailRequest confirms an email address with the token from the verification email.
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ResetPasswordRequest sets a new password using the token from the password reset email.
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// SubscriptionRequest represents the request payload for creating or updating a webhook subscription.
+// Secret is optional on creation; one is generated when empty.
+type SubscriptionRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret,omitempty"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+// CreateSubscriptionResponse is returned on creation and is the only time the signing secret is shown.
+type CreateSubscriptionResponse struct {
+	postgres.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// Delivery is the public representation of a delivery log entry
+type Delivery struct {
+	ID             int64                          `json:"id"`
+	SubscriptionID int64                          `json:"subscription_id"`
+	EventType      string                         `json:"event_type"`
+	Payload        json.RawMessage                `json:"payload"`
+	Status         postgres.WebhookDeliveryStatus `json:"status"`
+	Attempts       int32                          `json:"attempts"`
+	ResponseStatus *int32                         `json:"response_status,omitempty"`
+	ResponseBody   *string                        `json:"response_body,omitempty"`
+	LastError      *string                        `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time                      `json:"next_attempt_at"`
+	DeliveredAt    *time.Time                     `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time                      `json:"created_at"`
+}
+
+// ParseFrom converts a stored delivery into its public representation
+func ParseFrom(src postgres.WebhookDelivery) (dst Delivery) {
+	dst = Delivery{
+		ID:             src.ID,
+		SubscriptionID: src.SubscriptionID,
+		EventType:      src.EventType,
+		Payload:        src.Payload,
+		Status:         src.Status,
+		Attempts:       src.Attempts,
+		NextAttemptAt:  src.NextAttemptAt,
+		CreatedAt:      src.CreatedAt,
+	}
+	if src.ResponseStatus.Valid {
+		dst.ResponseStatus = &src.ResponseStatus.Int32
+	}
+	if src.ResponseBody.Valid {
+		dst.ResponseBody = &src.ResponseBody.String
+	}
+	if src.LastError.Valid {
+		dst.LastError = &src.LastError.String
+	}
+	if src.DeliveredAt.Valid {
+		dst.DeliveredAt = &src.DeliveredAt.Time
+	}
+
+	return
+}
+
+// ParseFromList converts a list of stored deliveries into their public representation
+func ParseFromList(src []postgres.WebhookDelivery) (dst []Delivery) {
+	dst = make([]Delivery, 0, len(src))
+	for _, data := range src {
+		dst = append(dst, ParseFrom(data))
+	}
+
+	return
+}
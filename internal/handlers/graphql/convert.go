@@ -0,0 +1,116 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+type pageInput struct {
+	Limit  *int32
+	Cursor *string
+	Sort   *string
+}
+
+func pageParams(page *pageInput) (dst postgres.PageParams) {
+	if page == nil {
+		return
+	}
+	if page.Limit != nil {
+		dst.Limit = *page.Limit
+	}
+	if page.Cursor != nil {
+		dst.Cursor = *page.Cursor
+	}
+	if page.Sort != nil {
+		dst.Sort = *page.Sort
+	}
+	return
+}
+
+// includeDeleted checks that the caller may see the deleted records of resource, which needs permission to change it
+func includeDeleted(ctx context.Context, resource string, include bool) error {
+	if include {
+		return permit(ctx, resource+":write")
+	}
+	return nil
+}
+
+func id(value int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(value, 10))
+}
+
+func parseID(name string, value graphql.ID) (int64, error) {
+	parsed, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, invalidArgument("invalid %s: %s", name, value)
+	}
+	return parsed, nil
+}
+
+func parseOptionalID(name string, value *graphql.ID) (*int64, error) {
+	if value == nil {
+		return nil, nil
+	}
+	parsed, err := parseID(name, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func decimal(name string, value *string) (sql.NullString, error) {
+	if value == nil {
+		return sql.NullString{}, nil
+	}
+
+	if _, err := strconv.ParseFloat(*value, 64); err != nil {
+		return sql.NullString{}, invalidArgument("invalid %s: %s", name, *value)
+	}
+	return sql.NullString{String: *value, Valid: true}, nil
+}
+
+func nullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func nullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
+
+func optionalID(value sql.NullInt64) *graphql.ID {
+	if !value.Valid {
+		return nil
+	}
+	v := id(value.Int64)
+	return &v
+}
+
+func optionalString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func timestamp(t time.Time) graphql.Time {
+	return graphql.Time{Time: t}
+}
+
+func nullTimestamp(t sql.NullTime) *graphql.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &graphql.Time{Time: t.Time}
+}
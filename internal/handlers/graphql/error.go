@@ -0,0 +1,112 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/internal/service/catalog"
+	ordersvc "ecommerce_management/internal/service/order"
+	paymentsvc "ecommerce_management/internal/service/payment"
+	"ecommerce_management/internal/service/promotion"
+	"ecommerce_management/internal/service/shipping"
+	"ecommerce_management/internal/service/tax"
+	usersvc "ecommerce_management/internal/service/user"
+)
+
+var (
+	// notFound are the errors of records that do not exist or have been deleted
+	notFound = []error{
+		ordersvc.ErrNotFound,
+		paymentsvc.ErrNotFound,
+		paymentsvc.ErrOrderNotFound,
+		catalog.ErrNotFound,
+		usersvc.ErrNotFound,
+	}
+
+	// conflicts are the errors of changes the current state of a record does not allow
+	conflicts = []error{
+		ordersvc.ErrConflict,
+		ordersvc.ErrClosed,
+		paymentsvc.ErrConflict,
+		catalog.ErrConflict,
+		usersvc.ErrConflict,
+	}
+
+	// invalid are the errors caused by the request
+	invalid = []error{
+		postgres.ErrInvalidSort,
+		postgres.ErrInvalidCursor,
+		ordersvc.ErrInvalidItem,
+		ordersvc.ErrInvalidAddress,
+		ordersvc.ErrInsufficientStock,
+		catalog.ErrInvalidProduct,
+		promotion.ErrCouponNotFound,
+		promotion.ErrNotApplicable,
+		shipping.ErrNotAvailable,
+		tax.ErrInvalidCountry,
+	}
+)
+
+// Error is an error of a field; its code is reported in the extensions of the error
+// so that clients can tell missing records and invalid input from failures
+type Error struct {
+	Code string
+	err  error
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Extensions implements the interface graphql-go reads the extensions of an error from
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// serviceError converts an error returned by a domain service to an Error with the code of its kind
+func serviceError(err error) error {
+	var target *Error
+	if err == nil || errors.As(err, &target) {
+		return err
+	}
+
+	switch {
+	case isAny(err, notFound):
+		return &Error{Code: "NOT_FOUND", err: err}
+	case isAny(err, conflicts):
+		return &Error{Code: "CONFLICT", err: err}
+	case isAny(err, invalid):
+		return &Error{Code: "BAD_USER_INPUT", err: err}
+	default:
+		return &Error{Code: "INTERNAL_SERVER_ERROR", err: err}
+	}
+}
+
+// invalidArgument reports an argument that cannot be parsed
+func invalidArgument(format string, args ...any) error {
+	return &Error{Code: "BAD_USER_INPUT", err: fmt.Errorf(format, args...)}
+}
+
+// permit restricts requests made with an API key to keys with permission, as RequirePermission does for HTTP routes
+func permit(ctx context.Context, permission string) error {
+	if !auth.Permitted(ctx, permission) {
+		return &Error{Code: "FORBIDDEN", err: errors.New("api key lacks permission " + permission)}
+	}
+	return nil
+}
+
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
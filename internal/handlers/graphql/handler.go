@@ -0,0 +1,66 @@
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/graph-gophers/graphql-go"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/service/catalog"
+	ordersvc "ecommerce_management/internal/service/order"
+	usersvc "ecommerce_management/internal/service/user"
+	"ecommerce_management/pkg/server/response"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// Handler serves GraphQL queries over users, products and orders with their items, products and payments,
+// along with the cart and checkout mutations
+type Handler struct {
+	db     repository.Querier
+	schema *graphql.Schema
+}
+
+// NewHandler parses the schema against the resolvers of the domain services.
+// Nested records are read from db in batches, once per level of the query.
+func NewHandler(db repository.Querier, users *usersvc.Service, catalog *catalog.Service, orders *ordersvc.Service) (*Handler, error) {
+	root := &resolver{
+		users:   users,
+		catalog: catalog,
+		orders:  orders,
+	}
+
+	parsed, err := graphql.ParseSchema(schema, root, graphql.MaxDepth(8))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		db:     db,
+		schema: parsed,
+	}, nil
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP executes the query of a POST request; errors of fields are reported in the errors of the result
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	ctx := contextWithLoaders(r.Context(), newLoaders(h.db))
+	result := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	render.JSON(w, r, result)
+}
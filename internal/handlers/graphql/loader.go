@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/catalog"
+	usersvc "ecommerce_management/internal/service/user"
+)
+
+type loadersKey struct{}
+
+// loaders batch the lookups of the nested fields of one request, so that a page of orders
+// reads its users, items, products and payments with one query each instead of one per order
+type loaders struct {
+	users      *dataloader.Loader[int64, postgres.User]
+	products   *dataloader.Loader[int64, postgres.Product]
+	orderItems *dataloader.Loader[int64, []postgres.OrderItem]
+	payments   *dataloader.Loader[int64, []postgres.Payment]
+}
+
+// newLoaders creates the loaders of one request; they cache what they load until the request ends
+func newLoaders(db repository.Querier) *loaders {
+	return &loaders{
+		users: dataloader.NewBatchedLoader(byID(db.ListUsersByIDs, func(u postgres.User) int64 { return u.ID },
+			func(id int64) error { return fmt.Errorf("%w: user ID %d", usersvc.ErrNotFound, id) })),
+		products: dataloader.NewBatchedLoader(byID(db.ListProductsByIDs, func(p postgres.Product) int64 { return p.ID },
+			func(id int64) error { return fmt.Errorf("%w: product ID %d", catalog.ErrNotFound, id) })),
+		orderItems: dataloader.NewBatchedLoader(groupedBy(db.ListOrderItemsByOrders, func(i postgres.OrderItem) int64 { return i.OrderID })),
+		payments:   dataloader.NewBatchedLoader(groupedBy(db.ListPaymentsByOrders, func(p postgres.Payment) int64 { return p.OrderID })),
+	}
+}
+
+func contextWithLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// byID batches the lookup of single rows by primary key; keys without a row fail with missing
+func byID[T any](list func(ctx context.Context, ids []int64) ([]T, error), key func(T) int64, missing func(id int64) error) dataloader.BatchFunc[int64, T] {
+	return func(ctx context.Context, ids []int64) []*dataloader.Result[T] {
+		rows, err := list(ctx, ids)
+
+		found := make(map[int64]T, len(rows))
+		for _, row := range rows {
+			found[key(row)] = row
+		}
+
+		results := make([]*dataloader.Result[T], len(ids))
+		for i, id := range ids {
+			row, ok := found[id]
+			switch {
+			case err != nil:
+				results[i] = &dataloader.Result[T]{Error: err}
+			case !ok:
+				results[i] = &dataloader.Result[T]{Error: missing(id)}
+			default:
+				results[i] = &dataloader.Result[T]{Data: row}
+			}
+		}
+		return results
+	}
+}
+
+// groupedBy batches the lookup of the rows that belong to a parent, such as the items of orders
+func groupedBy[T any](list func(ctx context.Context, parentIDs []int64) ([]T, error), parent func(T) int64) dataloader.BatchFunc[int64, []T] {
+	return func(ctx context.Context, ids []int64) []*dataloader.Result[[]T] {
+		rows, err := list(ctx, ids)
+
+		groups := make(map[int64][]T, len(ids))
+		for _, row := range rows {
+			groups[parent(row)] = append(groups[parent(row)], row)
+		}
+
+		results := make([]*dataloader.Result[[]T], len(ids))
+		for i, id := range ids {
+			if err != nil {
+				results[i] = &dataloader.Result[[]T]{Error: err}
+				continue
+			}
+			results[i] = &dataloader.Result[[]T]{Data: groups[id]}
+		}
+		return results
+	}
+}
@@ -0,0 +1,155 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	domain "ecommerce_management/internal/domain/order"
+)
+
+// Cart mutations change what a user is about to order, so like orders they need orders:write
+
+func (r *resolver) AddToCart(ctx context.Context, args struct {
+	UserID    graphql.ID
+	ProductID graphql.ID
+	VariantID *graphql.ID
+	Quantity  int32
+}) (*cartItemResolver, error) {
+	if err := permit(ctx, "orders:write"); err != nil {
+		return nil, err
+	}
+
+	userID, err := parseID("userId", args.UserID)
+	if err != nil {
+		return nil, err
+	}
+	productID, err := parseID("productId", args.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	variantID, err := parseOptionalID("variantId", args.VariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = r.users.Get(ctx, userID, false); err != nil {
+		return nil, serviceError(err)
+	}
+
+	data, err := r.orders.AddToCart(ctx, userID, domain.OrderItem{
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  args.Quantity,
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &cartItemResolver{data: data}, nil
+}
+
+func (r *resolver) UpdateCartItem(ctx context.Context, args struct {
+	UserID   graphql.ID
+	ID       graphql.ID
+	Quantity int32
+}) (*cartItemResolver, error) {
+	if err := permit(ctx, "orders:write"); err != nil {
+		return nil, err
+	}
+
+	userID, err := parseID("userId", args.UserID)
+	if err != nil {
+		return nil, err
+	}
+	itemID, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.orders.UpdateCartItem(ctx, userID, itemID, args.Quantity)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &cartItemResolver{data: data}, nil
+}
+
+func (r *resolver) RemoveFromCart(ctx context.Context, args struct {
+	UserID graphql.ID
+	ID     graphql.ID
+}) (bool, error) {
+	if err := permit(ctx, "orders:write"); err != nil {
+		return false, err
+	}
+
+	userID, err := parseID("userId", args.UserID)
+	if err != nil {
+		return false, err
+	}
+	itemID, err := parseID("id", args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.orders.RemoveFromCart(ctx, userID, itemID); err != nil {
+		return false, serviceError(err)
+	}
+	return true, nil
+}
+
+func (r *resolver) ClearCart(ctx context.Context, args struct{ UserID graphql.ID }) (bool, error) {
+	if err := permit(ctx, "orders:write"); err != nil {
+		return false, err
+	}
+
+	userID, err := parseID("userId", args.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.orders.ClearCart(ctx, userID); err != nil {
+		return false, serviceError(err)
+	}
+	return true, nil
+}
+
+type checkoutInput struct {
+	UserID            graphql.ID
+	CouponCode        *string
+	Country           *string
+	ShippingMethodID  *graphql.ID
+	ShippingAddressID *graphql.ID
+	BillingAddressID  *graphql.ID
+}
+
+func (r *resolver) Checkout(ctx context.Context, args struct{ Input checkoutInput }) (*orderResolver, error) {
+	if err := permit(ctx, "orders:write"); err != nil {
+		return nil, err
+	}
+
+	var req domain.CreateOrderRequest
+	var err error
+	if req.UserID, err = parseID("userId", args.Input.UserID); err != nil {
+		return nil, err
+	}
+	if req.ShippingMethodID, err = parseOptionalID("shippingMethodId", args.Input.ShippingMethodID); err != nil {
+		return nil, err
+	}
+	if req.ShippingAddressID, err = parseOptionalID("shippingAddressId", args.Input.ShippingAddressID); err != nil {
+		return nil, err
+	}
+	if req.BillingAddressID, err = parseOptionalID("billingAddressId", args.Input.BillingAddressID); err != nil {
+		return nil, err
+	}
+	if args.Input.CouponCode != nil {
+		req.CouponCode = *args.Input.CouponCode
+	}
+	if args.Input.Country != nil {
+		req.Country = *args.Input.Country
+	}
+
+	data, err := r.orders.Checkout(ctx, req)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &orderResolver{data: data}, nil
+}
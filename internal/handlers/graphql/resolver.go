@@ -0,0 +1,225 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/catalog"
+	ordersvc "ecommerce_management/internal/service/order"
+	usersvc "ecommerce_management/internal/service/user"
+)
+
+// resolver resolves the fields of Query and Mutation through the domain services.
+// Like the HTTP routes they mirror, fields need the read or write permission of their resource.
+type resolver struct {
+	users   *usersvc.Service
+	catalog *catalog.Service
+	orders  *ordersvc.Service
+}
+
+func (r *resolver) User(ctx context.Context, args struct {
+	ID             graphql.ID
+	IncludeDeleted bool
+}) (*userResolver, error) {
+	if err := permit(ctx, "users:read"); err != nil {
+		return nil, err
+	}
+	if err := includeDeleted(ctx, "users", args.IncludeDeleted); err != nil {
+		return nil, err
+	}
+
+	userID, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.users.Get(ctx, userID, args.IncludeDeleted)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &userResolver{data: data}, nil
+}
+
+func (r *resolver) Users(ctx context.Context, args struct {
+	Page           *pageInput
+	Name           *string
+	Email          *string
+	Role           *string
+	IncludeDeleted bool
+}) (*pageResolver[*userResolver], error) {
+	if err := permit(ctx, "users:read"); err != nil {
+		return nil, err
+	}
+	if err := includeDeleted(ctx, "users", args.IncludeDeleted); err != nil {
+		return nil, err
+	}
+
+	page, err := r.users.List(ctx, postgres.ListUsersPageParams{
+		PageParams:     pageParams(args.Page),
+		Name:           nullString(args.Name),
+		Email:          nullString(args.Email),
+		Role:           nullString(args.Role),
+		IncludeDeleted: args.IncludeDeleted,
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPage(page, func(data postgres.User) *userResolver { return &userResolver{data: data} }), nil
+}
+
+func (r *resolver) Product(ctx context.Context, args struct {
+	ID             graphql.ID
+	IncludeDeleted bool
+}) (*productResolver, error) {
+	if err := permit(ctx, "products:read"); err != nil {
+		return nil, err
+	}
+	if err := includeDeleted(ctx, "products", args.IncludeDeleted); err != nil {
+		return nil, err
+	}
+
+	productID, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.catalog.Get(ctx, productID, args.IncludeDeleted)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &productResolver{data: data}, nil
+}
+
+func (r *resolver) Products(ctx context.Context, args struct {
+	Page           *pageInput
+	Name           *string
+	CategoryID     *graphql.ID
+	MinPrice       *string
+	MaxPrice       *string
+	InStock        *bool
+	IncludeDeleted bool
+}) (*pageResolver[*productResolver], error) {
+	if err := permit(ctx, "products:read"); err != nil {
+		return nil, err
+	}
+	if err := includeDeleted(ctx, "products", args.IncludeDeleted); err != nil {
+		return nil, err
+	}
+
+	arg := postgres.ListProductsPageParams{
+		PageParams:     pageParams(args.Page),
+		Name:           nullString(args.Name),
+		IncludeDeleted: args.IncludeDeleted,
+	}
+
+	categoryID, err := parseOptionalID("categoryId", args.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	arg.CategoryID = nullInt64(categoryID)
+
+	if arg.MinPrice, err = decimal("minPrice", args.MinPrice); err != nil {
+		return nil, err
+	}
+	if arg.MaxPrice, err = decimal("maxPrice", args.MaxPrice); err != nil {
+		return nil, err
+	}
+	if args.InStock != nil {
+		arg.InStock.Bool, arg.InStock.Valid = *args.InStock, true
+	}
+
+	page, err := r.catalog.List(ctx, arg)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPage(page, func(data postgres.Product) *productResolver { return &productResolver{data: data} }), nil
+}
+
+func (r *resolver) Order(ctx context.Context, args struct {
+	ID             graphql.ID
+	IncludeDeleted bool
+}) (*orderResolver, error) {
+	if err := permit(ctx, "orders:read"); err != nil {
+		return nil, err
+	}
+	if err := includeDeleted(ctx, "orders", args.IncludeDeleted); err != nil {
+		return nil, err
+	}
+
+	orderID, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.orders.Get(ctx, orderID, args.IncludeDeleted)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &orderResolver{data: data}, nil
+}
+
+func (r *resolver) Orders(ctx context.Context, args struct {
+	Page           *pageInput
+	UserID         *graphql.ID
+	Status         *string
+	IncludeDeleted bool
+}) (*pageResolver[*orderResolver], error) {
+	if err := permit(ctx, "orders:read"); err != nil {
+		return nil, err
+	}
+	if err := includeDeleted(ctx, "orders", args.IncludeDeleted); err != nil {
+		return nil, err
+	}
+
+	arg := postgres.ListOrdersPageParams{
+		PageParams:     pageParams(args.Page),
+		IncludeDeleted: args.IncludeDeleted,
+	}
+
+	userID, err := parseOptionalID("userId", args.UserID)
+	if err != nil {
+		return nil, err
+	}
+	arg.UserID = nullInt64(userID)
+
+	if args.Status != nil {
+		orderStatus := postgres.OrderStatus(*args.Status)
+		if !orderStatus.Valid() {
+			return nil, invalidArgument("invalid status: %s", *args.Status)
+		}
+		arg.Status = postgres.NullOrderStatus{OrderStatus: orderStatus, Valid: true}
+	}
+
+	page, err := r.orders.List(ctx, arg)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPage(page, func(data postgres.Order) *orderResolver { return &orderResolver{data: data} }), nil
+}
+
+func (r *resolver) Cart(ctx context.Context, args struct{ UserID graphql.ID }) ([]*cartItemResolver, error) {
+	if err := permit(ctx, "orders:read"); err != nil {
+		return nil, err
+	}
+
+	userID, err := parseID("userId", args.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.orders.Cart(ctx, userID)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	dst := make([]*cartItemResolver, 0, len(items))
+	for _, item := range items {
+		dst = append(dst, &cartItemResolver{data: item})
+	}
+	return dst, nil
+}
@@ -0,0 +1,187 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+type userResolver struct {
+	data postgres.User
+}
+
+func (r *userResolver) ID() graphql.ID                 { return id(r.data.ID) }
+func (r *userResolver) FullName() string               { return r.data.FullName }
+func (r *userResolver) Email() string                  { return r.data.Email }
+func (r *userResolver) Address() string                { return r.data.Address }
+func (r *userResolver) Role() string                   { return r.data.Role }
+func (r *userResolver) Locale() string                 { return r.data.Locale }
+func (r *userResolver) RegistrationDate() graphql.Time { return timestamp(r.data.RegistrationDate) }
+func (r *userResolver) EmailVerifiedAt() *graphql.Time { return nullTimestamp(r.data.EmailVerifiedAt) }
+func (r *userResolver) Version() int32                 { return r.data.Version }
+func (r *userResolver) DeletedAt() *graphql.Time       { return nullTimestamp(r.data.DeletedAt) }
+
+type productResolver struct {
+	data postgres.Product
+}
+
+func (r *productResolver) ID() graphql.ID             { return id(r.data.ID) }
+func (r *productResolver) Name() string               { return r.data.Name }
+func (r *productResolver) Description() string        { return r.data.Description }
+func (r *productResolver) Price() string              { return r.data.Price }
+func (r *productResolver) StockQuantity() int32       { return r.data.StockQuantity }
+func (r *productResolver) CategoryID() graphql.ID     { return id(r.data.CategoryID) }
+func (r *productResolver) Sku() string                { return r.data.Sku }
+func (r *productResolver) TaxClass() string           { return r.data.TaxClass }
+func (r *productResolver) WeightGrams() int32         { return r.data.WeightGrams }
+func (r *productResolver) AdditionDate() graphql.Time { return timestamp(r.data.AdditionDate) }
+func (r *productResolver) Version() int32             { return r.data.Version }
+func (r *productResolver) DeletedAt() *graphql.Time   { return nullTimestamp(r.data.DeletedAt) }
+
+type orderResolver struct {
+	data postgres.Order
+}
+
+func (r *orderResolver) ID() graphql.ID                { return id(r.data.ID) }
+func (r *orderResolver) Status() string                { return string(r.data.Status) }
+func (r *orderResolver) OrderDate() graphql.Time       { return timestamp(r.data.OrderDate) }
+func (r *orderResolver) NetAmount() string             { return r.data.NetAmount }
+func (r *orderResolver) TaxAmount() string             { return r.data.TaxAmount }
+func (r *orderResolver) ShippingAmount() string        { return r.data.ShippingAmount }
+func (r *orderResolver) TotalAmount() string           { return r.data.TotalAmount }
+func (r *orderResolver) PricesIncludeTax() bool        { return r.data.PricesIncludeTax }
+func (r *orderResolver) DestinationCountry() string    { return r.data.DestinationCountry }
+func (r *orderResolver) ShippingMethodID() *graphql.ID { return optionalID(r.data.ShippingMethodID) }
+func (r *orderResolver) Version() int32                { return r.data.Version }
+func (r *orderResolver) DeletedAt() *graphql.Time      { return nullTimestamp(r.data.DeletedAt) }
+
+// User returns the customer of the order, who is shown even when deleted since
+func (r *orderResolver) User(ctx context.Context) (*userResolver, error) {
+	if err := permit(ctx, "users:read"); err != nil {
+		return nil, err
+	}
+
+	data, err := loadersFromContext(ctx).users.Load(ctx, r.data.UserID)()
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &userResolver{data: data}, nil
+}
+
+func (r *orderResolver) Items(ctx context.Context) ([]*orderItemResolver, error) {
+	items, err := loadersFromContext(ctx).orderItems.Load(ctx, r.data.ID)()
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	dst := make([]*orderItemResolver, 0, len(items))
+	for _, item := range items {
+		dst = append(dst, &orderItemResolver{data: item})
+	}
+	return dst, nil
+}
+
+func (r *orderResolver) Payments(ctx context.Context) ([]*paymentResolver, error) {
+	if err := permit(ctx, "payments:read"); err != nil {
+		return nil, err
+	}
+
+	payments, err := loadersFromContext(ctx).payments.Load(ctx, r.data.ID)()
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	dst := make([]*paymentResolver, 0, len(payments))
+	for _, payment := range payments {
+		dst = append(dst, &paymentResolver{data: payment})
+	}
+	return dst, nil
+}
+
+type orderItemResolver struct {
+	data postgres.OrderItem
+}
+
+func (r *orderItemResolver) ID() graphql.ID         { return id(r.data.ID) }
+func (r *orderItemResolver) VariantID() *graphql.ID { return optionalID(r.data.VariantID) }
+func (r *orderItemResolver) Quantity() int32        { return r.data.Quantity }
+func (r *orderItemResolver) Price() string          { return r.data.Price }
+func (r *orderItemResolver) TaxRate() string        { return r.data.TaxRate }
+func (r *orderItemResolver) NetAmount() string      { return r.data.NetAmount }
+func (r *orderItemResolver) TaxAmount() string      { return r.data.TaxAmount }
+func (r *orderItemResolver) GrossAmount() string    { return r.data.GrossAmount }
+
+// Product returns the product ordered, which is shown even when deleted since
+func (r *orderItemResolver) Product(ctx context.Context) (*productResolver, error) {
+	return loadProduct(ctx, r.data.ProductID)
+}
+
+type paymentResolver struct {
+	data postgres.Payment
+}
+
+func (r *paymentResolver) ID() graphql.ID            { return id(r.data.ID) }
+func (r *paymentResolver) Amount() string            { return r.data.Amount }
+func (r *paymentResolver) Status() string            { return string(r.data.Status) }
+func (r *paymentResolver) PaymentDate() graphql.Time { return timestamp(r.data.PaymentDate) }
+func (r *paymentResolver) InvoiceID() *string        { return optionalString(r.data.InvoiceID) }
+func (r *paymentResolver) RefundedAmount() string    { return r.data.RefundedAmount }
+func (r *paymentResolver) Version() int32            { return r.data.Version }
+func (r *paymentResolver) DeletedAt() *graphql.Time  { return nullTimestamp(r.data.DeletedAt) }
+
+type cartItemResolver struct {
+	data postgres.CartItem
+}
+
+func (r *cartItemResolver) ID() graphql.ID         { return id(r.data.ID) }
+func (r *cartItemResolver) VariantID() *graphql.ID { return optionalID(r.data.VariantID) }
+func (r *cartItemResolver) Quantity() int32        { return r.data.Quantity }
+func (r *cartItemResolver) AddedAt() graphql.Time  { return timestamp(r.data.AddedAt) }
+
+func (r *cartItemResolver) Product(ctx context.Context) (*productResolver, error) {
+	return loadProduct(ctx, r.data.ProductID)
+}
+
+func loadProduct(ctx context.Context, productID int64) (*productResolver, error) {
+	if err := permit(ctx, "products:read"); err != nil {
+		return nil, err
+	}
+
+	data, err := loadersFromContext(ctx).products.Load(ctx, productID)()
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &productResolver{data: data}, nil
+}
+
+// pageResolver is a page of a list together with the cursor of the following page and the number of matching rows
+type pageResolver[T any] struct {
+	items      []T
+	nextCursor string
+	total      int64
+}
+
+func newPage[T, R any](page postgres.Page[T], resolve func(T) R) *pageResolver[R] {
+	items := make([]R, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, resolve(item))
+	}
+	return &pageResolver[R]{
+		items:      items,
+		nextCursor: page.NextCursor,
+		total:      page.Total,
+	}
+}
+
+func (p *pageResolver[T]) Items() []T { return p.items }
+
+func (p *pageResolver[T]) NextCursor() *string {
+	if p.nextCursor == "" {
+		return nil
+	}
+	return &p.nextCursor
+}
+
+func (p *pageResolver[T]) Total() int32 { return int32(p.total) }
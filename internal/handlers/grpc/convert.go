@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+	ecommercev1 "ecommerce_management/pkg/api/ecommerce/v1"
+)
+
+func pageParams(page *ecommercev1.PageRequest) postgres.PageParams {
+	return postgres.PageParams{
+		Limit:  page.GetLimit(),
+		Cursor: page.GetCursor(),
+		Sort:   page.GetSort(),
+	}
+}
+
+func pageInfo[T any](page postgres.Page[T]) *ecommercev1.PageInfo {
+	return &ecommercev1.PageInfo{
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+}
+
+// includeDeleted checks that the caller may see the deleted records of resource, which needs permission to change it
+func includeDeleted(ctx context.Context, resource string, include bool) error {
+	if include && !auth.Permitted(ctx, resource+":write") {
+		return status.Errorf(codes.PermissionDenied, "include_deleted needs permission %s:write", resource)
+	}
+	return nil
+}
+
+func decimal(name, value string) (sql.NullString, error) {
+	if value == "" {
+		return sql.NullString{}, nil
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return sql.NullString{}, status.Errorf(codes.InvalidArgument, "invalid %s: %s", name, value)
+	}
+	return sql.NullString{String: value, Valid: true}, nil
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	return timestamppb.New(t)
+}
+
+func nullTimestamp(t sql.NullTime) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
+
+func optionalInt64(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
+}
+
+// checkVersion rejects a change made to a version of a record other than the one stored,
+// as If-Match does over HTTP
+func checkVersion(kind string, id int64, stored, version int32) error {
+	if stored != version {
+		return status.Error(codes.Aborted, fmt.Sprintf("%s ID %d is at version %d, not %d; read it again and retry", kind, id, stored, version))
+	}
+	return nil
+}
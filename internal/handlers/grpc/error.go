@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/internal/service/catalog"
+	ordersvc "ecommerce_management/internal/service/order"
+	paymentsvc "ecommerce_management/internal/service/payment"
+	"ecommerce_management/internal/service/promotion"
+	"ecommerce_management/internal/service/shipping"
+	"ecommerce_management/internal/service/tax"
+	usersvc "ecommerce_management/internal/service/user"
+)
+
+var (
+	// notFound are the errors of records that do not exist or have been deleted
+	notFound = []error{
+		ordersvc.ErrNotFound,
+		paymentsvc.ErrNotFound,
+		paymentsvc.ErrOrderNotFound,
+		catalog.ErrNotFound,
+		usersvc.ErrNotFound,
+	}
+
+	// versionChanged are the errors of updates made to a version that is no longer current
+	versionChanged = []error{
+		ordersvc.ErrVersionChanged,
+		paymentsvc.ErrVersionChanged,
+		catalog.ErrVersionChanged,
+		usersvc.ErrVersionChanged,
+	}
+
+	// conflicts are the errors of changes the current state of a record does not allow
+	conflicts = []error{
+		ordersvc.ErrConflict,
+		ordersvc.ErrClosed,
+		paymentsvc.ErrConflict,
+		catalog.ErrConflict,
+		usersvc.ErrConflict,
+	}
+
+	// invalid are the errors caused by the request
+	invalid = []error{
+		postgres.ErrInvalidSort,
+		postgres.ErrInvalidCursor,
+		ordersvc.ErrInvalidItem,
+		ordersvc.ErrInvalidAddress,
+		ordersvc.ErrInsufficientStock,
+		paymentsvc.ErrInvalidAmount,
+		catalog.ErrInvalidProduct,
+		auth.ErrInvalidToken,
+		auth.ErrWeakPassword,
+		promotion.ErrCouponNotFound,
+		promotion.ErrNotApplicable,
+		shipping.ErrNotAvailable,
+		tax.ErrInvalidCountry,
+	}
+
+	// unauthenticated are the errors of credentials that cannot be accepted
+	unauthenticated = []error{
+		auth.ErrInvalidAPIKey,
+		auth.ErrRevokedAPIKey,
+		auth.ErrInvalidBearerToken,
+		auth.ErrUnsupportedScheme,
+	}
+)
+
+// serviceError converts an error returned by a domain service to a gRPC status.
+// A version mismatch aborts the call, so that clients read the record again and retry.
+func serviceError(err error) error {
+	switch {
+	case isAny(err, notFound):
+		return status.Error(codes.NotFound, err.Error())
+	case isAny(err, versionChanged):
+		return status.Error(codes.Aborted, err.Error())
+	case isAny(err, conflicts):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case isAny(err, invalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case isAny(err, unauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"ecommerce_management/internal/service/auth"
+	ecommercev1 "ecommerce_management/pkg/api/ecommerce/v1"
+	"ecommerce_management/pkg/log"
+)
+
+// resources maps each service to the resource its API key permissions are scoped to,
+// as the HTTP routes they mirror are
+var resources = map[string]string{
+	ecommercev1.UserService_ServiceDesc.ServiceName:    "users",
+	ecommercev1.ProductService_ServiceDesc.ServiceName: "products",
+	ecommercev1.OrderService_ServiceDesc.ServiceName:   "orders",
+	ecommercev1.PaymentService_ServiceDesc.ServiceName: "payments",
+}
+
+// Logging adds a logger naming the method to the context of every call and logs its outcome
+func Logging() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		logger := log.LoggerFromContext(ctx).Named("grpc").With(zap.String("method", info.FullMethod))
+		ctx = log.ContextWithLogger(ctx, logger)
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		fields := []zap.Field{zap.String("code", code.String()), zap.Duration("duration", time.Since(start))}
+		switch code {
+		case codes.OK:
+			logger.Info("call finished", fields...)
+		case codes.Internal, codes.Unknown:
+			logger.Error("call failed", append(fields, zap.Error(err))...)
+		default:
+			logger.Warn("call rejected", append(fields, zap.Error(err))...)
+		}
+
+		return resp, err
+	}
+}
+
+// Authentication authenticates the credentials of the authorization metadata like the HTTP
+// Authorization header and restricts calls made with an API key to keys scoped to the resource
+// of the service. Get and List methods need "<resource>:read", everything else "<resource>:write".
+func Authentication(authService *auth.Service) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var credentials string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				credentials = values[0]
+			}
+		}
+
+		ctx, err := authService.AuthenticateCredentials(ctx, credentials)
+		if err != nil {
+			return nil, serviceError(err)
+		}
+
+		service, method := path.Split(info.FullMethod)
+		if resource, ok := resources[strings.Trim(service, "/")]; ok {
+			permission := resource + ":write"
+			if strings.HasPrefix(method, "Get") || strings.HasPrefix(method, "List") {
+				permission = resource + ":read"
+			}
+
+			if !auth.Permitted(ctx, permission) {
+				return nil, status.Error(codes.PermissionDenied, "api key lacks permission "+permission)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
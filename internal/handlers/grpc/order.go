@@ -0,0 +1,221 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	domain "ecommerce_management/internal/domain/order"
+	"ecommerce_management/internal/repository/postgres"
+	ordersvc "ecommerce_management/internal/service/order"
+	ecommercev1 "ecommerce_management/pkg/api/ecommerce/v1"
+)
+
+type OrderServer struct {
+	ecommercev1.UnimplementedOrderServiceServer
+	orders *ordersvc.Service
+}
+
+func NewOrderServer(orders *ordersvc.Service) *OrderServer {
+	return &OrderServer{
+		orders: orders,
+	}
+}
+
+func (s *OrderServer) ListOrders(ctx context.Context, req *ecommercev1.ListOrdersRequest) (*ecommercev1.ListOrdersResponse, error) {
+	if err := includeDeleted(ctx, "orders", req.GetIncludeDeleted()); err != nil {
+		return nil, err
+	}
+
+	arg := postgres.ListOrdersPageParams{
+		PageParams:     pageParams(req.GetPage()),
+		UserID:         nullInt64(req.UserId),
+		IncludeDeleted: req.GetIncludeDeleted(),
+	}
+	if req.GetStatus() != "" {
+		orderStatus, err := parseOrderStatus(req.GetStatus())
+		if err != nil {
+			return nil, err
+		}
+		arg.Status = postgres.NullOrderStatus{OrderStatus: orderStatus, Valid: true}
+	}
+
+	page, err := s.orders.List(ctx, arg)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resp := &ecommercev1.ListOrdersResponse{Page: pageInfo(page)}
+	for _, data := range page.Items {
+		resp.Orders = append(resp.Orders, orderMessage(data))
+	}
+	return resp, nil
+}
+
+func (s *OrderServer) GetOrder(ctx context.Context, req *ecommercev1.GetOrderRequest) (*ecommercev1.Order, error) {
+	if err := includeDeleted(ctx, "orders", req.GetIncludeDeleted()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.orders.Get(ctx, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return orderMessage(data), nil
+}
+
+func (s *OrderServer) CreateOrder(ctx context.Context, req *ecommercev1.CreateOrderRequest) (*ecommercev1.Order, error) {
+	arg := domain.CreateOrderRequest{
+		UserID:            req.GetUserId(),
+		CouponCode:        req.GetCouponCode(),
+		Country:           req.GetCountry(),
+		ShippingMethodID:  req.ShippingMethodId,
+		ShippingAddressID: req.ShippingAddressId,
+		BillingAddressID:  req.BillingAddressId,
+	}
+	for _, item := range req.GetItems() {
+		arg.Items = append(arg.Items, orderLine(item))
+	}
+
+	data, err := s.orders.Create(ctx, arg)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return orderMessage(data), nil
+}
+
+func (s *OrderServer) UpdateOrderStatus(ctx context.Context, req *ecommercev1.UpdateOrderStatusRequest) (*ecommercev1.Order, error) {
+	orderStatus, err := parseOrderStatus(req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.orders.Get(ctx, req.GetId(), false)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	if err = checkVersion("order", previous.ID, previous.Version, req.GetVersion()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.orders.Update(ctx, previous, postgres.UpdateOrderParams{
+		UserID:      previous.UserID,
+		TotalAmount: previous.TotalAmount,
+		Status:      orderStatus,
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return orderMessage(data), nil
+}
+
+func (s *OrderServer) DeleteOrder(ctx context.Context, req *ecommercev1.DeleteOrderRequest) (*emptypb.Empty, error) {
+	if err := s.orders.Delete(ctx, req.GetId()); err != nil {
+		return nil, serviceError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *OrderServer) RestoreOrder(ctx context.Context, req *ecommercev1.RestoreOrderRequest) (*ecommercev1.Order, error) {
+	data, err := s.orders.Restore(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return orderMessage(data), nil
+}
+
+func (s *OrderServer) ListOrderItems(ctx context.Context, req *ecommercev1.ListOrderItemsRequest) (*ecommercev1.ListOrderItemsResponse, error) {
+	if _, err := s.orders.Get(ctx, req.GetOrderId(), false); err != nil {
+		return nil, serviceError(err)
+	}
+
+	page, err := s.orders.ListItems(ctx, postgres.ListOrderItemsPageParams{
+		PageParams: pageParams(req.GetPage()),
+		OrderID:    nullInt64(&req.OrderId),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resp := &ecommercev1.ListOrderItemsResponse{Page: pageInfo(page)}
+	for _, data := range page.Items {
+		resp.Items = append(resp.Items, orderItemMessage(data))
+	}
+	return resp, nil
+}
+
+func (s *OrderServer) AddOrderItem(ctx context.Context, req *ecommercev1.AddOrderItemRequest) (*ecommercev1.Order, error) {
+	data, err := s.orders.AddItem(ctx, req.GetOrderId(), orderLine(req.GetItem()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return orderMessage(data), nil
+}
+
+func (s *OrderServer) UpdateOrderItem(ctx context.Context, req *ecommercev1.UpdateOrderItemRequest) (*ecommercev1.Order, error) {
+	data, err := s.orders.UpdateItem(ctx, req.GetOrderId(), req.GetItemId(), req.GetQuantity())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return orderMessage(data), nil
+}
+
+func (s *OrderServer) DeleteOrderItem(ctx context.Context, req *ecommercev1.DeleteOrderItemRequest) (*ecommercev1.Order, error) {
+	data, err := s.orders.DeleteItem(ctx, req.GetOrderId(), req.GetItemId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return orderMessage(data), nil
+}
+
+func parseOrderStatus(value string) (postgres.OrderStatus, error) {
+	orderStatus := postgres.OrderStatus(value)
+	if !orderStatus.Valid() {
+		return orderStatus, status.Errorf(codes.InvalidArgument, "invalid status: %s", value)
+	}
+	return orderStatus, nil
+}
+
+func orderLine(item *ecommercev1.OrderLine) domain.OrderItem {
+	return domain.OrderItem{
+		ProductID: item.GetProductId(),
+		VariantID: item.VariantId,
+		Quantity:  item.GetQuantity(),
+	}
+}
+
+func orderMessage(data postgres.Order) *ecommercev1.Order {
+	return &ecommercev1.Order{
+		Id:                 data.ID,
+		UserId:             data.UserID,
+		Status:             string(data.Status),
+		OrderDate:          timestamp(data.OrderDate),
+		TotalAmount:        data.TotalAmount,
+		NetAmount:          data.NetAmount,
+		TaxAmount:          data.TaxAmount,
+		ShippingAmount:     data.ShippingAmount,
+		PricesIncludeTax:   data.PricesIncludeTax,
+		DestinationCountry: data.DestinationCountry,
+		ShippingMethodId:   optionalInt64(data.ShippingMethodID),
+		Version:            data.Version,
+		DeletedAt:          nullTimestamp(data.DeletedAt),
+	}
+}
+
+func orderItemMessage(data postgres.OrderItem) *ecommercev1.OrderItem {
+	return &ecommercev1.OrderItem{
+		Id:          data.ID,
+		OrderId:     data.OrderID,
+		ProductId:   data.ProductID,
+		VariantId:   optionalInt64(data.VariantID),
+		Quantity:    data.Quantity,
+		Price:       data.Price,
+		TaxRate:     data.TaxRate,
+		NetAmount:   data.NetAmount,
+		TaxAmount:   data.TaxAmount,
+		GrossAmount: data.GrossAmount,
+	}
+}
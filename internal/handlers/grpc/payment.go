@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	domain "ecommerce_management/internal/domain/payment"
+	"ecommerce_management/internal/repository/postgres"
+	paymentsvc "ecommerce_management/internal/service/payment"
+	ecommercev1 "ecommerce_management/pkg/api/ecommerce/v1"
+)
+
+type PaymentServer struct {
+	ecommercev1.UnimplementedPaymentServiceServer
+	payments *paymentsvc.Service
+}
+
+func NewPaymentServer(payments *paymentsvc.Service) *PaymentServer {
+	return &PaymentServer{
+		payments: payments,
+	}
+}
+
+func (s *PaymentServer) ListPayments(ctx context.Context, req *ecommercev1.ListPaymentsRequest) (*ecommercev1.ListPaymentsResponse, error) {
+	if err := includeDeleted(ctx, "payments", req.GetIncludeDeleted()); err != nil {
+		return nil, err
+	}
+
+	arg := postgres.ListPaymentsPageParams{
+		PageParams:     pageParams(req.GetPage()),
+		UserID:         nullInt64(req.UserId),
+		OrderID:        nullInt64(req.OrderId),
+		IncludeDeleted: req.GetIncludeDeleted(),
+	}
+	if req.GetStatus() != "" {
+		paymentStatus, err := parsePaymentStatus(req.GetStatus())
+		if err != nil {
+			return nil, err
+		}
+		arg.Status = postgres.NullPaymentStatus{PaymentStatus: paymentStatus, Valid: true}
+	}
+
+	page, err := s.payments.List(ctx, arg)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resp := &ecommercev1.ListPaymentsResponse{Page: pageInfo(page)}
+	for _, data := range page.Items {
+		resp.Payments = append(resp.Payments, paymentMessage(data))
+	}
+	return resp, nil
+}
+
+func (s *PaymentServer) GetPayment(ctx context.Context, req *ecommercev1.GetPaymentRequest) (*ecommercev1.Payment, error) {
+	if err := includeDeleted(ctx, "payments", req.GetIncludeDeleted()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.payments.Get(ctx, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return paymentMessage(data), nil
+}
+
+func (s *PaymentServer) CreatePayment(ctx context.Context, req *ecommercev1.CreatePaymentRequest) (*ecommercev1.Payment, error) {
+	data, err := s.payments.Create(ctx, domain.CreatePaymentParams{
+		OrderID: req.GetOrderId(),
+		HPAN:    req.GetHpan(),
+		ExpDate: req.GetExpDate(),
+		CVC:     req.GetCvc(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return paymentMessage(data), nil
+}
+
+func (s *PaymentServer) UpdatePaymentStatus(ctx context.Context, req *ecommercev1.UpdatePaymentStatusRequest) (*ecommercev1.Payment, error) {
+	paymentStatus, err := parsePaymentStatus(req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.payments.Get(ctx, req.GetId(), false)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	if err = checkVersion("payment", previous.ID, previous.Version, req.GetVersion()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.payments.Update(ctx, previous, postgres.UpdatePaymentParams{
+		UserID:  previous.UserID,
+		OrderID: previous.OrderID,
+		Amount:  previous.Amount,
+		Status:  paymentStatus,
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return paymentMessage(data), nil
+}
+
+func (s *PaymentServer) DeletePayment(ctx context.Context, req *ecommercev1.DeletePaymentRequest) (*emptypb.Empty, error) {
+	if err := s.payments.Delete(ctx, req.GetId()); err != nil {
+		return nil, serviceError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *PaymentServer) RestorePayment(ctx context.Context, req *ecommercev1.RestorePaymentRequest) (*ecommercev1.Payment, error) {
+	data, err := s.payments.Restore(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return paymentMessage(data), nil
+}
+
+func parsePaymentStatus(value string) (postgres.PaymentStatus, error) {
+	paymentStatus := postgres.PaymentStatus(value)
+	if !paymentStatus.Valid() {
+		return paymentStatus, status.Errorf(codes.InvalidArgument, "invalid status: %s", value)
+	}
+	return paymentStatus, nil
+}
+
+func paymentMessage(data postgres.Payment) *ecommercev1.Payment {
+	return &ecommercev1.Payment{
+		Id:             data.ID,
+		UserId:         data.UserID,
+		OrderId:        data.OrderID,
+		Amount:         data.Amount,
+		PaymentDate:    timestamp(data.PaymentDate),
+		Status:         string(data.Status),
+		InvoiceId:      data.InvoiceID.String,
+		RefundedAmount: data.RefundedAmount,
+		Version:        data.Version,
+		DeletedAt:      nullTimestamp(data.DeletedAt),
+	}
+}
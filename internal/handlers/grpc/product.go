@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/catalog"
+	ecommercev1 "ecommerce_management/pkg/api/ecommerce/v1"
+)
+
+type ProductServer struct {
+	ecommercev1.UnimplementedProductServiceServer
+	catalog *catalog.Service
+}
+
+func NewProductServer(catalog *catalog.Service) *ProductServer {
+	return &ProductServer{
+		catalog: catalog,
+	}
+}
+
+func (s *ProductServer) ListProducts(ctx context.Context, req *ecommercev1.ListProductsRequest) (*ecommercev1.ListProductsResponse, error) {
+	if err := includeDeleted(ctx, "products", req.GetIncludeDeleted()); err != nil {
+		return nil, err
+	}
+
+	arg := postgres.ListProductsPageParams{
+		PageParams:     pageParams(req.GetPage()),
+		Name:           nullString(req.GetName()),
+		CategoryID:     nullInt64(req.CategoryId),
+		IncludeDeleted: req.GetIncludeDeleted(),
+	}
+	if req.InStock != nil {
+		arg.InStock = sql.NullBool{Bool: req.GetInStock(), Valid: true}
+	}
+
+	var err error
+	if arg.MinPrice, err = decimal("min_price", req.GetMinPrice()); err != nil {
+		return nil, err
+	}
+	if arg.MaxPrice, err = decimal("max_price", req.GetMaxPrice()); err != nil {
+		return nil, err
+	}
+
+	page, err := s.catalog.List(ctx, arg)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resp := &ecommercev1.ListProductsResponse{Page: pageInfo(page)}
+	for _, data := range page.Items {
+		resp.Products = append(resp.Products, productMessage(data))
+	}
+	return resp, nil
+}
+
+func (s *ProductServer) GetProduct(ctx context.Context, req *ecommercev1.GetProductRequest) (*ecommercev1.Product, error) {
+	if err := includeDeleted(ctx, "products", req.GetIncludeDeleted()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.catalog.Get(ctx, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return productMessage(data), nil
+}
+
+func (s *ProductServer) CreateProduct(ctx context.Context, req *ecommercev1.CreateProductRequest) (*ecommercev1.Product, error) {
+	data, err := s.catalog.Create(ctx, postgres.CreateProductParams{
+		Sku:           req.GetSku(),
+		Name:          req.GetName(),
+		Description:   req.GetDescription(),
+		Price:         req.GetPrice(),
+		CategoryID:    req.GetCategoryId(),
+		StockQuantity: req.GetStockQuantity(),
+		TaxClass:      req.GetTaxClass(),
+		WeightGrams:   req.GetWeightGrams(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return productMessage(data), nil
+}
+
+func (s *ProductServer) UpdateProduct(ctx context.Context, req *ecommercev1.UpdateProductRequest) (*ecommercev1.Product, error) {
+	previous, err := s.catalog.Get(ctx, req.GetId(), false)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	if err = checkVersion("product", previous.ID, previous.Version, req.GetVersion()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.catalog.Update(ctx, previous, postgres.UpdateProductParams{
+		Sku:           req.GetSku(),
+		Name:          req.GetName(),
+		Description:   req.GetDescription(),
+		Price:         req.GetPrice(),
+		CategoryID:    req.GetCategoryId(),
+		StockQuantity: req.GetStockQuantity(),
+		TaxClass:      req.GetTaxClass(),
+		WeightGrams:   req.GetWeightGrams(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return productMessage(data), nil
+}
+
+func (s *ProductServer) DeleteProduct(ctx context.Context, req *ecommercev1.DeleteProductRequest) (*emptypb.Empty, error) {
+	if err := s.catalog.Delete(ctx, req.GetId()); err != nil {
+		return nil, serviceError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *ProductServer) RestoreProduct(ctx context.Context, req *ecommercev1.RestoreProductRequest) (*ecommercev1.Product, error) {
+	data, err := s.catalog.Restore(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return productMessage(data), nil
+}
+
+func productMessage(data postgres.Product) *ecommercev1.Product {
+	return &ecommercev1.Product{
+		Id:            data.ID,
+		Sku:           data.Sku,
+		Name:          data.Name,
+		Description:   data.Description,
+		Price:         data.Price,
+		StockQuantity: data.StockQuantity,
+		AdditionDate:  timestamp(data.AdditionDate),
+		CategoryId:    data.CategoryID,
+		TaxClass:      data.TaxClass,
+		WeightGrams:   data.WeightGrams,
+		Version:       data.Version,
+		DeletedAt:     nullTimestamp(data.DeletedAt),
+	}
+}
@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	domain "ecommerce_management/internal/domain/user"
+	"ecommerce_management/internal/repository/postgres"
+	usersvc "ecommerce_management/internal/service/user"
+	ecommercev1 "ecommerce_management/pkg/api/ecommerce/v1"
+)
+
+type UserServer struct {
+	ecommercev1.UnimplementedUserServiceServer
+	users *usersvc.Service
+}
+
+func NewUserServer(users *usersvc.Service) *UserServer {
+	return &UserServer{
+		users: users,
+	}
+}
+
+func (s *UserServer) ListUsers(ctx context.Context, req *ecommercev1.ListUsersRequest) (*ecommercev1.ListUsersResponse, error) {
+	if err := includeDeleted(ctx, "users", req.GetIncludeDeleted()); err != nil {
+		return nil, err
+	}
+
+	page, err := s.users.List(ctx, postgres.ListUsersPageParams{
+		PageParams:     pageParams(req.GetPage()),
+		Name:           nullString(req.GetName()),
+		Email:          nullString(req.GetEmail()),
+		Role:           nullString(req.GetRole()),
+		IncludeDeleted: req.GetIncludeDeleted(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resp := &ecommercev1.ListUsersResponse{Page: pageInfo(page)}
+	for _, data := range page.Items {
+		resp.Users = append(resp.Users, userMessage(data))
+	}
+	return resp, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *ecommercev1.GetUserRequest) (*ecommercev1.User, error) {
+	if err := includeDeleted(ctx, "users", req.GetIncludeDeleted()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.users.Get(ctx, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return userMessage(data), nil
+}
+
+func (s *UserServer) CreateUser(ctx context.Context, req *ecommercev1.CreateUserRequest) (*ecommercev1.User, error) {
+	data, err := s.users.Create(ctx, domain.CreateUserRequest{
+		FullName: req.GetFullName(),
+		Email:    req.GetEmail(),
+		Address:  req.GetAddress(),
+		Role:     req.GetRole(),
+		Password: req.GetPassword(),
+		Locale:   req.GetLocale(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return userMessage(data), nil
+}
+
+func (s *UserServer) UpdateUser(ctx context.Context, req *ecommercev1.UpdateUserRequest) (*ecommercev1.User, error) {
+	previous, err := s.users.Get(ctx, req.GetId(), false)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	if err = checkVersion("user", previous.ID, previous.Version, req.GetVersion()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.users.Update(ctx, previous, postgres.UpdateUserParams{
+		FullName: req.GetFullName(),
+		Email:    req.GetEmail(),
+		Address:  req.GetAddress(),
+		Role:     req.GetRole(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return userMessage(data), nil
+}
+
+func (s *UserServer) DeleteUser(ctx context.Context, req *ecommercev1.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := s.users.Delete(ctx, req.GetId()); err != nil {
+		return nil, serviceError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServer) RestoreUser(ctx context.Context, req *ecommercev1.RestoreUserRequest) (*ecommercev1.User, error) {
+	data, err := s.users.Restore(ctx, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return userMessage(data), nil
+}
+
+func userMessage(data postgres.User) *ecommercev1.User {
+	return &ecommercev1.User{
+		Id:               data.ID,
+		FullName:         data.FullName,
+		Email:            data.Email,
+		Address:          data.Address,
+		RegistrationDate: timestamp(data.RegistrationDate),
+		Role:             data.Role,
+		EmailVerifiedAt:  nullTimestamp(data.EmailVerifiedAt),
+		Locale:           data.Locale,
+		Version:          data.Version,
+		DeletedAt:        nullTimestamp(data.DeletedAt),
+	}
+}
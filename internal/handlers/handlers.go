@@ -1,27 +1,68 @@
 package handlers
 
 import (
-	"database/sql"
+	nethttp "net/http"
 	"os"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
 	"github.com/hellofresh/health-go/v5"
 	healthPg "github.com/hellofresh/health-go/v5/checks/postgres"
 	httpSwagger "github.com/swaggo/http-swagger"
+	googlegrpc "google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
 	"ecommerce_management/docs"
 	"ecommerce_management/internal/config"
+	"ecommerce_management/internal/handlers/graphql"
+	"ecommerce_management/internal/handlers/grpc"
 	"ecommerce_management/internal/handlers/http"
 	"ecommerce_management/internal/provider/epay"
+	"ecommerce_management/internal/provider/mail"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/internal/service/catalog"
+	"ecommerce_management/internal/service/idempotency"
 	"ecommerce_management/internal/service/kafka"
+	"ecommerce_management/internal/service/media"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/order"
+	"ecommerce_management/internal/service/payment"
+	"ecommerce_management/internal/service/promotion"
+	"ecommerce_management/internal/service/returns"
+	"ecommerce_management/internal/service/shipping"
+	"ecommerce_management/internal/service/tax"
+	"ecommerce_management/internal/service/user"
+	"ecommerce_management/internal/service/webhook"
+	ecommercev1 "ecommerce_management/pkg/api/ecommerce/v1"
 )
 
 type Dependencies struct {
-	DB           *sql.DB
+	Repository   repository.Store
 	Configs      config.Config
 	EpayClient   *epay.Client
 	KafkaService kafka.KafkaService
+	Mailer       mail.Sender
+	Notification *notification.Service
+	Webhook      *webhook.Service
+	Promotion    *promotion.Service
+	Tax          *tax.Service
+	Shipping     *shipping.Service
+	Returns      *returns.Service
+	Catalog      *catalog.Service
+	Orders       *order.Service
+	Payments     *payment.Service
+	Users        *user.Service
+	Auth         *auth.Service
+	Media        *media.Service
+	Idempotency  *idempotency.Service
+	Audit        *audit.Service
+	// MediaFiles serves uploaded media below /media; nil when a remote blob store serves them
+	MediaFiles nethttp.Handler
 }
 
 // Configuration is an alias for a function that modifies the Handler
@@ -31,6 +72,7 @@ type Configuration func(h *Handler) error
 type Handler struct {
 	dependencies Dependencies
 	HTTP         *chi.Mux
+	GRPC         *googlegrpc.Server
 }
 
 // New creates a new Handler
@@ -64,43 +106,78 @@ func WithHTTPHandler() Configuration {
 		docs.SwaggerInfo.BasePath = h.dependencies.Configs.BaseURL
 		h.HTTP.Get("/swagger/*", httpSwagger.WrapHandler)
 
-		// Creating new Kafka service with credentials struct
-		kafkaCredentials := kafka.Credentials{
-			KafkaURL:       h.dependencies.Configs.KafkaURL,
-			KafkaUsername:  h.dependencies.Configs.KafkaUsername,
-			KafkaPassword:  h.dependencies.Configs.KafkaPassword,
-			SchemaURL: h.dependencies.Configs.SchemaURL,
+		authService := h.dependencies.Auth
+
+		bearerServer := oauth.NewBearerServer(
+			h.dependencies.Configs.TokenSymmetricKey,
+			h.dependencies.Configs.AccessTokenDuration,
+			authService,
+			nil)
+		h.HTTP.Post("/oauth/token", bearerServer.UserCredentials)
+
+		// Uploaded media is public, like the storefront pages that embed it
+		if h.dependencies.MediaFiles != nil {
+			h.HTTP.Handle("/media/*", nethttp.StripPrefix("/media", h.dependencies.MediaFiles))
 		}
-		kafkaService := kafka.NewKafkaService(kafkaCredentials)
 
 		// Init service handlers
-		userHandler := http.NewUserHandler(h.dependencies.DB, kafkaService)
-		productHandler := http.NewProductHandler(h.dependencies.DB)
-		orderHandler := http.NewOrderHandler(h.dependencies.DB)
-		paymentHandler := http.NewPaymentsHandler(h.dependencies.DB, h.dependencies.EpayClient)
+		userHandler := http.NewUserHandler(h.dependencies.Users)
+		productHandler := http.NewProductHandler(h.dependencies.Catalog, h.dependencies.Media)
+		categoryHandler := http.NewCategoryHandler(h.dependencies.Catalog)
+		orderHandler := http.NewOrderHandler(h.dependencies.Orders, h.dependencies.Returns, h.dependencies.Shipping)
+		paymentHandler := http.NewPaymentsHandler(h.dependencies.Payments)
+		webhookHandler := http.NewWebhookHandler(h.dependencies.Webhook)
+		promotionHandler := http.NewPromotionHandler(h.dependencies.Promotion)
+		taxRateHandler := http.NewTaxRateHandler(h.dependencies.Tax)
+		shippingMethodHandler := http.NewShippingMethodHandler(h.dependencies.Shipping)
+		shipmentHandler := http.NewShipmentHandler(h.dependencies.Shipping)
+		returnHandler := http.NewReturnHandler(h.dependencies.Returns)
+		auditHandler := http.NewAuditHandler(h.dependencies.Audit)
+
+		graphqlHandler, err := graphql.NewHandler(h.dependencies.Repository, h.dependencies.Users, h.dependencies.Catalog, h.dependencies.Orders)
+		if err != nil {
+			return err
+		}
 
 		h.HTTP.Route("/", func(r chi.Router) {
-			r.Mount("/users", userHandler.Routes())
-			r.Mount("/products", productHandler.Routes())
-			r.Mount("/orders", orderHandler.Routes())
-
-			r.Mount("/payments", paymentHandler.Routes())
+			r.Use(authService.Authenticate)
+			r.Use(h.dependencies.Idempotency.Middleware)
+			r.Use(h.dependencies.Audit.Middleware)
+
+			r.With(auth.RequirePermission("users")).Mount("/users", userHandler.Routes())
+			r.With(auth.RequirePermission("products")).Mount("/products", productHandler.Routes())
+			r.With(auth.RequirePermission("products")).Mount("/categories", categoryHandler.Routes())
+			r.With(auth.RequirePermission("products")).Mount("/tax-rates", taxRateHandler.Routes())
+			r.With(auth.RequirePermission("orders")).Mount("/orders", orderHandler.Routes())
+			r.With(auth.RequirePermission("orders")).Mount("/shipments", shipmentHandler.Routes())
+			r.With(auth.RequirePermission("orders")).Mount("/shipping-methods", shippingMethodHandler.Routes())
+			r.With(auth.RequirePermission("orders")).Mount("/returns", returnHandler.Routes())
+
+			r.With(auth.RequirePermission("payments")).Mount("/payments", paymentHandler.Routes())
+			r.With(auth.RequirePermission("webhooks")).Mount("/webhooks", webhookHandler.Routes())
+			r.With(auth.RequirePermission("promotions")).Mount("/promotions", promotionHandler.Routes())
+			r.With(auth.RequirePermission("audit")).Mount("/audit", auditHandler.Routes())
+
+			// Every field of the GraphQL schema checks the permission of its own resource
+			r.Method(nethttp.MethodPost, "/graphql", graphqlHandler)
 		})
 
-		// Setting up health checks
-		healthHandler, _ := health.New(health.WithComponent(health.Component{
-			Name:    "ecommerce-management-service",
-			Version: "v1.0",
-		}), health.WithChecks(
-			health.Config{
+		// Setting up health checks; the memory store has no server to check
+		checks := []health.Config{}
+		if h.dependencies.Configs.DBDriver != repository.DriverMemory {
+			checks = append(checks, health.Config{
 				Name:      "postgres",
 				Timeout:   time.Second * 10,
 				SkipOnErr: false,
 				Check: healthPg.New(healthPg.Config{
 					DSN: os.Getenv("DB_SOURCE"),
 				}),
-			},
-		))
+			})
+		}
+		healthHandler, _ := health.New(health.WithComponent(health.Component{
+			Name:    "ecommerce-management-service",
+			Version: "v1.0",
+		}), health.WithChecks(checks...))
 
 		// Registering health check endpoint
 		h.HTTP.Get("/status", healthHandler.HandlerFunc)
@@ -108,3 +185,32 @@ func WithHTTPHandler() Configuration {
 		return nil
 	}
 }
+
+// WithGRPCHandler applies a gRPC server serving the users, products, orders and payments
+// through the same domain services as the HTTP API, along with the health and reflection services
+func WithGRPCHandler() Configuration {
+	return func(h *Handler) error {
+		h.GRPC = googlegrpc.NewServer(
+			googlegrpc.ChainUnaryInterceptor(
+				grpc.Logging(),
+				grpc.Authentication(h.dependencies.Auth),
+				h.dependencies.Audit.UnaryInterceptor()))
+
+		ecommercev1.RegisterUserServiceServer(h.GRPC, grpc.NewUserServer(h.dependencies.Users))
+		ecommercev1.RegisterProductServiceServer(h.GRPC, grpc.NewProductServer(h.dependencies.Catalog))
+		ecommercev1.RegisterOrderServiceServer(h.GRPC, grpc.NewOrderServer(h.dependencies.Orders))
+		ecommercev1.RegisterPaymentServiceServer(h.GRPC, grpc.NewPaymentServer(h.dependencies.Payments))
+
+		// Setting up health checks; the empty service name reports on the server as a whole
+		healthServer := grpchealth.NewServer()
+		for service := range h.GRPC.GetServiceInfo() {
+			healthServer.SetServingStatus(service, grpc_health_v1.HealthCheckResponse_SERVING)
+		}
+		grpc_health_v1.RegisterHealthServer(h.GRPC, healthServer)
+
+		// Let clients such as grpcurl discover the services
+		reflection.Register(h.GRPC)
+
+		return nil
+	}
+}
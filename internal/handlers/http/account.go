@@ -0,0 +1,108 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"ecommerce_management/internal/domain/user"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary Resend the email verification link
+// @Description Always succeeds so that registered addresses cannot be discovered.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body user.EmailRequest true "Email address"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/verify-email/request [post]
+func (h *UsersHandler) requestEmailVerification(w http.ResponseWriter, r *http.Request) {
+	var req user.EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := h.users.RequestEmailVerification(r.Context(), req.Email); err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// @Summary Confirm an email address
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body user.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} postgres.User
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/verify-email/confirm [post]
+func (h *UsersHandler) confirmEmailVerification(w http.ResponseWriter, r *http.Request) {
+	var req user.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.users.ConfirmEmail(r.Context(), req.Token)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, data)
+}
+
+// @Summary Request a password reset email
+// @Description Always succeeds so that registered addresses cannot be discovered.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body user.EmailRequest true "Email address"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/password-reset/request [post]
+func (h *UsersHandler) requestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req user.EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := h.users.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// @Summary Set a new password
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body user.ResetPasswordRequest true "Reset token and new password"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/password-reset/confirm [post]
+func (h *UsersHandler) confirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req user.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.users.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
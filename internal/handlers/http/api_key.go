@@ -0,0 +1,150 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/apikey"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary List API keys of a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {array} apikey.APIKey
+// @Failure 400 {object} response.Object
+// @Failure 401 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/api-keys [get]
+func (h *UsersHandler) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if !h.authorizeAPIKeys(w, r, userID, nil) {
+		return
+	}
+
+	keys, err := h.users.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, apikey.ParseFromList(keys))
+}
+
+// @Summary Create an API key for a user
+// @Description The returned key is shown only once; store it securely.
+// @Description A request made with an API key may only create keys for its own user, unless that user is an admin, and only with permissions it has itself.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body apikey.CreateAPIKeyRequest true "API key details"
+// @Success 200 {object} apikey.CreateAPIKeyResponse
+// @Failure 400 {object} response.Object
+// @Failure 401 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/api-keys [post]
+func (h *UsersHandler) addAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req apikey.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if !h.authorizeAPIKeys(w, r, userID, req.Permissions) {
+		return
+	}
+
+	stored, token, err := h.users.CreateAPIKey(r.Context(), userID, req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, apikey.CreateAPIKeyResponse{
+		APIKey: apikey.ParseFrom(stored),
+		Key:    token,
+	})
+}
+
+// @Summary Revoke an API key of a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param keyID path int true "API key ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 401 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/api-keys/{keyID} [delete]
+func (h *UsersHandler) revokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	keyID, err := strconv.ParseInt(chi.URLParam(r, "keyID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if !h.authorizeAPIKeys(w, r, userID, nil) {
+		return
+	}
+
+	if err := h.users.RevokeAPIKey(r.Context(), userID, keyID); err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// authorizeAPIKeys checks that a request made with an API key may manage the keys of userID and grant them permissions.
+// Requests with a bearer token are made by the OAuth client and may manage every key; anonymous requests may manage none.
+func (h *UsersHandler) authorizeAPIKeys(w http.ResponseWriter, r *http.Request, userID int64, permissions []string) bool {
+	p, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		if !auth.Authenticated(r.Context()) {
+			response.Unauthorized(w, r, auth.ErrUnauthenticated)
+			return false
+		}
+		return true
+	}
+
+	if err := h.users.AuthorizeAPIKeys(r.Context(), p, userID, permissions); err != nil {
+		if errors.Is(err, auth.ErrForbiddenAPIKey) {
+			response.Forbidden(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/pkg/server/response"
+)
+
+type AuditHandler struct {
+	audit *audit.Service
+}
+
+func NewAuditHandler(audit *audit.Service) *AuditHandler {
+	return &AuditHandler{
+		audit: audit,
+	}
+}
+
+func (h *AuditHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Get("/{id}", h.get)
+
+	return r
+}
+
+// @Summary List audit events
+// @Description Changes made through the API to users, products, orders and payments, newest first. before and after hold the fields that changed. Keyset paginated; filters combine.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param sort query string false "created_at or id; prefix with - for descending (default -created_at)"
+// @Param actor_type query string false "api_key, user or anonymous"
+// @Param actor_id query string false "API key ID or user email"
+// @Param action query string false "create, update, delete or restore"
+// @Param entity_type query string false "user, product, order or payment"
+// @Param entity_id query int false "Entity ID"
+// @Param request_id query string false "Request ID"
+// @Param from query string false "Recorded at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Recorded before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {array} postgres.AuditEvent
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /audit [get]
+func (h *AuditHandler) list(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePage(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	req := postgres.ListAuditEventsPageParams{
+		PageParams: page,
+		ActorType:  queryString(r, "actor_type"),
+		ActorID:    queryString(r, "actor_id"),
+		Action:     queryString(r, "action"),
+		EntityType: queryString(r, "entity_type"),
+		RequestID:  queryString(r, "request_id"),
+	}
+	if req.Action.Valid && !slices.Contains(audit.Actions, req.Action.String) {
+		response.BadRequest(w, r, fmt.Errorf("invalid action: %s", req.Action.String), nil)
+		return
+	}
+	if req.EntityType.Valid && !slices.Contains(audit.Entities, req.EntityType.String) {
+		response.BadRequest(w, r, fmt.Errorf("invalid entity_type: %s", req.EntityType.String), nil)
+		return
+	}
+	if req.EntityID, err = queryInt64(r, "entity_id"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.From, err = queryTime(r, "from"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.To, err = queryTime(r, "to"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	events, err := h.audit.List(r.Context(), req)
+	if err != nil {
+		listError(w, r, err)
+		return
+	}
+	response.Page(w, r, events.Items, pagination(events))
+}
+
+// @Summary Get an audit event by ID
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param id path int true "Audit event ID"
+// @Success 200 {object} postgres.AuditEvent
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /audit/{id} [get]
+func (h *AuditHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	event, err := h.audit.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, audit.ErrNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, event)
+}
@@ -0,0 +1,240 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/category"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/catalog"
+	"ecommerce_management/pkg/server/response"
+)
+
+type CategoriesHandler struct {
+	catalog *catalog.Service
+}
+
+func NewCategoryHandler(catalog *catalog.Service) *CategoriesHandler {
+	return &CategoriesHandler{
+		catalog: catalog,
+	}
+}
+
+func (h *CategoriesHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+	r.Get("/tree", h.tree)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+		r.Get("/products", h.listProducts)
+	})
+
+	return r
+}
+
+// @Summary List all categories
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {array} category.Category
+// @Failure 500 {object} response.Object
+// @Router /categories [get]
+func (h *CategoriesHandler) list(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.catalog.ListCategories(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+	response.OK(w, r, category.ParseFromList(categories))
+}
+
+// @Summary Get the category tree
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {array} category.Node
+// @Failure 500 {object} response.Object
+// @Router /categories/tree [get]
+func (h *CategoriesHandler) tree(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.catalog.ListCategories(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+	response.OK(w, r, category.BuildTree(categories))
+}
+
+// @Summary Create a new category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param request body category.Request true "Category details"
+// @Success 200 {object} category.Category
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /categories [post]
+func (h *CategoriesHandler) add(w http.ResponseWriter, r *http.Request) {
+	var req category.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.catalog.CreateCategory(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, catalog.ErrInvalidCategory) {
+			response.BadRequest(w, r, err, req)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, category.ParseFrom(data))
+}
+
+// @Summary Get a category by ID
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} category.Category
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /categories/{id} [get]
+func (h *CategoriesHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	data, err := h.catalog.GetCategory(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, catalog.ErrCategoryNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, category.ParseFrom(data))
+}
+
+// @Summary Update a category by ID
+// @Description Moving a category under one of its own descendants is rejected.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param request body category.Request true "Category details"
+// @Success 200 {object} category.Category
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /categories/{id} [put]
+func (h *CategoriesHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req category.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.catalog.UpdateCategory(r.Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, catalog.ErrCategoryNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, catalog.ErrInvalidCategory):
+			response.BadRequest(w, r, err, req)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, category.ParseFrom(data))
+}
+
+// @Summary Delete a category by ID
+// @Description Only empty categories without subcategories can be deleted.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /categories/{id} [delete]
+func (h *CategoriesHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.catalog.DeleteCategory(r.Context(), id); err != nil {
+		if errors.Is(err, catalog.ErrInvalidCategory) {
+			response.BadRequest(w, r, err, nil)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// @Summary List products of a category and its subcategories
+// @Description Keyset paginated like GET /products, which accepts the same sort and filters.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param sort query string false "addition_date, name, price, stock_quantity or id; prefix with - for descending"
+// @Success 200 {array} postgres.Product
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /categories/{id}/products [get]
+func (h *CategoriesHandler) listProducts(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	page, err := parsePage(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	products, err := h.catalog.List(r.Context(), postgres.ListProductsPageParams{
+		PageParams: page,
+		CategoryID: sql.NullInt64{Int64: id, Valid: true},
+	})
+	if err != nil {
+		listError(w, r, err)
+		return
+	}
+	response.Page(w, r, products.Items, pagination(products))
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/internal/service/catalog"
+	ordersvc "ecommerce_management/internal/service/order"
+	paymentsvc "ecommerce_management/internal/service/payment"
+	"ecommerce_management/internal/service/promotion"
+	"ecommerce_management/internal/service/shipping"
+	"ecommerce_management/internal/service/tax"
+	usersvc "ecommerce_management/internal/service/user"
+	"ecommerce_management/internal/service/webhook"
+	"ecommerce_management/pkg/server/response"
+)
+
+var (
+	// notFound are the errors of records that do not exist or have been deleted
+	notFound = []error{
+		ordersvc.ErrNotFound,
+		paymentsvc.ErrNotFound,
+		paymentsvc.ErrOrderNotFound,
+		catalog.ErrNotFound,
+		catalog.ErrVariantNotFound,
+		usersvc.ErrNotFound,
+		usersvc.ErrAPIKeyNotFound,
+		tax.ErrRateNotFound,
+		promotion.ErrNotFound,
+		webhook.ErrSubscriptionNotFound,
+		webhook.ErrDeliveryNotFound,
+	}
+
+	// versionChanged are the errors of updates whose If-Match no longer holds
+	versionChanged = []error{
+		ordersvc.ErrVersionChanged,
+		paymentsvc.ErrVersionChanged,
+		catalog.ErrVersionChanged,
+		usersvc.ErrVersionChanged,
+	}
+
+	// conflicts are the errors of changes the current state of a record does not allow
+	conflicts = []error{
+		ordersvc.ErrConflict,
+		ordersvc.ErrClosed,
+		paymentsvc.ErrConflict,
+		catalog.ErrConflict,
+		usersvc.ErrConflict,
+	}
+
+	// invalid are the errors caused by the request
+	invalid = []error{
+		postgres.ErrInvalidSort,
+		postgres.ErrInvalidCursor,
+		ordersvc.ErrInvalidItem,
+		ordersvc.ErrInvalidAddress,
+		ordersvc.ErrInsufficientStock,
+		paymentsvc.ErrInvalidAmount,
+		catalog.ErrInvalidProduct,
+		catalog.ErrInvalidVariant,
+		usersvc.ErrInvalidAPIKey,
+		auth.ErrInvalidToken,
+		auth.ErrWeakPassword,
+		promotion.ErrCouponNotFound,
+		promotion.ErrNotApplicable,
+		promotion.ErrInvalidPromotion,
+		shipping.ErrNotAvailable,
+		tax.ErrInvalidCountry,
+		tax.ErrInvalidRate,
+		webhook.ErrInvalidSubscription,
+		webhook.ErrUnknownEvent,
+		webhook.ErrNotReplayable,
+	}
+)
+
+// serviceError writes the response for an error returned by a domain service
+func serviceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case isAny(err, notFound):
+		response.NotFound(w, r, err)
+	case isAny(err, versionChanged):
+		response.PreconditionFailed(w, r, errVersionChanged)
+	case isAny(err, conflicts):
+		response.Conflict(w, r, err)
+	case isAny(err, invalid):
+		response.BadRequest(w, r, err, nil)
+	default:
+		response.InternalServerError(w, r, err)
+	}
+}
+
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,7 +3,6 @@ package http
 import (
 	"database/sql"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -11,16 +10,23 @@ import (
 	"github.com/go-chi/chi/v5"
 	"ecommerce_management/internal/repository/postgres"
 	"ecommerce_management/internal/domain/order"
+	ordersvc "ecommerce_management/internal/service/order"
+	returnsvc "ecommerce_management/internal/service/returns"
+	shippingsvc "ecommerce_management/internal/service/shipping"
 	"ecommerce_management/pkg/server/response"
 )
 
 type OrdersHandler struct {
-	store *postgres.Store
+	orders   *ordersvc.Service
+	returns  *returnsvc.Service
+	shipping *shippingsvc.Service
 }
 
-func NewOrderHandler(db *sql.DB) *OrdersHandler {
+func NewOrderHandler(orders *ordersvc.Service, returns *returnsvc.Service, shipping *shippingsvc.Service) *OrdersHandler {
 	return &OrdersHandler{
-		store: postgres.NewStore(db),
+		orders:   orders,
+		returns:  returns,
+		shipping: shipping,
 	}
 }
 
@@ -35,29 +41,86 @@ func (h *OrdersHandler) Routes() chi.Router {
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.update)
 		r.Delete("/", h.delete)
+		r.Post("/restore", h.restore)
+		r.Get("/items", h.listItems)
+		r.Post("/items", h.addItem)
+		r.Put("/items/{itemID}", h.updateItem)
+		r.Delete("/items/{itemID}", h.deleteItem)
+		r.Get("/adjustments", h.listAdjustments)
+		r.Get("/invoice", h.invoice)
+		r.Get("/addresses", h.listAddresses)
+		r.Get("/shipments", h.listShipments)
+		r.Post("/shipments", h.addShipment)
+		r.Get("/returns", h.listReturns)
+		r.Post("/returns", h.addReturn)
 	})
 
 	return r
 }
 
 // @Summary List all orders
+// @Description Keyset paginated; pass pagination.next_cursor as cursor to fetch the next page. Filters combine.
 // @Tags orders
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param sort query string false "order_date, total_amount or id; prefix with - for descending"
+// @Param user_id query int false "User ID"
+// @Param status query string false "Order status"
+// @Param from query string false "Ordered at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Ordered before (RFC 3339 or YYYY-MM-DD)"
+// @Param include_deleted query bool false "Include deleted orders; API keys need orders:write"
 // @Success 200 {array} postgres.Order
+// @Failure 400 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /orders [get]
 func (h *OrdersHandler) list(w http.ResponseWriter, r *http.Request) {
-	orders, err := h.store.ListOrders(r.Context())
+	page, err := parsePage(r)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		response.BadRequest(w, r, err, nil)
 		return
 	}
-	response.OK(w, r, orders)
+
+	req := postgres.ListOrdersPageParams{PageParams: page}
+	if status := postgres.OrderStatus(r.URL.Query().Get("status")); status != "" {
+		if !status.Valid() {
+			response.BadRequest(w, r, fmt.Errorf("invalid status: %s", status), nil)
+			return
+		}
+		req.Status = postgres.NullOrderStatus{OrderStatus: status, Valid: true}
+	}
+	if req.UserID, err = queryInt64(r, "user_id"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.From, err = queryTime(r, "from"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.To, err = queryTime(r, "to"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var ok bool
+	if req.IncludeDeleted, ok = includeDeleted(w, r, "orders"); !ok {
+		return
+	}
+
+	orders, err := h.orders.List(r.Context(), req)
+	if err != nil {
+		listError(w, r, err)
+		return
+	}
+	response.Page(w, r, orders.Items, pagination(orders))
 }
 
 // @Summary Create a new order
+// @Description Items are taxed at the rates of the destination country; see /tax-rates. shipping_method_id adds the shipping cost; see /shipping-methods.
+// @Description The user's default addresses are used unless shipping_address_id or billing_address_id is given, and are copied onto the order; see /orders/{id}/addresses.
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -73,201 +136,194 @@ func (h *OrdersHandler) add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tx, err := h.store.BeginTx(r.Context(), nil)
+	data, err := h.orders.Create(r.Context(), req)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		serviceError(w, r, err)
 		return
 	}
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p) // re-throw panic after Rollback
-		} else if err != nil {
-			tx.Rollback() // err is non-nil; don't change it
-		} else {
-			err = tx.Commit() // err is nil; if Commit returns error update err
-		}
-	}()
 
-	// Create the order first with a placeholder total amount ("0.00")
-	order, err := tx.CreateOrder(r.Context(), postgres.CreateOrderParams{
-		UserID:      req.UserID,
-		TotalAmount: "0.00", // Dummy value, will be updated later
-	})
+	response.OK(w, r, data)
+}
+
+// @Summary Get an order by ID
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param include_deleted query bool false "Return the order even if it was deleted; API keys need orders:write"
+// @Success 200 {object} postgres.Order
+// @Header 200 {string} ETag "Version of the order, sent back in If-Match to update it"
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id} [get]
+func (h *OrdersHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	var totalAmount float64
-	for _, item := range req.Items {
-		// Fetch the product
-		product, err := tx.GetProduct(r.Context(), item.ProductID)
-		if err != nil {
-			return
-		}
+	include, ok := includeDeleted(w, r, "orders")
+	if !ok {
+		return
+	}
 
-		// Check stock quantity
-		if product.StockQuantity < item.Quantity {
-			response.BadRequest(w, r, fmt.Errorf("insufficient stock for product ID %d", item.ProductID), nil)
-			return
-		}
+	data, err := h.orders.Get(r.Context(), id, include)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
 
-		// Convert product.Price to float64
-		productPrice, err := strconv.ParseFloat(product.Price, 64)
-		if err != nil {
-			response.InternalServerError(w, r, fmt.Errorf("invalid product price format: %v", err))
-			return
-		}
+	setETag(w, data.Version)
+	response.OK(w, r, data)
+}
 
-		// Calculate item price
-		itemPrice := productPrice * float64(item.Quantity)
-		totalAmount += itemPrice
-
-		// Format itemPrice to string with 2 decimal places
-		itemPriceStr := fmt.Sprintf("%.2f", itemPrice)
-
-		_, err = tx.CreateOrderItem(r.Context(), postgres.CreateOrderItemParams{
-			OrderID:   order.ID, // Use the ID of the newly created order
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     itemPriceStr, // Use the formatted string
-		})
-		if err != nil {
-			response.InternalServerError(w, r, err)
-			return
-		}
+// @Summary Update an order by ID
+// @Description PUT replaces the order and PATCH applies a JSON Merge Patch. If-Match must carry the ETag of the order.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param If-Match header string true "ETag of the order"
+// @Param request body postgres.UpdateOrderParams true "Order details"
+// @Success 200 {object} postgres.Order
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 412 {object} response.Object
+// @Failure 428 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id} [put]
+// @Router /orders/{id} [patch]
+func (h *OrdersHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
 
-		_, err = tx.UpdateProductStock(r.Context(), postgres.UpdateProductStockParams{
-			StockQuantity: item.Quantity,
-			ID:            item.ProductID,
-		})
-		if err != nil {
-			response.InternalServerError(w, r, err)
-			return
-		}
+	previous, err := h.orders.Get(r.Context(), id, false)
+	if err != nil {
+		serviceError(w, r, err)
+		return
 	}
 
-	// Format totalAmount to have at most 2 decimal places
-	totalAmountStr := fmt.Sprintf("%.2f", totalAmount)
+	if !ifMatch(w, r, previous.Version) {
+		return
+	}
 
-	// Update the order with the final totalAmount
-	_, err = tx.UpdateOrder(r.Context(), postgres.UpdateOrderParams{
-		ID:          order.ID, // Use the ID of the newly created order
-		UserID:      order.UserID,
-		TotalAmount: totalAmountStr, // Use the formatted string
-		Status:      order.Status,
+	req, err := decodeUpdate(r, postgres.UpdateOrderParams{
+		UserID:      previous.UserID,
+		TotalAmount: previous.TotalAmount,
+		Status:      previous.Status,
 	})
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		response.BadRequest(w, r, err, req)
 		return
 	}
 
-	// Re-fetch the updated order to return the correct total amount
-	updatedOrder, err := h.store.GetOrder(r.Context(), order.ID)
+	data, err := h.orders.Update(r.Context(), previous, req)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		serviceError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, updatedOrder)
+	setETag(w, data.Version)
+	response.OK(w, r, data)
 }
 
-// @Summary Get an order by ID
+// @Summary Delete an order by ID
+// @Description The order is hidden but kept, so that it can be restored until the retention period has passed.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
-// @Success 200 {object} postgres.Order
+// @Success 204 {object} response.Object
 // @Failure 404 {object} response.Object
 // @Failure 500 {object} response.Object
-// @Router /orders/{id} [get]
-func (h *OrdersHandler) get(w http.ResponseWriter, r *http.Request) {
+// @Router /orders/{id} [delete]
+func (h *OrdersHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
 		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	order, err := h.store.GetOrder(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+	if err = h.orders.Delete(r.Context(), id); err != nil {
+		serviceError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, order)
+	response.NoContent(w, r)
 }
 
-// @Summary Update an order by ID
+// @Summary Restore a deleted order
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
-// @Param request body postgres.UpdateOrderParams true "Order details"
 // @Success 200 {object} postgres.Order
-// @Failure 400 {object} response.Object
 // @Failure 404 {object} response.Object
 // @Failure 500 {object} response.Object
-// @Router /orders/{id} [put]
-func (h *OrdersHandler) update(w http.ResponseWriter, r *http.Request) {
+// @Router /orders/{id}/restore [post]
+func (h *OrdersHandler) restore(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
 		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	var req postgres.UpdateOrderParams
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, err, req)
-		return
-	}
-
-	req.ID = id
-
-	order, err := h.store.UpdateOrder(r.Context(), req)
+	data, err := h.orders.Restore(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		serviceError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, order)
+	setETag(w, data.Version)
+	response.OK(w, r, data)
 }
 
-// @Summary Delete an order by ID
+// @Summary List items of an order
+// @Description Keyset paginated; pass pagination.next_cursor as cursor to fetch the next page.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
-// @Success 204 {object} response.Object
-// @Failure 404 {object} response.Object
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param sort query string false "id, quantity or price; prefix with - for descending"
+// @Param product_id query int false "Product ID"
+// @Success 200 {array} postgres.OrderItem
+// @Failure 400 {object} response.Object
 // @Failure 500 {object} response.Object
-// @Router /orders/{id} [delete]
-func (h *OrdersHandler) delete(w http.ResponseWriter, r *http.Request) {
+// @Router /orders/{id}/items [get]
+func (h *OrdersHandler) listItems(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
 		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	err = h.store.DeleteOrder(r.Context(), id)
+	page, err := parsePage(r)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	response.NoContent(w, r)
+	req := postgres.ListOrderItemsPageParams{
+		PageParams: page,
+		OrderID:    sql.NullInt64{Int64: id, Valid: true},
+	}
+	if req.ProductID, err = queryInt64(r, "product_id"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	items, err := h.orders.ListItems(r.Context(), req)
+	if err != nil {
+		listError(w, r, err)
+		return
+	}
+	response.Page(w, r, items.Items, pagination(items))
 }
 
 // @Summary Search orders by user ID
@@ -277,6 +333,7 @@ func (h *OrdersHandler) delete(w http.ResponseWriter, r *http.Request) {
 // @Param user_id query int true "User ID"
 // @Success 200 {array} postgres.Order
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /orders/search/user [get]
 func (h *OrdersHandler) searchByUser(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
@@ -285,7 +342,7 @@ func (h *OrdersHandler) searchByUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orders, err := h.store.SearchOrdersByUser(r.Context(), userID)
+	orders, err := h.orders.SearchByUser(r.Context(), userID)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -301,11 +358,12 @@ func (h *OrdersHandler) searchByUser(w http.ResponseWriter, r *http.Request) {
 // @Param status query string true "Order status"
 // @Success 200 {array} postgres.Order
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /orders/search/status [get]
 func (h *OrdersHandler) searchByStatus(w http.ResponseWriter, r *http.Request) {
 	status := r.URL.Query().Get("status")
 
-	orders, err := h.store.SearchOrdersByStatus(r.Context(), postgres.OrderStatus(status))
+	orders, err := h.orders.SearchByStatus(r.Context(), postgres.OrderStatus(status))
 	
 	if err != nil {
 		response.InternalServerError(w, r, err)
@@ -315,3 +373,56 @@ func (h *OrdersHandler) searchByStatus(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, r, orders)
 }
 
+
+// @Summary List adjustments of an order
+// @Description Discounts, tax added to net prices and other lines that explain how the order total differs from the sum of its items.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {array} order.Adjustment
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/adjustments [get]
+func (h *OrdersHandler) listAdjustments(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	adjustments, err := h.orders.Adjustments(r.Context(), id)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, adjustments)
+}
+
+// @Summary Get the invoice of an order
+// @Description Lists the addresses the order was placed with, every line with its tax rate and net, tax and gross amounts after discounts, the totals per rate and the order totals.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} order.Invoice
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/invoice [get]
+func (h *OrdersHandler) invoice(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	invoice, err := h.orders.Invoice(r.Context(), id)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, invoice)
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/address"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary List addresses of an order
+// @Description The shipping and billing addresses as they were when the order was placed.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {array} address.Snapshot
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/addresses [get]
+func (h *OrdersHandler) listAddresses(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	addresses, err := h.orders.Addresses(r.Context(), id)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, address.ParseSnapshots(addresses))
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/order"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary Add an item to an order
+// @Description Only new and processing orders without a payment can be changed. Stock is reserved and the order is priced again with its coupon, shipping method and tax.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body order.OrderItem true "Item details"
+// @Success 200 {object} postgres.Order
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 409 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/items [post]
+func (h *OrdersHandler) addItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req order.OrderItem
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.orders.AddItem(r.Context(), id, req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, data)
+}
+
+// @Summary Change the quantity of an order item
+// @Description Only new and processing orders without a payment can be changed. Stock follows the new quantity and the order is priced again; the item keeps the unit price it was ordered at.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param itemID path int true "Order item ID"
+// @Param request body order.UpdateOrderItemRequest true "Item details"
+// @Success 200 {object} postgres.Order
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 409 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/items/{itemID} [put]
+func (h *OrdersHandler) updateItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, err := orderItemParams(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req order.UpdateOrderItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.orders.UpdateItem(r.Context(), id, itemID, req.Quantity)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, data)
+}
+
+// @Summary Remove an item from an order
+// @Description Only new and processing orders without a payment can be changed. The stock is released and the order is priced again. The last item cannot be removed; delete the order instead.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param itemID path int true "Order item ID"
+// @Success 200 {object} postgres.Order
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 409 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/items/{itemID} [delete]
+func (h *OrdersHandler) deleteItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, err := orderItemParams(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	data, err := h.orders.DeleteItem(r.Context(), id, itemID)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, data)
+}
+
+// orderItemParams reads the order and item IDs of an order item route
+func orderItemParams(r *http.Request) (id, itemID int64, err error) {
+	if id, err = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64); err != nil {
+		return
+	}
+	itemID, err = strconv.ParseInt(chi.URLParam(r, "itemID"), 10, 64)
+	return
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/returns"
+	returnsvc "ecommerce_management/internal/service/returns"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary List returns of an order
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {array} returns.Return
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/returns [get]
+func (h *OrdersHandler) listReturns(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	list, err := h.returns.ListByOrder(r.Context(), id)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, list)
+}
+
+// @Summary Request a return of order items
+// @Description Shipped, delivered and completed orders accept returns. Each line is refunded its share of the item's gross amount after discounts; shipping is not refunded. Move the return through /returns/{id}.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body returns.Request true "Items to return"
+// @Success 200 {object} returns.Return
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/returns [post]
+func (h *OrdersHandler) addReturn(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req returns.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	lines := make([]returnsvc.Line, 0, len(req.Items))
+	for _, item := range req.Items {
+		lines = append(lines, returnsvc.Line{
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	data, items, err := h.returns.Create(r.Context(), id, req.Reason, lines)
+	if err != nil {
+		switch {
+		case errors.Is(err, returnsvc.ErrOrderNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, returnsvc.ErrInvalidReturn):
+			response.BadRequest(w, r, err, req)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, returns.ParseReturn(data, items))
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/shipping"
+	shippingsvc "ecommerce_management/internal/service/shipping"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary List shipments of an order
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {array} shipping.Shipment
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/shipments [get]
+func (h *OrdersHandler) listShipments(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	shipments, err := h.shipping.ListShipments(r.Context(), id)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, shipping.ParseShipments(shipments))
+}
+
+// @Summary Create a shipment for an order
+// @Description An order may be split across several shipments. A new shipment is pending; update its status through /shipments/{id}.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body shipping.ShipmentRequest true "Shipment details"
+// @Success 200 {object} shipping.Shipment
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /orders/{id}/shipments [post]
+func (h *OrdersHandler) addShipment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req shipping.ShipmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	shipment, err := h.shipping.CreateShipment(r.Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, shippingsvc.ErrOrderNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, shippingsvc.ErrInvalidShipment):
+			response.BadRequest(w, r, err, req)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, shipping.ParseShipment(shipment))
+}
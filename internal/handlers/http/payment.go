@@ -1,37 +1,27 @@
 package http
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
-	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"ecommerce_management/internal/domain/payment"
-	"ecommerce_management/internal/provider/epay"
 	"ecommerce_management/internal/repository/postgres"
+	paymentsvc "ecommerce_management/internal/service/payment"
 	"ecommerce_management/pkg/server/response"
 	"fmt"
 
 	"github.com/go-chi/chi/v5"
-	"golang.org/x/exp/rand"
 )
 
 type PaymentsHandler struct {
-	db         *postgres.Queries
-	epayClient *epay.Client
-	shopID     string
-	terminalID string
+	payments *paymentsvc.Service
 }
 
-func NewPaymentsHandler(conn *sql.DB, epayClient *epay.Client) *PaymentsHandler {
+func NewPaymentsHandler(payments *paymentsvc.Service) *PaymentsHandler {
 	return &PaymentsHandler{
-		db:         postgres.New(conn),
-		epayClient: epayClient,
-		shopID:     epayClient.Credentials.ShopID,
-		terminalID: epayClient.Credentials.TerminalID,
+		payments: payments,
 	}
 }
 
@@ -42,7 +32,9 @@ func (h *PaymentsHandler) Routes() chi.Router {
 	r.Post("/", h.add)
 	r.Get("/{id}", h.get)
 	r.Put("/{id}", h.update)
+	r.Patch("/{id}", h.update)
 	r.Delete("/{id}", h.delete)
+	r.Post("/{id}/restore", h.restore)
 
 	r.Get("/search/user", h.searchByUser)
 	r.Get("/search/order", h.searchByOrder)
@@ -52,159 +44,92 @@ func (h *PaymentsHandler) Routes() chi.Router {
 }
 
 // @Summary List all payments
+// @Description Keyset paginated; pass pagination.next_cursor as cursor to fetch the next page. Filters combine.
 // @Tags payments
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param sort query string false "payment_date, amount or id; prefix with - for descending"
+// @Param user_id query int false "User ID"
+// @Param order_id query int false "Order ID"
+// @Param status query string false "Payment status"
+// @Param from query string false "Paid at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Paid before (RFC 3339 or YYYY-MM-DD)"
+// @Param include_deleted query bool false "Include deleted payments; API keys need payments:write"
 // @Success 200 {array} postgres.Payment
+// @Failure 400 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /payments [get]
 func (h *PaymentsHandler) list(w http.ResponseWriter, r *http.Request) {
-	payments, err := h.db.ListPayments(r.Context())
+	page, err := parsePage(r)
 	if err != nil {
-		response.InternalServerError(w, r, err)
-		return
-	}
-	response.OK(w, r, payments)
-}
-
-func generateInvoiceID() string {
-	rand.Seed(uint64(time.Now().UnixNano())) // Convert int64 to uint64
-	return fmt.Sprintf("%012d", rand.Int63n(1e12))
-}
-
-// @Summary Create a new payment
-// @Tags payments
-// @Accept json
-// @Produce json
-// @Param request body payment.CreatePaymentParams true "Payment details"
-// @Success 200 {object} postgres.Payment
-// @Failure 400 {object} response.Object
-// @Failure 500 {object} response.Object
-// @Router /payments [post]
-func (h *PaymentsHandler) add(w http.ResponseWriter, r *http.Request) {
-	var req payment.CreatePaymentParams
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, err, req)
+		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	order, err := h.db.GetOrder(r.Context(), req.OrderID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			response.NotFound(w, r, fmt.Errorf("order not found"))
+	req := postgres.ListPaymentsPageParams{PageParams: page}
+	if status := postgres.PaymentStatus(r.URL.Query().Get("status")); status != "" {
+		if !status.Valid() {
+			response.BadRequest(w, r, fmt.Errorf("invalid status: %s", status), nil)
 			return
 		}
-		response.InternalServerError(w, r, err)
-		return
+		req.Status = postgres.NullPaymentStatus{PaymentStatus: status, Valid: true}
 	}
-
-	amount, err := strconv.ParseInt(order.TotalAmount, 10, 64)
-	if err != nil {
-		response.BadRequest(w, r, fmt.Errorf("invalid amount format"), req)
+	if req.UserID, err = queryInt64(r, "user_id"); err != nil {
+		response.BadRequest(w, r, err, nil)
 		return
 	}
-
-	user, err := h.db.GetUser(r.Context(), order.UserID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			response.NotFound(w, r, fmt.Errorf("user not found"))
-			return
-		}
-		response.InternalServerError(w, r, err)
+	if req.OrderID, err = queryInt64(r, "order_id"); err != nil {
+		response.BadRequest(w, r, err, nil)
 		return
 	}
-
-	payment, err := h.db.CreatePayment(r.Context(), postgres.CreatePaymentParams{
-		UserID:  order.UserID,
-		OrderID: req.OrderID,
-		Amount:  order.TotalAmount,
-		Status:  postgres.PaymentStatusUnsuccessful,
-	})
-	if err != nil {
-		response.InternalServerError(w, r, err)
+	if req.From, err = queryTime(r, "from"); err != nil {
+		response.BadRequest(w, r, err, nil)
 		return
 	}
-
-	invoiceID := generateInvoiceID()
-
-	src := epay.PaymentRequest{
-		Amount:    order.TotalAmount,
-		Currency:  "KZT",
-		InvoiceID: invoiceID,
-	}
-
-	token, err := h.epayClient.GetPaymentToken(r.Context(), &src)
-	if err != nil {
-		response.InternalServerError(w, r, err)
+	if req.To, err = queryTime(r, "to"); err != nil {
+		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	cryptogram := epay.Cryptogram{
-		HPAN:       req.HPAN,
-		ExpDate:    req.ExpDate,
-		CVC:        req.CVC,
-		TerminalID: h.terminalID,
-	}
-
-	jsonData, err := json.Marshal(cryptogram)
-	if err != nil {
-		log.Printf("Error marshaling cryptogram to JSON: %v", err)
-		response.InternalServerError(w, r, err)
+	var ok bool
+	if req.IncludeDeleted, ok = includeDeleted(w, r, "payments"); !ok {
 		return
 	}
 
-	cryptogramString, err := epay.EncryptWithPublicKey(jsonData, epay.PublicKeyPEM)
+	payments, err := h.payments.List(r.Context(), req)
 	if err != nil {
-		log.Printf("Error encrypting data: %v", err)
-		response.InternalServerError(w, r, err)
+		listError(w, r, err)
 		return
 	}
+	response.Page(w, r, payments.Items, pagination(payments))
+}
 
-	invoiceReq := epay.CreateInvoiceRequest{
-		Amount:      amount,
-		Currency:    "KZT",
-		Name:        user.FullName,
-		Cryptogram:  cryptogramString,
-		Email:       user.Email,
-		InvoiceID:   invoiceID,
-		Description: "Payment for Order " + fmt.Sprint(req.OrderID),
-		CardSave:    false,
-		PostLink:    "https://testmerchant/order/" + fmt.Sprint(req.OrderID),
-	}
+// @Summary Create a new payment
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param request body payment.CreatePaymentParams true "Payment details"
+// @Success 200 {object} postgres.Payment
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /payments [post]
+func (h *PaymentsHandler) add(w http.ResponseWriter, r *http.Request) {
+	var req payment.CreatePaymentParams
 
-	invoiceResp, err := h.epayClient.CreateInvoice(r.Context(), token.AccessToken, invoiceReq)
-	if err != nil {
-		log.Printf("Error creating invoice: %v", err)
-		response.InternalServerError(w, r, err)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
 		return
 	}
 
-	// Update the payment status based on the response from the invoice creation
-	var status postgres.PaymentStatus
-	if invoiceResp.Success {
-		status = postgres.PaymentStatusSuccessful
-	} else {
-		status = postgres.PaymentStatusUnsuccessful
-	}
-
-	// Update the payment record in the database with the new status
-	updateReq := postgres.UpdatePaymentParams{
-		ID:      payment.ID,
-		UserID:  payment.UserID,
-		OrderID: payment.OrderID,
-		Amount:  payment.Amount,
-		Status:  status,
-	}
-
-	payment, err = h.db.UpdatePayment(r.Context(), updateReq)
+	data, err := h.payments.Create(r.Context(), req)
 	if err != nil {
-		log.Printf("Error updating payment: %v", err)
-		response.InternalServerError(w, r, err)
+		serviceError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, payment)
+	response.OK(w, r, data)
 }
 
 // @Summary Get a payment by ID
@@ -212,7 +137,9 @@ func (h *PaymentsHandler) add(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Payment ID"
+// @Param include_deleted query bool false "Return the payment even if it was deleted; API keys need payments:write"
 // @Success 200 {object} postgres.Payment
+// @Header 200 {string} ETag "Version of the payment, sent back in If-Match to update it"
 // @Failure 404 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /payments/{id} [get]
@@ -223,30 +150,37 @@ func (h *PaymentsHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	payment, err := h.db.GetPayment(r.Context(), id)
+	include, ok := includeDeleted(w, r, "payments")
+	if !ok {
+		return
+	}
+
+	data, err := h.payments.Get(r.Context(), id, include)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		serviceError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, payment)
+	setETag(w, data.Version)
+	response.OK(w, r, data)
 }
 
 // @Summary Update a payment by ID
+// @Description PUT replaces the payment and PATCH applies a JSON Merge Patch. If-Match must carry the ETag of the payment.
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param id path int true "Payment ID"
+// @Param If-Match header string true "ETag of the payment"
 // @Param request body postgres.UpdatePaymentParams true "Payment details"
 // @Success 200 {object} postgres.Payment
 // @Failure 400 {object} response.Object
 // @Failure 404 {object} response.Object
+// @Failure 412 {object} response.Object
+// @Failure 428 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /payments/{id} [put]
+// @Router /payments/{id} [patch]
 func (h *PaymentsHandler) update(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
@@ -254,28 +188,39 @@ func (h *PaymentsHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req postgres.UpdatePaymentParams
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, err, req)
+	previous, err := h.payments.Get(r.Context(), id, false)
+	if err != nil {
+		serviceError(w, r, err)
 		return
 	}
 
-	req.ID = id
+	if !ifMatch(w, r, previous.Version) {
+		return
+	}
 
-	payment, err := h.db.UpdatePayment(r.Context(), req)
+	req, err := decodeUpdate(r, postgres.UpdatePaymentParams{
+		UserID:  previous.UserID,
+		OrderID: previous.OrderID,
+		Amount:  previous.Amount,
+		Status:  previous.Status,
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.payments.Update(r.Context(), previous, req)
+	if err != nil {
+		serviceError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, payment)
+	setETag(w, data.Version)
+	response.OK(w, r, data)
 }
 
 // @Summary Delete a payment by ID
+// @Description The payment is hidden but kept, so that it can be restored until the retention period has passed.
 // @Tags payments
 // @Accept json
 // @Produce json
@@ -291,18 +236,40 @@ func (h *PaymentsHandler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.db.DeletePayment(r.Context(), id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+	if err = h.payments.Delete(r.Context(), id); err != nil {
+		serviceError(w, r, err)
 		return
 	}
 
 	response.NoContent(w, r)
 }
 
+// @Summary Restore a deleted payment
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment ID"
+// @Success 200 {object} postgres.Payment
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /payments/{id}/restore [post]
+func (h *PaymentsHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	data, err := h.payments.Restore(r.Context(), id)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	setETag(w, data.Version)
+	response.OK(w, r, data)
+}
+
 // @Summary Search payments by user ID
 // @Tags payments
 // @Accept json
@@ -310,6 +277,7 @@ func (h *PaymentsHandler) delete(w http.ResponseWriter, r *http.Request) {
 // @Param user query int true "User ID"
 // @Success 200 {array} postgres.Payment
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /payments/search/user [get]
 func (h *PaymentsHandler) searchByUser(w http.ResponseWriter, r *http.Request) {
 	userIDStr := r.URL.Query().Get("user")
@@ -324,7 +292,7 @@ func (h *PaymentsHandler) searchByUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	payments, err := h.db.SearchPaymentsByUser(r.Context(), userID)
+	payments, err := h.payments.SearchByUser(r.Context(), userID)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -340,6 +308,7 @@ func (h *PaymentsHandler) searchByUser(w http.ResponseWriter, r *http.Request) {
 // @Param order query int true "Order ID"
 // @Success 200 {array} postgres.Payment
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /payments/search/order [get]
 func (h *PaymentsHandler) searchByOrder(w http.ResponseWriter, r *http.Request) {
 	orderIDStr := r.URL.Query().Get("order")
@@ -354,7 +323,7 @@ func (h *PaymentsHandler) searchByOrder(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	payments, err := h.db.SearchPaymentsByOrder(r.Context(), orderID)
+	payments, err := h.payments.SearchByOrder(r.Context(), orderID)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -370,6 +339,7 @@ func (h *PaymentsHandler) searchByOrder(w http.ResponseWriter, r *http.Request)
 // @Param status query string true "Payment Status"
 // @Success 200 {array} postgres.Payment
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /payments/search/status [get]
 func (h *PaymentsHandler) searchByStatus(w http.ResponseWriter, r *http.Request) {
 	status := r.URL.Query().Get("status")
@@ -378,7 +348,7 @@ func (h *PaymentsHandler) searchByStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	payments, err := h.db.SearchPaymentsByStatus(r.Context(), postgres.PaymentStatus(status))
+	payments, err := h.payments.SearchByStatus(r.Context(), postgres.PaymentStatus(status))
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ecommerce_management/pkg/server/response"
+)
+
+var (
+	errIfMatchRequired = errors.New("If-Match header with the ETag of the resource is required")
+	errVersionChanged  = errors.New("resource has changed since it was read; fetch it again and retry")
+)
+
+// setETag sets the ETag header to the version of the returned resource
+func setETag(w http.ResponseWriter, version int32) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(int64(version), 10)))
+}
+
+// ifMatch checks the If-Match header of an update against the current version of the resource.
+// A missing header is answered with 428 and a stale one with 412; "*" matches any version.
+// Tags are compared strongly (RFC 9110, section 13.1.1), so weak tags never match.
+func ifMatch(w http.ResponseWriter, r *http.Request, version int32) bool {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		response.PreconditionRequired(w, r, errIfMatchRequired)
+		return false
+	}
+
+	current := strconv.Quote(strconv.FormatInt(int64(version), 10))
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	response.PreconditionFailed(w, r, errVersionChanged)
+	return false
+}
+
+// decodeUpdate reads the body of an update. PUT bodies replace the resource, while PATCH bodies
+// are JSON Merge Patch documents (RFC 7396) applied to current: fields left out keep their value.
+func decodeUpdate[T any](r *http.Request, current T) (dst T, err error) {
+	if r.Method != http.MethodPatch {
+		err = json.NewDecoder(r.Body).Decode(&dst)
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	target, err := json.Marshal(current)
+	if err != nil {
+		return
+	}
+
+	merged, err := mergePatch(target, patch)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(merged, &dst)
+	return
+}
+
+// mergePatch applies a JSON Merge Patch document to a JSON document
+func mergePatch(target, patch []byte) ([]byte, error) {
+	var t, p any
+	if err := json.Unmarshal(target, &t); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	return json.Marshal(mergeValue(t, p))
+}
+
+// mergeValue merges patch into target: objects are merged key by key, null removes a key
+// and any other value replaces the target
+func mergeValue(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+		} else {
+			t[key] = mergeValue(t[key], value)
+		}
+	}
+
+	return t
+}
@@ -1,24 +1,28 @@
 package http
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/catalog"
+	"ecommerce_management/internal/service/media"
 	"ecommerce_management/pkg/server/response"
 )
 
 type ProductsHandler struct {
-	db *postgres.Queries
+	catalog *catalog.Service
+	media   *media.Service
 }
 
-func NewProductHandler(conn *sql.DB) *ProductsHandler {
+func NewProductHandler(catalog *catalog.Service, media *media.Service) *ProductsHandler {
 	return &ProductsHandler{
-		db: postgres.New(conn),
+		catalog: catalog,
+		media:   media,
 	}
 }
 
@@ -27,32 +31,95 @@ func (h *ProductsHandler) Routes() chi.Router {
 
 	r.Get("/", h.list)
 	r.Post("/", h.add)
+	r.Get("/search", h.search)
 	r.Get("/search/name", h.searchByName)
 	r.Get("/search/category", h.searchByCategory)
+	r.Post("/import", h.importProducts)
+	r.Get("/import/{jobID}", h.getImportJob)
+	r.Get("/export", h.exportProducts)
 
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.update)
 		r.Delete("/", h.delete)
+		r.Post("/restore", h.restore)
+
+		r.Route("/variants", func(r chi.Router) {
+			r.Get("/", h.listVariants)
+			r.Post("/", h.addVariant)
+			r.Put("/{variantID}", h.updateVariant)
+			r.Delete("/{variantID}", h.deleteVariant)
+		})
+
+		r.Route("/images", func(r chi.Router) {
+			r.Get("/", h.listImages)
+			r.Post("/", h.addImage)
+			r.Put("/{imageID}", h.updateImage)
+			r.Delete("/{imageID}", h.deleteImage)
+		})
 	})
 
 	return r
 }
 
 // @Summary List all products
+// @Description Keyset paginated; pass pagination.next_cursor as cursor to fetch the next page.
 // @Tags products
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param sort query string false "addition_date, name, price, stock_quantity or id; prefix with - for descending"
+// @Param name query string false "Name contains"
+// @Param category_id query int false "Category ID, including its subcategories"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param in_stock query bool false "Only products in (true) or out of (false) stock"
+// @Param include_deleted query bool false "Include deleted products; API keys need products:write"
 // @Success 200 {array} postgres.Product
+// @Failure 400 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /products [get]
 func (h *ProductsHandler) list(w http.ResponseWriter, r *http.Request) {
-	products, err := h.db.ListProducts(r.Context())
+	page, err := parsePage(r)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		response.BadRequest(w, r, err, nil)
 		return
 	}
-	response.OK(w, r, products)
+
+	req := postgres.ListProductsPageParams{
+		PageParams: page,
+		Name:       queryString(r, "name"),
+	}
+	if req.CategoryID, err = queryInt64(r, "category_id"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.MinPrice, err = queryDecimal(r, "min_price"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.MaxPrice, err = queryDecimal(r, "max_price"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.InStock, err = queryBool(r, "in_stock"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var ok bool
+	if req.IncludeDeleted, ok = includeDeleted(w, r, "products"); !ok {
+		return
+	}
+
+	products, err := h.catalog.List(r.Context(), req)
+	if err != nil {
+		listError(w, r, err)
+		return
+	}
+	response.Page(w, r, products.Items, pagination(products))
 }
 
 // @Summary Create a new product
@@ -71,13 +138,13 @@ func (h *ProductsHandler) add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	product, err := h.db.CreateProduct(r.Context(), req)
+	data, err := h.catalog.Create(r.Context(), req)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		serviceError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, product)
+	response.OK(w, r, data)
 }
 
 // @Summary Get a product by ID
@@ -85,7 +152,9 @@ func (h *ProductsHandler) add(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param include_deleted query bool false "Return the product even if it was deleted; API keys need products:write"
 // @Success 200 {object} postgres.Product
+// @Header 200 {string} ETag "Version of the product, sent back in If-Match to update it"
 // @Failure 404 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /products/{id} [get]
@@ -96,30 +165,37 @@ func (h *ProductsHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	product, err := h.db.GetProduct(r.Context(), id)
+	include, ok := includeDeleted(w, r, "products")
+	if !ok {
+		return
+	}
+
+	data, err := h.catalog.Get(r.Context(), id, include)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		serviceError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, product)
+	setETag(w, data.Version)
+	response.OK(w, r, data)
 }
 
 // @Summary Update a product by ID
+// @Description PUT replaces the product and PATCH applies a JSON Merge Patch. If-Match must carry the ETag of the product.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param If-Match header string true "ETag of the product"
 // @Param request body postgres.UpdateProductParams true "Product details"
 // @Success 200 {object} postgres.Product
 // @Failure 400 {object} response.Object
 // @Failure 404 {object} response.Object
+// @Failure 412 {object} response.Object
+// @Failure 428 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /products/{id} [put]
+// @Router /products/{id} [patch]
 func (h *ProductsHandler) update(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
@@ -127,28 +203,43 @@ func (h *ProductsHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req postgres.UpdateProductParams
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, err, req)
+	previous, err := h.catalog.Get(r.Context(), id, false)
+	if err != nil {
+		serviceError(w, r, err)
 		return
 	}
 
-	req.ID = id
+	if !ifMatch(w, r, previous.Version) {
+		return
+	}
 
-	product, err := h.db.UpdateProduct(r.Context(), req)
+	req, err := decodeUpdate(r, postgres.UpdateProductParams{
+		Sku:           previous.Sku,
+		Name:          previous.Name,
+		Description:   previous.Description,
+		Price:         previous.Price,
+		CategoryID:    previous.CategoryID,
+		StockQuantity: previous.StockQuantity,
+		TaxClass:      previous.TaxClass,
+		WeightGrams:   previous.WeightGrams,
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		response.BadRequest(w, r, err, req)
 		return
 	}
 
-	response.OK(w, r, product)
+	data, err := h.catalog.Update(r.Context(), previous, req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	setETag(w, data.Version)
+	response.OK(w, r, data)
 }
 
 // @Summary Delete a product by ID
+// @Description The product is hidden but kept, so that it can be restored until the retention period has passed.
 // @Tags products
 // @Accept json
 // @Produce json
@@ -164,18 +255,123 @@ func (h *ProductsHandler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.db.DeleteProduct(r.Context(), id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+	if err = h.catalog.Delete(r.Context(), id); err != nil {
+		serviceError(w, r, err)
 		return
 	}
 
 	response.NoContent(w, r)
 }
 
+// @Summary Restore a deleted product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} postgres.Product
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/restore [post]
+func (h *ProductsHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	data, err := h.catalog.Restore(r.Context(), id)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	setETag(w, data.Version)
+	response.OK(w, r, data)
+}
+
+// @Summary Full-text search of products
+// @Description Matches name and description in Russian and English, tolerating typos in the name. Results are ranked by relevance and come with category and price facets.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param category_id query int false "Category ID, including its subcategories"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param in_stock query bool false "Only products in stock"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Success 200 {object} product.SearchResponse
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/search [get]
+func (h *ProductsHandler) search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		response.BadRequest(w, r, errors.New("missing q parameter"), nil)
+		return
+	}
+
+	page, err := parsePage(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if page.Limit <= 0 {
+		page.Limit = postgres.DefaultPageLimit
+	}
+	if page.Limit > postgres.MaxPageLimit {
+		page.Limit = postgres.MaxPageLimit
+	}
+
+	// Ranked results cannot be keyset paginated, so the cursor is the offset of the next page
+	var offset int64
+	if page.Cursor != "" {
+		if offset, err = strconv.ParseInt(page.Cursor, 10, 32); err != nil || offset < 0 {
+			response.BadRequest(w, r, postgres.ErrInvalidCursor, nil)
+			return
+		}
+	}
+
+	req := postgres.SearchProductsParams{
+		Query:      query,
+		PageLimit:  page.Limit,
+		PageOffset: int32(offset),
+	}
+	categoryID, err := queryInt64(r, "category_id")
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.MinPrice, err = queryDecimal(r, "min_price"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.MaxPrice, err = queryDecimal(r, "max_price"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	inStock, err := queryBool(r, "in_stock")
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	req.InStock = inStock.Bool
+
+	results, total, err := h.catalog.Search(r.Context(), req, categoryID)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	result := response.Pagination{Total: total}
+	if next := offset + int64(len(results.Items)); next < result.Total {
+		result.NextCursor = strconv.FormatInt(next, 10)
+	}
+
+	response.Page(w, r, results, result)
+}
+
 // @Summary Search products by name
 // @Tags products
 // @Accept json
@@ -183,6 +379,7 @@ func (h *ProductsHandler) delete(w http.ResponseWriter, r *http.Request) {
 // @Param name query string true "Product name"
 // @Success 200 {array} postgres.Product
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /products/search/name [get]
 func (h *ProductsHandler) searchByName(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
@@ -191,10 +388,7 @@ func (h *ProductsHandler) searchByName(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Convert string to sql.NullString
-	searchName := sql.NullString{String: name, Valid: true}
-
-	products, err := h.db.SearchProductsByName(r.Context(), searchName)
+	products, err := h.catalog.SearchByName(r.Context(), name)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -211,6 +405,7 @@ func (h *ProductsHandler) searchByName(w http.ResponseWriter, r *http.Request) {
 // @Param category query string true "Product category"
 // @Success 200 {array} postgres.Product
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /products/search/category [get]
 func (h *ProductsHandler) searchByCategory(w http.ResponseWriter, r *http.Request) {
 	category := r.URL.Query().Get("category")
@@ -219,7 +414,7 @@ func (h *ProductsHandler) searchByCategory(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	products, err := h.db.SearchProductsByCategory(r.Context(), category)
+	products, err := h.catalog.SearchByCategory(r.Context(), category)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
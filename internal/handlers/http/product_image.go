@@ -0,0 +1,187 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/product"
+	"ecommerce_management/internal/service/catalog"
+	"ecommerce_management/internal/service/media"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary List images of a product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} product.Image
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/images [get]
+func (h *ProductsHandler) listImages(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	images, err := h.catalog.ListImages(r.Context(), productID)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, product.ParseImages(images))
+}
+
+// @Summary Upload an image of a product
+// @Description Accepts JPEG, PNG or GIF up to 10MB and generates small and medium thumbnails. The first image of a product becomes primary.
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param file formData file true "Image file"
+// @Param position formData int false "Position among the product images"
+// @Param primary formData bool false "Make this the primary image"
+// @Success 200 {object} product.Image
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/images [post]
+func (h *ProductsHandler) addImage(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	// Leave room for the multipart envelope and the other form fields
+	r.Body = http.MaxBytesReader(w, r.Body, h.media.MaxSize()+1<<20)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, r, fmt.Errorf("file is required: %w", err), nil)
+		return
+	}
+	defer file.Close()
+
+	var position int64
+	if value := r.FormValue("position"); value != "" {
+		if position, err = strconv.ParseInt(value, 10, 32); err != nil {
+			response.BadRequest(w, r, fmt.Errorf("invalid position: %s", value), nil)
+			return
+		}
+	}
+
+	primary := false
+	if value := r.FormValue("primary"); value != "" {
+		if primary, err = strconv.ParseBool(value); err != nil {
+			response.BadRequest(w, r, fmt.Errorf("invalid primary: %s", value), nil)
+			return
+		}
+	}
+
+	image, err := h.catalog.AddImage(r.Context(), productID, file, int32(position), primary)
+	if err != nil {
+		switch {
+		case errors.Is(err, catalog.ErrNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, media.ErrUnsupportedImage), errors.Is(err, media.ErrImageTooLarge):
+			response.BadRequest(w, r, err, nil)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, product.ParseImage(image))
+}
+
+// @Summary Reorder an image of a product or make it primary
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param imageID path int true "Image ID"
+// @Param request body product.ImageUpdateRequest true "Image details"
+// @Success 200 {object} product.Image
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/images/{imageID} [put]
+func (h *ProductsHandler) updateImage(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	imageID, err := strconv.ParseInt(chi.URLParam(r, "imageID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req product.ImageUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	image, err := h.catalog.UpdateImage(r.Context(), productID, imageID, req.Position, req.IsPrimary)
+	if err != nil {
+		switch {
+		case errors.Is(err, catalog.ErrImageNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, catalog.ErrInvalidImage):
+			response.BadRequest(w, r, err, req)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, product.ParseImage(image))
+}
+
+// @Summary Delete an image of a product
+// @Description Removes the stored files as well. When the primary image is deleted the next one in order becomes primary.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param imageID path int true "Image ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/images/{imageID} [delete]
+func (h *ProductsHandler) deleteImage(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	imageID, err := strconv.ParseInt(chi.URLParam(r, "imageID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.catalog.DeleteImage(r.Context(), productID, imageID); err != nil {
+		if errors.Is(err, catalog.ErrImageNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.NoContent(w, r)
+}
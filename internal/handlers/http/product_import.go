@@ -0,0 +1,181 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/domain/product"
+	"ecommerce_management/internal/service/catalog"
+	"ecommerce_management/pkg/log"
+	"ecommerce_management/pkg/server/response"
+)
+
+const (
+	maxImportSize      = 5 << 20
+	maxAsyncImportSize = 100 << 20
+)
+
+// @Summary Import products from CSV or JSON lines
+// @Description Upserts products by SKU. CSV needs a header row with any of sku, name, description, price, stock_quantity, category_id and category (slug); JSON lines use the same keys.
+// @Description Fields left out keep their value on existing products. A deleted product is only restored when its row sets restore to true.
+// @Description Invalid rows are skipped and listed in the report. Files over 5MB must be imported with async=true, which returns a job to poll.
+// @Tags products
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "csv or jsonl, defaults to the Content-Type"
+// @Param dry_run query bool false "Only validate the rows"
+// @Param async query bool false "Run the import in the background"
+// @Success 200 {object} product.ImportReport
+// @Success 202 {object} product.ImportJob
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/import [post]
+func (h *ProductsHandler) importProducts(w http.ResponseWriter, r *http.Request) {
+	format, err := importFormat(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	dryRun, err := queryBool(r, "dry_run")
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	async, err := queryBool(r, "async")
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	limit := int64(maxImportSize)
+	if async.Bool {
+		limit = maxAsyncImportSize
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) && !async.Bool {
+			err = fmt.Errorf("file exceeds %d bytes, import it with async=true", limit)
+		}
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if async.Bool {
+		job, err := h.catalog.ImportAsync(r.Context(), format, data, dryRun.Bool)
+		if err != nil {
+			response.InternalServerError(w, r, err)
+			return
+		}
+
+		response.Accepted(w, r, product.ParseImportJob(job))
+		return
+	}
+
+	report, err := h.catalog.Import(r.Context(), format, bytes.NewReader(data), dryRun.Bool)
+	if err != nil {
+		if errors.Is(err, catalog.ErrInvalidFile) {
+			response.BadRequest(w, r, err, report)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, report)
+}
+
+// @Summary Get an asynchronous product import
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param jobID path int true "Import job ID"
+// @Success 200 {object} product.ImportJob
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/import/{jobID} [get]
+func (h *ProductsHandler) getImportJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "jobID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	job, err := h.catalog.GetImportJob(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, catalog.ErrJobNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, product.ParseImportJob(job))
+}
+
+// @Summary Export the product catalogue
+// @Description Streams every product in the import format, ordered by ID.
+// @Tags products
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "csv (default) or jsonl"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Object
+// @Router /products/export [get]
+func (h *ProductsHandler) exportProducts(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = catalog.FormatCSV
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case catalog.FormatCSV:
+	case catalog.FormatJSONL:
+		contentType = "application/x-ndjson"
+	default:
+		response.BadRequest(w, r, catalog.ErrUnknownFormat, nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="products.%s"`, format))
+
+	// The status is already sent once rows stream, so a failure can only be logged and the body cut short
+	if err := h.catalog.Export(r.Context(), format, w); err != nil {
+		log.LoggerFromContext(r.Context()).Named("exportProducts").Error("failed to export products", zap.Error(err))
+	}
+}
+
+// importFormat takes the format from the query or falls back to the Content-Type of the body
+func importFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if format != catalog.FormatCSV && format != catalog.FormatJSONL {
+			return "", catalog.ErrUnknownFormat
+		}
+		return format, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return catalog.FormatCSV, nil
+	case "application/x-ndjson", "application/jsonl", "application/json-lines", "application/x-jsonlines":
+		return catalog.FormatJSONL, nil
+	default:
+		return "", errors.New("set format to csv or jsonl, or send a text/csv or application/x-ndjson body")
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/product"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary List variants of a product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} product.Variant
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/variants [get]
+func (h *ProductsHandler) listVariants(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	variants, err := h.catalog.ListVariants(r.Context(), productID)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, product.ParseVariants(variants))
+}
+
+// @Summary Create a variant of a product
+// @Description Once a product has variants, orders must name the variant and its stock is used instead of the product's.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body product.VariantRequest true "Variant details"
+// @Success 200 {object} product.Variant
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/variants [post]
+func (h *ProductsHandler) addVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req product.VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	variant, err := h.catalog.CreateVariant(r.Context(), productID, req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, product.ParseVariant(variant))
+}
+
+// @Summary Update a variant of a product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantID path int true "Variant ID"
+// @Param request body product.VariantRequest true "Variant details"
+// @Success 200 {object} product.Variant
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/variants/{variantID} [put]
+func (h *ProductsHandler) updateVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	variantID, err := strconv.ParseInt(chi.URLParam(r, "variantID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req product.VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	variant, err := h.catalog.UpdateVariant(r.Context(), productID, variantID, req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, product.ParseVariant(variant))
+}
+
+// @Summary Delete a variant of a product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantID path int true "Variant ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /products/{id}/variants/{variantID} [delete]
+func (h *ProductsHandler) deleteVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	variantID, err := strconv.ParseInt(chi.URLParam(r, "variantID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.catalog.DeleteVariant(r.Context(), productID, variantID); err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
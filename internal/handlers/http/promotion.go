@@ -0,0 +1,164 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/promotion"
+	promotionsvc "ecommerce_management/internal/service/promotion"
+	"ecommerce_management/pkg/server/response"
+)
+
+type PromotionsHandler struct {
+	promotions *promotionsvc.Service
+}
+
+func NewPromotionHandler(promotions *promotionsvc.Service) *PromotionsHandler {
+	return &PromotionsHandler{
+		promotions: promotions,
+	}
+}
+
+func (h *PromotionsHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+	})
+
+	return r
+}
+
+// @Summary List all promotions
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Success 200 {array} promotion.Promotion
+// @Failure 500 {object} response.Object
+// @Router /promotions [get]
+func (h *PromotionsHandler) list(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.promotions.List(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+	response.OK(w, r, promotion.ParseFromList(promotions))
+}
+
+// @Summary Create a new promotion
+// @Description A promotion with a code is a coupon applied through coupon_code when ordering; without a code it applies automatically.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param request body promotion.Request true "Promotion details"
+// @Success 200 {object} promotion.Promotion
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /promotions [post]
+func (h *PromotionsHandler) add(w http.ResponseWriter, r *http.Request) {
+	var req promotion.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.promotions.Create(r.Context(), req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, promotion.ParseFrom(data))
+}
+
+// @Summary Get a promotion by ID
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} promotion.Promotion
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /promotions/{id} [get]
+func (h *PromotionsHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	data, err := h.promotions.Get(r.Context(), id)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, promotion.ParseFrom(data))
+}
+
+// @Summary Update a promotion by ID
+// @Description The usage count is kept; lowering usage_limit below it stops further use.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param request body promotion.Request true "Promotion details"
+// @Success 200 {object} promotion.Promotion
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /promotions/{id} [put]
+func (h *PromotionsHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req promotion.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.promotions.Update(r.Context(), id, req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, promotion.ParseFrom(data))
+}
+
+// @Summary Delete a promotion by ID
+// @Description Discounts already applied to orders keep their code and description.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /promotions/{id} [delete]
+func (h *PromotionsHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.promotions.Delete(r.Context(), id); err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
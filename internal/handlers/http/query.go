@@ -0,0 +1,122 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/pkg/server/response"
+)
+
+// parsePage reads the limit, cursor and sort query parameters shared by every list endpoint
+func parsePage(r *http.Request) (dst postgres.PageParams, err error) {
+	query := r.URL.Query()
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.ParseInt(limit, 10, 32)
+		if err != nil || value < 1 {
+			return dst, fmt.Errorf("invalid limit: %s", limit)
+		}
+		dst.Limit = int32(value)
+	}
+	dst.Cursor = query.Get("cursor")
+	dst.Sort = query.Get("sort")
+
+	return
+}
+
+func queryString(r *http.Request, key string) sql.NullString {
+	value := r.URL.Query().Get(key)
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func queryInt64(r *http.Request, key string) (sql.NullInt64, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return sql.NullInt64{}, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return sql.NullInt64{}, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return sql.NullInt64{Int64: parsed, Valid: true}, nil
+}
+
+func queryBool(r *http.Request, key string) (sql.NullBool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return sql.NullBool{}, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return sql.NullBool{}, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return sql.NullBool{Bool: parsed, Valid: true}, nil
+}
+
+// queryTime accepts either an RFC 3339 timestamp or a plain date
+func queryTime(r *http.Request, key string) (sql.NullTime, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return sql.NullTime{Time: parsed, Valid: true}, nil
+		}
+	}
+	return sql.NullTime{}, fmt.Errorf("invalid %s: %s", key, value)
+}
+
+// queryDecimal validates a numeric filter without converting it, as prices are stored as numeric
+func queryDecimal(r *http.Request, key string) (sql.NullString, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return sql.NullString{}, nil
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return sql.NullString{}, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return sql.NullString{String: value, Valid: true}, nil
+}
+
+func pagination[T any](page postgres.Page[T]) response.Pagination {
+	return response.Pagination{
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+}
+
+// listError reports an invalid sort or cursor as a bad request and anything else as a server error
+func listError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, postgres.ErrInvalidSort) || errors.Is(err, postgres.ErrInvalidCursor) {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	response.InternalServerError(w, r, err)
+}
+
+// includeDeleted reads the include_deleted query parameter of the admin views of resource.
+// Only callers allowed to change the resource may see its deleted records.
+func includeDeleted(w http.ResponseWriter, r *http.Request, resource string) (include bool, ok bool) {
+	value, err := queryBool(r, "include_deleted")
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return false, false
+	}
+	if value.Bool && !auth.Permitted(r.Context(), resource+":write") {
+		response.Forbidden(w, r, fmt.Errorf("include_deleted needs permission %s:write", resource))
+		return false, false
+	}
+
+	return value.Bool, true
+}
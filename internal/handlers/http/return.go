@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/returns"
+	"ecommerce_management/internal/repository/postgres"
+	returnsvc "ecommerce_management/internal/service/returns"
+	"ecommerce_management/pkg/server/response"
+)
+
+type ReturnsHandler struct {
+	returns *returnsvc.Service
+}
+
+func NewReturnHandler(returns *returnsvc.Service) *ReturnsHandler {
+	return &ReturnsHandler{
+		returns: returns,
+	}
+}
+
+func (h *ReturnsHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+	})
+
+	return r
+}
+
+// @Summary List returns
+// @Description Newest first; with status, the oldest first so that the queue is worked in order.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param status query string false "requested, approved, rejected, received, refunding or refunded"
+// @Success 200 {array} returns.Return
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /returns [get]
+func (h *ReturnsHandler) list(w http.ResponseWriter, r *http.Request) {
+	status := postgres.ReturnStatus(r.URL.Query().Get("status"))
+	if status != "" && !status.Valid() {
+		response.BadRequest(w, r, fmt.Errorf("invalid status: %s", status), nil)
+		return
+	}
+
+	list, err := h.returns.List(r.Context(), status)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, list)
+}
+
+// @Summary Get a return by ID
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Success 200 {object} returns.Return
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /returns/{id} [get]
+func (h *ReturnsHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	data, items, err := h.returns.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, returnsvc.ErrNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, returns.ParseReturn(data, items))
+}
+
+// @Summary Update a return by ID
+// @Description Staff approve or reject a requested return, then mark the goods received, which puts them back in stock and refunds the return through ePay.
+// @Description While ePay refunds a return it is refunding, and other changes of its status fail with 409. A refund that fails leaves the return received; retry it by setting the status to refunded.
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Param request body returns.UpdateRequest true "Return details"
+// @Success 200 {object} returns.Return
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 409 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /returns/{id} [put]
+func (h *ReturnsHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req returns.UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, items, err := h.returns.Update(r.Context(), id, req.Status, req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, returnsvc.ErrNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, returnsvc.ErrInvalidTransition), errors.Is(err, returnsvc.ErrNotRefundable):
+			response.BadRequest(w, r, err, req)
+		case errors.Is(err, returnsvc.ErrRefundInProgress):
+			response.Conflict(w, r, err)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, returns.ParseReturn(data, items))
+}
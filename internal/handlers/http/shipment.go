@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/shipping"
+	shippingsvc "ecommerce_management/internal/service/shipping"
+	"ecommerce_management/pkg/server/response"
+)
+
+type ShipmentsHandler struct {
+	shipping *shippingsvc.Service
+}
+
+func NewShipmentHandler(shipping *shippingsvc.Service) *ShipmentsHandler {
+	return &ShipmentsHandler{
+		shipping: shipping,
+	}
+}
+
+func (h *ShipmentsHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+	})
+
+	return r
+}
+
+// @Summary Get a shipment by ID
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Success 200 {object} shipping.Shipment
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /shipments/{id} [get]
+func (h *ShipmentsHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	shipment, err := h.shipping.GetShipment(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, shippingsvc.ErrShipmentNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, shipping.ParseShipment(shipment))
+}
+
+// @Summary Update a shipment by ID
+// @Description Sets the carrier, tracking number and status. The first shipment to ship marks the order shipped, and the order is delivered once all its shipments are.
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Param request body shipping.ShipmentUpdateRequest true "Shipment details"
+// @Success 200 {object} shipping.Shipment
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /shipments/{id} [put]
+func (h *ShipmentsHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req shipping.ShipmentUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	shipment, err := h.shipping.UpdateShipment(r.Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, shippingsvc.ErrShipmentNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, shippingsvc.ErrInvalidTransition):
+			response.BadRequest(w, r, err, req)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, shipping.ParseShipment(shipment))
+}
+
+// @Summary Delete a shipment by ID
+// @Description The order keeps its status.
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /shipments/{id} [delete]
+func (h *ShipmentsHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.shipping.DeleteShipment(r.Context(), id); err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/shipping"
+	shippingsvc "ecommerce_management/internal/service/shipping"
+	"ecommerce_management/pkg/server/response"
+)
+
+type ShippingMethodsHandler struct {
+	shipping *shippingsvc.Service
+}
+
+func NewShippingMethodHandler(shipping *shippingsvc.Service) *ShippingMethodsHandler {
+	return &ShippingMethodsHandler{
+		shipping: shipping,
+	}
+}
+
+func (h *ShippingMethodsHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+	})
+
+	return r
+}
+
+// @Summary List all shipping methods
+// @Tags shipping-methods
+// @Accept json
+// @Produce json
+// @Success 200 {array} shipping.Method
+// @Failure 500 {object} response.Object
+// @Router /shipping-methods [get]
+func (h *ShippingMethodsHandler) list(w http.ResponseWriter, r *http.Request) {
+	methods, err := h.shipping.ListMethods(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, methods)
+}
+
+// @Summary Create a new shipping method
+// @Description Flat methods cost price. Weight methods add price to the lightest rate whose max_weight_grams fits the order. Either is free once the order subtotal reaches free_over.
+// @Tags shipping-methods
+// @Accept json
+// @Produce json
+// @Param request body shipping.MethodRequest true "Shipping method details"
+// @Success 200 {object} shipping.Method
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /shipping-methods [post]
+func (h *ShippingMethodsHandler) add(w http.ResponseWriter, r *http.Request) {
+	var req shipping.MethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	method, rates, err := h.shipping.CreateMethod(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, shippingsvc.ErrInvalidMethod) {
+			response.BadRequest(w, r, err, req)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, shipping.ParseMethod(method, rates))
+}
+
+// @Summary Get a shipping method by ID
+// @Tags shipping-methods
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping method ID"
+// @Success 200 {object} shipping.Method
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /shipping-methods/{id} [get]
+func (h *ShippingMethodsHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	method, rates, err := h.shipping.GetMethod(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, shippingsvc.ErrMethodNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, shipping.ParseMethod(method, rates))
+}
+
+// @Summary Update a shipping method by ID
+// @Description Replaces the rates of the method. Orders keep the shipping cost they were placed with.
+// @Tags shipping-methods
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping method ID"
+// @Param request body shipping.MethodRequest true "Shipping method details"
+// @Success 200 {object} shipping.Method
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /shipping-methods/{id} [put]
+func (h *ShippingMethodsHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req shipping.MethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	method, rates, err := h.shipping.UpdateMethod(r.Context(), id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, shippingsvc.ErrMethodNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, shippingsvc.ErrInvalidMethod):
+			response.BadRequest(w, r, err, req)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, shipping.ParseMethod(method, rates))
+}
+
+// @Summary Delete a shipping method by ID
+// @Description Orders and shipments that used the method keep their cost and carrier.
+// @Tags shipping-methods
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipping method ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /shipping-methods/{id} [delete]
+func (h *ShippingMethodsHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.shipping.DeleteMethod(r.Context(), id); err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/tax"
+	"ecommerce_management/pkg/server/response"
+)
+
+type TaxRatesHandler struct {
+	taxes *tax.Service
+}
+
+func NewTaxRateHandler(taxes *tax.Service) *TaxRatesHandler {
+	return &TaxRatesHandler{
+		taxes: taxes,
+	}
+}
+
+func (h *TaxRatesHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+	})
+
+	return r
+}
+
+// @Summary List all tax rates
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Success 200 {array} postgres.TaxRate
+// @Failure 500 {object} response.Object
+// @Router /tax-rates [get]
+func (h *TaxRatesHandler) list(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.taxes.ListRates(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+	response.OK(w, r, rates)
+}
+
+// @Summary Create a new tax rate
+// @Description Products are taxed at the rate of their tax_class in the destination country of the order; a class without a rate is not taxed.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param request body postgres.CreateTaxRateParams true "Tax rate details; rate is a fraction, 0.12 for 12%"
+// @Success 200 {object} postgres.TaxRate
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /tax-rates [post]
+func (h *TaxRatesHandler) add(w http.ResponseWriter, r *http.Request) {
+	var req postgres.CreateTaxRateParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	rate, err := h.taxes.CreateRate(r.Context(), req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, rate)
+}
+
+// @Summary Get a tax rate by ID
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} postgres.TaxRate
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /tax-rates/{id} [get]
+func (h *TaxRatesHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	rate, err := h.taxes.GetRate(r.Context(), id)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, rate)
+}
+
+// @Summary Update a tax rate by ID
+// @Description Orders keep the rate they were placed with.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Param request body postgres.UpdateTaxRateParams true "Tax rate details"
+// @Success 200 {object} postgres.TaxRate
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /tax-rates/{id} [put]
+func (h *TaxRatesHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req postgres.UpdateTaxRateParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	req.ID = id
+
+	rate, err := h.taxes.UpdateRate(r.Context(), req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, rate)
+}
+
+// @Summary Delete a tax rate by ID
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /tax-rates/{id} [delete]
+func (h *TaxRatesHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.taxes.DeleteRate(r.Context(), id); err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
@@ -1,28 +1,27 @@
 package http
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
 
+	"ecommerce_management/internal/domain/user"
 	"ecommerce_management/internal/repository/postgres"
-	"ecommerce_management/internal/service/kafka"
+	"ecommerce_management/internal/service/auth"
+	usersvc "ecommerce_management/internal/service/user"
 	"ecommerce_management/pkg/server/response"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type UsersHandler struct {
-	db           *postgres.Queries
-	kafkaService kafka.KafkaService
+	users *usersvc.Service
 }
 
-func NewUserHandler(conn *sql.DB, kafkaService kafka.KafkaService) *UsersHandler {
+func NewUserHandler(users *usersvc.Service) *UsersHandler {
 	return &UsersHandler{
-		db:           postgres.New(conn),
-		kafkaService: kafkaService,
+		users: users,
 	}
 }
 
@@ -34,68 +33,113 @@ func (h *UsersHandler) Routes() chi.Router {
 	r.Get("/search/email", h.searchByEmail)
 	r.Get("/search/name", h.searchByName)
 
+	r.Post("/verify-email/request", h.requestEmailVerification)
+	r.Post("/verify-email/confirm", h.confirmEmailVerification)
+	r.Post("/password-reset/request", h.requestPasswordReset)
+	r.Post("/password-reset/confirm", h.confirmPasswordReset)
+
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.update)
 		r.Delete("/", h.delete)
+		r.Post("/restore", h.restore)
+
+		r.Get("/notifications", h.listNotifications)
+
+		r.Route("/addresses", func(r chi.Router) {
+			r.Get("/", h.listAddresses)
+			r.Post("/", h.addAddress)
+			r.Get("/{addressID}", h.getAddress)
+			r.Put("/{addressID}", h.updateAddress)
+			r.Delete("/{addressID}", h.deleteAddress)
+		})
+
+		r.Route("/api-keys", func(r chi.Router) {
+			r.Use(auth.RequireAuthentication)
+
+			r.Get("/", h.listAPIKeys)
+			r.Post("/", h.addAPIKey)
+			r.Delete("/{keyID}", h.revokeAPIKey)
+		})
 	})
 
 	return r
 }
 
 // @Summary List of users from the repository
+// @Description Keyset paginated; pass pagination.next_cursor as cursor to fetch the next page.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param sort query string false "registration_date, full_name, email or id; prefix with - for descending"
+// @Param name query string false "Full name contains"
+// @Param email query string false "Email"
+// @Param role query string false "Role"
+// @Param registered_from query string false "Registered at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param registered_to query string false "Registered before (RFC 3339 or YYYY-MM-DD)"
+// @Param include_deleted query bool false "Include deleted users; API keys need users:write"
 // @Success 200 {array} postgres.User
+// @Failure 400 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /users [get]
 func (h *UsersHandler) list(w http.ResponseWriter, r *http.Request) {
-	users, err := h.db.ListUsers(r.Context())
+	page, err := parsePage(r)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		response.BadRequest(w, r, err, nil)
 		return
 	}
-	response.OK(w, r, users)
+
+	req := postgres.ListUsersPageParams{
+		PageParams: page,
+		Name:       queryString(r, "name"),
+		Email:      queryString(r, "email"),
+		Role:       queryString(r, "role"),
+	}
+	if req.RegisteredFrom, err = queryTime(r, "registered_from"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+	if req.RegisteredTo, err = queryTime(r, "registered_to"); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var ok bool
+	if req.IncludeDeleted, ok = includeDeleted(w, r, "users"); !ok {
+		return
+	}
+
+	users, err := h.users.List(r.Context(), req)
+	if err != nil {
+		listError(w, r, err)
+		return
+	}
+	response.Page(w, r, users.Items, pagination(users))
 }
 
 // @Summary Add a new user to the repository
+// @Description A verification email is sent to the new user's address.
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param request body postgres.CreateUserParams true "User details"
+// @Param request body user.CreateUserRequest true "User details"
 // @Success 200 {object} postgres.User
 // @Failure 400 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /users [post]
 func (h *UsersHandler) add(w http.ResponseWriter, r *http.Request) {
-	var req postgres.CreateUserParams
+	var req user.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.BadRequest(w, r, err, req)
 		return
 	}
 
-	user, err := h.db.CreateUser(r.Context(), req)
-	if err != nil {
-		response.InternalServerError(w, r, err)
-		return
-	}
-
-	// Produce Kafka message
-	userCreated, err := h.db.GetUser(r.Context(), user.ID)
+	user, err := h.users.Create(r.Context(), req)
 	if err != nil {
-		response.InternalServerError(w, r, err)
-		return
-	}
-
-	userCreatedJSON, err := json.Marshal(userCreated)
-	if err != nil {
-		response.InternalServerError(w, r, err)
-		return
-	}
-
-	if err := h.kafkaService.Producer(r.Context(), "new-user", userCreatedJSON); err != nil {
-		response.InternalServerError(w, r, err)
+		serviceError(w, r, err)
 		return
 	}
 
@@ -107,7 +151,9 @@ func (h *UsersHandler) add(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param include_deleted query bool false "Return the user even if it was deleted; API keys need users:write"
 // @Success 200 {object} postgres.User
+// @Header 200 {string} ETag "Version of the user, sent back in If-Match to update it"
 // @Failure 404 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /users/{id} [get]
@@ -118,30 +164,37 @@ func (h *UsersHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.db.GetUser(r.Context(), id)
+	include, ok := includeDeleted(w, r, "users")
+	if !ok {
+		return
+	}
+
+	user, err := h.users.Get(r.Context(), id, include)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		serviceError(w, r, err)
 		return
 	}
 
+	setETag(w, user.Version)
 	response.OK(w, r, user)
 }
 
 // @Summary Update a user in the repository
+// @Description PUT replaces the user and PATCH applies a JSON Merge Patch. If-Match must carry the ETag of the user.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param If-Match header string true "ETag of the user"
 // @Param request body postgres.UpdateUserParams true "User details"
 // @Success 200 {object} postgres.User
 // @Failure 400 {object} response.Object
 // @Failure 404 {object} response.Object
+// @Failure 412 {object} response.Object
+// @Failure 428 {object} response.Object
 // @Failure 500 {object} response.Object
 // @Router /users/{id} [put]
+// @Router /users/{id} [patch]
 func (h *UsersHandler) update(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
@@ -149,28 +202,39 @@ func (h *UsersHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req postgres.UpdateUserParams
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, err, req)
+	previous, err := h.users.Get(r.Context(), id, false)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	if !ifMatch(w, r, previous.Version) {
 		return
 	}
 
-	req.ID = id
+	req, err := decodeUpdate(r, postgres.UpdateUserParams{
+		FullName: previous.FullName,
+		Email:    previous.Email,
+		Address:  previous.Address,
+		Role:     previous.Role,
+	})
+	if err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
 
-	user, err := h.db.UpdateUser(r.Context(), req)
+	user, err := h.users.Update(r.Context(), previous, req)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		serviceError(w, r, err)
 		return
 	}
 
+	setETag(w, user.Version)
 	response.OK(w, r, user)
 }
 
 // @Summary Delete a user from the repository
+// @Description The user is hidden but kept, so that it can be restored until the retention period has passed.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -186,18 +250,40 @@ func (h *UsersHandler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.db.DeleteUser(r.Context(), id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+	if err = h.users.Delete(r.Context(), id); err != nil {
+		serviceError(w, r, err)
 		return
 	}
 
 	response.NoContent(w, r)
 }
 
+// @Summary Restore a deleted user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} postgres.User
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/restore [post]
+func (h *UsersHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	user, err := h.users.Restore(r.Context(), id)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	setETag(w, user.Version)
+	response.OK(w, r, user)
+}
+
 // @Summary Search users by email
 // @Tags users
 // @Accept json
@@ -205,6 +291,7 @@ func (h *UsersHandler) delete(w http.ResponseWriter, r *http.Request) {
 // @Param email query string true "User Email"
 // @Success 200 {array} postgres.User
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /users/search/email [get]
 func (h *UsersHandler) searchByEmail(w http.ResponseWriter, r *http.Request) {
 	email := r.URL.Query().Get("email")
@@ -213,7 +300,7 @@ func (h *UsersHandler) searchByEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	users, err := h.db.SearchUsersByEmail(r.Context(), email)
+	users, err := h.users.SearchByEmail(r.Context(), email)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -229,6 +316,7 @@ func (h *UsersHandler) searchByEmail(w http.ResponseWriter, r *http.Request) {
 // @Param name query string true "User Name"
 // @Success 200 {array} postgres.User
 // @Failure 500 {object} response.Object
+// @Deprecated
 // @Router /users/search/name [get]
 func (h *UsersHandler) searchByName(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
@@ -237,7 +325,7 @@ func (h *UsersHandler) searchByName(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	users, err := h.db.SearchUsersByName(r.Context(), sql.NullString{String: name, Valid: true})
+	users, err := h.users.SearchByName(r.Context(), name)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
@@ -245,3 +333,28 @@ func (h *UsersHandler) searchByName(w http.ResponseWriter, r *http.Request) {
 
 	response.OK(w, r, users)
 }
+
+// @Summary List notifications sent to a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {array} postgres.Notification
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/notifications [get]
+func (h *UsersHandler) listNotifications(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	notifications, err := h.users.Notifications(r.Context(), id)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, notifications)
+}
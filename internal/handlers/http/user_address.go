@@ -0,0 +1,195 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ecommerce_management/internal/domain/address"
+	"ecommerce_management/internal/repository/postgres"
+	usersvc "ecommerce_management/internal/service/user"
+	"ecommerce_management/pkg/server/response"
+)
+
+// @Summary List addresses of a user
+// @Description Shipping addresses first, each type starting with its default.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {array} address.Address
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/addresses [get]
+func (h *UsersHandler) listAddresses(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	addresses, err := h.users.ListAddresses(r.Context(), userID)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, address.ParseFromList(addresses))
+}
+
+// @Summary Add an address to a user
+// @Description The first address of a type becomes its default; is_default moves the default to the new address.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body address.Request true "Address details"
+// @Success 200 {object} address.Address
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/addresses [post]
+func (h *UsersHandler) addAddress(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req address.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.users.CreateAddress(r.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usersvc.ErrNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, usersvc.ErrInvalidAddress):
+			response.BadRequest(w, r, err, req)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, address.ParseFrom(data))
+}
+
+// @Summary Get an address of a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param addressID path int true "Address ID"
+// @Success 200 {object} address.Address
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/addresses/{addressID} [get]
+func (h *UsersHandler) getAddress(w http.ResponseWriter, r *http.Request) {
+	params, err := addressParams(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	data, err := h.users.GetAddress(r.Context(), params.UserID, params.ID)
+	if err != nil {
+		if errors.Is(err, usersvc.ErrAddressNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, address.ParseFrom(data))
+}
+
+// @Summary Update an address of a user
+// @Description Orders keep the copy of the address they were placed with. Changing the type of a default address makes another address the default of its old type.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param addressID path int true "Address ID"
+// @Param request body address.Request true "Address details"
+// @Success 200 {object} address.Address
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/addresses/{addressID} [put]
+func (h *UsersHandler) updateAddress(w http.ResponseWriter, r *http.Request) {
+	params, err := addressParams(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req address.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.users.UpdateAddress(r.Context(), params.UserID, params.ID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usersvc.ErrAddressNotFound):
+			response.NotFound(w, r, err)
+		case errors.Is(err, usersvc.ErrInvalidAddress):
+			response.BadRequest(w, r, err, req)
+		default:
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.OK(w, r, address.ParseFrom(data))
+}
+
+// @Summary Delete an address of a user
+// @Description Deleting a default address makes the oldest remaining address of its type the default. Orders keep their copy.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param addressID path int true "Address ID"
+// @Success 204
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /users/{id}/addresses/{addressID} [delete]
+func (h *UsersHandler) deleteAddress(w http.ResponseWriter, r *http.Request) {
+	params, err := addressParams(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.users.DeleteAddress(r.Context(), params.UserID, params.ID); err != nil {
+		if errors.Is(err, usersvc.ErrAddressNotFound) {
+			response.NotFound(w, r, err)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// addressParams reads the user and address IDs of an address route
+func addressParams(r *http.Request) (params postgres.GetUserAddressParams, err error) {
+	if params.UserID, err = strconv.ParseInt(chi.URLParam(r, "id"), 10, 64); err != nil {
+		return
+	}
+	params.ID, err = strconv.ParseInt(chi.URLParam(r, "addressID"), 10, 64)
+	return
+}
@@ -0,0 +1,228 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "ecommerce_management/internal/domain/webhook"
+	"ecommerce_management/internal/service/webhook"
+	"ecommerce_management/pkg/server/response"
+)
+
+type WebhooksHandler struct {
+	webhooks *webhook.Service
+}
+
+func NewWebhookHandler(webhooks *webhook.Service) *WebhooksHandler {
+	return &WebhooksHandler{
+		webhooks: webhooks,
+	}
+}
+
+func (h *WebhooksHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+		r.Get("/deliveries", h.listDeliveries)
+		r.Post("/deliveries/{deliveryID}/replay", h.replay)
+	})
+
+	return r
+}
+
+// @Summary List all webhook subscriptions
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {array} postgres.WebhookSubscription
+// @Failure 500 {object} response.Object
+// @Router /webhooks [get]
+func (h *WebhooksHandler) list(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhooks.ListSubscriptions(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+	response.OK(w, r, subscriptions)
+}
+
+// @Summary Create a webhook subscription
+// @Description Deliveries are signed with the returned secret, which is shown only once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body domain.SubscriptionRequest true "Subscription details"
+// @Success 200 {object} domain.CreateSubscriptionResponse
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /webhooks [post]
+func (h *WebhooksHandler) add(w http.ResponseWriter, r *http.Request) {
+	var req domain.SubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	subscription, err := h.webhooks.CreateSubscription(r.Context(), req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, domain.CreateSubscriptionResponse{
+		WebhookSubscription: subscription,
+		Secret:              subscription.Secret,
+	})
+}
+
+// @Summary Get a webhook subscription by ID
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} postgres.WebhookSubscription
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /webhooks/{id} [get]
+func (h *WebhooksHandler) get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	subscription, err := h.webhooks.GetSubscription(r.Context(), id)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, subscription)
+}
+
+// @Summary Update a webhook subscription
+// @Description The signing secret cannot be changed; create a new subscription to rotate it.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param request body domain.SubscriptionRequest true "Subscription details"
+// @Success 200 {object} postgres.WebhookSubscription
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /webhooks/{id} [put]
+func (h *WebhooksHandler) update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req domain.SubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	subscription, err := h.webhooks.UpdateSubscription(r.Context(), id, req)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, subscription)
+}
+
+// @Summary Delete a webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 204 {object} response.Object
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /webhooks/{id} [delete]
+func (h *WebhooksHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := h.webhooks.DeleteSubscription(r.Context(), id); err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// @Summary List deliveries of a webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {array} domain.Delivery
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhooksHandler) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	deliveries, err := h.webhooks.ListDeliveries(r.Context(), id)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, domain.ParseFromList(deliveries))
+}
+
+// @Summary Replay a failed webhook delivery
+// @Description Queues the delivery again; the worker sends it on its next run.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param deliveryID path int true "Delivery ID"
+// @Success 200 {object} domain.Delivery
+// @Failure 400 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Router /webhooks/{id}/deliveries/{deliveryID}/replay [post]
+func (h *WebhooksHandler) replay(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	delivery, err := h.webhooks.Replay(r.Context(), id, deliveryID)
+	if err != nil {
+		serviceError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, domain.ParseFrom(delivery))
+}
@@ -0,0 +1,183 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Credentials for an S3-compatible object storage (AWS S3, MinIO, Yandex Object Storage, ...)
+type Credentials struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
+
+// S3 stores objects in a bucket using path-style requests signed with AWS Signature Version 4
+type S3 struct {
+	Credentials Credentials
+	client      *http.Client
+}
+
+func NewS3(credentials Credentials) (*S3, error) {
+	// Ensure that all required fields are provided
+	if credentials.Endpoint == "" {
+		return nil, errors.New("Endpoint cannot be empty")
+	}
+	if credentials.Bucket == "" {
+		return nil, errors.New("Bucket cannot be empty")
+	}
+	if credentials.AccessKey == "" || credentials.SecretKey == "" {
+		return nil, errors.New("AccessKey and SecretKey cannot be empty")
+	}
+	if credentials.Region == "" {
+		credentials.Region = "us-east-1"
+	}
+	credentials.Endpoint = strings.TrimSuffix(credentials.Endpoint, "/")
+	if credentials.PublicURL == "" {
+		credentials.PublicURL = credentials.Endpoint + "/" + credentials.Bucket
+	}
+	credentials.PublicURL = strings.TrimSuffix(credentials.PublicURL, "/")
+
+	return &S3{
+		Credentials: credentials,
+		client:      &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.request(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(req)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if res != nil {
+		res.Body.Close()
+	}
+	return nil
+}
+
+func (s *S3) URL(key string) string {
+	return s.Credentials.PublicURL + "/" + key
+}
+
+func (s *S3) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	target := s.Credentials.Endpoint + "/" + s.Credentials.Bucket + "/" + (&url.URL{Path: key}).EscapedPath()
+	return http.NewRequestWithContext(ctx, method, target, body)
+}
+
+// do signs and sends req, turning error responses into errors
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, time.Now().UTC())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, ErrNotFound
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+		res.Body.Close()
+		return nil, fmt.Errorf("s3: %s %s: %s: %s", req.Method, req.URL.Path, res.Status, message)
+	}
+	return res, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header; the payload is left unsigned so bodies can be streamed
+func (s *S3) sign(req *http.Request, now time.Time) {
+	const payload = "UNSIGNED-PAYLOAD"
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	var headers strings.Builder
+	for _, name := range names {
+		headers.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		headers.String(),
+		signedHeaders,
+		payload,
+	}, "\n")
+
+	scope := date + "/" + s.Credentials.Region + "/s3/aws4_request"
+	digest := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.Credentials.SecretKey), date)
+	key = hmacSHA256(key, s.Credentials.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.Credentials.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps values in the process. It stands in for Redis in tests and local development,
+// where a single instance serves every request.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time // zero when the entry does not expire
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		entries: map[string]entry{},
+	}
+}
+
+// Get returns the value under key, or ErrMiss when there is none or it has expired
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	if !e.expiresAt.IsZero() && !time.Now().Before(e.expiresAt) {
+		delete(m.entries, key)
+		return nil, ErrMiss
+	}
+	return append([]byte(nil), e.value...), nil
+}
+
+// Set stores value under key for ttl
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := entry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	m.entries[key] = e
+	return nil
+}
+
+// Delete removes the values under keys; missing keys are ignored
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// Len returns the number of values kept, including expired ones not read since
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
+
+// Reset discards every value
+func (m *Memory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = map[string]entry{}
+}
@@ -40,3 +40,23 @@ func (c *Client) Cancel(ctx context.Context, token, transactionID string) (err e
 
 	return c.request(ctx, true, "POST", path.String(), nil, headers, nil)
 }
+
+func (c *Client) Refund(ctx context.Context, token, transactionID, amount string) (err error) {
+	path, err := url.Parse(c.Credentials.URL)
+	if err != nil {
+		return
+	}
+	path = path.JoinPath("/operation", transactionID, "/refund")
+
+	params := url.Values{
+		"amount": []string{amount},
+	}
+	path.RawQuery = params.Encode()
+
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": fmt.Sprintf("Bearer %s", token),
+	}
+
+	return c.request(ctx, true, "POST", path.String(), nil, headers, nil)
+}
@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// Capture keeps sent messages in memory instead of delivering them.
+// It stands in for SMTP in tests and local development.
+type Capture struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewCapture() *Capture {
+	return &Capture{}
+}
+
+// Send records msg
+func (c *Capture) Send(ctx context.Context, msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of every message sent so far
+func (c *Capture) Messages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Message(nil), c.messages...)
+}
+
+// Last returns the most recently sent message
+func (c *Capture) Last() (msg Message, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.messages) == 0 {
+		return
+	}
+	return c.messages[len(c.messages)-1], true
+}
+
+// Reset discards all captured messages
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = nil
+}
@@ -0,0 +1,18 @@
+package mail
+
+import (
+	"context"
+)
+
+// Message is a single email; Text and HTML are sent as alternative parts when both are set
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender delivers email messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,123 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Credentials for the SMTP server
+type Credentials struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Client sends messages through an SMTP server using STARTTLS when available
+type Client struct {
+	Credentials Credentials
+}
+
+func New(credentials Credentials) (*Client, error) {
+	// Ensure that all required fields are provided
+	if credentials.Host == "" {
+		return nil, errors.New("Host cannot be empty")
+	}
+	if credentials.Port == 0 {
+		return nil, errors.New("Port cannot be empty")
+	}
+	if credentials.From == "" {
+		credentials.From = credentials.Username
+	}
+
+	return &Client{Credentials: credentials}, nil
+}
+
+// Send delivers msg to every recipient in msg.To
+func (c *Client) Send(ctx context.Context, msg Message) (err error) {
+	if len(msg.To) == 0 {
+		return errors.New("message has no recipients")
+	}
+
+	body, err := c.compose(msg)
+	if err != nil {
+		return
+	}
+
+	addr := net.JoinHostPort(c.Credentials.Host, strconv.Itoa(c.Credentials.Port))
+
+	var auth smtp.Auth
+	if c.Credentials.Username != "" {
+		auth = smtp.PlainAuth("", c.Credentials.Username, c.Credentials.Password, c.Credentials.Host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, c.Credentials.From, msg.To, body)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-done:
+		return
+	}
+}
+
+func (c *Client) compose(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	headers := []string{
+		"From: " + c.Credentials.From,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+	}
+
+	if msg.HTML == "" {
+		headers = append(headers, "Content-Type: text/plain; charset=utf-8")
+		fmt.Fprintf(&buf, "%s\r\n\r\n%s", strings.Join(headers, "\r\n"), msg.Text)
+		return buf.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buf)
+	headers = append(headers, "Content-Type: multipart/alternative; boundary="+writer.Boundary())
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s\r\n\r\n", strings.Join(headers, "\r\n"))
+
+	parts := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+	for _, p := range parts {
+		if p.content == "" {
+			continue
+		}
+		w, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {p.contentType}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = w.Write([]byte(p.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	out.Write(buf.Bytes())
+
+	return out.Bytes(), nil
+}
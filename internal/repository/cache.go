@@ -0,0 +1,411 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/provider/cache"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/pkg/log"
+)
+
+// Cached queries are grouped by the table they read. Every key of a group contains the group's
+// generation, so a write invalidates all of them, lists included, by replacing the generation.
+const (
+	cacheProducts   = "products"
+	cacheCategories = "categories"
+)
+
+// CacheTTL sets how long the results of product and category queries are served from the cache
+type CacheTTL struct {
+	Products   time.Duration
+	Categories time.Duration
+}
+
+// WithCache serves product and category queries from c, reading through to the store on a miss.
+// Writes to products, including stock changes, and to categories invalidate the cached results once committed.
+// When c fails, queries run against the store as if there were no cache.
+// It must be applied after the store.
+func WithCache(c cache.Store, ttl CacheTTL) Configuration {
+	return func(r *Repository) error {
+		if r.Store == nil {
+			return errors.New("cache requires a store")
+		}
+
+		s := &cachedStore{
+			store: r.Store,
+			cache: c,
+			ttl: map[string]time.Duration{
+				cacheProducts:   ttl.Products,
+				cacheCategories: ttl.Categories,
+			},
+			stale: map[string]bool{},
+		}
+		s.cachedQuerier = cachedQuerier{Querier: r.Store, invalidate: s.invalidate}
+		r.Store = s
+		return nil
+	}
+}
+
+// cachedQuerier reports the groups every write changes to invalidate
+type cachedQuerier struct {
+	Querier
+	invalidate func(ctx context.Context, groups ...string)
+}
+
+// cachedStore reads products and categories through the cache
+type cachedStore struct {
+	cachedQuerier
+	store Store
+	cache cache.Store
+	ttl   map[string]time.Duration
+
+	mu sync.Mutex
+	// stale are the groups whose invalidation failed; they bypass the cache until it succeeds
+	stale map[string]bool
+}
+
+// BeginTx starts a transaction that invalidates the groups it writes when it commits,
+// so that no other request caches what it has not committed yet. Reads in it bypass the cache.
+func (s *cachedStore) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := s.store.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &cachedTx{tx: tx, store: s, changed: map[string]bool{}}
+	t.cachedQuerier = cachedQuerier{Querier: tx, invalidate: t.record}
+	return t, nil
+}
+
+// invalidate replaces the generation of groups; a group that cannot be invalidated bypasses the cache until it can
+func (s *cachedStore) invalidate(ctx context.Context, groups ...string) {
+	logger := log.LoggerFromContext(ctx).Named("cache")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, group := range groups {
+		if _, err := s.renew(ctx, group); err != nil {
+			logger.Warn("failed to invalidate cached queries", zap.String("group", group), zap.Error(err))
+			s.stale[group] = true
+			continue
+		}
+		delete(s.stale, group)
+	}
+}
+
+// generation returns the current generation of a group, starting one when there is none.
+// ok is false when the cache is unavailable or the group could not be invalidated.
+func (s *cachedStore) generation(ctx context.Context, group string) (generation string, ok bool) {
+	logger := log.LoggerFromContext(ctx).Named("cache")
+
+	s.mu.Lock()
+	stale := s.stale[group]
+	s.mu.Unlock()
+	if stale {
+		s.invalidate(ctx, group)
+
+		s.mu.Lock()
+		stale = s.stale[group]
+		s.mu.Unlock()
+		if stale {
+			return "", false
+		}
+	}
+
+	value, err := s.cache.Get(ctx, generationKey(group))
+	switch {
+	case err == nil:
+		return string(value), true
+	case errors.Is(err, cache.ErrMiss):
+		generation, err = s.renew(ctx, group)
+	}
+	if err != nil {
+		logger.Warn("cache unavailable", zap.String("group", group), zap.Error(err))
+		return "", false
+	}
+	return generation, true
+}
+
+// renew stores a new generation for a group, leaving the keys of the previous one to expire
+func (s *cachedStore) renew(ctx context.Context, group string) (string, error) {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	return generation, s.cache.Set(ctx, generationKey(group), []byte(generation), 0)
+}
+
+func generationKey(group string) string {
+	return "cache:" + group + ":generation"
+}
+
+// cached returns the cached result of a query, running load and caching its result on a miss.
+// Errors, such as sql.ErrNoRows, are not cached.
+func cached[T any](ctx context.Context, s *cachedStore, group, query string, arg any, load func() (T, error)) (T, error) {
+	logger := log.LoggerFromContext(ctx).Named("cache")
+
+	generation, ok := s.generation(ctx, group)
+	if !ok {
+		return load()
+	}
+
+	params, err := json.Marshal(arg)
+	if err != nil {
+		return load()
+	}
+	hash := sha256.Sum256(params)
+	key := "cache:" + group + ":" + generation + ":" + query + ":" + hex.EncodeToString(hash[:16])
+
+	value, err := s.cache.Get(ctx, key)
+	if err == nil {
+		var data T
+		if err = json.Unmarshal(value, &data); err == nil {
+			return data, nil
+		}
+	}
+	if !errors.Is(err, cache.ErrMiss) {
+		logger.Warn("failed to read cached query", zap.String("query", query), zap.Error(err))
+	}
+
+	data, err := load()
+	if err != nil {
+		return data, err
+	}
+
+	if value, err = json.Marshal(data); err == nil {
+		err = s.cache.Set(ctx, key, value, s.ttl[group])
+	}
+	if err != nil {
+		logger.Warn("failed to cache query", zap.String("query", query), zap.Error(err))
+	}
+
+	return data, nil
+}
+
+// cachedTx collects the groups a transaction writes and invalidates them once it commits
+type cachedTx struct {
+	cachedQuerier
+	tx    Tx
+	store *cachedStore
+
+	mu      sync.Mutex
+	changed map[string]bool
+}
+
+func (t *cachedTx) record(ctx context.Context, groups ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, group := range groups {
+		t.changed[group] = true
+	}
+}
+
+func (t *cachedTx) Commit() error {
+	if err := t.tx.Commit(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	groups := make([]string, 0, len(t.changed))
+	for group := range t.changed {
+		groups = append(groups, group)
+	}
+	t.mu.Unlock()
+
+	if len(groups) > 0 {
+		t.store.invalidate(context.Background(), groups...)
+	}
+	return nil
+}
+
+func (t *cachedTx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Product reads
+
+func (s *cachedStore) GetProduct(ctx context.Context, id int64) (postgres.Product, error) {
+	return cached(ctx, s, cacheProducts, "GetProduct", id, func() (postgres.Product, error) {
+		return s.store.GetProduct(ctx, id)
+	})
+}
+
+func (s *cachedStore) GetProductBySku(ctx context.Context, sku string) (postgres.Product, error) {
+	return cached(ctx, s, cacheProducts, "GetProductBySku", sku, func() (postgres.Product, error) {
+		return s.store.GetProductBySku(ctx, sku)
+	})
+}
+
+func (s *cachedStore) ListProducts(ctx context.Context) ([]postgres.Product, error) {
+	return cached(ctx, s, cacheProducts, "ListProducts", nil, func() ([]postgres.Product, error) {
+		return s.store.ListProducts(ctx)
+	})
+}
+
+func (s *cachedStore) ListProductsByIDs(ctx context.Context, ids []int64) ([]postgres.Product, error) {
+	return cached(ctx, s, cacheProducts, "ListProductsByIDs", ids, func() ([]postgres.Product, error) {
+		return s.store.ListProductsByIDs(ctx, ids)
+	})
+}
+
+func (s *cachedStore) ListProductsPage(ctx context.Context, arg postgres.ListProductsPageParams) (postgres.Page[postgres.Product], error) {
+	return cached(ctx, s, cacheProducts, "ListProductsPage", arg, func() (postgres.Page[postgres.Product], error) {
+		return s.store.ListProductsPage(ctx, arg)
+	})
+}
+
+func (s *cachedStore) SearchProducts(ctx context.Context, arg postgres.SearchProductsParams) ([]postgres.SearchProductsRow, error) {
+	return cached(ctx, s, cacheProducts, "SearchProducts", arg, func() ([]postgres.SearchProductsRow, error) {
+		return s.store.SearchProducts(ctx, arg)
+	})
+}
+
+func (s *cachedStore) SearchProductCategoryFacets(ctx context.Context, arg postgres.SearchProductCategoryFacetsParams) ([]postgres.SearchProductCategoryFacetsRow, error) {
+	return cached(ctx, s, cacheProducts, "SearchProductCategoryFacets", arg, func() ([]postgres.SearchProductCategoryFacetsRow, error) {
+		return s.store.SearchProductCategoryFacets(ctx, arg)
+	})
+}
+
+func (s *cachedStore) SearchProductPriceFacets(ctx context.Context, arg postgres.SearchProductPriceFacetsParams) ([]postgres.SearchProductPriceFacetsRow, error) {
+	return cached(ctx, s, cacheProducts, "SearchProductPriceFacets", arg, func() ([]postgres.SearchProductPriceFacetsRow, error) {
+		return s.store.SearchProductPriceFacets(ctx, arg)
+	})
+}
+
+func (s *cachedStore) SearchProductsByCategory(ctx context.Context, name string) ([]postgres.Product, error) {
+	return cached(ctx, s, cacheProducts, "SearchProductsByCategory", name, func() ([]postgres.Product, error) {
+		return s.store.SearchProductsByCategory(ctx, name)
+	})
+}
+
+func (s *cachedStore) SearchProductsByName(ctx context.Context, dollar_1 sql.NullString) ([]postgres.Product, error) {
+	return cached(ctx, s, cacheProducts, "SearchProductsByName", dollar_1, func() ([]postgres.Product, error) {
+		return s.store.SearchProductsByName(ctx, dollar_1)
+	})
+}
+
+// Category reads
+
+func (s *cachedStore) GetCategory(ctx context.Context, id int64) (postgres.Category, error) {
+	return cached(ctx, s, cacheCategories, "GetCategory", id, func() (postgres.Category, error) {
+		return s.store.GetCategory(ctx, id)
+	})
+}
+
+func (s *cachedStore) GetCategoryBySlug(ctx context.Context, slug string) (postgres.Category, error) {
+	return cached(ctx, s, cacheCategories, "GetCategoryBySlug", slug, func() (postgres.Category, error) {
+		return s.store.GetCategoryBySlug(ctx, slug)
+	})
+}
+
+func (s *cachedStore) ListCategories(ctx context.Context) ([]postgres.Category, error) {
+	return cached(ctx, s, cacheCategories, "ListCategories", nil, func() ([]postgres.Category, error) {
+		return s.store.ListCategories(ctx)
+	})
+}
+
+func (s *cachedStore) ListCategoryChildren(ctx context.Context, parentID sql.NullInt64) ([]postgres.Category, error) {
+	return cached(ctx, s, cacheCategories, "ListCategoryChildren", parentID, func() ([]postgres.Category, error) {
+		return s.store.ListCategoryChildren(ctx, parentID)
+	})
+}
+
+func (s *cachedStore) ListCategoryDescendantIDs(ctx context.Context, id int64) ([]int64, error) {
+	return cached(ctx, s, cacheCategories, "ListCategoryDescendantIDs", id, func() ([]int64, error) {
+		return s.store.ListCategoryDescendantIDs(ctx, id)
+	})
+}
+
+// Product writes, stock changes included
+
+func (q cachedQuerier) CreateProduct(ctx context.Context, arg postgres.CreateProductParams) (postgres.Product, error) {
+	data, err := q.Querier.CreateProduct(ctx, arg)
+	q.changed(ctx, err, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) UpdateProduct(ctx context.Context, arg postgres.UpdateProductParams) (postgres.Product, error) {
+	data, err := q.Querier.UpdateProduct(ctx, arg)
+	q.changed(ctx, err, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) UpsertProductBySku(ctx context.Context, arg postgres.UpsertProductBySkuParams) (postgres.UpsertProductBySkuRow, error) {
+	data, err := q.Querier.UpsertProductBySku(ctx, arg)
+	q.changed(ctx, err, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) DeleteProduct(ctx context.Context, id int64) (postgres.Product, error) {
+	data, err := q.Querier.DeleteProduct(ctx, id)
+	q.changed(ctx, err, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) RestoreProduct(ctx context.Context, id int64) (postgres.Product, error) {
+	data, err := q.Querier.RestoreProduct(ctx, id)
+	q.changed(ctx, err, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) PurgeDeletedProducts(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	purged, err := q.Querier.PurgeDeletedProducts(ctx, deletedAt)
+	if purged > 0 {
+		q.changed(ctx, err, cacheProducts)
+	}
+	return purged, err
+}
+
+func (q cachedQuerier) UpdateProductStock(ctx context.Context, arg postgres.UpdateProductStockParams) (postgres.Product, error) {
+	data, err := q.Querier.UpdateProductStock(ctx, arg)
+	q.changed(ctx, err, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) ReserveProductStock(ctx context.Context, arg postgres.ReserveProductStockParams) (postgres.Product, error) {
+	data, err := q.Querier.ReserveProductStock(ctx, arg)
+	q.changed(ctx, err, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) RestockProduct(ctx context.Context, arg postgres.RestockProductParams) (postgres.Product, error) {
+	data, err := q.Querier.RestockProduct(ctx, arg)
+	q.changed(ctx, err, cacheProducts)
+	return data, err
+}
+
+// Category writes; the category filter of product lists follows the category tree, so they change product lists too
+
+func (q cachedQuerier) CreateCategory(ctx context.Context, arg postgres.CreateCategoryParams) (postgres.Category, error) {
+	data, err := q.Querier.CreateCategory(ctx, arg)
+	q.changed(ctx, err, cacheCategories, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) UpdateCategory(ctx context.Context, arg postgres.UpdateCategoryParams) (postgres.Category, error) {
+	data, err := q.Querier.UpdateCategory(ctx, arg)
+	q.changed(ctx, err, cacheCategories, cacheProducts)
+	return data, err
+}
+
+func (q cachedQuerier) DeleteCategory(ctx context.Context, id int64) error {
+	err := q.Querier.DeleteCategory(ctx, id)
+	q.changed(ctx, err, cacheCategories, cacheProducts)
+	return err
+}
+
+// changed invalidates groups after a write that succeeded
+func (q cachedQuerier) changed(ctx context.Context, err error, groups ...string) {
+	if err == nil {
+		q.invalidate(ctx, groups...)
+	}
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// GetAPIKeyByPrefix does not find keys of deleted users, so they stop authenticating
+func (q *Queries) GetAPIKeyByPrefix(ctx context.Context, prefix string) (postgres.ApiKey, error) {
+	defer q.read()()
+	data := q.data
+	i, ok := data.apiKeys.first(func(i postgres.ApiKey) bool {
+		if i.Prefix != prefix {
+			return false
+		}
+		user, ok := data.users.get(i.UserID)
+		return ok && !user.DeletedAt.Valid
+	})
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) ListAPIKeysByUser(ctx context.Context, userID int64) ([]postgres.ApiKey, error) {
+	defer q.read()()
+	items := q.data.apiKeys.all(func(i postgres.ApiKey) bool { return i.UserID == userID })
+	sortRows(items, func(a, b postgres.ApiKey) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return items, nil
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg postgres.CreateAPIKeyParams) (postgres.ApiKey, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.users.get(arg.UserID); !ok {
+		return postgres.ApiKey{}, foreignKeyViolation("api_keys", "api_keys_user_id_fkey")
+	}
+	if data.apiKeys.exists(func(k postgres.ApiKey) bool { return k.Prefix == arg.Prefix }) {
+		return postgres.ApiKey{}, uniqueViolation("api_keys_prefix_key")
+	}
+	i := postgres.ApiKey{
+		ID:          data.apiKeys.next(),
+		UserID:      arg.UserID,
+		Name:        arg.Name,
+		Prefix:      arg.Prefix,
+		SecretHash:  arg.SecretHash,
+		Permissions: cloneStrings(arg.Permissions),
+		CreatedAt:   now(),
+	}
+	put(q, &data.apiKeys, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg postgres.RevokeAPIKeyParams) (postgres.ApiKey, error) {
+	defer q.write()()
+	return update(q, &q.data.apiKeys, arg.ID, func(i postgres.ApiKey) bool {
+		return i.UserID == arg.UserID && !i.RevokedAt.Valid
+	}, func(i *postgres.ApiKey) error {
+		i.RevokedAt = nullNow()
+		return nil
+	})
+}
+
+func (q *Queries) TouchAPIKey(ctx context.Context, id int64) error {
+	defer q.write()()
+	_, err := update(q, &q.data.apiKeys, id, nil, func(i *postgres.ApiKey) error {
+		i.LastUsedAt = nullNow()
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
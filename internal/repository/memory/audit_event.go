@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg postgres.CreateAuditEventParams) error {
+	defer q.write()()
+	data := q.data
+	before, err := jsonb("audit_events", "before", arg.Before)
+	if err != nil {
+		return err
+	}
+	after, err := jsonb("audit_events", "after", arg.After)
+	if err != nil {
+		return err
+	}
+	i := postgres.AuditEvent{
+		ID:         data.auditEvents.next(),
+		ActorType:  arg.ActorType,
+		ActorID:    arg.ActorID,
+		Action:     arg.Action,
+		EntityType: arg.EntityType,
+		EntityID:   arg.EntityID,
+		Before:     before,
+		After:      after,
+		RequestID:  arg.RequestID,
+		Ip:         arg.Ip,
+		CreatedAt:  now(),
+	}
+	put(q, &data.auditEvents, i.ID, i)
+	return nil
+}
+
+func (q *Queries) GetAuditEvent(ctx context.Context, id int64) (postgres.AuditEvent, error) {
+	defer q.read()()
+	i, ok := q.data.auditEvents.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
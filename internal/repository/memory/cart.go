@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// checkCartItem enforces the constraints of the cart_items table on a row about to be stored
+func (q *Queries) checkCartItem(i postgres.CartItem) error {
+	data := q.data
+	if i.Quantity <= 0 {
+		return checkViolation("cart_items", "cart_items_quantity_check")
+	}
+	if _, ok := data.users.get(i.UserID); !ok {
+		return foreignKeyViolation("cart_items", "cart_items_user_id_fkey")
+	}
+	if _, ok := data.products.get(i.ProductID); !ok {
+		return foreignKeyViolation("cart_items", "cart_items_product_id_fkey")
+	}
+	if i.VariantID.Valid {
+		if _, ok := data.productVariants.get(i.VariantID.Int64); !ok {
+			return foreignKeyViolation("cart_items", "cart_items_variant_id_fkey")
+		}
+	}
+	return nil
+}
+
+// sameLine reports whether two cart items are the same product and variant, as cart_items_line_idx compares them
+func sameLine(a, b postgres.CartItem) bool {
+	return a.UserID == b.UserID && a.ProductID == b.ProductID && a.VariantID.Int64 == b.VariantID.Int64
+}
+
+func (q *Queries) ListCartItems(ctx context.Context, userID int64) ([]postgres.CartItem, error) {
+	defer q.read()()
+	return q.data.cartItems.all(func(i postgres.CartItem) bool { return i.UserID == userID }), nil
+}
+
+func (q *Queries) AddCartItem(ctx context.Context, arg postgres.AddCartItemParams) (postgres.CartItem, error) {
+	defer q.write()()
+	data := q.data
+	i := postgres.CartItem{
+		UserID:    arg.UserID,
+		ProductID: arg.ProductID,
+		VariantID: arg.VariantID,
+		Quantity:  arg.Quantity,
+		AddedAt:   now(),
+	}
+	if err := q.checkCartItem(i); err != nil {
+		return postgres.CartItem{}, err
+	}
+	if existing, ok := data.cartItems.first(func(c postgres.CartItem) bool { return sameLine(c, i) }); ok {
+		return update(q, &data.cartItems, existing.ID, nil, func(c *postgres.CartItem) error {
+			c.Quantity += arg.Quantity
+			return nil
+		})
+	}
+	i.ID = data.cartItems.next()
+	put(q, &data.cartItems, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) UpdateCartItemQuantity(ctx context.Context, arg postgres.UpdateCartItemQuantityParams) (postgres.CartItem, error) {
+	defer q.write()()
+	return update(q, &q.data.cartItems, arg.ID, func(i postgres.CartItem) bool { return i.UserID == arg.UserID }, func(i *postgres.CartItem) error {
+		if arg.Quantity <= 0 {
+			return checkViolation("cart_items", "cart_items_quantity_check")
+		}
+		i.Quantity = arg.Quantity
+		return nil
+	})
+}
+
+func (q *Queries) DeleteCartItem(ctx context.Context, arg postgres.DeleteCartItemParams) (int64, error) {
+	defer q.write()()
+	data := q.data
+	i, ok := data.cartItems.get(arg.ID)
+	if !ok || i.UserID != arg.UserID {
+		return 0, nil
+	}
+	remove(q, &data.cartItems, i.ID)
+	return 1, nil
+}
+
+func (q *Queries) ClearCart(ctx context.Context, userID int64) error {
+	defer q.write()()
+	q.removeCartItems(func(i postgres.CartItem) bool { return i.UserID == userID })
+	return nil
+}
+
+// removeCartItems deletes the cart items accepted by match, as the ON DELETE CASCADE of their references does
+func (q *Queries) removeCartItems(match func(postgres.CartItem) bool) {
+	data := q.data
+	for _, id := range data.cartItems.ids(match) {
+		remove(q, &data.cartItems, id)
+	}
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// categoryTree returns the id of a category followed by the ids of all its descendants, as the recursive CTE does
+func (q *Queries) categoryTree(id int64) []int64 {
+	data := q.data
+	if _, ok := data.categories.get(id); !ok {
+		return []int64{}
+	}
+	tree := []int64{id}
+	for i := 0; i < len(tree); i++ {
+		parent := tree[i]
+		tree = append(tree, data.categories.ids(func(c postgres.Category) bool {
+			return c.ParentID.Valid && c.ParentID.Int64 == parent
+		})...)
+	}
+	return tree
+}
+
+// checkCategory enforces the constraints of the categories table on a row about to be stored
+func (q *Queries) checkCategory(i postgres.Category) error {
+	data := q.data
+	if i.ParentID.Valid {
+		if _, ok := data.categories.get(i.ParentID.Int64); !ok {
+			return foreignKeyViolation("categories", "categories_parent_id_fkey")
+		}
+	}
+	if data.categories.exists(func(c postgres.Category) bool { return c.ID != i.ID && c.Slug == i.Slug }) {
+		return uniqueViolation("categories_slug_key")
+	}
+	return nil
+}
+
+func (q *Queries) GetCategory(ctx context.Context, id int64) (postgres.Category, error) {
+	defer q.read()()
+	i, ok := q.data.categories.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) GetCategoryBySlug(ctx context.Context, slug string) (postgres.Category, error) {
+	defer q.read()()
+	i, ok := q.data.categories.first(func(i postgres.Category) bool { return i.Slug == slug })
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// byPosition orders categories by position and then by name
+func byPosition(a, b postgres.Category) bool {
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	return strings.Compare(a.Name, b.Name) < 0
+}
+
+func (q *Queries) ListCategories(ctx context.Context) ([]postgres.Category, error) {
+	defer q.read()()
+	items := q.data.categories.all(nil)
+	sortRows(items, func(a, b postgres.Category) bool {
+		// parent_id NULLS FIRST
+		if a.ParentID.Valid != b.ParentID.Valid {
+			return !a.ParentID.Valid
+		}
+		if a.ParentID.Int64 != b.ParentID.Int64 {
+			return a.ParentID.Int64 < b.ParentID.Int64
+		}
+		return byPosition(a, b)
+	})
+	return items, nil
+}
+
+func (q *Queries) ListCategoryChildren(ctx context.Context, parentID sql.NullInt64) ([]postgres.Category, error) {
+	defer q.read()()
+	items := q.data.categories.all(func(i postgres.Category) bool {
+		return parentID.Valid && i.ParentID.Valid && i.ParentID.Int64 == parentID.Int64
+	})
+	sortRows(items, byPosition)
+	return items, nil
+}
+
+func (q *Queries) ListCategoryDescendantIDs(ctx context.Context, id int64) ([]int64, error) {
+	defer q.read()()
+	return q.categoryTree(id), nil
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg postgres.CreateCategoryParams) (postgres.Category, error) {
+	defer q.write()()
+	data := q.data
+	i := postgres.Category{
+		ParentID:  arg.ParentID,
+		Name:      arg.Name,
+		Slug:      arg.Slug,
+		Position:  arg.Position,
+		CreatedAt: now(),
+	}
+	if err := q.checkCategory(i); err != nil {
+		return postgres.Category{}, err
+	}
+	i.ID = data.categories.next()
+	put(q, &data.categories, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) UpdateCategory(ctx context.Context, arg postgres.UpdateCategoryParams) (postgres.Category, error) {
+	defer q.write()()
+	return update(q, &q.data.categories, arg.ID, nil, func(i *postgres.Category) error {
+		i.ParentID = arg.ParentID
+		i.Name = arg.Name
+		i.Slug = arg.Slug
+		i.Position = arg.Position
+		return q.checkCategory(*i)
+	})
+}
+
+func (q *Queries) DeleteCategory(ctx context.Context, id int64) error {
+	defer q.write()()
+	data := q.data
+	if data.categories.exists(func(c postgres.Category) bool { return c.ParentID.Valid && c.ParentID.Int64 == id }) {
+		return foreignKeyViolation("categories", "categories_parent_id_fkey")
+	}
+	if data.products.exists(func(p postgres.Product) bool { return p.CategoryID == id }) {
+		return foreignKeyViolation("products", "products_category_id_fkey")
+	}
+	for _, p := range data.promotions.ids(func(p postgres.Promotion) bool { return p.CategoryID.Valid && p.CategoryID.Int64 == id }) {
+		q.removePromotion(p)
+	}
+	remove(q, &data.categories, id)
+	return nil
+}
+
+func (q *Queries) CountProductsByCategory(ctx context.Context, categoryID int64) (int64, error) {
+	defer q.read()()
+	return q.data.products.count(func(p postgres.Product) bool { return p.CategoryID == categoryID }), nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// ClaimIdempotencyKey claims a key for a new request. A key whose claim is older than ExpiresBefore is taken over;
+// no row is returned while the key is held by an earlier request.
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg postgres.ClaimIdempotencyKeyParams) (postgres.IdempotencyKey, error) {
+	defer q.write()()
+	data := q.data
+	held, ok := data.idempotencyKeys.first(func(i postgres.IdempotencyKey) bool {
+		return i.Owner == arg.Owner && i.Key == arg.Key
+	})
+	if !ok {
+		i := postgres.IdempotencyKey{
+			ID:          data.idempotencyKeys.next(),
+			Owner:       arg.Owner,
+			Key:         arg.Key,
+			Fingerprint: arg.Fingerprint,
+			CreatedAt:   now(),
+		}
+		put(q, &data.idempotencyKeys, i.ID, i)
+		return i, nil
+	}
+	return update(q, &data.idempotencyKeys, held.ID, func(i postgres.IdempotencyKey) bool {
+		return i.CreatedAt.Before(arg.ExpiresBefore)
+	}, func(i *postgres.IdempotencyKey) error {
+		i.Fingerprint = arg.Fingerprint
+		i.StatusCode = sql.NullInt32{}
+		i.ContentType = ""
+		i.ResponseBody = nil
+		i.CreatedAt = now()
+		i.CompletedAt = sql.NullTime{}
+		return nil
+	})
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg postgres.GetIdempotencyKeyParams) (postgres.IdempotencyKey, error) {
+	defer q.read()()
+	i, ok := q.data.idempotencyKeys.first(func(i postgres.IdempotencyKey) bool {
+		return i.Owner == arg.Owner && i.Key == arg.Key
+	})
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg postgres.CompleteIdempotencyKeyParams) error {
+	defer q.write()()
+	_, err := update(q, &q.data.idempotencyKeys, arg.ID, nil, func(i *postgres.IdempotencyKey) error {
+		i.StatusCode = arg.StatusCode
+		i.ContentType = arg.ContentType
+		i.ResponseBody = cloneBytes(arg.ResponseBody)
+		i.CompletedAt = nullNow()
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+func (q *Queries) DeleteIdempotencyKey(ctx context.Context, id int64) error {
+	defer q.write()()
+	remove(q, &q.data.idempotencyKeys, id)
+	return nil
+}
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context, createdAt time.Time) (int64, error) {
+	defer q.write()()
+	data := q.data
+	expired := data.idempotencyKeys.ids(func(i postgres.IdempotencyKey) bool { return i.CreatedAt.Before(createdAt) })
+	for _, id := range expired {
+		remove(q, &data.idempotencyKeys, id)
+	}
+	return int64(len(expired)), nil
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// containsFold matches like ILIKE '%' || substr || '%'
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+var userList = listQuery[postgres.User]{
+	sorts: map[string]sortField[postgres.User]{
+		"id":                {"bigint", func(i postgres.User) string { return formatID(i.ID) }},
+		"full_name":         {"text", func(i postgres.User) string { return i.FullName }},
+		"email":             {"text", func(i postgres.User) string { return i.Email }},
+		"registration_date": {"timestamp", func(i postgres.User) string { return formatTime(i.RegistrationDate) }},
+	},
+	defaultSort: "registration_date",
+	id:          func(i postgres.User) int64 { return i.ID },
+}
+
+// ListUsersPage returns one page of users matching every set filter; deleted users are left out unless IncludeDeleted is set
+func (q *Queries) ListUsersPage(ctx context.Context, arg postgres.ListUsersPageParams) (postgres.Page[postgres.User], error) {
+	defer q.read()()
+	rows := q.data.users.all(func(i postgres.User) bool {
+		return (arg.IncludeDeleted || !i.DeletedAt.Valid) &&
+			(!arg.Name.Valid || containsFold(i.FullName, arg.Name.String)) &&
+			(!arg.Email.Valid || i.Email == arg.Email.String) &&
+			(!arg.Role.Valid || i.Role == arg.Role.String) &&
+			(!arg.RegisteredFrom.Valid || !i.RegistrationDate.Before(arg.RegisteredFrom.Time)) &&
+			(!arg.RegisteredTo.Valid || i.RegistrationDate.Before(arg.RegisteredTo.Time))
+	})
+	return userList.list(rows, arg.PageParams)
+}
+
+var productList = listQuery[postgres.Product]{
+	sorts: map[string]sortField[postgres.Product]{
+		"id":             {"bigint", func(i postgres.Product) string { return formatID(i.ID) }},
+		"name":           {"text", func(i postgres.Product) string { return i.Name }},
+		"price":          {"numeric", func(i postgres.Product) string { return i.Price }},
+		"stock_quantity": {"int", func(i postgres.Product) string { return strconv.Itoa(int(i.StockQuantity)) }},
+		"addition_date":  {"timestamp", func(i postgres.Product) string { return formatTime(i.AdditionDate) }},
+	},
+	defaultSort: "addition_date",
+	id:          func(i postgres.Product) int64 { return i.ID },
+}
+
+// ListProductsPage returns one page of products matching every set filter; deleted products are left out unless IncludeDeleted is set.
+// A category filter also matches products of every descendant category.
+func (q *Queries) ListProductsPage(ctx context.Context, arg postgres.ListProductsPageParams) (postgres.Page[postgres.Product], error) {
+	defer q.read()()
+
+	inRange, err := priceRange(arg.MinPrice, arg.MaxPrice)
+	if err != nil {
+		return postgres.Page[postgres.Product]{}, err
+	}
+	var categories map[int64]bool
+	if arg.CategoryID.Valid {
+		categories = map[int64]bool{}
+		for _, id := range q.categoryTree(arg.CategoryID.Int64) {
+			categories[id] = true
+		}
+	}
+
+	rows := q.data.products.all(func(i postgres.Product) bool {
+		return (arg.IncludeDeleted || !i.DeletedAt.Valid) &&
+			(!arg.Name.Valid || containsFold(i.Name, arg.Name.String)) &&
+			(categories == nil || categories[i.CategoryID]) &&
+			inRange(i.Price) &&
+			(!arg.InStock.Valid || (i.StockQuantity > 0) == arg.InStock.Bool)
+	})
+	return productList.list(rows, arg.PageParams)
+}
+
+var orderList = listQuery[postgres.Order]{
+	sorts: map[string]sortField[postgres.Order]{
+		"id":           {"bigint", func(i postgres.Order) string { return formatID(i.ID) }},
+		"total_amount": {"numeric", func(i postgres.Order) string { return i.TotalAmount }},
+		"order_date":   {"timestamp", func(i postgres.Order) string { return formatTime(i.OrderDate) }},
+	},
+	defaultSort: "order_date",
+	id:          func(i postgres.Order) int64 { return i.ID },
+}
+
+// ListOrdersPage returns one page of orders matching every set filter; deleted orders are left out unless IncludeDeleted is set
+func (q *Queries) ListOrdersPage(ctx context.Context, arg postgres.ListOrdersPageParams) (postgres.Page[postgres.Order], error) {
+	defer q.read()()
+	rows := q.data.orders.all(func(i postgres.Order) bool {
+		return (arg.IncludeDeleted || !i.DeletedAt.Valid) &&
+			(!arg.UserID.Valid || i.UserID == arg.UserID.Int64) &&
+			(!arg.Status.Valid || i.Status == arg.Status.OrderStatus) &&
+			(!arg.From.Valid || !i.OrderDate.Before(arg.From.Time)) &&
+			(!arg.To.Valid || i.OrderDate.Before(arg.To.Time))
+	})
+	return orderList.list(rows, arg.PageParams)
+}
+
+var paymentList = listQuery[postgres.Payment]{
+	sorts: map[string]sortField[postgres.Payment]{
+		"id":           {"bigint", func(i postgres.Payment) string { return formatID(i.ID) }},
+		"amount":       {"numeric", func(i postgres.Payment) string { return i.Amount }},
+		"payment_date": {"timestamp", func(i postgres.Payment) string { return formatTime(i.PaymentDate) }},
+	},
+	defaultSort: "payment_date",
+	id:          func(i postgres.Payment) int64 { return i.ID },
+}
+
+// ListPaymentsPage returns one page of payments matching every set filter; deleted payments are left out unless IncludeDeleted is set
+func (q *Queries) ListPaymentsPage(ctx context.Context, arg postgres.ListPaymentsPageParams) (postgres.Page[postgres.Payment], error) {
+	defer q.read()()
+	rows := q.data.payments.all(func(i postgres.Payment) bool {
+		return (arg.IncludeDeleted || !i.DeletedAt.Valid) &&
+			(!arg.UserID.Valid || i.UserID == arg.UserID.Int64) &&
+			(!arg.OrderID.Valid || i.OrderID == arg.OrderID.Int64) &&
+			(!arg.Status.Valid || i.Status == arg.Status.PaymentStatus) &&
+			(!arg.From.Valid || !i.PaymentDate.Before(arg.From.Time)) &&
+			(!arg.To.Valid || i.PaymentDate.Before(arg.To.Time))
+	})
+	return paymentList.list(rows, arg.PageParams)
+}
+
+var orderItemList = listQuery[postgres.OrderItem]{
+	sorts: map[string]sortField[postgres.OrderItem]{
+		"id":       {"bigint", func(i postgres.OrderItem) string { return formatID(i.ID) }},
+		"quantity": {"int", func(i postgres.OrderItem) string { return strconv.Itoa(int(i.Quantity)) }},
+		"price":    {"numeric", func(i postgres.OrderItem) string { return i.Price }},
+	},
+	defaultSort: "id",
+	id:          func(i postgres.OrderItem) int64 { return i.ID },
+}
+
+// ListOrderItemsPage returns one page of order items matching every set filter
+func (q *Queries) ListOrderItemsPage(ctx context.Context, arg postgres.ListOrderItemsPageParams) (postgres.Page[postgres.OrderItem], error) {
+	defer q.read()()
+	rows := q.data.orderItems.all(func(i postgres.OrderItem) bool {
+		return (!arg.OrderID.Valid || i.OrderID == arg.OrderID.Int64) &&
+			(!arg.ProductID.Valid || i.ProductID == arg.ProductID.Int64)
+	})
+	return orderItemList.list(rows, arg.PageParams)
+}
+
+var auditEventList = listQuery[postgres.AuditEvent]{
+	sorts: map[string]sortField[postgres.AuditEvent]{
+		"id":         {"bigint", func(i postgres.AuditEvent) string { return formatID(i.ID) }},
+		"created_at": {"timestamp", func(i postgres.AuditEvent) string { return formatTime(i.CreatedAt) }},
+	},
+	defaultSort: "-created_at",
+	id:          func(i postgres.AuditEvent) int64 { return i.ID },
+}
+
+// ListAuditEventsPage returns one page of audit events matching every set filter, newest first by default
+func (q *Queries) ListAuditEventsPage(ctx context.Context, arg postgres.ListAuditEventsPageParams) (postgres.Page[postgres.AuditEvent], error) {
+	defer q.read()()
+	rows := q.data.auditEvents.all(func(i postgres.AuditEvent) bool {
+		return (!arg.ActorType.Valid || i.ActorType == arg.ActorType.String) &&
+			(!arg.ActorID.Valid || i.ActorID == arg.ActorID.String) &&
+			(!arg.Action.Valid || i.Action == arg.Action.String) &&
+			(!arg.EntityType.Valid || i.EntityType == arg.EntityType.String) &&
+			(!arg.EntityID.Valid || i.EntityID == arg.EntityID.Int64) &&
+			(!arg.RequestID.Valid || i.RequestID == arg.RequestID.String) &&
+			(!arg.From.Valid || !i.CreatedAt.Before(arg.From.Time)) &&
+			(!arg.To.Valid || i.CreatedAt.Before(arg.To.Time))
+	})
+	return auditEventList.list(rows, arg.PageParams)
+}
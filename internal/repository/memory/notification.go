@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func (q *Queries) GetNotification(ctx context.Context, id int64) (postgres.Notification, error) {
+	defer q.read()()
+	i, ok := q.data.notifications.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) ListNotificationsByUser(ctx context.Context, userID int64) ([]postgres.Notification, error) {
+	defer q.read()()
+	items := q.data.notifications.all(func(i postgres.Notification) bool { return i.UserID == userID })
+	sortRows(items, func(a, b postgres.Notification) bool { return a.CreatedAt.After(b.CreatedAt) })
+	return items, nil
+}
+
+func (q *Queries) CreateNotification(ctx context.Context, arg postgres.CreateNotificationParams) (postgres.Notification, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.users.get(arg.UserID); !ok {
+		return postgres.Notification{}, foreignKeyViolation("notifications", "notifications_user_id_fkey")
+	}
+	if arg.OrderID.Valid {
+		if _, ok := data.orders.get(arg.OrderID.Int64); !ok {
+			return postgres.Notification{}, foreignKeyViolation("notifications", "notifications_order_id_fkey")
+		}
+	}
+	i := postgres.Notification{
+		ID:            data.notifications.next(),
+		UserID:        arg.UserID,
+		OrderID:       arg.OrderID,
+		Event:         arg.Event,
+		Channel:       "email",
+		Recipient:     arg.Recipient,
+		Locale:        arg.Locale,
+		Subject:       arg.Subject,
+		BodyText:      arg.BodyText,
+		BodyHtml:      arg.BodyHtml,
+		Status:        postgres.NotificationStatusPending,
+		NextAttemptAt: arg.NextAttemptAt,
+		CreatedAt:     now(),
+	}
+	put(q, &data.notifications, i.ID, i)
+	return i, nil
+}
+
+// ClaimDueNotifications moves the due notifications to the end of a lease, so that other workers skip them
+func (q *Queries) ClaimDueNotifications(ctx context.Context, arg postgres.ClaimDueNotificationsParams) ([]postgres.Notification, error) {
+	defer q.write()()
+	data := q.data
+	due := now()
+	items := data.notifications.all(func(i postgres.Notification) bool {
+		return i.Status == postgres.NotificationStatusPending && !i.NextAttemptAt.After(due)
+	})
+	sortRows(items, func(a, b postgres.Notification) bool { return a.NextAttemptAt.Before(b.NextAttemptAt) })
+	items = head(items, arg.Limit)
+
+	for n, i := range items {
+		i.NextAttemptAt = arg.NextAttemptAt
+		put(q, &data.notifications, i.ID, i)
+		items[n] = i
+	}
+	return items, nil
+}
+
+func (q *Queries) MarkNotificationSent(ctx context.Context, id int64) error {
+	defer q.write()()
+	_, err := update(q, &q.data.notifications, id, nil, func(i *postgres.Notification) error {
+		i.Status = postgres.NotificationStatusSent
+		i.Attempts++
+		i.LastError = sql.NullString{}
+		i.SentAt = nullNow()
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+func (q *Queries) MarkNotificationFailed(ctx context.Context, arg postgres.MarkNotificationFailedParams) error {
+	defer q.write()()
+	_, err := update(q, &q.data.notifications, arg.ID, nil, func(i *postgres.Notification) error {
+		i.Status = arg.Status
+		i.Attempts++
+		i.LastError = arg.LastError
+		i.NextAttemptAt = arg.NextAttemptAt
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
@@ -1 +1,189 @@
-package memory
\ No newline at end of file
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// updateOrder changes an order accepted by match and bumps its version, as the bump_version trigger does
+func (q *Queries) updateOrder(id int64, match func(postgres.Order) bool, change func(*postgres.Order) error) (postgres.Order, error) {
+	return update(q, &q.data.orders, id, match, func(i *postgres.Order) error {
+		if err := change(i); err != nil {
+			return err
+		}
+		i.Version++
+		return nil
+	})
+}
+
+// removeOrder deletes an order together with the rows that reference it ON DELETE CASCADE
+func (q *Queries) removeOrder(id int64) {
+	data := q.data
+	for _, r := range data.promotionRedemptions.ids(func(r postgres.PromotionRedemption) bool { return r.OrderID == id }) {
+		remove(q, &data.promotionRedemptions, r)
+	}
+	for _, a := range data.orderAdjustments.ids(func(a postgres.OrderAdjustment) bool { return a.OrderID == id }) {
+		remove(q, &data.orderAdjustments, a)
+	}
+	for _, s := range data.shipments.ids(func(s postgres.Shipment) bool { return s.OrderID == id }) {
+		remove(q, &data.shipments, s)
+	}
+	for _, a := range data.orderAddresses.ids(func(a postgres.OrderAddress) bool { return a.OrderID == id }) {
+		remove(q, &data.orderAddresses, a)
+	}
+	for _, r := range data.returns.ids(func(r postgres.Return) bool { return r.OrderID == id }) {
+		q.removeReturn(r)
+	}
+	remove(q, &data.orders, id)
+}
+
+func (q *Queries) GetOrder(ctx context.Context, id int64) (postgres.Order, error) {
+	defer q.read()()
+	i, ok := q.data.orders.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// byOrderDate orders orders by order_date
+func byOrderDate(a, b postgres.Order) bool {
+	return a.OrderDate.Before(b.OrderDate)
+}
+
+func (q *Queries) ListOrders(ctx context.Context) ([]postgres.Order, error) {
+	defer q.read()()
+	items := q.data.orders.all(func(i postgres.Order) bool { return !i.DeletedAt.Valid })
+	sortRows(items, byOrderDate)
+	return items, nil
+}
+
+func (q *Queries) CreateOrder(ctx context.Context, arg postgres.CreateOrderParams) (postgres.Order, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.users.get(arg.UserID); !ok {
+		return postgres.Order{}, foreignKeyViolation("orders", "orders_user_id_fkey")
+	}
+	if arg.ShippingMethodID.Valid {
+		if _, ok := data.shippingMethods.get(arg.ShippingMethodID.Int64); !ok {
+			return postgres.Order{}, foreignKeyViolation("orders", "orders_shipping_method_id_fkey")
+		}
+	}
+	total, err := money(arg.TotalAmount)
+	if err != nil {
+		return postgres.Order{}, err
+	}
+	i := postgres.Order{
+		ID:                 data.orders.next(),
+		UserID:             arg.UserID,
+		TotalAmount:        total,
+		OrderDate:          now(),
+		Status:             postgres.OrderStatusNew,
+		NetAmount:          "0.00",
+		TaxAmount:          "0.00",
+		PricesIncludeTax:   arg.PricesIncludeTax,
+		DestinationCountry: arg.DestinationCountry,
+		ShippingMethodID:   arg.ShippingMethodID,
+		ShippingAmount:     "0.00",
+		Version:            1,
+	}
+	put(q, &data.orders, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) UpdateOrder(ctx context.Context, arg postgres.UpdateOrderParams) (postgres.Order, error) {
+	defer q.write()()
+	data := q.data
+	return q.updateOrder(arg.ID, func(i postgres.Order) bool { return i.Version == arg.Version }, func(i *postgres.Order) error {
+		if _, ok := data.users.get(arg.UserID); !ok {
+			return foreignKeyViolation("orders", "orders_user_id_fkey")
+		}
+		total, err := money(arg.TotalAmount)
+		if err != nil {
+			return err
+		}
+		i.UserID = arg.UserID
+		i.TotalAmount = total
+		i.Status = arg.Status
+		return nil
+	})
+}
+
+func (q *Queries) DeleteOrder(ctx context.Context, id int64) (postgres.Order, error) {
+	defer q.write()()
+	return q.updateOrder(id, func(i postgres.Order) bool { return !i.DeletedAt.Valid }, func(i *postgres.Order) error {
+		i.DeletedAt = nullNow()
+		return nil
+	})
+}
+
+func (q *Queries) RestoreOrder(ctx context.Context, id int64) (postgres.Order, error) {
+	defer q.write()()
+	return q.updateOrder(id, func(i postgres.Order) bool { return i.DeletedAt.Valid }, func(i *postgres.Order) error {
+		i.DeletedAt = sql.NullTime{}
+		return nil
+	})
+}
+
+// PurgeDeletedOrders keeps orders with payments until those are purged; items and notifications go with the order
+func (q *Queries) PurgeDeletedOrders(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	defer q.write()()
+	data := q.data
+	expired := data.orders.ids(func(o postgres.Order) bool {
+		return deletedBefore(o.DeletedAt, deletedAt) &&
+			!data.payments.exists(func(p postgres.Payment) bool { return p.OrderID == o.ID })
+	})
+	for _, id := range expired {
+		for _, item := range data.orderItems.ids(func(i postgres.OrderItem) bool { return i.OrderID == id }) {
+			q.removeOrderItem(item)
+		}
+		for _, n := range data.notifications.ids(func(n postgres.Notification) bool { return n.OrderID.Valid && n.OrderID.Int64 == id }) {
+			remove(q, &data.notifications, n)
+		}
+		q.removeOrder(id)
+	}
+	return int64(len(expired)), nil
+}
+
+func (q *Queries) SearchOrdersByUser(ctx context.Context, userID int64) ([]postgres.Order, error) {
+	defer q.read()()
+	items := q.data.orders.all(func(i postgres.Order) bool { return i.UserID == userID && !i.DeletedAt.Valid })
+	sortRows(items, byOrderDate)
+	return items, nil
+}
+
+func (q *Queries) SearchOrdersByStatus(ctx context.Context, status postgres.OrderStatus) ([]postgres.Order, error) {
+	defer q.read()()
+	items := q.data.orders.all(func(i postgres.Order) bool { return i.Status == status && !i.DeletedAt.Valid })
+	sortRows(items, byOrderDate)
+	return items, nil
+}
+
+func (q *Queries) SetOrderTotals(ctx context.Context, arg postgres.SetOrderTotalsParams) (postgres.Order, error) {
+	defer q.write()()
+	if err := monies(&arg.NetAmount, &arg.TaxAmount, &arg.ShippingAmount, &arg.TotalAmount); err != nil {
+		return postgres.Order{}, err
+	}
+	return q.updateOrder(arg.ID, nil, func(i *postgres.Order) error {
+		i.NetAmount = arg.NetAmount
+		i.TaxAmount = arg.TaxAmount
+		i.ShippingAmount = arg.ShippingAmount
+		i.TotalAmount = arg.TotalAmount
+		return nil
+	})
+}
+
+func (q *Queries) SetOrderStatus(ctx context.Context, arg postgres.SetOrderStatusParams) (postgres.Order, error) {
+	defer q.write()()
+	return q.updateOrder(arg.ID, nil, func(i *postgres.Order) error {
+		i.Status = arg.Status
+		return nil
+	})
+}
+
+// GetOrderForUpdate needs no row lock of its own, as transactions of the memory store already run one at a time
+func (q *Queries) GetOrderForUpdate(ctx context.Context, id int64) (postgres.Order, error) {
+	return q.GetOrder(ctx, id)
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// CreateOrderAddress copies a user address onto an order; type is given as the billing address may be a shipping one
+func (q *Queries) CreateOrderAddress(ctx context.Context, arg postgres.CreateOrderAddressParams) (postgres.OrderAddress, error) {
+	defer q.write()()
+	data := q.data
+	a, ok := data.userAddresses.get(arg.AddressID)
+	if !ok {
+		return postgres.OrderAddress{}, sql.ErrNoRows
+	}
+	if _, ok := data.orders.get(arg.OrderID); !ok {
+		return postgres.OrderAddress{}, foreignKeyViolation("order_addresses", "order_addresses_order_id_fkey")
+	}
+	if data.orderAddresses.exists(func(o postgres.OrderAddress) bool { return o.OrderID == arg.OrderID && o.Type == arg.Type }) {
+		return postgres.OrderAddress{}, uniqueViolation("order_addresses_order_id_type_key")
+	}
+	i := postgres.OrderAddress{
+		ID:         data.orderAddresses.next(),
+		OrderID:    arg.OrderID,
+		Type:       arg.Type,
+		FullName:   a.FullName,
+		Phone:      a.Phone,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		CreatedAt:  now(),
+	}
+	put(q, &data.orderAddresses, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) ListOrderAddresses(ctx context.Context, orderID int64) ([]postgres.OrderAddress, error) {
+	defer q.read()()
+	items := q.data.orderAddresses.all(func(i postgres.OrderAddress) bool { return i.OrderID == orderID })
+	sortRows(items, func(a, b postgres.OrderAddress) bool { return addressTypeOrder(a.Type) < addressTypeOrder(b.Type) })
+	return items, nil
+}
+
+// addressTypeOrder is the position of an address type in its enum, which is how Postgres sorts enums
+func addressTypeOrder(t postgres.AddressType) int {
+	if t == postgres.AddressTypeShipping {
+		return 0
+	}
+	return 1
+}
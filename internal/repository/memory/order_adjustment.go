@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func (q *Queries) CreateOrderAdjustment(ctx context.Context, arg postgres.CreateOrderAdjustmentParams) (postgres.OrderAdjustment, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.orders.get(arg.OrderID); !ok {
+		return postgres.OrderAdjustment{}, foreignKeyViolation("order_adjustments", "order_adjustments_order_id_fkey")
+	}
+	if arg.PromotionID.Valid {
+		if _, ok := data.promotions.get(arg.PromotionID.Int64); !ok {
+			return postgres.OrderAdjustment{}, foreignKeyViolation("order_adjustments", "order_adjustments_promotion_id_fkey")
+		}
+	}
+	amount, err := money(arg.Amount)
+	if err != nil {
+		return postgres.OrderAdjustment{}, err
+	}
+	i := postgres.OrderAdjustment{
+		ID:          data.orderAdjustments.next(),
+		OrderID:     arg.OrderID,
+		PromotionID: arg.PromotionID,
+		Kind:        arg.Kind,
+		Code:        arg.Code,
+		Description: arg.Description,
+		Amount:      amount,
+		CreatedAt:   now(),
+	}
+	put(q, &data.orderAdjustments, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) ListOrderAdjustmentsByOrder(ctx context.Context, orderID int64) ([]postgres.OrderAdjustment, error) {
+	defer q.read()()
+	return q.data.orderAdjustments.all(func(i postgres.OrderAdjustment) bool { return i.OrderID == orderID }), nil
+}
+
+func (q *Queries) DeleteOrderAdjustmentsByOrder(ctx context.Context, orderID int64) error {
+	defer q.write()()
+	data := q.data
+	for _, id := range data.orderAdjustments.ids(func(i postgres.OrderAdjustment) bool { return i.OrderID == orderID }) {
+		remove(q, &data.orderAdjustments, id)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// removeOrderItem deletes an order item together with the return items that reference it ON DELETE CASCADE
+func (q *Queries) removeOrderItem(id int64) {
+	data := q.data
+	for _, r := range data.returnItems.ids(func(r postgres.ReturnItem) bool { return r.OrderItemID == id }) {
+		remove(q, &data.returnItems, r)
+	}
+	remove(q, &data.orderItems, id)
+}
+
+// checkOrderItem enforces the foreign keys of the order_items table on a row about to be stored
+func (q *Queries) checkOrderItem(i postgres.OrderItem) error {
+	data := q.data
+	if _, ok := data.orders.get(i.OrderID); !ok {
+		return foreignKeyViolation("order_items", "order_items_order_id_fkey")
+	}
+	if _, ok := data.products.get(i.ProductID); !ok {
+		return foreignKeyViolation("order_items", "order_items_product_id_fkey")
+	}
+	if i.VariantID.Valid {
+		if _, ok := data.productVariants.get(i.VariantID.Int64); !ok {
+			return foreignKeyViolation("order_items", "order_items_variant_id_fkey")
+		}
+	}
+	return nil
+}
+
+func (q *Queries) GetOrderItem(ctx context.Context, id int64) (postgres.OrderItem, error) {
+	defer q.read()()
+	i, ok := q.data.orderItems.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) ListOrderItems(ctx context.Context) ([]postgres.OrderItem, error) {
+	defer q.read()()
+	return q.data.orderItems.all(nil), nil
+}
+
+func (q *Queries) CreateOrderItem(ctx context.Context, arg postgres.CreateOrderItemParams) (postgres.OrderItem, error) {
+	defer q.write()()
+	data := q.data
+	if err := monies(&arg.Price, &arg.NetAmount, &arg.TaxAmount, &arg.GrossAmount); err != nil {
+		return postgres.OrderItem{}, err
+	}
+	rate, err := numeric(arg.TaxRate, 6, 4)
+	if err != nil {
+		return postgres.OrderItem{}, err
+	}
+	i := postgres.OrderItem{
+		OrderID:     arg.OrderID,
+		ProductID:   arg.ProductID,
+		Quantity:    arg.Quantity,
+		Price:       arg.Price,
+		VariantID:   arg.VariantID,
+		TaxRate:     rate,
+		NetAmount:   arg.NetAmount,
+		TaxAmount:   arg.TaxAmount,
+		GrossAmount: arg.GrossAmount,
+	}
+	if err := q.checkOrderItem(i); err != nil {
+		return postgres.OrderItem{}, err
+	}
+	i.ID = data.orderItems.next()
+	put(q, &data.orderItems, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) UpdateOrderItem(ctx context.Context, arg postgres.UpdateOrderItemParams) (postgres.OrderItem, error) {
+	defer q.write()()
+	price, err := money(arg.Price)
+	if err != nil {
+		return postgres.OrderItem{}, err
+	}
+	return update(q, &q.data.orderItems, arg.ID, nil, func(i *postgres.OrderItem) error {
+		i.OrderID = arg.OrderID
+		i.ProductID = arg.ProductID
+		i.Quantity = arg.Quantity
+		i.Price = price
+		return q.checkOrderItem(*i)
+	})
+}
+
+func (q *Queries) DeleteOrderItem(ctx context.Context, id int64) error {
+	defer q.write()()
+	q.removeOrderItem(id)
+	return nil
+}
+
+func (q *Queries) ListOrderItemsByOrder(ctx context.Context, orderID int64) ([]postgres.OrderItem, error) {
+	defer q.read()()
+	return q.data.orderItems.all(func(i postgres.OrderItem) bool { return i.OrderID == orderID }), nil
+}
+
+func (q *Queries) ListOrderItemsByOrders(ctx context.Context, orderIds []int64) ([]postgres.OrderItem, error) {
+	defer q.read()()
+	match := anyOf(orderIds)
+	items := q.data.orderItems.all(func(i postgres.OrderItem) bool { return match(i.OrderID) })
+	sortRows(items, func(a, b postgres.OrderItem) bool { return a.OrderID < b.OrderID })
+	return items, nil
+}
+
+func (q *Queries) ListOrderItemsByProduct(ctx context.Context, productID int64) ([]postgres.OrderItem, error) {
+	defer q.read()()
+	return q.data.orderItems.all(func(i postgres.OrderItem) bool { return i.ProductID == productID }), nil
+}
+
+// SummarizeOrderItemTaxes sums the amounts of the items of an order per tax rate, highest rate first
+func (q *Queries) SummarizeOrderItemTaxes(ctx context.Context, orderID int64) ([]postgres.SummarizeOrderItemTaxesRow, error) {
+	defer q.read()()
+	type sums struct{ net, tax, gross *big.Rat }
+	groups := map[string]*sums{}
+	rates := []string{}
+	for _, i := range q.data.orderItems.all(func(i postgres.OrderItem) bool { return i.OrderID == orderID }) {
+		g, ok := groups[i.TaxRate]
+		if !ok {
+			g = &sums{new(big.Rat), new(big.Rat), new(big.Rat)}
+			groups[i.TaxRate] = g
+			rates = append(rates, i.TaxRate)
+		}
+		g.net.Add(g.net, rat(i.NetAmount))
+		g.tax.Add(g.tax, rat(i.TaxAmount))
+		g.gross.Add(g.gross, rat(i.GrossAmount))
+	}
+	sortRows(rates, func(a, b string) bool { return compareNumeric(a, b) > 0 })
+
+	items := make([]postgres.SummarizeOrderItemTaxesRow, 0, len(rates))
+	for _, rate := range rates {
+		g := groups[rate]
+		items = append(items, postgres.SummarizeOrderItemTaxesRow{
+			TaxRate:     rate,
+			NetAmount:   g.net.FloatString(2),
+			TaxAmount:   g.tax.FloatString(2),
+			GrossAmount: g.gross.FloatString(2),
+		})
+	}
+	return items, nil
+}
+
+func (q *Queries) SetOrderItemAmounts(ctx context.Context, arg postgres.SetOrderItemAmountsParams) (postgres.OrderItem, error) {
+	defer q.write()()
+	if err := monies(&arg.Price, &arg.NetAmount, &arg.TaxAmount, &arg.GrossAmount); err != nil {
+		return postgres.OrderItem{}, err
+	}
+	rate, err := numeric(arg.TaxRate, 6, 4)
+	if err != nil {
+		return postgres.OrderItem{}, err
+	}
+	return update(q, &q.data.orderItems, arg.ID, nil, func(i *postgres.OrderItem) error {
+		i.Quantity = arg.Quantity
+		i.Price = arg.Price
+		i.TaxRate = rate
+		i.NetAmount = arg.NetAmount
+		i.TaxAmount = arg.TaxAmount
+		i.GrossAmount = arg.GrossAmount
+		return nil
+	})
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// cursor is the position after the last row of a page; it is encoded like the cursors of the postgres store
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (c cursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, postgres.ErrInvalidCursor
+	}
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return c, postgres.ErrInvalidCursor
+	}
+	return
+}
+
+// sortField is a field a list may be ordered by; cast is the type its values are compared as
+type sortField[T any] struct {
+	cast  string
+	value func(T) string
+}
+
+// listQuery describes how to page through a table with whitelisted sorts
+type listQuery[T any] struct {
+	sorts       map[string]sortField[T]
+	defaultSort string
+	id          func(T) int64
+}
+
+// compare orders two field values as the given SQL type would
+func compare(cast, a, b string) (int, error) {
+	switch cast {
+	case "bigint", "int":
+		x, err := strconv.ParseInt(a, 10, 64)
+		if err != nil {
+			return 0, postgres.ErrInvalidCursor
+		}
+		y, err := strconv.ParseInt(b, 10, 64)
+		if err != nil {
+			return 0, postgres.ErrInvalidCursor
+		}
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+		return 0, nil
+	case "numeric":
+		x, err := parseNumeric(a)
+		if err != nil {
+			return 0, postgres.ErrInvalidCursor
+		}
+		y, err := parseNumeric(b)
+		if err != nil {
+			return 0, postgres.ErrInvalidCursor
+		}
+		return x.Cmp(y), nil
+	case "timestamp":
+		x, err := time.Parse(time.RFC3339Nano, a)
+		if err != nil {
+			return 0, postgres.ErrInvalidCursor
+		}
+		y, err := time.Parse(time.RFC3339Nano, b)
+		if err != nil {
+			return 0, postgres.ErrInvalidCursor
+		}
+		return x.Compare(y), nil
+	}
+	return strings.Compare(a, b), nil
+}
+
+func (l listQuery[T]) list(rows []T, arg postgres.PageParams) (dst postgres.Page[T], err error) {
+	sort := arg.Sort
+	if sort == "" {
+		sort = l.defaultSort
+	}
+	field, ok := l.sorts[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return dst, fmt.Errorf("%w: %s", postgres.ErrInvalidSort, strings.TrimPrefix(sort, "-"))
+	}
+	desc := strings.HasPrefix(sort, "-")
+
+	limit := arg.Limit
+	if limit <= 0 {
+		limit = postgres.DefaultPageLimit
+	}
+	if limit > postgres.MaxPageLimit {
+		limit = postgres.MaxPageLimit
+	}
+
+	// The total ignores the cursor so that every page reports the same number
+	dst.Total = int64(len(rows))
+
+	// order compares two rows by the sort field and then by id, in the direction of the sort
+	order := func(av string, aid int64, bv string, bid int64) (int, error) {
+		c, err := compare(field.cast, av, bv)
+		if err != nil {
+			return 0, err
+		}
+		if c == 0 {
+			switch {
+			case aid < bid:
+				c = -1
+			case aid > bid:
+				c = 1
+			}
+		}
+		if desc {
+			c = -c
+		}
+		return c, nil
+	}
+
+	if arg.Cursor != "" {
+		c, err := decodeCursor(arg.Cursor)
+		if err != nil {
+			return dst, err
+		}
+		if c.Sort != sort {
+			return dst, fmt.Errorf("%w: issued for sort %q", postgres.ErrInvalidCursor, c.Sort)
+		}
+
+		after := make([]T, 0, len(rows))
+		for _, row := range rows {
+			cmp, err := order(field.value(row), l.id(row), c.Value, c.ID)
+			if err != nil {
+				return dst, err
+			}
+			if cmp > 0 {
+				after = append(after, row)
+			}
+		}
+		rows = after
+	}
+
+	sorted := append([]T{}, rows...)
+	sortRows(sorted, func(a, b T) bool {
+		cmp, _ := order(field.value(a), l.id(a), field.value(b), l.id(b))
+		return cmp < 0
+	})
+
+	dst.Items = sorted
+	if len(dst.Items) > int(limit) {
+		dst.Items = dst.Items[:limit]
+		last := dst.Items[limit-1]
+		dst.NextCursor = encodeCursor(cursor{
+			Sort:  sort,
+			Value: field.value(last),
+			ID:    l.id(last),
+		})
+	}
+
+	return
+}
+
+// sortRows orders rows stably, so rows that compare equal keep their primary key order
+func sortRows[T any](rows []T, less func(a, b T) bool) {
+	sort.SliceStable(rows, func(i, j int) bool { return less(rows[i], rows[j]) })
+}
@@ -1 +1,206 @@
-package memory
\ No newline at end of file
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// updatePayment changes a payment accepted by match and bumps its version, as the bump_version trigger does
+func (q *Queries) updatePayment(id int64, match func(postgres.Payment) bool, change func(*postgres.Payment) error) (postgres.Payment, error) {
+	return update(q, &q.data.payments, id, match, func(i *postgres.Payment) error {
+		if err := change(i); err != nil {
+			return err
+		}
+		i.Version++
+		return nil
+	})
+}
+
+// checkPayment enforces the foreign keys of the payments table on a row about to be stored
+func (q *Queries) checkPayment(i postgres.Payment) error {
+	data := q.data
+	if _, ok := data.users.get(i.UserID); !ok {
+		return foreignKeyViolation("payments", "payments_user_id_fkey")
+	}
+	if _, ok := data.orders.get(i.OrderID); !ok {
+		return foreignKeyViolation("payments", "payments_order_id_fkey")
+	}
+	return nil
+}
+
+func (q *Queries) GetPayment(ctx context.Context, id int64) (postgres.Payment, error) {
+	defer q.read()()
+	i, ok := q.data.payments.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// byPaymentDate orders payments by payment_date
+func byPaymentDate(a, b postgres.Payment) bool {
+	return a.PaymentDate.Before(b.PaymentDate)
+}
+
+func (q *Queries) ListPayments(ctx context.Context) ([]postgres.Payment, error) {
+	defer q.read()()
+	items := q.data.payments.all(func(i postgres.Payment) bool { return !i.DeletedAt.Valid })
+	sortRows(items, byPaymentDate)
+	return items, nil
+}
+
+func (q *Queries) CreatePayment(ctx context.Context, arg postgres.CreatePaymentParams) (postgres.Payment, error) {
+	defer q.write()()
+	data := q.data
+	amount, err := money(arg.Amount)
+	if err != nil {
+		return postgres.Payment{}, err
+	}
+	i := postgres.Payment{
+		UserID:         arg.UserID,
+		OrderID:        arg.OrderID,
+		Amount:         amount,
+		PaymentDate:    now(),
+		Status:         arg.Status,
+		InvoiceID:      arg.InvoiceID,
+		RefundedAmount: "0.00",
+		Version:        1,
+	}
+	if err := q.checkPayment(i); err != nil {
+		return postgres.Payment{}, err
+	}
+	i.ID = data.payments.next()
+	put(q, &data.payments, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) UpdatePayment(ctx context.Context, arg postgres.UpdatePaymentParams) (postgres.Payment, error) {
+	defer q.write()()
+	amount, err := money(arg.Amount)
+	if err != nil {
+		return postgres.Payment{}, err
+	}
+	return q.updatePayment(arg.ID, func(i postgres.Payment) bool { return i.Version == arg.Version }, func(i *postgres.Payment) error {
+		i.UserID = arg.UserID
+		i.OrderID = arg.OrderID
+		i.Amount = amount
+		i.Status = arg.Status
+		return q.checkPayment(*i)
+	})
+}
+
+func (q *Queries) DeletePayment(ctx context.Context, id int64) (postgres.Payment, error) {
+	defer q.write()()
+	return q.updatePayment(id, func(i postgres.Payment) bool { return !i.DeletedAt.Valid }, func(i *postgres.Payment) error {
+		i.DeletedAt = nullNow()
+		return nil
+	})
+}
+
+func (q *Queries) RestorePayment(ctx context.Context, id int64) (postgres.Payment, error) {
+	defer q.write()()
+	return q.updatePayment(id, func(i postgres.Payment) bool { return i.DeletedAt.Valid }, func(i *postgres.Payment) error {
+		i.DeletedAt = sql.NullTime{}
+		return nil
+	})
+}
+
+func (q *Queries) ListPaymentsByOrders(ctx context.Context, orderIds []int64) ([]postgres.Payment, error) {
+	defer q.read()()
+	match := anyOf(orderIds)
+	items := q.data.payments.all(func(i postgres.Payment) bool { return match(i.OrderID) && !i.DeletedAt.Valid })
+	sortRows(items, func(a, b postgres.Payment) bool {
+		if a.OrderID != b.OrderID {
+			return a.OrderID < b.OrderID
+		}
+		return byPaymentDate(a, b)
+	})
+	return items, nil
+}
+
+func (q *Queries) PurgeDeletedPayments(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	defer q.write()()
+	data := q.data
+	expired := data.payments.ids(func(p postgres.Payment) bool { return deletedBefore(p.DeletedAt, deletedAt) })
+	for _, id := range expired {
+		// returns.payment_id is ON DELETE SET NULL
+		for _, r := range data.returns.ids(func(r postgres.Return) bool { return r.PaymentID.Valid && r.PaymentID.Int64 == id }) {
+			row, _ := data.returns.get(r)
+			row.PaymentID = sql.NullInt64{}
+			put(q, &data.returns, r, row)
+		}
+		remove(q, &data.payments, id)
+	}
+	return int64(len(expired)), nil
+}
+
+func (q *Queries) SearchPaymentsByUser(ctx context.Context, userID int64) ([]postgres.Payment, error) {
+	defer q.read()()
+	items := q.data.payments.all(func(i postgres.Payment) bool { return i.UserID == userID && !i.DeletedAt.Valid })
+	sortRows(items, byPaymentDate)
+	return items, nil
+}
+
+func (q *Queries) SearchPaymentsByOrder(ctx context.Context, orderID int64) ([]postgres.Payment, error) {
+	defer q.read()()
+	items := q.data.payments.all(func(i postgres.Payment) bool { return i.OrderID == orderID && !i.DeletedAt.Valid })
+	sortRows(items, byPaymentDate)
+	return items, nil
+}
+
+func (q *Queries) SearchPaymentsByStatus(ctx context.Context, status postgres.PaymentStatus) ([]postgres.Payment, error) {
+	defer q.read()()
+	items := q.data.payments.all(func(i postgres.Payment) bool { return i.Status == status && !i.DeletedAt.Valid })
+	sortRows(items, byPaymentDate)
+	return items, nil
+}
+
+// GetRefundablePayment returns the latest successful ePay payment of an order that has not been refunded in full
+func (q *Queries) GetRefundablePayment(ctx context.Context, orderID int64) (postgres.Payment, error) {
+	defer q.read()()
+	items := q.data.payments.all(func(i postgres.Payment) bool {
+		return i.OrderID == orderID && i.Status == postgres.PaymentStatusSuccessful && i.InvoiceID.Valid &&
+			compareNumeric(i.RefundedAmount, i.Amount) < 0
+	})
+	if len(items) == 0 {
+		return postgres.Payment{}, sql.ErrNoRows
+	}
+	sortRows(items, func(a, b postgres.Payment) bool {
+		if !a.PaymentDate.Equal(b.PaymentDate) {
+			return a.PaymentDate.After(b.PaymentDate)
+		}
+		return a.ID > b.ID
+	})
+	return items[0], nil
+}
+
+// AddPaymentRefund adds to the refunded amount of a payment, which becomes refunded once its whole amount has been returned
+func (q *Queries) AddPaymentRefund(ctx context.Context, arg postgres.AddPaymentRefundParams) (postgres.Payment, error) {
+	defer q.write()()
+	amount, err := parseNumeric(arg.Amount)
+	if err != nil {
+		return postgres.Payment{}, err
+	}
+	return q.updatePayment(arg.ID, nil, func(i *postgres.Payment) error {
+		refunded, err := formatNumeric(new(big.Rat).Add(rat(i.RefundedAmount), amount), 10, 2)
+		if err != nil {
+			return err
+		}
+		if compareNumeric(refunded, i.Amount) >= 0 {
+			i.Status = postgres.PaymentStatusRefunded
+		}
+		i.RefundedAmount = refunded
+		return nil
+	})
+}
+
+// CountPaidPayments counts payments that took money from the customer, including ones refunded since
+func (q *Queries) CountPaidPayments(ctx context.Context, orderID int64) (int64, error) {
+	defer q.read()()
+	return q.data.payments.count(func(i postgres.Payment) bool {
+		return i.OrderID == orderID && i.Status != postgres.PaymentStatusUnsuccessful
+	}), nil
+}
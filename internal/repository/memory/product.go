@@ -1 +1,497 @@
-package memory
\ No newline at end of file
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// updateProduct changes a product accepted by match and bumps its version, as the bump_version trigger does
+func (q *Queries) updateProduct(id int64, match func(postgres.Product) bool, change func(*postgres.Product) error) (postgres.Product, error) {
+	return update(q, &q.data.products, id, match, func(i *postgres.Product) error {
+		if err := change(i); err != nil {
+			return err
+		}
+		i.Version++
+		return nil
+	})
+}
+
+// checkProduct enforces the constraints of the products table on a row about to be stored
+func (q *Queries) checkProduct(i postgres.Product) error {
+	data := q.data
+	if _, ok := data.categories.get(i.CategoryID); !ok {
+		return foreignKeyViolation("products", "products_category_id_fkey")
+	}
+	if data.products.exists(func(p postgres.Product) bool { return p.ID != i.ID && p.Sku == i.Sku }) {
+		return uniqueViolation("products_sku_key")
+	}
+	return nil
+}
+
+// removeProduct deletes a product together with the rows that reference it ON DELETE CASCADE
+func (q *Queries) removeProduct(id int64) {
+	data := q.data
+	for _, v := range data.productVariants.ids(func(v postgres.ProductVariant) bool { return v.ProductID == id }) {
+		remove(q, &data.productVariants, v)
+	}
+	for _, img := range data.productImages.ids(func(img postgres.ProductImage) bool { return img.ProductID == id }) {
+		remove(q, &data.productImages, img)
+	}
+	for _, p := range data.promotions.ids(func(p postgres.Promotion) bool { return p.ProductID.Valid && p.ProductID.Int64 == id }) {
+		q.removePromotion(p)
+	}
+	q.removeCartItems(func(i postgres.CartItem) bool { return i.ProductID == id })
+	remove(q, &data.products, id)
+}
+
+// priceRange returns a filter accepting the prices between two optional bounds
+func priceRange(minPrice, maxPrice sql.NullString) (func(price string) bool, error) {
+	var lower, upper *big.Rat
+	var err error
+	if minPrice.Valid {
+		if lower, err = parseNumeric(minPrice.String); err != nil {
+			return nil, err
+		}
+	}
+	if maxPrice.Valid {
+		if upper, err = parseNumeric(maxPrice.String); err != nil {
+			return nil, err
+		}
+	}
+	return func(price string) bool {
+		p := rat(price)
+		return (lower == nil || p.Cmp(lower) >= 0) && (upper == nil || p.Cmp(upper) <= 0)
+	}, nil
+}
+
+func (q *Queries) GetProduct(ctx context.Context, id int64) (postgres.Product, error) {
+	defer q.read()()
+	i, ok := q.data.products.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// byAdditionDate orders products by addition_date
+func byAdditionDate(a, b postgres.Product) bool {
+	return a.AdditionDate.Before(b.AdditionDate)
+}
+
+func (q *Queries) ListProducts(ctx context.Context) ([]postgres.Product, error) {
+	defer q.read()()
+	items := q.data.products.all(func(i postgres.Product) bool { return !i.DeletedAt.Valid })
+	sortRows(items, byAdditionDate)
+	return items, nil
+}
+
+func (q *Queries) GetProductBySku(ctx context.Context, sku string) (postgres.Product, error) {
+	defer q.read()()
+	i, ok := q.data.products.first(func(i postgres.Product) bool { return i.Sku == sku })
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) CreateProduct(ctx context.Context, arg postgres.CreateProductParams) (postgres.Product, error) {
+	defer q.write()()
+	return q.insertProduct(arg)
+}
+
+func (q *Queries) insertProduct(arg postgres.CreateProductParams) (postgres.Product, error) {
+	data := q.data
+	price, err := money(arg.Price)
+	if err != nil {
+		return postgres.Product{}, err
+	}
+	i := postgres.Product{
+		Name:          arg.Name,
+		Description:   arg.Description,
+		Price:         price,
+		StockQuantity: arg.StockQuantity,
+		AdditionDate:  now(),
+		CategoryID:    arg.CategoryID,
+		Sku:           arg.Sku,
+		TaxClass:      arg.TaxClass,
+		WeightGrams:   arg.WeightGrams,
+		Version:       1,
+	}
+	if err := q.checkProduct(i); err != nil {
+		return postgres.Product{}, err
+	}
+	i.ID = data.products.next()
+	put(q, &data.products, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) UpdateProduct(ctx context.Context, arg postgres.UpdateProductParams) (postgres.Product, error) {
+	defer q.write()()
+	price, err := money(arg.Price)
+	if err != nil {
+		return postgres.Product{}, err
+	}
+	return q.updateProduct(arg.ID, func(i postgres.Product) bool { return i.Version == arg.Version }, func(i *postgres.Product) error {
+		i.Sku = arg.Sku
+		i.Name = arg.Name
+		i.Description = arg.Description
+		i.Price = price
+		i.CategoryID = arg.CategoryID
+		i.StockQuantity = arg.StockQuantity
+		i.TaxClass = arg.TaxClass
+		i.WeightGrams = arg.WeightGrams
+		return q.checkProduct(*i)
+	})
+}
+
+func (q *Queries) DeleteProduct(ctx context.Context, id int64) (postgres.Product, error) {
+	defer q.write()()
+	return q.updateProduct(id, func(i postgres.Product) bool { return !i.DeletedAt.Valid }, func(i *postgres.Product) error {
+		i.DeletedAt = nullNow()
+		return nil
+	})
+}
+
+func (q *Queries) RestoreProduct(ctx context.Context, id int64) (postgres.Product, error) {
+	defer q.write()()
+	return q.updateProduct(id, func(i postgres.Product) bool { return i.DeletedAt.Valid }, func(i *postgres.Product) error {
+		i.DeletedAt = sql.NullTime{}
+		return nil
+	})
+}
+
+// purgeableProducts returns the products deleted before the given time that are on no order
+func (q *Queries) purgeableProducts(deletedAt sql.NullTime) []int64 {
+	data := q.data
+	return data.products.ids(func(p postgres.Product) bool {
+		return deletedBefore(p.DeletedAt, deletedAt) &&
+			!data.orderItems.exists(func(oi postgres.OrderItem) bool { return oi.ProductID == p.ID })
+	})
+}
+
+func (q *Queries) ListPurgeableProductImages(ctx context.Context, deletedAt sql.NullTime) ([]postgres.ProductImage, error) {
+	defer q.read()()
+	purgeable := map[int64]bool{}
+	for _, id := range q.purgeableProducts(deletedAt) {
+		purgeable[id] = true
+	}
+	return q.data.productImages.all(func(i postgres.ProductImage) bool { return purgeable[i.ProductID] }), nil
+}
+
+// PurgeDeletedProducts keeps products on orders for the invoices; variants, images and promotions go with the product
+func (q *Queries) PurgeDeletedProducts(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	defer q.write()()
+	expired := q.purgeableProducts(deletedAt)
+	for _, id := range expired {
+		q.removeProduct(id)
+	}
+	return int64(len(expired)), nil
+}
+
+func (q *Queries) SearchProductsByName(ctx context.Context, dollar_1 sql.NullString) ([]postgres.Product, error) {
+	defer q.read()()
+	// A NULL pattern matches no rows, as NULL ILIKE does
+	items := q.data.products.all(func(i postgres.Product) bool {
+		return dollar_1.Valid && containsFold(i.Name, dollar_1.String) && !i.DeletedAt.Valid
+	})
+	sortRows(items, byAdditionDate)
+	return items, nil
+}
+
+func (q *Queries) SearchProductsByCategory(ctx context.Context, name string) ([]postgres.Product, error) {
+	defer q.read()()
+	data := q.data
+	items := data.products.all(func(i postgres.Product) bool {
+		c, ok := data.categories.get(i.CategoryID)
+		return ok && c.Name == name && !i.DeletedAt.Valid
+	})
+	sortRows(items, byAdditionDate)
+	return items, nil
+}
+
+func (q *Queries) UpdateProductStock(ctx context.Context, arg postgres.UpdateProductStockParams) (postgres.Product, error) {
+	defer q.write()()
+	return q.updateProduct(arg.ID, nil, func(i *postgres.Product) error {
+		i.StockQuantity -= arg.StockQuantity
+		return nil
+	})
+}
+
+// SearchProducts ranks the live products matching a search query, best match first; see textQuery for how matching works
+func (q *Queries) SearchProducts(ctx context.Context, arg postgres.SearchProductsParams) ([]postgres.SearchProductsRow, error) {
+	defer q.read()()
+	inRange, err := priceRange(arg.MinPrice, arg.MaxPrice)
+	if err != nil {
+		return nil, err
+	}
+	query := parseTextQuery(arg.Query)
+
+	items := []postgres.SearchProductsRow{}
+	for _, i := range q.data.products.all(func(i postgres.Product) bool {
+		return !i.DeletedAt.Valid &&
+			inCategories(arg.CategoryIds, i.CategoryID) &&
+			inRange(i.Price) &&
+			(!arg.InStock || i.StockQuantity > 0)
+	}) {
+		rank, ok := query.rank(i)
+		if !ok {
+			continue
+		}
+		items = append(items, postgres.SearchProductsRow{
+			ID:            i.ID,
+			Name:          i.Name,
+			Description:   i.Description,
+			Price:         i.Price,
+			StockQuantity: i.StockQuantity,
+			AdditionDate:  i.AdditionDate,
+			CategoryID:    i.CategoryID,
+			Sku:           i.Sku,
+			TaxClass:      i.TaxClass,
+			WeightGrams:   i.WeightGrams,
+			Version:       i.Version,
+			DeletedAt:     i.DeletedAt,
+			Rank:          rank,
+		})
+	}
+	sortRows(items, func(a, b postgres.SearchProductsRow) bool { return a.Rank > b.Rank })
+	for n := range items {
+		items[n].Total = int64(len(items))
+	}
+
+	if arg.PageOffset > 0 {
+		items = items[min(int(arg.PageOffset), len(items)):]
+	}
+	return head(items, arg.PageLimit), nil
+}
+
+// inCategories reports whether a category is in a list; a nil list, like a NULL array, accepts every category
+func inCategories(ids []int64, id int64) bool {
+	if ids == nil {
+		return true
+	}
+	for _, c := range ids {
+		if c == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchProductCategoryFacets counts the products matching a search query per category, largest first
+func (q *Queries) SearchProductCategoryFacets(ctx context.Context, arg postgres.SearchProductCategoryFacetsParams) ([]postgres.SearchProductCategoryFacetsRow, error) {
+	defer q.read()()
+	data := q.data
+	inRange, err := priceRange(arg.MinPrice, arg.MaxPrice)
+	if err != nil {
+		return nil, err
+	}
+	query := parseTextQuery(arg.Query)
+
+	index := map[int64]int{}
+	items := []postgres.SearchProductCategoryFacetsRow{}
+	for _, i := range data.products.all(func(i postgres.Product) bool {
+		return !i.DeletedAt.Valid && inRange(i.Price) && (!arg.InStock || i.StockQuantity > 0)
+	}) {
+		if _, ok := query.rank(i); !ok {
+			continue
+		}
+		c, ok := data.categories.get(i.CategoryID)
+		if !ok {
+			continue
+		}
+		n, ok := index[c.ID]
+		if !ok {
+			n = len(items)
+			index[c.ID] = n
+			items = append(items, postgres.SearchProductCategoryFacetsRow{CategoryID: c.ID, Category: c.Name})
+		}
+		items[n].Count++
+	}
+	sortRows(items, func(a, b postgres.SearchProductCategoryFacetsRow) bool {
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Category < b.Category
+	})
+	return items, nil
+}
+
+// SearchProductPriceFacets counts the products matching a search query per price bucket, as width_bucket numbers them
+func (q *Queries) SearchProductPriceFacets(ctx context.Context, arg postgres.SearchProductPriceFacetsParams) ([]postgres.SearchProductPriceFacetsRow, error) {
+	defer q.read()()
+	bounds := make([]*big.Rat, 0, len(arg.Bounds))
+	for _, b := range arg.Bounds {
+		r, err := parseNumeric(b)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, r)
+	}
+	query := parseTextQuery(arg.Query)
+
+	counts := map[int32]int64{}
+	for _, i := range q.data.products.all(func(i postgres.Product) bool {
+		return !i.DeletedAt.Valid && inCategories(arg.CategoryIds, i.CategoryID) && (!arg.InStock || i.StockQuantity > 0)
+	}) {
+		if _, ok := query.rank(i); !ok {
+			continue
+		}
+		// The bucket is the number of bounds at or below the price
+		price := rat(i.Price)
+		var bucket int32
+		for _, b := range bounds {
+			if price.Cmp(b) >= 0 {
+				bucket++
+			}
+		}
+		counts[bucket]++
+	}
+
+	items := make([]postgres.SearchProductPriceFacetsRow, 0, len(counts))
+	for bucket, count := range counts {
+		items = append(items, postgres.SearchProductPriceFacetsRow{Bucket: bucket, Count: count})
+	}
+	sortRows(items, func(a, b postgres.SearchProductPriceFacetsRow) bool { return a.Bucket < b.Bucket })
+	return items, nil
+}
+
+func (q *Queries) ReserveProductStock(ctx context.Context, arg postgres.ReserveProductStockParams) (postgres.Product, error) {
+	defer q.write()()
+	return q.updateProduct(arg.ID, func(i postgres.Product) bool { return i.StockQuantity >= arg.Quantity }, func(i *postgres.Product) error {
+		i.StockQuantity -= arg.Quantity
+		return nil
+	})
+}
+
+// UpsertProductBySku creates a product or, when its SKU is taken, updates the fields given of the product holding it.
+// A deleted product is only undeleted when asked.
+func (q *Queries) UpsertProductBySku(ctx context.Context, arg postgres.UpsertProductBySkuParams) (postgres.UpsertProductBySkuRow, error) {
+	defer q.write()()
+	var (
+		i        postgres.Product
+		err      error
+		inserted bool
+	)
+	if held, ok := q.data.products.first(func(p postgres.Product) bool { return p.Sku == arg.Sku }); ok {
+		var price sql.NullString
+		if price, err = nullMoney(arg.Price); err != nil {
+			return postgres.UpsertProductBySkuRow{}, err
+		}
+		i, err = q.updateProduct(held.ID, nil, func(i *postgres.Product) error {
+			if arg.Name.Valid {
+				i.Name = arg.Name.String
+			}
+			if arg.Description.Valid {
+				i.Description = arg.Description.String
+			}
+			if price.Valid {
+				i.Price = price.String
+			}
+			if arg.CategoryID.Valid {
+				i.CategoryID = arg.CategoryID.Int64
+			}
+			if arg.StockQuantity.Valid {
+				i.StockQuantity = arg.StockQuantity.Int32
+			}
+			if arg.TaxClass.Valid {
+				i.TaxClass = arg.TaxClass.String
+			}
+			if arg.WeightGrams.Valid {
+				i.WeightGrams = arg.WeightGrams.Int32
+			}
+			if arg.Restore {
+				i.DeletedAt = sql.NullTime{}
+			}
+			return q.checkProduct(*i)
+		})
+	} else {
+		switch {
+		case !arg.Name.Valid:
+			err = notNull("products", "name")
+		case !arg.Price.Valid:
+			err = notNull("products", "price")
+		case !arg.CategoryID.Valid:
+			err = notNull("products", "category_id")
+		default:
+			inserted = true
+			taxClass := arg.TaxClass.String
+			if !arg.TaxClass.Valid {
+				taxClass = "standard"
+			}
+			i, err = q.insertProduct(postgres.CreateProductParams{
+				Sku:           arg.Sku,
+				Name:          arg.Name.String,
+				Description:   arg.Description.String,
+				Price:         arg.Price.String,
+				CategoryID:    arg.CategoryID.Int64,
+				StockQuantity: arg.StockQuantity.Int32,
+				TaxClass:      taxClass,
+				WeightGrams:   arg.WeightGrams.Int32,
+			})
+		}
+	}
+	if err != nil {
+		return postgres.UpsertProductBySkuRow{}, err
+	}
+	return postgres.UpsertProductBySkuRow{
+		ID:            i.ID,
+		Name:          i.Name,
+		Description:   i.Description,
+		Price:         i.Price,
+		StockQuantity: i.StockQuantity,
+		AdditionDate:  i.AdditionDate,
+		CategoryID:    i.CategoryID,
+		Sku:           i.Sku,
+		TaxClass:      i.TaxClass,
+		WeightGrams:   i.WeightGrams,
+		Version:       i.Version,
+		DeletedAt:     i.DeletedAt,
+		Inserted:      inserted,
+	}, nil
+}
+
+func (q *Queries) ListProductsByIDs(ctx context.Context, ids []int64) ([]postgres.Product, error) {
+	defer q.read()()
+	match := anyOf(ids)
+	return q.data.products.all(func(i postgres.Product) bool { return match(i.ID) }), nil
+}
+
+// ListProductsForExport returns the live products after a given id together with the slug of their category
+func (q *Queries) ListProductsForExport(ctx context.Context, arg postgres.ListProductsForExportParams) ([]postgres.ListProductsForExportRow, error) {
+	defer q.read()()
+	data := q.data
+	items := []postgres.ListProductsForExportRow{}
+	for _, i := range data.products.all(func(i postgres.Product) bool { return i.ID > arg.AfterID && !i.DeletedAt.Valid }) {
+		c, ok := data.categories.get(i.CategoryID)
+		if !ok {
+			continue
+		}
+		items = append(items, postgres.ListProductsForExportRow{
+			ID:            i.ID,
+			Name:          i.Name,
+			Description:   i.Description,
+			Price:         i.Price,
+			StockQuantity: i.StockQuantity,
+			AdditionDate:  i.AdditionDate,
+			CategoryID:    i.CategoryID,
+			Sku:           i.Sku,
+			TaxClass:      i.TaxClass,
+			WeightGrams:   i.WeightGrams,
+			Version:       i.Version,
+			DeletedAt:     i.DeletedAt,
+			Category:      c.Slug,
+		})
+	}
+	return head(items, arg.PageLimit), nil
+}
+
+func (q *Queries) RestockProduct(ctx context.Context, arg postgres.RestockProductParams) (postgres.Product, error) {
+	defer q.write()()
+	return q.updateProduct(arg.ID, nil, func(i *postgres.Product) error {
+		i.StockQuantity += arg.Quantity
+		return nil
+	})
+}
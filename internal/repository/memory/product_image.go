@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// checkProductImage enforces the foreign key and the single primary image per product of the product_images table
+func (q *Queries) checkProductImage(i postgres.ProductImage) error {
+	data := q.data
+	if _, ok := data.products.get(i.ProductID); !ok {
+		return foreignKeyViolation("product_images", "product_images_product_id_fkey")
+	}
+	if i.IsPrimary && data.productImages.exists(func(img postgres.ProductImage) bool {
+		return img.ID != i.ID && img.ProductID == i.ProductID && img.IsPrimary
+	}) {
+		return uniqueViolation("product_images_primary_idx")
+	}
+	return nil
+}
+
+// ofProduct accepts the images of a product
+func ofProduct(productID int64) func(postgres.ProductImage) bool {
+	return func(i postgres.ProductImage) bool { return i.ProductID == productID }
+}
+
+func (q *Queries) ClearPrimaryProductImage(ctx context.Context, productID int64) error {
+	defer q.write()()
+	data := q.data
+	for _, id := range data.productImages.ids(func(i postgres.ProductImage) bool { return i.ProductID == productID && i.IsPrimary }) {
+		row, _ := data.productImages.get(id)
+		row.IsPrimary = false
+		put(q, &data.productImages, id, row)
+	}
+	return nil
+}
+
+func (q *Queries) CountProductImagesByProduct(ctx context.Context, productID int64) (int64, error) {
+	defer q.read()()
+	return q.data.productImages.count(ofProduct(productID)), nil
+}
+
+func (q *Queries) CreateProductImage(ctx context.Context, arg postgres.CreateProductImageParams) (postgres.ProductImage, error) {
+	defer q.write()()
+	data := q.data
+	thumbnails, err := jsonb("product_images", "thumbnails", arg.Thumbnails)
+	if err != nil {
+		return postgres.ProductImage{}, err
+	}
+	i := postgres.ProductImage{
+		ProductID:   arg.ProductID,
+		StorageKey:  arg.StorageKey,
+		Url:         arg.Url,
+		Thumbnails:  thumbnails,
+		ContentType: arg.ContentType,
+		Width:       arg.Width,
+		Height:      arg.Height,
+		SizeBytes:   arg.SizeBytes,
+		Position:    arg.Position,
+		IsPrimary:   arg.IsPrimary,
+		CreatedAt:   now(),
+	}
+	if err := q.checkProductImage(i); err != nil {
+		return postgres.ProductImage{}, err
+	}
+	i.ID = data.productImages.next()
+	put(q, &data.productImages, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) DeleteProductImage(ctx context.Context, arg postgres.DeleteProductImageParams) error {
+	defer q.write()()
+	data := q.data
+	if i, ok := data.productImages.get(arg.ID); ok && i.ProductID == arg.ProductID {
+		remove(q, &data.productImages, arg.ID)
+	}
+	return nil
+}
+
+func (q *Queries) GetProductImage(ctx context.Context, arg postgres.GetProductImageParams) (postgres.ProductImage, error) {
+	defer q.read()()
+	i, ok := q.data.productImages.get(arg.ID)
+	if !ok || i.ProductID != arg.ProductID {
+		return postgres.ProductImage{}, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// ListProductImagesByProduct returns the images of a product, the primary one first and the rest by position
+func (q *Queries) ListProductImagesByProduct(ctx context.Context, productID int64) ([]postgres.ProductImage, error) {
+	defer q.read()()
+	items := q.data.productImages.all(ofProduct(productID))
+	sortRows(items, func(a, b postgres.ProductImage) bool {
+		if a.IsPrimary != b.IsPrimary {
+			return a.IsPrimary
+		}
+		return a.Position < b.Position
+	})
+	return items, nil
+}
+
+func (q *Queries) SetPrimaryProductImage(ctx context.Context, arg postgres.SetPrimaryProductImageParams) (postgres.ProductImage, error) {
+	defer q.write()()
+	return update(q, &q.data.productImages, arg.ID, ofProduct(arg.ProductID), func(i *postgres.ProductImage) error {
+		i.IsPrimary = true
+		return q.checkProductImage(*i)
+	})
+}
+
+func (q *Queries) UpdateProductImagePosition(ctx context.Context, arg postgres.UpdateProductImagePositionParams) (postgres.ProductImage, error) {
+	defer q.write()()
+	return update(q, &q.data.productImages, arg.ID, ofProduct(arg.ProductID), func(i *postgres.ProductImage) error {
+		i.Position = arg.Position
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func (q *Queries) CreateProductImportJob(ctx context.Context, arg postgres.CreateProductImportJobParams) (postgres.ProductImportJob, error) {
+	defer q.write()()
+	data := q.data
+	i := postgres.ProductImportJob{
+		ID:        data.productImportJobs.next(),
+		Format:    arg.Format,
+		Status:    postgres.ImportJobStatusPending,
+		DryRun:    arg.DryRun,
+		Errors:    json.RawMessage(`[]`),
+		CreatedAt: now(),
+	}
+	put(q, &data.productImportJobs, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) FinishProductImportJob(ctx context.Context, arg postgres.FinishProductImportJobParams) (postgres.ProductImportJob, error) {
+	defer q.write()()
+	errors, err := jsonb("product_import_jobs", "errors", arg.Errors)
+	if err != nil {
+		return postgres.ProductImportJob{}, err
+	}
+	return update(q, &q.data.productImportJobs, arg.ID, nil, func(i *postgres.ProductImportJob) error {
+		i.Status = arg.Status
+		i.TotalRows = arg.TotalRows
+		i.CreatedRows = arg.CreatedRows
+		i.UpdatedRows = arg.UpdatedRows
+		i.FailedRows = arg.FailedRows
+		i.Errors = errors
+		i.LastError = arg.LastError
+		i.FinishedAt = nullNow()
+		return nil
+	})
+}
+
+func (q *Queries) GetProductImportJob(ctx context.Context, id int64) (postgres.ProductImportJob, error) {
+	defer q.read()()
+	i, ok := q.data.productImportJobs.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) StartProductImportJob(ctx context.Context, id int64) error {
+	defer q.write()()
+	_, err := update(q, &q.data.productImportJobs, id, nil, func(i *postgres.ProductImportJob) error {
+		i.Status = postgres.ImportJobStatusRunning
+		i.StartedAt = nullNow()
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
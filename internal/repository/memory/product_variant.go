@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// checkProductVariant enforces the constraints of the product_variants table on a row about to be stored
+func (q *Queries) checkProductVariant(i postgres.ProductVariant) error {
+	data := q.data
+	if _, ok := data.products.get(i.ProductID); !ok {
+		return foreignKeyViolation("product_variants", "product_variants_product_id_fkey")
+	}
+	if data.productVariants.exists(func(v postgres.ProductVariant) bool { return v.ID != i.ID && v.Sku == i.Sku }) {
+		return uniqueViolation("product_variants_sku_key")
+	}
+	if i.StockQuantity < 0 {
+		return checkViolation("product_variants", "product_variants_stock_quantity_check")
+	}
+	return nil
+}
+
+func (q *Queries) CountProductVariantsByProduct(ctx context.Context, productID int64) (int64, error) {
+	defer q.read()()
+	return q.data.productVariants.count(func(i postgres.ProductVariant) bool { return i.ProductID == productID }), nil
+}
+
+func (q *Queries) CreateProductVariant(ctx context.Context, arg postgres.CreateProductVariantParams) (postgres.ProductVariant, error) {
+	defer q.write()()
+	data := q.data
+	attributes, err := jsonb("product_variants", "attributes", arg.Attributes)
+	if err != nil {
+		return postgres.ProductVariant{}, err
+	}
+	price, err := nullMoney(arg.Price)
+	if err != nil {
+		return postgres.ProductVariant{}, err
+	}
+	i := postgres.ProductVariant{
+		ProductID:     arg.ProductID,
+		Sku:           arg.Sku,
+		Attributes:    attributes,
+		Price:         price,
+		StockQuantity: arg.StockQuantity,
+		Position:      arg.Position,
+		CreatedAt:     now(),
+	}
+	if err := q.checkProductVariant(i); err != nil {
+		return postgres.ProductVariant{}, err
+	}
+	i.ID = data.productVariants.next()
+	put(q, &data.productVariants, i.ID, i)
+	return i, nil
+}
+
+// DeleteProductVariant refuses to delete a variant that was ordered, as order_items.variant_id has no ON DELETE action
+func (q *Queries) DeleteProductVariant(ctx context.Context, arg postgres.DeleteProductVariantParams) error {
+	defer q.write()()
+	data := q.data
+	i, ok := data.productVariants.get(arg.ID)
+	if !ok || i.ProductID != arg.ProductID {
+		return nil
+	}
+	if data.orderItems.exists(func(oi postgres.OrderItem) bool { return oi.VariantID.Valid && oi.VariantID.Int64 == i.ID }) {
+		return foreignKeyViolation("product_variants", "order_items_variant_id_fkey")
+	}
+	q.removeCartItems(func(c postgres.CartItem) bool { return c.VariantID.Valid && c.VariantID.Int64 == i.ID })
+	remove(q, &data.productVariants, i.ID)
+	return nil
+}
+
+func (q *Queries) GetProductVariant(ctx context.Context, id int64) (postgres.ProductVariant, error) {
+	defer q.read()()
+	i, ok := q.data.productVariants.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) GetProductVariantBySku(ctx context.Context, sku string) (postgres.ProductVariant, error) {
+	defer q.read()()
+	i, ok := q.data.productVariants.first(func(i postgres.ProductVariant) bool { return i.Sku == sku })
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) ListProductVariantsByProduct(ctx context.Context, productID int64) ([]postgres.ProductVariant, error) {
+	defer q.read()()
+	items := q.data.productVariants.all(func(i postgres.ProductVariant) bool { return i.ProductID == productID })
+	sortRows(items, func(a, b postgres.ProductVariant) bool { return a.Position < b.Position })
+	return items, nil
+}
+
+func (q *Queries) ReserveProductVariantStock(ctx context.Context, arg postgres.ReserveProductVariantStockParams) (postgres.ProductVariant, error) {
+	defer q.write()()
+	return update(q, &q.data.productVariants, arg.ID, func(i postgres.ProductVariant) bool { return i.StockQuantity >= arg.Quantity }, func(i *postgres.ProductVariant) error {
+		i.StockQuantity -= arg.Quantity
+		return q.checkProductVariant(*i)
+	})
+}
+
+func (q *Queries) RestockProductVariant(ctx context.Context, arg postgres.RestockProductVariantParams) (postgres.ProductVariant, error) {
+	defer q.write()()
+	return update(q, &q.data.productVariants, arg.ID, nil, func(i *postgres.ProductVariant) error {
+		i.StockQuantity += arg.Quantity
+		return q.checkProductVariant(*i)
+	})
+}
+
+func (q *Queries) UpdateProductVariant(ctx context.Context, arg postgres.UpdateProductVariantParams) (postgres.ProductVariant, error) {
+	defer q.write()()
+	attributes, err := jsonb("product_variants", "attributes", arg.Attributes)
+	if err != nil {
+		return postgres.ProductVariant{}, err
+	}
+	price, err := nullMoney(arg.Price)
+	if err != nil {
+		return postgres.ProductVariant{}, err
+	}
+	match := func(i postgres.ProductVariant) bool { return i.ProductID == arg.ProductID }
+	return update(q, &q.data.productVariants, arg.ID, match, func(i *postgres.ProductVariant) error {
+		i.Sku = arg.Sku
+		i.Attributes = attributes
+		i.Price = price
+		i.StockQuantity = arg.StockQuantity
+		i.Position = arg.Position
+		return q.checkProductVariant(*i)
+	})
+}
@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// removePromotion deletes a promotion with its redemptions; adjustments keep their amount but lose the link
+func (q *Queries) removePromotion(id int64) {
+	data := q.data
+	for _, r := range data.promotionRedemptions.ids(func(r postgres.PromotionRedemption) bool { return r.PromotionID == id }) {
+		remove(q, &data.promotionRedemptions, r)
+	}
+	for _, a := range data.orderAdjustments.ids(func(a postgres.OrderAdjustment) bool { return a.PromotionID.Valid && a.PromotionID.Int64 == id }) {
+		row, _ := data.orderAdjustments.get(a)
+		row.PromotionID = sql.NullInt64{}
+		put(q, &data.orderAdjustments, a, row)
+	}
+	remove(q, &data.promotions, id)
+}
+
+// checkPromotion enforces the constraints of the promotions table on a row about to be stored
+func (q *Queries) checkPromotion(i postgres.Promotion) error {
+	data := q.data
+	if i.ProductID.Valid {
+		if _, ok := data.products.get(i.ProductID.Int64); !ok {
+			return foreignKeyViolation("promotions", "promotions_product_id_fkey")
+		}
+	}
+	if i.CategoryID.Valid {
+		if _, ok := data.categories.get(i.CategoryID.Int64); !ok {
+			return foreignKeyViolation("promotions", "promotions_category_id_fkey")
+		}
+	}
+	if i.Code.Valid && data.promotions.exists(func(p postgres.Promotion) bool {
+		return p.ID != i.ID && p.Code.Valid && p.Code.String == i.Code.String
+	}) {
+		return uniqueViolation("promotions_code_key")
+	}
+	return nil
+}
+
+func (q *Queries) CountPromotionRedemptionsByUser(ctx context.Context, arg postgres.CountPromotionRedemptionsByUserParams) (int64, error) {
+	defer q.read()()
+	return q.data.promotionRedemptions.count(func(r postgres.PromotionRedemption) bool {
+		return r.PromotionID == arg.PromotionID && r.UserID == arg.UserID
+	}), nil
+}
+
+func (q *Queries) CreatePromotion(ctx context.Context, arg postgres.CreatePromotionParams) (postgres.Promotion, error) {
+	defer q.write()()
+	data := q.data
+	value, err := money(arg.Value)
+	if err != nil {
+		return postgres.Promotion{}, err
+	}
+	minOrderAmount, err := nullMoney(arg.MinOrderAmount)
+	if err != nil {
+		return postgres.Promotion{}, err
+	}
+	i := postgres.Promotion{
+		Name:           arg.Name,
+		Code:           arg.Code,
+		Type:           arg.Type,
+		Value:          value,
+		BuyQuantity:    arg.BuyQuantity,
+		GetQuantity:    arg.GetQuantity,
+		ProductID:      arg.ProductID,
+		CategoryID:     arg.CategoryID,
+		MinOrderAmount: minOrderAmount,
+		StartsAt:       arg.StartsAt,
+		EndsAt:         arg.EndsAt,
+		UsageLimit:     arg.UsageLimit,
+		PerUserLimit:   arg.PerUserLimit,
+		Active:         arg.Active,
+		CreatedAt:      now(),
+	}
+	i.UpdatedAt = i.CreatedAt
+	if err := q.checkPromotion(i); err != nil {
+		return postgres.Promotion{}, err
+	}
+	i.ID = data.promotions.next()
+	put(q, &data.promotions, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) CreatePromotionRedemption(ctx context.Context, arg postgres.CreatePromotionRedemptionParams) error {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.promotions.get(arg.PromotionID); !ok {
+		return foreignKeyViolation("promotion_redemptions", "promotion_redemptions_promotion_id_fkey")
+	}
+	if _, ok := data.orders.get(arg.OrderID); !ok {
+		return foreignKeyViolation("promotion_redemptions", "promotion_redemptions_order_id_fkey")
+	}
+	i := postgres.PromotionRedemption{
+		ID:          data.promotionRedemptions.next(),
+		PromotionID: arg.PromotionID,
+		OrderID:     arg.OrderID,
+		UserID:      arg.UserID,
+		CreatedAt:   now(),
+	}
+	put(q, &data.promotionRedemptions, i.ID, i)
+	return nil
+}
+
+func (q *Queries) DeletePromotion(ctx context.Context, id int64) error {
+	defer q.write()()
+	if _, ok := q.data.promotions.get(id); ok {
+		q.removePromotion(id)
+	}
+	return nil
+}
+
+func (q *Queries) DeletePromotionRedemptionsByOrder(ctx context.Context, orderID int64) error {
+	defer q.write()()
+	data := q.data
+	for _, r := range data.promotionRedemptions.ids(func(r postgres.PromotionRedemption) bool { return r.OrderID == orderID }) {
+		remove(q, &data.promotionRedemptions, r)
+	}
+	return nil
+}
+
+func (q *Queries) GetPromotion(ctx context.Context, id int64) (postgres.Promotion, error) {
+	defer q.read()()
+	i, ok := q.data.promotions.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) GetPromotionByCode(ctx context.Context, code sql.NullString) (postgres.Promotion, error) {
+	defer q.read()()
+	i, ok := q.data.promotions.first(func(i postgres.Promotion) bool {
+		return code.Valid && i.Code.Valid && i.Code.String == code.String
+	})
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// ListActiveAutomaticPromotions returns the promotions without a code that run at the given time
+func (q *Queries) ListActiveAutomaticPromotions(ctx context.Context, at time.Time) ([]postgres.Promotion, error) {
+	defer q.read()()
+	return q.data.promotions.all(func(i postgres.Promotion) bool {
+		return !i.Code.Valid && i.Active &&
+			(!i.StartsAt.Valid || !i.StartsAt.Time.After(at)) &&
+			(!i.EndsAt.Valid || i.EndsAt.Time.After(at))
+	}), nil
+}
+
+func (q *Queries) ListPromotions(ctx context.Context) ([]postgres.Promotion, error) {
+	defer q.read()()
+	return q.data.promotions.all(nil), nil
+}
+
+func (q *Queries) RedeemPromotion(ctx context.Context, id int64) (postgres.Promotion, error) {
+	defer q.write()()
+	available := func(i postgres.Promotion) bool { return !i.UsageLimit.Valid || i.TimesUsed < i.UsageLimit.Int32 }
+	return update(q, &q.data.promotions, id, available, func(i *postgres.Promotion) error {
+		i.TimesUsed++
+		return nil
+	})
+}
+
+// ReleaseOrderPromotions gives back the uses an order took from its promotions, before the order is priced again
+func (q *Queries) ReleaseOrderPromotions(ctx context.Context, orderID int64) error {
+	defer q.write()()
+	data := q.data
+	uses := map[int64]int32{}
+	for _, r := range data.promotionRedemptions.all(func(r postgres.PromotionRedemption) bool { return r.OrderID == orderID }) {
+		uses[r.PromotionID]++
+	}
+	for id, n := range uses {
+		if _, err := update(q, &data.promotions, id, nil, func(i *postgres.Promotion) error {
+			i.TimesUsed = max(i.TimesUsed-n, 0)
+			return nil
+		}); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *Queries) UpdatePromotion(ctx context.Context, arg postgres.UpdatePromotionParams) (postgres.Promotion, error) {
+	defer q.write()()
+	value, err := money(arg.Value)
+	if err != nil {
+		return postgres.Promotion{}, err
+	}
+	minOrderAmount, err := nullMoney(arg.MinOrderAmount)
+	if err != nil {
+		return postgres.Promotion{}, err
+	}
+	return update(q, &q.data.promotions, arg.ID, nil, func(i *postgres.Promotion) error {
+		i.Name = arg.Name
+		i.Code = arg.Code
+		i.Type = arg.Type
+		i.Value = value
+		i.BuyQuantity = arg.BuyQuantity
+		i.GetQuantity = arg.GetQuantity
+		i.ProductID = arg.ProductID
+		i.CategoryID = arg.CategoryID
+		i.MinOrderAmount = minOrderAmount
+		i.StartsAt = arg.StartsAt
+		i.EndsAt = arg.EndsAt
+		i.UsageLimit = arg.UsageLimit
+		i.PerUserLimit = arg.PerUserLimit
+		i.Active = arg.Active
+		i.UpdatedAt = now()
+		return q.checkPromotion(*i)
+	})
+}
@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// removeReturn deletes a return together with its items, which reference it ON DELETE CASCADE
+func (q *Queries) removeReturn(id int64) {
+	data := q.data
+	for _, item := range data.returnItems.ids(func(i postgres.ReturnItem) bool { return i.ReturnID == id }) {
+		remove(q, &data.returnItems, item)
+	}
+	remove(q, &data.returns, id)
+}
+
+// byCreatedAt orders returns by created_at, then by id
+func byCreatedAt(a, b postgres.Return) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// ClaimReturnRefund moves a received return to refunding; any other return is not found
+func (q *Queries) ClaimReturnRefund(ctx context.Context, arg postgres.ClaimReturnRefundParams) (postgres.Return, error) {
+	defer q.write()()
+	data := q.data
+	return update(q, &data.returns, arg.ID, func(i postgres.Return) bool {
+		return i.Status == postgres.ReturnStatusReceived
+	}, func(i *postgres.Return) error {
+		if arg.PaymentID.Valid {
+			if _, ok := data.payments.get(arg.PaymentID.Int64); !ok {
+				return foreignKeyViolation("returns", "returns_payment_id_fkey")
+			}
+		}
+		i.Status = postgres.ReturnStatusRefunding
+		i.PaymentID = arg.PaymentID
+		i.UpdatedAt = now()
+		return nil
+	})
+}
+
+// CompleteReturnRefund moves a refunding return to refunded; any other return is not found
+func (q *Queries) CompleteReturnRefund(ctx context.Context, arg postgres.CompleteReturnRefundParams) (postgres.Return, error) {
+	defer q.write()()
+	return update(q, &q.data.returns, arg.ID, func(i postgres.Return) bool {
+		return i.Status == postgres.ReturnStatusRefunding
+	}, func(i *postgres.Return) error {
+		i.Status = postgres.ReturnStatusRefunded
+		i.RefundedAt = arg.RefundedAt
+		i.UpdatedAt = now()
+		return nil
+	})
+}
+
+func (q *Queries) CreateReturn(ctx context.Context, arg postgres.CreateReturnParams) (postgres.Return, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.orders.get(arg.OrderID); !ok {
+		return postgres.Return{}, foreignKeyViolation("returns", "returns_order_id_fkey")
+	}
+	refund, err := money(arg.RefundAmount)
+	if err != nil {
+		return postgres.Return{}, err
+	}
+	i := postgres.Return{
+		ID:           data.returns.next(),
+		OrderID:      arg.OrderID,
+		Status:       postgres.ReturnStatusRequested,
+		Reason:       arg.Reason,
+		RefundAmount: refund,
+		CreatedAt:    now(),
+	}
+	i.UpdatedAt = i.CreatedAt
+	put(q, &data.returns, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) CreateReturnItem(ctx context.Context, arg postgres.CreateReturnItemParams) (postgres.ReturnItem, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.returns.get(arg.ReturnID); !ok {
+		return postgres.ReturnItem{}, foreignKeyViolation("return_items", "return_items_return_id_fkey")
+	}
+	if _, ok := data.orderItems.get(arg.OrderItemID); !ok {
+		return postgres.ReturnItem{}, foreignKeyViolation("return_items", "return_items_order_item_id_fkey")
+	}
+	if data.returnItems.exists(func(i postgres.ReturnItem) bool {
+		return i.ReturnID == arg.ReturnID && i.OrderItemID == arg.OrderItemID
+	}) {
+		return postgres.ReturnItem{}, uniqueViolation("return_items_return_id_order_item_id_key")
+	}
+	amount, err := money(arg.Amount)
+	if err != nil {
+		return postgres.ReturnItem{}, err
+	}
+	i := postgres.ReturnItem{
+		ID:          data.returnItems.next(),
+		ReturnID:    arg.ReturnID,
+		OrderItemID: arg.OrderItemID,
+		Quantity:    arg.Quantity,
+		Amount:      amount,
+	}
+	put(q, &data.returnItems, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) DeleteReturn(ctx context.Context, id int64) (postgres.Return, error) {
+	defer q.write()()
+	i, ok := q.data.returns.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	q.removeReturn(id)
+	return i, nil
+}
+
+func (q *Queries) GetReturn(ctx context.Context, id int64) (postgres.Return, error) {
+	defer q.read()()
+	i, ok := q.data.returns.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// GetReturnForUpdate needs no row lock of its own, as transactions of the memory store already run one at a time
+func (q *Queries) GetReturnForUpdate(ctx context.Context, id int64) (postgres.Return, error) {
+	return q.GetReturn(ctx, id)
+}
+
+func (q *Queries) ListReturnItems(ctx context.Context, returnID int64) ([]postgres.ReturnItem, error) {
+	defer q.read()()
+	return q.data.returnItems.all(func(i postgres.ReturnItem) bool { return i.ReturnID == returnID }), nil
+}
+
+// ListReturns returns every return, newest first
+func (q *Queries) ListReturns(ctx context.Context) ([]postgres.Return, error) {
+	defer q.read()()
+	items := q.data.returns.all(nil)
+	sortRows(items, func(a, b postgres.Return) bool { return byCreatedAt(b, a) })
+	return items, nil
+}
+
+func (q *Queries) ListReturnsByOrder(ctx context.Context, orderID int64) ([]postgres.Return, error) {
+	defer q.read()()
+	items := q.data.returns.all(func(i postgres.Return) bool { return i.OrderID == orderID })
+	sortRows(items, byCreatedAt)
+	return items, nil
+}
+
+func (q *Queries) ListReturnsByStatus(ctx context.Context, status postgres.ReturnStatus) ([]postgres.Return, error) {
+	defer q.read()()
+	items := q.data.returns.all(func(i postgres.Return) bool { return i.Status == status })
+	sortRows(items, byCreatedAt)
+	return items, nil
+}
+
+// ReleaseReturnRefund moves a refunding return back to received; any other return is not found
+func (q *Queries) ReleaseReturnRefund(ctx context.Context, id int64) (postgres.Return, error) {
+	defer q.write()()
+	return update(q, &q.data.returns, id, func(i postgres.Return) bool {
+		return i.Status == postgres.ReturnStatusRefunding
+	}, func(i *postgres.Return) error {
+		i.Status = postgres.ReturnStatusReceived
+		i.UpdatedAt = now()
+		return nil
+	})
+}
+
+// SumReturnedQuantity counts the units of an order item on returns that were not rejected
+func (q *Queries) SumReturnedQuantity(ctx context.Context, orderItemID int64) (int32, error) {
+	defer q.read()()
+	data := q.data
+	var quantity int32
+	for _, i := range data.returnItems.all(func(i postgres.ReturnItem) bool { return i.OrderItemID == orderItemID }) {
+		if r, ok := data.returns.get(i.ReturnID); ok && r.Status != postgres.ReturnStatusRejected {
+			quantity += i.Quantity
+		}
+	}
+	return quantity, nil
+}
+
+func (q *Queries) UpdateReturn(ctx context.Context, arg postgres.UpdateReturnParams) (postgres.Return, error) {
+	defer q.write()()
+	data := q.data
+	return update(q, &data.returns, arg.ID, nil, func(i *postgres.Return) error {
+		if arg.PaymentID.Valid {
+			if _, ok := data.payments.get(arg.PaymentID.Int64); !ok {
+				return foreignKeyViolation("returns", "returns_payment_id_fkey")
+			}
+		}
+		i.Status = arg.Status
+		i.Note = arg.Note
+		i.PaymentID = arg.PaymentID
+		i.ReceivedAt = arg.ReceivedAt
+		i.RefundedAt = arg.RefundedAt
+		i.UpdatedAt = now()
+		return nil
+	})
+}
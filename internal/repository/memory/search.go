@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// similarityThreshold is the default pg_trgm.similarity_threshold used by the % operator
+const similarityThreshold = 0.3
+
+// textQuery approximates the full-text search of the postgres store. Like websearch_to_tsquery it reads
+// "or" between terms as an alternative and a leading "-" as an exclusion; instead of stemming, a term
+// matches any word it is a prefix of, or a word that is a prefix of it and no more than two letters shorter.
+type textQuery struct {
+	raw          string
+	alternatives [][]string
+	excluded     []string
+}
+
+func parseTextQuery(query string) textQuery {
+	t := textQuery{raw: query, alternatives: [][]string{{}}}
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		switch {
+		case field == "or":
+			t.alternatives = append(t.alternatives, []string{})
+		case strings.HasPrefix(field, "-"):
+			t.excluded = append(t.excluded, words(field)...)
+		default:
+			last := len(t.alternatives) - 1
+			t.alternatives[last] = append(t.alternatives[last], words(field)...)
+		}
+	}
+	return t
+}
+
+// rank reports whether a product matches the query and how well, as the rank column of SearchProducts does
+func (t textQuery) rank(p postgres.Product) (float32, bool) {
+	name, description := words(p.Name), words(p.Description)
+	similar := similarity(p.Name, t.raw)
+
+	var best float32
+	matched := false
+	for _, terms := range t.alternatives {
+		if len(terms) == 0 {
+			continue
+		}
+		var weight float32
+		all := true
+		for _, term := range terms {
+			switch {
+			case matchesAny(term, name):
+				weight += 0.1
+			case matchesAny(term, description):
+				weight += 0.04
+			default:
+				all = false
+			}
+		}
+		if all && weight > best {
+			best, matched = weight, true
+		}
+	}
+	for _, term := range t.excluded {
+		if matchesAny(term, name) || matchesAny(term, description) {
+			matched = false
+		}
+	}
+
+	if !matched && similar < similarityThreshold {
+		return 0, false
+	}
+	return best + similar, true
+}
+
+func matchesAny(term string, words []string) bool {
+	for _, w := range words {
+		if strings.HasPrefix(w, term) || (strings.HasPrefix(term, w) && len([]rune(w)) >= 3 && len([]rune(term))-len([]rune(w)) <= 2) {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits text into lowercase words of letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// similarity computes the trigram similarity of pg_trgm: the shared trigrams of both strings over all of their trigrams
+func similarity(a, b string) float32 {
+	x, y := trigrams(a), trigrams(b)
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range x {
+		if y[g] {
+			shared++
+		}
+	}
+	return float32(shared) / float32(len(x)+len(y)-shared)
+}
+
+// trigrams returns the trigrams of every word padded with two spaces in front and one behind
+func trigrams(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range words(text) {
+		r := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			set[string(r[i:i+3])] = true
+		}
+	}
+	return set
+}
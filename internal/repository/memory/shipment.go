@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func (q *Queries) CountUndeliveredShipments(ctx context.Context, orderID int64) (int64, error) {
+	defer q.read()()
+	return q.data.shipments.count(func(i postgres.Shipment) bool {
+		return i.OrderID == orderID && i.Status != postgres.ShipmentStatusDelivered
+	}), nil
+}
+
+func (q *Queries) CreateShipment(ctx context.Context, arg postgres.CreateShipmentParams) (postgres.Shipment, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.orders.get(arg.OrderID); !ok {
+		return postgres.Shipment{}, foreignKeyViolation("shipments", "shipments_order_id_fkey")
+	}
+	if arg.ShippingMethodID.Valid {
+		if _, ok := data.shippingMethods.get(arg.ShippingMethodID.Int64); !ok {
+			return postgres.Shipment{}, foreignKeyViolation("shipments", "shipments_shipping_method_id_fkey")
+		}
+	}
+	i := postgres.Shipment{
+		ID:               data.shipments.next(),
+		OrderID:          arg.OrderID,
+		ShippingMethodID: arg.ShippingMethodID,
+		Carrier:          arg.Carrier,
+		TrackingNumber:   arg.TrackingNumber,
+		Status:           postgres.ShipmentStatusPending,
+		CreatedAt:        now(),
+	}
+	i.UpdatedAt = i.CreatedAt
+	put(q, &data.shipments, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) DeleteShipment(ctx context.Context, id int64) error {
+	defer q.write()()
+	remove(q, &q.data.shipments, id)
+	return nil
+}
+
+func (q *Queries) GetShipment(ctx context.Context, id int64) (postgres.Shipment, error) {
+	defer q.read()()
+	i, ok := q.data.shipments.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) ListShipmentsByOrder(ctx context.Context, orderID int64) ([]postgres.Shipment, error) {
+	defer q.read()()
+	return q.data.shipments.all(func(i postgres.Shipment) bool { return i.OrderID == orderID }), nil
+}
+
+func (q *Queries) UpdateShipment(ctx context.Context, arg postgres.UpdateShipmentParams) (postgres.Shipment, error) {
+	defer q.write()()
+	return update(q, &q.data.shipments, arg.ID, nil, func(i *postgres.Shipment) error {
+		i.Carrier = arg.Carrier
+		i.TrackingNumber = arg.TrackingNumber
+		i.Status = arg.Status
+		i.ShippedAt = arg.ShippedAt
+		i.DeliveredAt = arg.DeliveredAt
+		i.UpdatedAt = now()
+		return nil
+	})
+}
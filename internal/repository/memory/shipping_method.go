@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func (q *Queries) CreateShippingMethod(ctx context.Context, arg postgres.CreateShippingMethodParams) (postgres.ShippingMethod, error) {
+	defer q.write()()
+	data := q.data
+	price, err := money(arg.Price)
+	if err != nil {
+		return postgres.ShippingMethod{}, err
+	}
+	freeOver, err := nullMoney(arg.FreeOver)
+	if err != nil {
+		return postgres.ShippingMethod{}, err
+	}
+	i := postgres.ShippingMethod{
+		ID:        data.shippingMethods.next(),
+		Name:      arg.Name,
+		Carrier:   arg.Carrier,
+		RateType:  arg.RateType,
+		Price:     price,
+		FreeOver:  freeOver,
+		Active:    arg.Active,
+		CreatedAt: now(),
+	}
+	i.UpdatedAt = i.CreatedAt
+	put(q, &data.shippingMethods, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) CreateShippingRate(ctx context.Context, arg postgres.CreateShippingRateParams) (postgres.ShippingRate, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.shippingMethods.get(arg.MethodID); !ok {
+		return postgres.ShippingRate{}, foreignKeyViolation("shipping_rates", "shipping_rates_method_id_fkey")
+	}
+	if data.shippingRates.exists(func(r postgres.ShippingRate) bool {
+		return r.MethodID == arg.MethodID && r.MaxWeightGrams == arg.MaxWeightGrams
+	}) {
+		return postgres.ShippingRate{}, uniqueViolation("shipping_rates_method_id_max_weight_grams_key")
+	}
+	price, err := money(arg.Price)
+	if err != nil {
+		return postgres.ShippingRate{}, err
+	}
+	i := postgres.ShippingRate{
+		ID:             data.shippingRates.next(),
+		MethodID:       arg.MethodID,
+		MaxWeightGrams: arg.MaxWeightGrams,
+		Price:          price,
+	}
+	put(q, &data.shippingRates, i.ID, i)
+	return i, nil
+}
+
+// DeleteShippingMethod removes a method with its rates; shipments and orders that used it keep no method
+func (q *Queries) DeleteShippingMethod(ctx context.Context, id int64) error {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.shippingMethods.get(id); !ok {
+		return nil
+	}
+	q.removeShippingRates(id)
+	for _, s := range data.shipments.ids(func(s postgres.Shipment) bool { return s.ShippingMethodID.Valid && s.ShippingMethodID.Int64 == id }) {
+		row, _ := data.shipments.get(s)
+		row.ShippingMethodID = sql.NullInt64{}
+		put(q, &data.shipments, s, row)
+	}
+	for _, o := range data.orders.ids(func(o postgres.Order) bool { return o.ShippingMethodID.Valid && o.ShippingMethodID.Int64 == id }) {
+		if _, err := q.updateOrder(o, nil, func(i *postgres.Order) error {
+			i.ShippingMethodID = sql.NullInt64{}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	remove(q, &data.shippingMethods, id)
+	return nil
+}
+
+func (q *Queries) removeShippingRates(methodID int64) {
+	data := q.data
+	for _, r := range data.shippingRates.ids(func(r postgres.ShippingRate) bool { return r.MethodID == methodID }) {
+		remove(q, &data.shippingRates, r)
+	}
+}
+
+func (q *Queries) DeleteShippingRatesByMethod(ctx context.Context, methodID int64) error {
+	defer q.write()()
+	q.removeShippingRates(methodID)
+	return nil
+}
+
+func (q *Queries) GetShippingMethod(ctx context.Context, id int64) (postgres.ShippingMethod, error) {
+	defer q.read()()
+	i, ok := q.data.shippingMethods.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// byMaxWeight orders shipping rates by max_weight_grams
+func byMaxWeight(a, b postgres.ShippingRate) bool {
+	return a.MaxWeightGrams < b.MaxWeightGrams
+}
+
+// GetShippingRateForWeight returns the lightest rate of a method that still covers the given weight
+func (q *Queries) GetShippingRateForWeight(ctx context.Context, arg postgres.GetShippingRateForWeightParams) (postgres.ShippingRate, error) {
+	defer q.read()()
+	items := q.data.shippingRates.all(func(r postgres.ShippingRate) bool {
+		return r.MethodID == arg.MethodID && r.MaxWeightGrams >= arg.WeightGrams
+	})
+	if len(items) == 0 {
+		return postgres.ShippingRate{}, sql.ErrNoRows
+	}
+	sortRows(items, byMaxWeight)
+	return items[0], nil
+}
+
+func (q *Queries) ListShippingMethods(ctx context.Context) ([]postgres.ShippingMethod, error) {
+	defer q.read()()
+	return q.data.shippingMethods.all(nil), nil
+}
+
+func (q *Queries) ListShippingRatesByMethod(ctx context.Context, methodID int64) ([]postgres.ShippingRate, error) {
+	defer q.read()()
+	items := q.data.shippingRates.all(func(r postgres.ShippingRate) bool { return r.MethodID == methodID })
+	sortRows(items, byMaxWeight)
+	return items, nil
+}
+
+func (q *Queries) UpdateShippingMethod(ctx context.Context, arg postgres.UpdateShippingMethodParams) (postgres.ShippingMethod, error) {
+	defer q.write()()
+	price, err := money(arg.Price)
+	if err != nil {
+		return postgres.ShippingMethod{}, err
+	}
+	freeOver, err := nullMoney(arg.FreeOver)
+	if err != nil {
+		return postgres.ShippingMethod{}, err
+	}
+	return update(q, &q.data.shippingMethods, arg.ID, nil, func(i *postgres.ShippingMethod) error {
+		i.Name = arg.Name
+		i.Carrier = arg.Carrier
+		i.RateType = arg.RateType
+		i.Price = price
+		i.FreeOver = freeOver
+		i.Active = arg.Active
+		i.UpdatedAt = now()
+		return nil
+	})
+}
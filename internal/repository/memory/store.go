@@ -0,0 +1,502 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Store keeps every table in memory and serves the same queries as the postgres store.
+// It is safe for concurrent use. Transactions run one at a time and copy a table the first time they write to it:
+// until then they read its rows as other queries do, afterwards other queries do not see their writes
+// until they commit, and a rollback only drops the copies.
+// A commit fails with a serialization failure when a row it writes was changed by another query since the copy
+// was taken, and with a unique violation when another query has since stored a row with the same unique key.
+// Nothing retries such a commit: the Store is meant for a single writer, such as a developer or a test,
+// and only Postgres should serve concurrent writers.
+type Store struct {
+	*Queries
+	mu   sync.RWMutex
+	txs  chan struct{} // holds the running transaction
+	data *tables
+}
+
+// NewStore creates an empty Store holding the rows the migrations seed
+func NewStore() *Store {
+	s := &Store{
+		txs:  make(chan struct{}, 1),
+		data: &tables{},
+	}
+	s.data.constrain()
+	s.Queries = &Queries{store: s, data: s.data}
+	s.seed()
+	return s
+}
+
+// seed inserts the rows a freshly migrated database starts with
+func (s *Store) seed() {
+	for _, rate := range []postgres.CreateTaxRateParams{
+		{TaxClass: "standard", Country: "KZ", Rate: "0.12", Name: "VAT 12%"},
+		{TaxClass: "exempt", Country: "KZ", Rate: "0", Name: "VAT exempt"},
+	} {
+		if _, err := s.CreateTaxRate(context.Background(), rate); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// BeginTx starts a new transaction, waiting for the running one to finish first or for ctx to be done
+func (s *Store) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	select {
+	case s.txs <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	data := s.data.share()
+	s.mu.Unlock()
+
+	return &Tx{Queries: &Queries{store: s, data: data}}, nil
+}
+
+// Tx runs queries against the tables of the store, copying each one it writes to
+type Tx struct {
+	*Queries
+	done bool
+}
+
+// Commit writes the rows the transaction changed to the tables of the store.
+// Nothing is written when one of them was changed outside the transaction since its table was copied,
+// or when a written row has a unique key that another row of the store now has.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	defer func() { <-tx.store.txs }()
+
+	tx.store.mu.Lock()
+	defer tx.store.mu.Unlock()
+
+	live, written := tx.store.data.each(), tx.data.each()
+	for i := range written {
+		if written[i].conflicts(live[i]) {
+			return serializationFailure()
+		}
+	}
+	for i := range written {
+		if constraint := written[i].duplicate(live[i]); constraint != "" {
+			return uniqueViolation(constraint)
+		}
+	}
+	for i := range written {
+		written[i].apply(live[i])
+	}
+	return nil
+}
+
+// Rollback drops the writes of the transaction
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	<-tx.store.txs
+	return nil
+}
+
+// Queries runs the queries of the postgres.Querier against a set of tables:
+// those of the Store, or the copy of a transaction
+type Queries struct {
+	store *Store
+	data  *tables
+}
+
+var _ postgres.Querier = (*Queries)(nil)
+
+func (q *Queries) read() func() {
+	q.store.mu.RLock()
+	return q.store.mu.RUnlock
+}
+
+func (q *Queries) write() func() {
+	q.store.mu.Lock()
+	return q.store.mu.Unlock
+}
+
+type tables struct {
+	apiKeys              table[postgres.ApiKey]
+	auditEvents          table[postgres.AuditEvent]
+	cartItems            table[postgres.CartItem]
+	categories           table[postgres.Category]
+	idempotencyKeys      table[postgres.IdempotencyKey]
+	notifications        table[postgres.Notification]
+	orders               table[postgres.Order]
+	orderAddresses       table[postgres.OrderAddress]
+	orderAdjustments     table[postgres.OrderAdjustment]
+	orderItems           table[postgres.OrderItem]
+	payments             table[postgres.Payment]
+	products             table[postgres.Product]
+	productImages        table[postgres.ProductImage]
+	productImportJobs    table[postgres.ProductImportJob]
+	productVariants      table[postgres.ProductVariant]
+	promotions           table[postgres.Promotion]
+	promotionRedemptions table[postgres.PromotionRedemption]
+	returns              table[postgres.Return]
+	returnItems          table[postgres.ReturnItem]
+	shipments            table[postgres.Shipment]
+	shippingMethods      table[postgres.ShippingMethod]
+	shippingRates        table[postgres.ShippingRate]
+	taxRates             table[postgres.TaxRate]
+	users                table[postgres.User]
+	userAddresses        table[postgres.UserAddress]
+	webhookDeliveries    table[postgres.WebhookDelivery]
+	webhookSubscriptions table[postgres.WebhookSubscription]
+}
+
+// share sets up the tables of a transaction, which read the rows of t until they are first written to
+func (t *tables) share() *tables {
+	c := *t
+	live, copies := t.each(), c.each()
+	for i := range copies {
+		copies[i].isolate(live[i])
+	}
+	return &c
+}
+
+// constrain declares the unique keys of the tables, which a commit checks again against the rows of the store.
+// Queries check them as they write, like the migrations declare them.
+func (t *tables) constrain() {
+	t.apiKeys.unique("api_keys_prefix_key", func(i postgres.ApiKey) (any, bool) { return i.Prefix, true })
+	t.cartItems.unique("cart_items_line_idx", func(i postgres.CartItem) (any, bool) {
+		return [3]int64{i.UserID, i.ProductID, i.VariantID.Int64}, true
+	})
+	t.categories.unique("categories_slug_key", func(i postgres.Category) (any, bool) { return i.Slug, true })
+	t.idempotencyKeys.unique("idempotency_keys_owner_key_key", func(i postgres.IdempotencyKey) (any, bool) {
+		return [2]string{i.Owner, i.Key}, true
+	})
+	t.orderAddresses.unique("order_addresses_order_id_type_key", func(i postgres.OrderAddress) (any, bool) {
+		return [2]any{i.OrderID, i.Type}, true
+	})
+	t.products.unique("products_sku_key", func(i postgres.Product) (any, bool) { return i.Sku, true })
+	t.productImages.unique("product_images_primary_idx", func(i postgres.ProductImage) (any, bool) { return i.ProductID, i.IsPrimary })
+	t.productVariants.unique("product_variants_sku_key", func(i postgres.ProductVariant) (any, bool) { return i.Sku, true })
+	t.promotions.unique("promotions_code_key", func(i postgres.Promotion) (any, bool) { return i.Code.String, i.Code.Valid })
+	t.returnItems.unique("return_items_return_id_order_item_id_key", func(i postgres.ReturnItem) (any, bool) {
+		return [2]int64{i.ReturnID, i.OrderItemID}, true
+	})
+	t.shippingRates.unique("shipping_rates_method_id_max_weight_grams_key", func(i postgres.ShippingRate) (any, bool) {
+		return [2]any{i.MethodID, i.MaxWeightGrams}, true
+	})
+	t.taxRates.unique("tax_rates_tax_class_country_key", func(i postgres.TaxRate) (any, bool) {
+		return [2]string{i.TaxClass, i.Country}, true
+	})
+	t.users.unique("users_email_key", func(i postgres.User) (any, bool) { return i.Email, true })
+	t.userAddresses.unique("user_addresses_default_idx", func(i postgres.UserAddress) (any, bool) {
+		return [2]any{i.UserID, i.Type}, i.IsDefault
+	})
+}
+
+// each lists every table, in the same order for every set of tables
+func (t *tables) each() []rowTable {
+	return []rowTable{
+		&t.apiKeys,
+		&t.auditEvents,
+		&t.cartItems,
+		&t.categories,
+		&t.idempotencyKeys,
+		&t.notifications,
+		&t.orders,
+		&t.orderAddresses,
+		&t.orderAdjustments,
+		&t.orderItems,
+		&t.payments,
+		&t.products,
+		&t.productImages,
+		&t.productImportJobs,
+		&t.productVariants,
+		&t.promotions,
+		&t.promotionRedemptions,
+		&t.returns,
+		&t.returnItems,
+		&t.shipments,
+		&t.shippingMethods,
+		&t.shippingRates,
+		&t.taxRates,
+		&t.users,
+		&t.userAddresses,
+		&t.webhookDeliveries,
+		&t.webhookSubscriptions,
+	}
+}
+
+// rowTable is a table of any row type, as seen when setting up tables for a transaction and committing it
+type rowTable interface {
+	// isolate shares the rows of live until the first write and starts recording the rows written
+	isolate(live rowTable)
+	// conflicts reports whether live has changed a row written to the copy since it was taken
+	conflicts(live rowTable) bool
+	// duplicate returns the unique key a written row shares with a row of live that the copy does not replace
+	duplicate(live rowTable) string
+	// apply writes the rows written to the copy to live
+	apply(live rowTable)
+}
+
+// table holds the rows of one table by primary key; like a sequence, seq is shared with the copies
+// of transactions and is not rolled back
+type table[T any] struct {
+	rows map[int64]T
+	seq  *int64
+	// written holds the rows a transaction has written as they were when its copy was taken; nil outside a transaction
+	written map[int64]original[T]
+	// copied reports whether rows is the transaction's own copy rather than the map of the store
+	copied bool
+	keys   []uniqueKey[T]
+}
+
+// uniqueKey is a unique constraint; key returns the value of a row and whether the row is indexed at all,
+// which rows with a null value or outside a partial index are not
+type uniqueKey[T any] struct {
+	constraint string
+	key        func(T) (any, bool)
+}
+
+func (t *table[T]) unique(constraint string, key func(T) (any, bool)) {
+	t.keys = append(t.keys, uniqueKey[T]{constraint: constraint, key: key})
+}
+
+// original is a row as it was when a transaction began
+type original[T any] struct {
+	row     T
+	existed bool
+}
+
+func (t *table[T]) next() int64 {
+	if t.seq == nil {
+		t.seq = new(int64)
+	}
+	*t.seq++
+	return *t.seq
+}
+
+func (t *table[T]) isolate(live rowTable) {
+	l := live.(*table[T])
+	if l.seq == nil {
+		l.seq = new(int64)
+	}
+
+	if l.rows == nil {
+		l.rows = map[int64]T{}
+	}
+
+	t.rows = l.rows
+	t.seq = l.seq
+	t.written = map[int64]original[T]{}
+	t.copied = false
+}
+
+func (t *table[T]) conflicts(live rowTable) bool {
+	l := live.(*table[T])
+	for id, before := range t.written {
+		row, exists := l.rows[id]
+		if exists != before.existed || (exists && !reflect.DeepEqual(row, before.row)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *table[T]) duplicate(live rowTable) string {
+	l := live.(*table[T])
+	for _, u := range t.keys {
+		written := map[any]bool{}
+		for id := range t.written {
+			if row, ok := t.rows[id]; ok {
+				if key, indexed := u.key(row); indexed {
+					written[key] = true
+				}
+			}
+		}
+		if len(written) == 0 {
+			continue
+		}
+
+		for id, row := range l.rows {
+			if _, replaced := t.written[id]; replaced {
+				continue
+			}
+			if key, indexed := u.key(row); indexed && written[key] {
+				return u.constraint
+			}
+		}
+	}
+	return ""
+}
+
+func (t *table[T]) apply(live rowTable) {
+	l := live.(*table[T])
+	for id := range t.written {
+		if row, ok := t.rows[id]; ok {
+			if l.rows == nil {
+				l.rows = map[int64]T{}
+			}
+			l.rows[id] = row
+		} else {
+			delete(l.rows, id)
+		}
+	}
+}
+
+// track records the row with the given id as it was before a transaction first writes it,
+// copying the rows of the store the first time the transaction writes to the table
+func (t *table[T]) track(id int64) {
+	if t.written == nil {
+		return
+	}
+	if !t.copied {
+		rows := make(map[int64]T, len(t.rows))
+		for id, row := range t.rows {
+			rows[id] = row
+		}
+		t.rows = rows
+		t.copied = true
+	}
+	if _, ok := t.written[id]; !ok {
+		row, existed := t.rows[id]
+		t.written[id] = original[T]{row: row, existed: existed}
+	}
+}
+
+func (t *table[T]) get(id int64) (row T, ok bool) {
+	row, ok = t.rows[id]
+	return
+}
+
+// all returns the rows accepted by match in primary key order; a nil match accepts every row
+func (t *table[T]) all(match func(T) bool) []T {
+	ids := make([]int64, 0, len(t.rows))
+	for id, row := range t.rows {
+		if match == nil || match(row) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	dst := make([]T, 0, len(ids))
+	for _, id := range ids {
+		dst = append(dst, t.rows[id])
+	}
+	return dst
+}
+
+// ids returns the primary keys of the rows accepted by match in ascending order
+func (t *table[T]) ids(match func(T) bool) []int64 {
+	ids := make([]int64, 0)
+	for id, row := range t.rows {
+		if match(row) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// first returns the row with the lowest primary key accepted by match
+func (t *table[T]) first(match func(T) bool) (row T, ok bool) {
+	ids := t.ids(match)
+	if len(ids) == 0 {
+		return
+	}
+	return t.rows[ids[0]], true
+}
+
+func (t *table[T]) exists(match func(T) bool) bool {
+	for _, row := range t.rows {
+		if match(row) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *table[T]) count(match func(T) bool) (n int64) {
+	for _, row := range t.rows {
+		if match(row) {
+			n++
+		}
+	}
+	return
+}
+
+// anyOf returns a match for the keys in ids, as = ANY($1) does
+func anyOf(ids []int64) func(int64) bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return func(id int64) bool { return set[id] }
+}
+
+// head keeps the first rows up to a LIMIT
+func head[T any](rows []T, limit int32) []T {
+	if limit < 0 {
+		limit = 0
+	}
+	if len(rows) > int(limit) {
+		return rows[:limit]
+	}
+	return rows
+}
+
+// put stores a row
+func put[T any](q *Queries, t *table[T], id int64, row T) {
+	if t.rows == nil {
+		t.rows = map[int64]T{}
+	}
+	t.track(id)
+	t.rows[id] = row
+}
+
+// remove deletes a row
+func remove[T any](q *Queries, t *table[T], id int64) {
+	if _, existed := t.rows[id]; !existed {
+		return
+	}
+	t.track(id)
+	delete(t.rows, id)
+}
+
+// update changes the row with the given id when match accepts it and stores the result
+func update[T any](q *Queries, t *table[T], id int64, match func(T) bool, change func(*T) error) (row T, err error) {
+	row, ok := t.rows[id]
+	if !ok || (match != nil && !match(row)) {
+		var zero T
+		return zero, sql.ErrNoRows
+	}
+	if err = change(&row); err != nil {
+		var zero T
+		return zero, err
+	}
+	put(q, t, id, row)
+	return row, nil
+}
+
+// now is the value of NOW() at the precision of a timestamp column
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func nullNow() sql.NullTime {
+	return sql.NullTime{Time: now(), Valid: true}
+}
+
+// deletedBefore reports whether a row was deleted before the given time, as deleted_at < $1 does
+func deletedBefore(deletedAt, before sql.NullTime) bool {
+	return deletedAt.Valid && before.Valid && deletedAt.Time.Before(before.Time)
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func TestCommit(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// outside writes through the store while the transaction is open
+		outside func(s *Store) error
+		code    pq.ErrorCode
+	}{
+		{
+			name:    "no concurrent write",
+			outside: func(s *Store) error { return nil },
+		},
+		{
+			name: "same unique key",
+			outside: func(s *Store) error {
+				_, err := s.CreateUser(ctx, postgres.CreateUserParams{Email: "new@example.com", Role: "customer"})
+				return err
+			},
+			code: "23505",
+		},
+		{
+			name: "same row",
+			outside: func(s *Store) error {
+				_, err := s.DeleteUser(ctx, 1)
+				return err
+			},
+			code: "40001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStore()
+			if _, err := s.CreateUser(ctx, postgres.CreateUserParams{Email: "old@example.com", Role: "customer"}); err != nil {
+				t.Fatal(err)
+			}
+
+			tx, err := s.BeginTx(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = tx.CreateUser(ctx, postgres.CreateUserParams{Email: "new@example.com", Role: "customer"}); err != nil {
+				t.Fatal(err)
+			}
+			if err = tx.UpdateUserPassword(ctx, postgres.UpdateUserPasswordParams{ID: 1, PasswordHash: "hash"}); err != nil {
+				t.Fatal(err)
+			}
+			if err = tt.outside(s); err != nil {
+				t.Fatal(err)
+			}
+
+			err = tx.Commit()
+			var pqErr *pq.Error
+			switch {
+			case tt.code == "" && err != nil:
+				t.Fatalf("commit failed: %v", err)
+			case tt.code != "" && (!errors.As(err, &pqErr) || pqErr.Code != tt.code):
+				t.Fatalf("commit returned %v, want SQLSTATE %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestTxReadsUnwrittenTables(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	user, err := s.CreateUser(ctx, postgres.CreateUserParams{Email: "jane@example.com", Role: "customer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tx.GetUser(ctx, user.ID); err != nil {
+		t.Errorf("a table the transaction has not written should show committed rows: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// checkTaxRate enforces the single rate per tax class and country of the tax_rates table
+func (q *Queries) checkTaxRate(i postgres.TaxRate) error {
+	if q.data.taxRates.exists(func(r postgres.TaxRate) bool {
+		return r.ID != i.ID && r.TaxClass == i.TaxClass && r.Country == i.Country
+	}) {
+		return uniqueViolation("tax_rates_tax_class_country_key")
+	}
+	return nil
+}
+
+func (q *Queries) CreateTaxRate(ctx context.Context, arg postgres.CreateTaxRateParams) (postgres.TaxRate, error) {
+	defer q.write()()
+	data := q.data
+	rate, err := numeric(arg.Rate, 6, 4)
+	if err != nil {
+		return postgres.TaxRate{}, err
+	}
+	i := postgres.TaxRate{
+		TaxClass:  arg.TaxClass,
+		Country:   arg.Country,
+		Rate:      rate,
+		Name:      arg.Name,
+		CreatedAt: now(),
+	}
+	if err := q.checkTaxRate(i); err != nil {
+		return postgres.TaxRate{}, err
+	}
+	i.ID = data.taxRates.next()
+	put(q, &data.taxRates, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) DeleteTaxRate(ctx context.Context, id int64) error {
+	defer q.write()()
+	remove(q, &q.data.taxRates, id)
+	return nil
+}
+
+func (q *Queries) GetTaxRate(ctx context.Context, id int64) (postgres.TaxRate, error) {
+	defer q.read()()
+	i, ok := q.data.taxRates.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) GetTaxRateByClass(ctx context.Context, arg postgres.GetTaxRateByClassParams) (postgres.TaxRate, error) {
+	defer q.read()()
+	i, ok := q.data.taxRates.first(func(r postgres.TaxRate) bool {
+		return r.TaxClass == arg.TaxClass && r.Country == arg.Country
+	})
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) ListTaxRates(ctx context.Context) ([]postgres.TaxRate, error) {
+	defer q.read()()
+	items := q.data.taxRates.all(nil)
+	sortRows(items, func(a, b postgres.TaxRate) bool {
+		if a.Country != b.Country {
+			return a.Country < b.Country
+		}
+		return a.TaxClass < b.TaxClass
+	})
+	return items, nil
+}
+
+func (q *Queries) UpdateTaxRate(ctx context.Context, arg postgres.UpdateTaxRateParams) (postgres.TaxRate, error) {
+	defer q.write()()
+	rate, err := numeric(arg.Rate, 6, 4)
+	if err != nil {
+		return postgres.TaxRate{}, err
+	}
+	return update(q, &q.data.taxRates, arg.ID, nil, func(i *postgres.TaxRate) error {
+		i.TaxClass = arg.TaxClass
+		i.Country = arg.Country
+		i.Rate = rate
+		i.Name = arg.Name
+		return q.checkTaxRate(*i)
+	})
+}
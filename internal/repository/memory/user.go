@@ -1 +1,168 @@
-package memory
\ No newline at end of file
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// updateUser changes a user accepted by match and bumps its version, as the bump_version trigger does
+func (q *Queries) updateUser(id int64, match func(postgres.User) bool, change func(*postgres.User) error) (postgres.User, error) {
+	data := q.data
+	return update(q, &data.users, id, match, func(i *postgres.User) error {
+		old := i.Email
+		if err := change(i); err != nil {
+			return err
+		}
+		if i.Email != old && data.users.exists(func(u postgres.User) bool { return u.ID != id && u.Email == i.Email }) {
+			return uniqueViolation("users_email_key")
+		}
+		i.Version++
+		return nil
+	})
+}
+
+func (q *Queries) GetUser(ctx context.Context, id int64) (postgres.User, error) {
+	defer q.read()()
+	i, ok := q.data.users.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) ListUsers(ctx context.Context) ([]postgres.User, error) {
+	defer q.read()()
+	items := q.data.users.all(func(i postgres.User) bool { return !i.DeletedAt.Valid })
+	sortRows(items, func(a, b postgres.User) bool { return a.RegistrationDate.Before(b.RegistrationDate) })
+	return items, nil
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg postgres.CreateUserParams) (postgres.User, error) {
+	defer q.write()()
+	data := q.data
+	if data.users.exists(func(u postgres.User) bool { return u.Email == arg.Email }) {
+		return postgres.User{}, uniqueViolation("users_email_key")
+	}
+	i := postgres.User{
+		ID:               data.users.next(),
+		FullName:         arg.FullName,
+		Email:            arg.Email,
+		Address:          arg.Address,
+		RegistrationDate: now(),
+		Role:             arg.Role,
+		PasswordHash:     arg.PasswordHash,
+		Locale:           arg.Locale,
+		Version:          1,
+	}
+	put(q, &data.users, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg postgres.UpdateUserParams) (postgres.User, error) {
+	defer q.write()()
+	return q.updateUser(arg.ID, func(i postgres.User) bool { return i.Version == arg.Version }, func(i *postgres.User) error {
+		i.FullName = arg.FullName
+		if i.Email != arg.Email {
+			i.EmailVerifiedAt = sql.NullTime{}
+		}
+		i.Email = arg.Email
+		i.Address = arg.Address
+		i.Role = arg.Role
+		return nil
+	})
+}
+
+func (q *Queries) DeleteUser(ctx context.Context, id int64) (postgres.User, error) {
+	defer q.write()()
+	return q.updateUser(id, func(i postgres.User) bool { return !i.DeletedAt.Valid }, func(i *postgres.User) error {
+		i.DeletedAt = nullNow()
+		return nil
+	})
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (postgres.User, error) {
+	defer q.write()()
+	return q.updateUser(id, func(i postgres.User) bool { return i.DeletedAt.Valid }, func(i *postgres.User) error {
+		i.DeletedAt = sql.NullTime{}
+		return nil
+	})
+}
+
+func (q *Queries) ListUsersByIDs(ctx context.Context, ids []int64) ([]postgres.User, error) {
+	defer q.read()()
+	match := anyOf(ids)
+	return q.data.users.all(func(i postgres.User) bool { return match(i.ID) }), nil
+}
+
+// PurgeDeletedUsers keeps users who still have orders or payments; their API keys, notifications, addresses and carts go with them
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	defer q.write()()
+	data := q.data
+	expired := data.users.ids(func(u postgres.User) bool {
+		return deletedBefore(u.DeletedAt, deletedAt) &&
+			!data.orders.exists(func(o postgres.Order) bool { return o.UserID == u.ID }) &&
+			!data.payments.exists(func(p postgres.Payment) bool { return p.UserID == u.ID })
+	})
+	for _, id := range expired {
+		for _, key := range data.apiKeys.ids(func(k postgres.ApiKey) bool { return k.UserID == id }) {
+			remove(q, &data.apiKeys, key)
+		}
+		for _, n := range data.notifications.ids(func(n postgres.Notification) bool { return n.UserID == id }) {
+			remove(q, &data.notifications, n)
+		}
+		for _, a := range data.userAddresses.ids(func(a postgres.UserAddress) bool { return a.UserID == id }) {
+			remove(q, &data.userAddresses, a)
+		}
+		q.removeCartItems(func(i postgres.CartItem) bool { return i.UserID == id })
+		remove(q, &data.users, id)
+	}
+	return int64(len(expired)), nil
+}
+
+func (q *Queries) SearchUsersByName(ctx context.Context, dollar_1 sql.NullString) ([]postgres.User, error) {
+	defer q.read()()
+	// A NULL pattern matches no rows, as NULL ILIKE does
+	items := q.data.users.all(func(i postgres.User) bool {
+		return dollar_1.Valid && containsFold(i.FullName, dollar_1.String) && !i.DeletedAt.Valid
+	})
+	sortRows(items, func(a, b postgres.User) bool { return a.RegistrationDate.Before(b.RegistrationDate) })
+	return items, nil
+}
+
+func (q *Queries) SearchUsersByEmail(ctx context.Context, email string) ([]postgres.User, error) {
+	defer q.read()()
+	items := q.data.users.all(func(i postgres.User) bool { return i.Email == email && !i.DeletedAt.Valid })
+	sortRows(items, func(a, b postgres.User) bool { return a.RegistrationDate.Before(b.RegistrationDate) })
+	return items, nil
+}
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (postgres.User, error) {
+	defer q.read()()
+	i, ok := q.data.users.first(func(i postgres.User) bool { return i.Email == email && !i.DeletedAt.Valid })
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg postgres.UpdateUserPasswordParams) error {
+	defer q.write()()
+	_, err := q.updateUser(arg.ID, nil, func(i *postgres.User) error {
+		i.PasswordHash = arg.PasswordHash
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, id int64) (postgres.User, error) {
+	defer q.write()()
+	return q.updateUser(id, nil, func(i *postgres.User) error {
+		i.EmailVerifiedAt = nullNow()
+		return nil
+	})
+}
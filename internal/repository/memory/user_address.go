@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// checkUserAddress enforces the foreign key and the single default address per user and type of the user_addresses table
+func (q *Queries) checkUserAddress(i postgres.UserAddress) error {
+	data := q.data
+	if _, ok := data.users.get(i.UserID); !ok {
+		return foreignKeyViolation("user_addresses", "user_addresses_user_id_fkey")
+	}
+	if i.IsDefault && data.userAddresses.exists(func(a postgres.UserAddress) bool {
+		return a.ID != i.ID && a.UserID == i.UserID && a.Type == i.Type && a.IsDefault
+	}) {
+		return uniqueViolation("user_addresses_default_idx")
+	}
+	return nil
+}
+
+// ofUser accepts the addresses of a user
+func ofUser(userID int64) func(postgres.UserAddress) bool {
+	return func(a postgres.UserAddress) bool { return a.UserID == userID }
+}
+
+func (q *Queries) ClearDefaultUserAddress(ctx context.Context, arg postgres.ClearDefaultUserAddressParams) error {
+	defer q.write()()
+	data := q.data
+	for _, id := range data.userAddresses.ids(func(a postgres.UserAddress) bool {
+		return a.UserID == arg.UserID && a.Type == arg.Type && a.IsDefault
+	}) {
+		row, _ := data.userAddresses.get(id)
+		row.IsDefault = false
+		put(q, &data.userAddresses, id, row)
+	}
+	return nil
+}
+
+func (q *Queries) CreateUserAddress(ctx context.Context, arg postgres.CreateUserAddressParams) (postgres.UserAddress, error) {
+	defer q.write()()
+	data := q.data
+	i := postgres.UserAddress{
+		UserID:     arg.UserID,
+		Type:       arg.Type,
+		FullName:   arg.FullName,
+		Phone:      arg.Phone,
+		Line1:      arg.Line1,
+		Line2:      arg.Line2,
+		City:       arg.City,
+		Region:     arg.Region,
+		PostalCode: arg.PostalCode,
+		Country:    arg.Country,
+		CreatedAt:  now(),
+	}
+	i.UpdatedAt = i.CreatedAt
+	if err := q.checkUserAddress(i); err != nil {
+		return postgres.UserAddress{}, err
+	}
+	i.ID = data.userAddresses.next()
+	put(q, &data.userAddresses, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) DeleteUserAddress(ctx context.Context, arg postgres.DeleteUserAddressParams) (postgres.UserAddress, error) {
+	defer q.write()()
+	data := q.data
+	i, ok := data.userAddresses.get(arg.ID)
+	if !ok || i.UserID != arg.UserID {
+		return postgres.UserAddress{}, sql.ErrNoRows
+	}
+	remove(q, &data.userAddresses, arg.ID)
+	return i, nil
+}
+
+// EnsureDefaultUserAddress makes the oldest address of a type the default when the type has none
+func (q *Queries) EnsureDefaultUserAddress(ctx context.Context, arg postgres.EnsureDefaultUserAddressParams) error {
+	defer q.write()()
+	data := q.data
+	ofType := func(a postgres.UserAddress) bool { return a.UserID == arg.UserID && a.Type == arg.Type }
+	if data.userAddresses.exists(func(a postgres.UserAddress) bool { return ofType(a) && a.IsDefault }) {
+		return nil
+	}
+	oldest, ok := data.userAddresses.first(ofType)
+	if !ok {
+		return nil
+	}
+	oldest.IsDefault = true
+	put(q, &data.userAddresses, oldest.ID, oldest)
+	return nil
+}
+
+func (q *Queries) GetDefaultUserAddress(ctx context.Context, arg postgres.GetDefaultUserAddressParams) (postgres.UserAddress, error) {
+	defer q.read()()
+	i, ok := q.data.userAddresses.first(func(a postgres.UserAddress) bool {
+		return a.UserID == arg.UserID && a.Type == arg.Type && a.IsDefault
+	})
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) GetUserAddress(ctx context.Context, arg postgres.GetUserAddressParams) (postgres.UserAddress, error) {
+	defer q.read()()
+	i, ok := q.data.userAddresses.get(arg.ID)
+	if !ok || i.UserID != arg.UserID {
+		return postgres.UserAddress{}, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+// ListUserAddresses returns the addresses of a user by type, the default of each type first
+func (q *Queries) ListUserAddresses(ctx context.Context, userID int64) ([]postgres.UserAddress, error) {
+	defer q.read()()
+	items := q.data.userAddresses.all(ofUser(userID))
+	sortRows(items, func(a, b postgres.UserAddress) bool {
+		if a.Type != b.Type {
+			return addressTypeOrder(a.Type) < addressTypeOrder(b.Type)
+		}
+		return a.IsDefault && !b.IsDefault
+	})
+	return items, nil
+}
+
+func (q *Queries) SetDefaultUserAddress(ctx context.Context, id int64) (postgres.UserAddress, error) {
+	defer q.write()()
+	return update(q, &q.data.userAddresses, id, nil, func(i *postgres.UserAddress) error {
+		i.IsDefault = true
+		return q.checkUserAddress(*i)
+	})
+}
+
+// UpdateUserAddress rewrites an address; one moved to another type stops being the default of its old type
+func (q *Queries) UpdateUserAddress(ctx context.Context, arg postgres.UpdateUserAddressParams) (postgres.UserAddress, error) {
+	defer q.write()()
+	return update(q, &q.data.userAddresses, arg.ID, ofUser(arg.UserID), func(i *postgres.UserAddress) error {
+		i.IsDefault = i.IsDefault && i.Type == arg.Type
+		i.Type = arg.Type
+		i.FullName = arg.FullName
+		i.Phone = arg.Phone
+		i.Line1 = arg.Line1
+		i.Line2 = arg.Line2
+		i.City = arg.City
+		i.Region = arg.Region
+		i.PostalCode = arg.PostalCode
+		i.Country = arg.Country
+		i.UpdatedAt = now()
+		return q.checkUserAddress(*i)
+	})
+}
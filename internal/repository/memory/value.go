@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// The errors below carry the SQLSTATE codes Postgres reports, so callers handle both stores alike
+
+func uniqueViolation(constraint string) error {
+	return &pq.Error{
+		Severity:   "ERROR",
+		Code:       "23505",
+		Message:    fmt.Sprintf("duplicate key value violates unique constraint %q", constraint),
+		Constraint: constraint,
+	}
+}
+
+func serializationFailure() error {
+	return &pq.Error{
+		Severity: "ERROR",
+		Code:     "40001",
+		Message:  "could not serialize access due to concurrent update",
+	}
+}
+
+func foreignKeyViolation(table, constraint string) error {
+	return &pq.Error{
+		Severity:   "ERROR",
+		Code:       "23503",
+		Message:    fmt.Sprintf("insert, update or delete on table %q violates foreign key constraint %q", table, constraint),
+		Table:      table,
+		Constraint: constraint,
+	}
+}
+
+func checkViolation(table, constraint string) error {
+	return &pq.Error{
+		Severity:   "ERROR",
+		Code:       "23514",
+		Message:    fmt.Sprintf("new row for relation %q violates check constraint %q", table, constraint),
+		Table:      table,
+		Constraint: constraint,
+	}
+}
+
+// parseNumeric reads a numeric value the way a ::numeric cast does
+func parseNumeric(value string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return nil, &pq.Error{
+			Severity: "ERROR",
+			Code:     "22P02",
+			Message:  fmt.Sprintf("invalid input syntax for type numeric: %q", value),
+		}
+	}
+	return r, nil
+}
+
+// formatNumeric rounds a value to the scale of a numeric(precision, scale) column, half away from zero as Postgres does
+func formatNumeric(r *big.Rat, precision, scale int) (string, error) {
+	s := r.FloatString(scale)
+	if strings.Trim(s, "-0.") == "" {
+		s = strings.TrimPrefix(s, "-")
+	}
+
+	digits := strings.TrimLeft(strings.TrimPrefix(strings.SplitN(s, ".", 2)[0], "-"), "0")
+	if len(digits) > precision-scale {
+		return "", &pq.Error{
+			Severity: "ERROR",
+			Code:     "22003",
+			Message:  "numeric field overflow",
+		}
+	}
+	return s, nil
+}
+
+// numeric stores a value in a numeric(precision, scale) column
+func numeric(value string, precision, scale int) (string, error) {
+	r, err := parseNumeric(value)
+	if err != nil {
+		return "", err
+	}
+	return formatNumeric(r, precision, scale)
+}
+
+// money stores a value in a numeric(10,2) column
+func money(value string) (string, error) {
+	return numeric(value, 10, 2)
+}
+
+// nullMoney stores a value in a nullable numeric(10,2) column
+func nullMoney(value sql.NullString) (sql.NullString, error) {
+	if !value.Valid {
+		return value, nil
+	}
+	s, err := money(value.String)
+	return sql.NullString{String: s, Valid: true}, err
+}
+
+// rat reads a value that was stored by numeric; stored values always parse
+func rat(value string) *big.Rat {
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+func compareNumeric(a, b string) int {
+	return rat(a).Cmp(rat(b))
+}
+
+// monies converts several numeric(10,2) values at once, stopping at the first invalid one
+func monies(values ...*string) error {
+	for _, v := range values {
+		s, err := money(*v)
+		if err != nil {
+			return err
+		}
+		*v = s
+	}
+	return nil
+}
+
+func cloneStrings(src []string) []string {
+	if src == nil {
+		return []string{}
+	}
+	return append([]string{}, src...)
+}
+
+func cloneBytes(src []byte) []byte {
+	if src == nil {
+		return nil
+	}
+	return append([]byte{}, src...)
+}
+
+// notNull reports a NULL written to a NOT NULL column
+func notNull(table, column string) error {
+	return &pq.Error{
+		Severity: "ERROR",
+		Code:     "23502",
+		Message:  fmt.Sprintf("null value in column %q of relation %q violates not-null constraint", column, table),
+		Table:    table,
+		Column:   column,
+	}
+}
+
+// jsonb stores a document in a jsonb NOT NULL column
+func jsonb(table, column string, src json.RawMessage) (json.RawMessage, error) {
+	if src == nil {
+		return nil, notNull(table, column)
+	}
+	if !json.Valid(src) {
+		return nil, &pq.Error{
+			Severity: "ERROR",
+			Code:     "22P02",
+			Message:  "invalid input syntax for type json",
+		}
+	}
+	return json.RawMessage(cloneBytes(src)), nil
+}
@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg postgres.CreateWebhookDeliveryParams) (postgres.WebhookDelivery, error) {
+	defer q.write()()
+	data := q.data
+	if _, ok := data.webhookSubscriptions.get(arg.SubscriptionID); !ok {
+		return postgres.WebhookDelivery{}, foreignKeyViolation("webhook_deliveries", "webhook_deliveries_subscription_id_fkey")
+	}
+	payload, err := jsonb("webhook_deliveries", "payload", arg.Payload)
+	if err != nil {
+		return postgres.WebhookDelivery{}, err
+	}
+	i := postgres.WebhookDelivery{
+		ID:             data.webhookDeliveries.next(),
+		SubscriptionID: arg.SubscriptionID,
+		EventType:      arg.EventType,
+		Payload:        payload,
+		Status:         postgres.WebhookDeliveryStatusPending,
+		NextAttemptAt:  arg.NextAttemptAt,
+		CreatedAt:      now(),
+	}
+	put(q, &data.webhookDeliveries, i.ID, i)
+	return i, nil
+}
+
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg postgres.CreateWebhookSubscriptionParams) (postgres.WebhookSubscription, error) {
+	defer q.write()()
+	data := q.data
+	i := postgres.WebhookSubscription{
+		ID:         data.webhookSubscriptions.next(),
+		Url:        arg.Url,
+		EventTypes: cloneStrings(arg.EventTypes),
+		Secret:     arg.Secret,
+		Active:     arg.Active,
+		CreatedAt:  now(),
+	}
+	i.UpdatedAt = i.CreatedAt
+	put(q, &data.webhookSubscriptions, i.ID, i)
+	return i, nil
+}
+
+// DeleteWebhookSubscription removes a subscription together with its deliveries
+func (q *Queries) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	defer q.write()()
+	data := q.data
+	for _, d := range data.webhookDeliveries.ids(func(d postgres.WebhookDelivery) bool { return d.SubscriptionID == id }) {
+		remove(q, &data.webhookDeliveries, d)
+	}
+	remove(q, &data.webhookSubscriptions, id)
+	return nil
+}
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, id int64) (postgres.WebhookDelivery, error) {
+	defer q.read()()
+	i, ok := q.data.webhookDeliveries.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) GetWebhookSubscription(ctx context.Context, id int64) (postgres.WebhookSubscription, error) {
+	defer q.read()()
+	i, ok := q.data.webhookSubscriptions.get(id)
+	if !ok {
+		return i, sql.ErrNoRows
+	}
+	return i, nil
+}
+
+func (q *Queries) ListActiveWebhookSubscriptionsByEvent(ctx context.Context, eventType string) ([]postgres.WebhookSubscription, error) {
+	defer q.read()()
+	return q.data.webhookSubscriptions.all(func(i postgres.WebhookSubscription) bool {
+		if !i.Active {
+			return false
+		}
+		for _, t := range i.EventTypes {
+			if t == eventType {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// ClaimDueWebhookDeliveries moves the due deliveries to the end of a lease, so that other workers skip them
+func (q *Queries) ClaimDueWebhookDeliveries(ctx context.Context, arg postgres.ClaimDueWebhookDeliveriesParams) ([]postgres.WebhookDelivery, error) {
+	defer q.write()()
+	data := q.data
+	due := now()
+	items := data.webhookDeliveries.all(func(i postgres.WebhookDelivery) bool {
+		return i.Status == postgres.WebhookDeliveryStatusPending && !i.NextAttemptAt.After(due)
+	})
+	sortRows(items, func(a, b postgres.WebhookDelivery) bool { return a.NextAttemptAt.Before(b.NextAttemptAt) })
+	items = head(items, arg.Limit)
+
+	for n, i := range items {
+		i.NextAttemptAt = arg.NextAttemptAt
+		put(q, &data.webhookDeliveries, i.ID, i)
+		items[n] = i
+	}
+	return items, nil
+}
+
+func (q *Queries) ListWebhookDeliveriesBySubscription(ctx context.Context, subscriptionID int64) ([]postgres.WebhookDelivery, error) {
+	defer q.read()()
+	items := q.data.webhookDeliveries.all(func(i postgres.WebhookDelivery) bool { return i.SubscriptionID == subscriptionID })
+	sortRows(items, func(a, b postgres.WebhookDelivery) bool { return a.CreatedAt.After(b.CreatedAt) })
+	return items, nil
+}
+
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context) ([]postgres.WebhookSubscription, error) {
+	defer q.read()()
+	items := q.data.webhookSubscriptions.all(nil)
+	sortRows(items, func(a, b postgres.WebhookSubscription) bool { return a.CreatedAt.Before(b.CreatedAt) })
+	return items, nil
+}
+
+func (q *Queries) MarkWebhookDeliveryFailed(ctx context.Context, arg postgres.MarkWebhookDeliveryFailedParams) error {
+	defer q.write()()
+	_, err := update(q, &q.data.webhookDeliveries, arg.ID, nil, func(i *postgres.WebhookDelivery) error {
+		i.Status = arg.Status
+		i.Attempts++
+		i.ResponseStatus = arg.ResponseStatus
+		i.ResponseBody = arg.ResponseBody
+		i.LastError = arg.LastError
+		i.NextAttemptAt = arg.NextAttemptAt
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+func (q *Queries) MarkWebhookDeliverySucceeded(ctx context.Context, arg postgres.MarkWebhookDeliverySucceededParams) error {
+	defer q.write()()
+	_, err := update(q, &q.data.webhookDeliveries, arg.ID, nil, func(i *postgres.WebhookDelivery) error {
+		i.Status = postgres.WebhookDeliveryStatusSucceeded
+		i.Attempts++
+		i.ResponseStatus = arg.ResponseStatus
+		i.ResponseBody = arg.ResponseBody
+		i.LastError = sql.NullString{}
+		i.DeliveredAt = nullNow()
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+// RequeueWebhookDelivery schedules a failed delivery to be attempted again right away
+func (q *Queries) RequeueWebhookDelivery(ctx context.Context, id int64) (postgres.WebhookDelivery, error) {
+	defer q.write()()
+	failed := func(i postgres.WebhookDelivery) bool { return i.Status == postgres.WebhookDeliveryStatusFailed }
+	return update(q, &q.data.webhookDeliveries, id, failed, func(i *postgres.WebhookDelivery) error {
+		i.Status = postgres.WebhookDeliveryStatusPending
+		i.Attempts = 0
+		i.ResponseStatus = sql.NullInt32{}
+		i.ResponseBody = sql.NullString{}
+		i.LastError = sql.NullString{}
+		i.NextAttemptAt = now()
+		return nil
+	})
+}
+
+func (q *Queries) UpdateWebhookSubscription(ctx context.Context, arg postgres.UpdateWebhookSubscriptionParams) (postgres.WebhookSubscription, error) {
+	defer q.write()()
+	return update(q, &q.data.webhookSubscriptions, arg.ID, nil, func(i *postgres.WebhookSubscription) error {
+		i.Url = arg.Url
+		i.EventTypes = cloneStrings(arg.EventTypes)
+		i.Active = arg.Active
+		i.UpdatedAt = now()
+		return nil
+	})
+}
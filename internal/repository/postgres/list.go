@@ -0,0 +1,391 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+var userList = listQuery[User]{
+	table:   "users",
+	columns: "id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at",
+	sorts: map[string]sortField[User]{
+		"id":                {"id", "bigint", func(i User) string { return formatID(i.ID) }},
+		"full_name":         {"full_name", "text", func(i User) string { return i.FullName }},
+		"email":             {"email", "text", func(i User) string { return i.Email }},
+		"registration_date": {"registration_date", "timestamp", func(i User) string { return formatTime(i.RegistrationDate) }},
+	},
+	defaultSort: "registration_date",
+	id:          func(i User) int64 { return i.ID },
+	scan: func(row scanner) (i User, err error) {
+		err = row.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.Email,
+			&i.Address,
+			&i.RegistrationDate,
+			&i.Role,
+			&i.PasswordHash,
+			&i.EmailVerifiedAt,
+			&i.Locale,
+			&i.Version,
+			&i.DeletedAt,
+		)
+		return
+	},
+}
+
+type ListUsersPageParams struct {
+	PageParams
+	Name           sql.NullString `json:"name"`
+	Email          sql.NullString `json:"email"`
+	Role           sql.NullString `json:"role"`
+	RegisteredFrom sql.NullTime   `json:"registered_from"`
+	RegisteredTo   sql.NullTime   `json:"registered_to"`
+	IncludeDeleted bool           `json:"include_deleted"`
+}
+
+// ListUsersPage returns one page of users matching every set filter; deleted users are left out unless IncludeDeleted is set
+func (q *Queries) ListUsersPage(ctx context.Context, arg ListUsersPageParams) (Page[User], error) {
+	var f filters
+	if !arg.IncludeDeleted {
+		f.conditions = append(f.conditions, "deleted_at IS NULL")
+	}
+	if arg.Name.Valid {
+		f.add("full_name ILIKE '%%' || %s || '%%'", arg.Name.String)
+	}
+	if arg.Email.Valid {
+		f.add("email = %s", arg.Email.String)
+	}
+	if arg.Role.Valid {
+		f.add("role = %s", arg.Role.String)
+	}
+	if arg.RegisteredFrom.Valid {
+		f.add("registration_date >= %s", arg.RegisteredFrom.Time)
+	}
+	if arg.RegisteredTo.Valid {
+		f.add("registration_date < %s", arg.RegisteredTo.Time)
+	}
+	return userList.list(ctx, q.db, arg.PageParams, f)
+}
+
+var productList = listQuery[Product]{
+	table:   "products",
+	columns: "id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at",
+	sorts: map[string]sortField[Product]{
+		"id":             {"id", "bigint", func(i Product) string { return formatID(i.ID) }},
+		"name":           {"name", "text", func(i Product) string { return i.Name }},
+		"price":          {"price", "numeric", func(i Product) string { return i.Price }},
+		"stock_quantity": {"stock_quantity", "int", func(i Product) string { return strconv.Itoa(int(i.StockQuantity)) }},
+		"addition_date":  {"addition_date", "timestamp", func(i Product) string { return formatTime(i.AdditionDate) }},
+	},
+	defaultSort: "addition_date",
+	id:          func(i Product) int64 { return i.ID },
+	scan: func(row scanner) (i Product, err error) {
+		err = row.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.StockQuantity,
+			&i.AdditionDate,
+			&i.CategoryID,
+			&i.Sku,
+			&i.TaxClass,
+			&i.WeightGrams,
+			&i.Version,
+			&i.DeletedAt,
+		)
+		return
+	},
+}
+
+type ListProductsPageParams struct {
+	PageParams
+	Name           sql.NullString `json:"name"`
+	CategoryID     sql.NullInt64  `json:"category_id"`
+	MinPrice       sql.NullString `json:"min_price"`
+	MaxPrice       sql.NullString `json:"max_price"`
+	InStock        sql.NullBool   `json:"in_stock"`
+	IncludeDeleted bool           `json:"include_deleted"`
+}
+
+// ListProductsPage returns one page of products matching every set filter; deleted products are left out unless IncludeDeleted is set.
+// A category filter also matches products of every descendant category.
+func (q *Queries) ListProductsPage(ctx context.Context, arg ListProductsPageParams) (Page[Product], error) {
+	var f filters
+	if !arg.IncludeDeleted {
+		f.conditions = append(f.conditions, "deleted_at IS NULL")
+	}
+	if arg.Name.Valid {
+		f.add("name ILIKE '%%' || %s || '%%'", arg.Name.String)
+	}
+	if arg.CategoryID.Valid {
+		f.add(`category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT categories.id FROM categories WHERE categories.id = %s
+				UNION ALL
+				SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+			)
+			SELECT tree.id FROM tree
+		)`, arg.CategoryID.Int64)
+	}
+	if arg.MinPrice.Valid {
+		f.add("price >= %s::numeric", arg.MinPrice.String)
+	}
+	if arg.MaxPrice.Valid {
+		f.add("price <= %s::numeric", arg.MaxPrice.String)
+	}
+	if arg.InStock.Valid {
+		if arg.InStock.Bool {
+			f.conditions = append(f.conditions, "stock_quantity > 0")
+		} else {
+			f.conditions = append(f.conditions, "stock_quantity <= 0")
+		}
+	}
+	return productList.list(ctx, q.db, arg.PageParams, f)
+}
+
+var orderList = listQuery[Order]{
+	table:   "orders",
+	columns: "id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at",
+	sorts: map[string]sortField[Order]{
+		"id":           {"id", "bigint", func(i Order) string { return formatID(i.ID) }},
+		"total_amount": {"total_amount", "numeric", func(i Order) string { return i.TotalAmount }},
+		"order_date":   {"order_date", "timestamp", func(i Order) string { return formatTime(i.OrderDate) }},
+	},
+	defaultSort: "order_date",
+	id:          func(i Order) int64 { return i.ID },
+	scan: func(row scanner) (i Order, err error) {
+		err = row.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.TotalAmount,
+			&i.OrderDate,
+			&i.Status,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.PricesIncludeTax,
+			&i.DestinationCountry,
+			&i.ShippingMethodID,
+			&i.ShippingAmount,
+			&i.Version,
+			&i.DeletedAt,
+		)
+		return
+	},
+}
+
+type ListOrdersPageParams struct {
+	PageParams
+	UserID         sql.NullInt64   `json:"user_id"`
+	Status         NullOrderStatus `json:"status"`
+	From           sql.NullTime    `json:"from"`
+	To             sql.NullTime    `json:"to"`
+	IncludeDeleted bool            `json:"include_deleted"`
+}
+
+// ListOrdersPage returns one page of orders matching every set filter; deleted orders are left out unless IncludeDeleted is set
+func (q *Queries) ListOrdersPage(ctx context.Context, arg ListOrdersPageParams) (Page[Order], error) {
+	var f filters
+	if !arg.IncludeDeleted {
+		f.conditions = append(f.conditions, "deleted_at IS NULL")
+	}
+	if arg.UserID.Valid {
+		f.add("user_id = %s", arg.UserID.Int64)
+	}
+	if arg.Status.Valid {
+		f.add("status = %s", arg.Status.OrderStatus)
+	}
+	if arg.From.Valid {
+		f.add("order_date >= %s", arg.From.Time)
+	}
+	if arg.To.Valid {
+		f.add("order_date < %s", arg.To.Time)
+	}
+	return orderList.list(ctx, q.db, arg.PageParams, f)
+}
+
+var paymentList = listQuery[Payment]{
+	table:   "payments",
+	columns: "id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at",
+	sorts: map[string]sortField[Payment]{
+		"id":           {"id", "bigint", func(i Payment) string { return formatID(i.ID) }},
+		"amount":       {"amount", "numeric", func(i Payment) string { return i.Amount }},
+		"payment_date": {"payment_date", "timestamp", func(i Payment) string { return formatTime(i.PaymentDate) }},
+	},
+	defaultSort: "payment_date",
+	id:          func(i Payment) int64 { return i.ID },
+	scan: func(row scanner) (i Payment, err error) {
+		err = row.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrderID,
+			&i.Amount,
+			&i.PaymentDate,
+			&i.Status,
+			&i.InvoiceID,
+			&i.RefundedAmount,
+			&i.Version,
+			&i.DeletedAt,
+		)
+		return
+	},
+}
+
+type ListPaymentsPageParams struct {
+	PageParams
+	UserID         sql.NullInt64     `json:"user_id"`
+	OrderID        sql.NullInt64     `json:"order_id"`
+	Status         NullPaymentStatus `json:"status"`
+	From           sql.NullTime      `json:"from"`
+	To             sql.NullTime      `json:"to"`
+	IncludeDeleted bool              `json:"include_deleted"`
+}
+
+// ListPaymentsPage returns one page of payments matching every set filter; deleted payments are left out unless IncludeDeleted is set
+func (q *Queries) ListPaymentsPage(ctx context.Context, arg ListPaymentsPageParams) (Page[Payment], error) {
+	var f filters
+	if !arg.IncludeDeleted {
+		f.conditions = append(f.conditions, "deleted_at IS NULL")
+	}
+	if arg.UserID.Valid {
+		f.add("user_id = %s", arg.UserID.Int64)
+	}
+	if arg.OrderID.Valid {
+		f.add("order_id = %s", arg.OrderID.Int64)
+	}
+	if arg.Status.Valid {
+		f.add("status = %s", arg.Status.PaymentStatus)
+	}
+	if arg.From.Valid {
+		f.add("payment_date >= %s", arg.From.Time)
+	}
+	if arg.To.Valid {
+		f.add("payment_date < %s", arg.To.Time)
+	}
+	return paymentList.list(ctx, q.db, arg.PageParams, f)
+}
+
+var orderItemList = listQuery[OrderItem]{
+	table:   "order_items",
+	columns: "id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount",
+	sorts: map[string]sortField[OrderItem]{
+		"id":       {"id", "bigint", func(i OrderItem) string { return formatID(i.ID) }},
+		"quantity": {"quantity", "int", func(i OrderItem) string { return strconv.Itoa(int(i.Quantity)) }},
+		"price":    {"price", "numeric", func(i OrderItem) string { return i.Price }},
+	},
+	defaultSort: "id",
+	id:          func(i OrderItem) int64 { return i.ID },
+	scan: func(row scanner) (i OrderItem, err error) {
+		err = row.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.Price,
+			&i.VariantID,
+			&i.TaxRate,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.GrossAmount,
+		)
+		return
+	},
+}
+
+type ListOrderItemsPageParams struct {
+	PageParams
+	OrderID   sql.NullInt64 `json:"order_id"`
+	ProductID sql.NullInt64 `json:"product_id"`
+}
+
+// ListOrderItemsPage returns one page of order items matching every set filter
+func (q *Queries) ListOrderItemsPage(ctx context.Context, arg ListOrderItemsPageParams) (Page[OrderItem], error) {
+	var f filters
+	if arg.OrderID.Valid {
+		f.add("order_id = %s", arg.OrderID.Int64)
+	}
+	if arg.ProductID.Valid {
+		f.add("product_id = %s", arg.ProductID.Int64)
+	}
+	return orderItemList.list(ctx, q.db, arg.PageParams, f)
+}
+
+var auditEventList = listQuery[AuditEvent]{
+	table:   "audit_events",
+	columns: "id, actor_type, actor_id, action, entity_type, entity_id, before, after, request_id, ip, created_at",
+	sorts: map[string]sortField[AuditEvent]{
+		"id":         {"id", "bigint", func(i AuditEvent) string { return formatID(i.ID) }},
+		"created_at": {"created_at", "timestamp", func(i AuditEvent) string { return formatTime(i.CreatedAt) }},
+	},
+	defaultSort: "-created_at",
+	id:          func(i AuditEvent) int64 { return i.ID },
+	scan: func(row scanner) (i AuditEvent, err error) {
+		err = row.Scan(
+			&i.ID,
+			&i.ActorType,
+			&i.ActorID,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Before,
+			&i.After,
+			&i.RequestID,
+			&i.Ip,
+			&i.CreatedAt,
+		)
+		return
+	},
+}
+
+type ListAuditEventsPageParams struct {
+	PageParams
+	ActorType  sql.NullString `json:"actor_type"`
+	ActorID    sql.NullString `json:"actor_id"`
+	Action     sql.NullString `json:"action"`
+	EntityType sql.NullString `json:"entity_type"`
+	EntityID   sql.NullInt64  `json:"entity_id"`
+	RequestID  sql.NullString `json:"request_id"`
+	From       sql.NullTime   `json:"from"`
+	To         sql.NullTime   `json:"to"`
+}
+
+// ListAuditEventsPage returns one page of audit events matching every set filter, newest first by default
+func (q *Queries) ListAuditEventsPage(ctx context.Context, arg ListAuditEventsPageParams) (Page[AuditEvent], error) {
+	var f filters
+	if arg.ActorType.Valid {
+		f.add("actor_type = %s", arg.ActorType.String)
+	}
+	if arg.ActorID.Valid {
+		f.add("actor_id = %s", arg.ActorID.String)
+	}
+	if arg.Action.Valid {
+		f.add("action = %s", arg.Action.String)
+	}
+	if arg.EntityType.Valid {
+		f.add("entity_type = %s", arg.EntityType.String)
+	}
+	if arg.EntityID.Valid {
+		f.add("entity_id = %s", arg.EntityID.Int64)
+	}
+	if arg.RequestID.Valid {
+		f.add("request_id = %s", arg.RequestID.String)
+	}
+	if arg.From.Valid {
+		f.add("created_at >= %s", arg.From.Time)
+	}
+	if arg.To.Valid {
+		f.add("created_at < %s", arg.To.Time)
+	}
+	return auditEventList.list(ctx, q.db, arg.PageParams, f)
+}
@@ -5,16 +5,179 @@
 package postgres
 
 import (
+	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
+type AddressType string
+
+const (
+	AddressTypeShipping AddressType = "shipping"
+	AddressTypeBilling  AddressType = "billing"
+)
+
+func (e *AddressType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AddressType(s)
+	case string:
+		*e = AddressType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AddressType: %T", src)
+	}
+	return nil
+}
+
+type NullAddressType struct {
+	AddressType AddressType `json:"address_type"`
+	Valid       bool        `json:"valid"` // Valid is true if AddressType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAddressType) Scan(value interface{}) error {
+	if value == nil {
+		ns.AddressType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AddressType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAddressType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AddressType), nil
+}
+
+func (e AddressType) Valid() bool {
+	switch e {
+	case AddressTypeShipping,
+		AddressTypeBilling:
+		return true
+	}
+	return false
+}
+
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending   ImportJobStatus = "pending"
+	ImportJobStatusRunning   ImportJobStatus = "running"
+	ImportJobStatusCompleted ImportJobStatus = "completed"
+	ImportJobStatusFailed    ImportJobStatus = "failed"
+)
+
+func (e *ImportJobStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ImportJobStatus(s)
+	case string:
+		*e = ImportJobStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ImportJobStatus: %T", src)
+	}
+	return nil
+}
+
+type NullImportJobStatus struct {
+	ImportJobStatus ImportJobStatus `json:"import_job_status"`
+	Valid           bool            `json:"valid"` // Valid is true if ImportJobStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullImportJobStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ImportJobStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ImportJobStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullImportJobStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ImportJobStatus), nil
+}
+
+func (e ImportJobStatus) Valid() bool {
+	switch e {
+	case ImportJobStatusPending,
+		ImportJobStatusRunning,
+		ImportJobStatusCompleted,
+		ImportJobStatusFailed:
+		return true
+	}
+	return false
+}
+
+type NotificationStatus string
+
+const (
+	NotificationStatusPending NotificationStatus = "pending"
+	NotificationStatusSent    NotificationStatus = "sent"
+	NotificationStatusFailed  NotificationStatus = "failed"
+)
+
+func (e *NotificationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationStatus(s)
+	case string:
+		*e = NotificationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationStatus struct {
+	NotificationStatus NotificationStatus `json:"notification_status"`
+	Valid              bool               `json:"valid"` // Valid is true if NotificationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationStatus), nil
+}
+
+func (e NotificationStatus) Valid() bool {
+	switch e {
+	case NotificationStatusPending,
+		NotificationStatusSent,
+		NotificationStatusFailed:
+		return true
+	}
+	return false
+}
+
 type OrderStatus string
 
 const (
 	OrderStatusNew        OrderStatus = "new"
 	OrderStatusProcessing OrderStatus = "processing"
+	OrderStatusShipped    OrderStatus = "shipped"
+	OrderStatusDelivered  OrderStatus = "delivered"
 	OrderStatusCompleted  OrderStatus = "completed"
 )
 
@@ -53,11 +216,24 @@ func (ns NullOrderStatus) Value() (driver.Value, error) {
 	return string(ns.OrderStatus), nil
 }
 
+func (e OrderStatus) Valid() bool {
+	switch e {
+	case OrderStatusNew,
+		OrderStatusProcessing,
+		OrderStatusShipped,
+		OrderStatusDelivered,
+		OrderStatusCompleted:
+		return true
+	}
+	return false
+}
+
 type PaymentStatus string
 
 const (
 	PaymentStatusSuccessful   PaymentStatus = "successful"
 	PaymentStatusUnsuccessful PaymentStatus = "unsuccessful"
+	PaymentStatusRefunded     PaymentStatus = "refunded"
 )
 
 func (e *PaymentStatus) Scan(src interface{}) error {
@@ -95,46 +271,631 @@ func (ns NullPaymentStatus) Value() (driver.Value, error) {
 	return string(ns.PaymentStatus), nil
 }
 
+func (e PaymentStatus) Valid() bool {
+	switch e {
+	case PaymentStatusSuccessful,
+		PaymentStatusUnsuccessful,
+		PaymentStatusRefunded:
+		return true
+	}
+	return false
+}
+
+type PromotionType string
+
+const (
+	PromotionTypePercentage   PromotionType = "percentage"
+	PromotionTypeFixed        PromotionType = "fixed"
+	PromotionTypeFreeShipping PromotionType = "free_shipping"
+	PromotionTypeBuyXGetY     PromotionType = "buy_x_get_y"
+)
+
+func (e *PromotionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PromotionType(s)
+	case string:
+		*e = PromotionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PromotionType: %T", src)
+	}
+	return nil
+}
+
+type NullPromotionType struct {
+	PromotionType PromotionType `json:"promotion_type"`
+	Valid         bool          `json:"valid"` // Valid is true if PromotionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPromotionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.PromotionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PromotionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPromotionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PromotionType), nil
+}
+
+func (e PromotionType) Valid() bool {
+	switch e {
+	case PromotionTypePercentage,
+		PromotionTypeFixed,
+		PromotionTypeFreeShipping,
+		PromotionTypeBuyXGetY:
+		return true
+	}
+	return false
+}
+
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusRejected  ReturnStatus = "rejected"
+	ReturnStatusReceived  ReturnStatus = "received"
+	ReturnStatusRefunding ReturnStatus = "refunding"
+	ReturnStatusRefunded  ReturnStatus = "refunded"
+)
+
+func (e *ReturnStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReturnStatus(s)
+	case string:
+		*e = ReturnStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReturnStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReturnStatus struct {
+	ReturnStatus ReturnStatus `json:"return_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ReturnStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReturnStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReturnStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReturnStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReturnStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReturnStatus), nil
+}
+
+func (e ReturnStatus) Valid() bool {
+	switch e {
+	case ReturnStatusRequested,
+		ReturnStatusApproved,
+		ReturnStatusRejected,
+		ReturnStatusReceived,
+		ReturnStatusRefunding,
+		ReturnStatusRefunded:
+		return true
+	}
+	return false
+}
+
+type ShipmentStatus string
+
+const (
+	ShipmentStatusPending   ShipmentStatus = "pending"
+	ShipmentStatusShipped   ShipmentStatus = "shipped"
+	ShipmentStatusDelivered ShipmentStatus = "delivered"
+)
+
+func (e *ShipmentStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ShipmentStatus(s)
+	case string:
+		*e = ShipmentStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ShipmentStatus: %T", src)
+	}
+	return nil
+}
+
+type NullShipmentStatus struct {
+	ShipmentStatus ShipmentStatus `json:"shipment_status"`
+	Valid          bool           `json:"valid"` // Valid is true if ShipmentStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullShipmentStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ShipmentStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ShipmentStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullShipmentStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ShipmentStatus), nil
+}
+
+func (e ShipmentStatus) Valid() bool {
+	switch e {
+	case ShipmentStatusPending,
+		ShipmentStatusShipped,
+		ShipmentStatusDelivered:
+		return true
+	}
+	return false
+}
+
+type ShippingRateType string
+
+const (
+	ShippingRateTypeFlat   ShippingRateType = "flat"
+	ShippingRateTypeWeight ShippingRateType = "weight"
+)
+
+func (e *ShippingRateType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ShippingRateType(s)
+	case string:
+		*e = ShippingRateType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ShippingRateType: %T", src)
+	}
+	return nil
+}
+
+type NullShippingRateType struct {
+	ShippingRateType ShippingRateType `json:"shipping_rate_type"`
+	Valid            bool             `json:"valid"` // Valid is true if ShippingRateType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullShippingRateType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ShippingRateType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ShippingRateType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullShippingRateType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ShippingRateType), nil
+}
+
+func (e ShippingRateType) Valid() bool {
+	switch e {
+	case ShippingRateTypeFlat,
+		ShippingRateTypeWeight:
+		return true
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhook_delivery_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
+func (e WebhookDeliveryStatus) Valid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusSucceeded,
+		WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+type ApiKey struct {
+	ID          int64        `json:"id"`
+	UserID      int64        `json:"user_id"`
+	Name        string       `json:"name"`
+	Prefix      string       `json:"prefix"`
+	SecretHash  string       `json:"secret_hash"`
+	Permissions []string     `json:"permissions"`
+	LastUsedAt  sql.NullTime `json:"last_used_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	RevokedAt   sql.NullTime `json:"revoked_at"`
+}
+
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    string          `json:"actor_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int64           `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"request_id"`
+	Ip         string          `json:"ip"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type CartItem struct {
+	ID        int64         `json:"id"`
+	UserID    int64         `json:"user_id"`
+	ProductID int64         `json:"product_id"`
+	VariantID sql.NullInt64 `json:"variant_id"`
+	Quantity  int32         `json:"quantity"`
+	AddedAt   time.Time     `json:"added_at"`
+}
+
+type Category struct {
+	ID        int64         `json:"id"`
+	ParentID  sql.NullInt64 `json:"parent_id"`
+	Name      string        `json:"name"`
+	Slug      string        `json:"slug"`
+	Position  int32         `json:"position"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	ID           int64         `json:"id"`
+	Owner        string        `json:"owner"`
+	Key          string        `json:"key"`
+	Fingerprint  string        `json:"fingerprint"`
+	StatusCode   sql.NullInt32 `json:"status_code"`
+	ContentType  string        `json:"content_type"`
+	ResponseBody []byte        `json:"response_body"`
+	CreatedAt    time.Time     `json:"created_at"`
+	CompletedAt  sql.NullTime  `json:"completed_at"`
+}
+
+type Notification struct {
+	ID            int64              `json:"id"`
+	UserID        int64              `json:"user_id"`
+	OrderID       sql.NullInt64      `json:"order_id"`
+	Event         string             `json:"event"`
+	Channel       string             `json:"channel"`
+	Recipient     string             `json:"recipient"`
+	Locale        string             `json:"locale"`
+	Subject       string             `json:"subject"`
+	BodyText      string             `json:"body_text"`
+	BodyHtml      string             `json:"body_html"`
+	Status        NotificationStatus `json:"status"`
+	Attempts      int32              `json:"attempts"`
+	LastError     sql.NullString     `json:"last_error"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	SentAt        sql.NullTime       `json:"sent_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
 type Order struct {
-	ID          int64       `json:"id"`
-	UserID      int64       `json:"user_id"`
-	TotalAmount string      `json:"total_amount"`
-	OrderDate   time.Time   `json:"order_date"`
-	Status      OrderStatus `json:"status"`
+	ID                 int64         `json:"id"`
+	UserID             int64         `json:"user_id"`
+	TotalAmount        string        `json:"total_amount"`
+	OrderDate          time.Time     `json:"order_date"`
+	Status             OrderStatus   `json:"status"`
+	NetAmount          string        `json:"net_amount"`
+	TaxAmount          string        `json:"tax_amount"`
+	PricesIncludeTax   bool          `json:"prices_include_tax"`
+	DestinationCountry string        `json:"destination_country"`
+	ShippingMethodID   sql.NullInt64 `json:"shipping_method_id"`
+	ShippingAmount     string        `json:"shipping_amount"`
+	Version            int32         `json:"version"`
+	DeletedAt          sql.NullTime  `json:"deleted_at"`
 }
 
-type OrderItem struct {
-	ID        int64  `json:"id"`
-	OrderID   int64  `json:"order_id"`
-	ProductID int64  `json:"product_id"`
-	Quantity  int32  `json:"quantity"`
-	Price     string `json:"price"`
+type OrderAddress struct {
+	ID         int64          `json:"id"`
+	OrderID    int64          `json:"order_id"`
+	Type       AddressType    `json:"type"`
+	FullName   string         `json:"full_name"`
+	Phone      sql.NullString `json:"phone"`
+	Line1      string         `json:"line1"`
+	Line2      sql.NullString `json:"line2"`
+	City       string         `json:"city"`
+	Region     sql.NullString `json:"region"`
+	PostalCode sql.NullString `json:"postal_code"`
+	Country    string         `json:"country"`
+	CreatedAt  time.Time      `json:"created_at"`
 }
 
-type Payment struct {
+type OrderAdjustment struct {
+	ID          int64          `json:"id"`
+	OrderID     int64          `json:"order_id"`
+	PromotionID sql.NullInt64  `json:"promotion_id"`
+	Kind        string         `json:"kind"`
+	Code        sql.NullString `json:"code"`
+	Description string         `json:"description"`
+	Amount      string         `json:"amount"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type OrderItem struct {
 	ID          int64         `json:"id"`
-	UserID      int64         `json:"user_id"`
 	OrderID     int64         `json:"order_id"`
-	Amount      string        `json:"amount"`
-	PaymentDate time.Time     `json:"payment_date"`
-	Status      PaymentStatus `json:"status"`
+	ProductID   int64         `json:"product_id"`
+	Quantity    int32         `json:"quantity"`
+	Price       string        `json:"price"`
+	VariantID   sql.NullInt64 `json:"variant_id"`
+	TaxRate     string        `json:"tax_rate"`
+	NetAmount   string        `json:"net_amount"`
+	TaxAmount   string        `json:"tax_amount"`
+	GrossAmount string        `json:"gross_amount"`
+}
+
+type Payment struct {
+	ID             int64          `json:"id"`
+	UserID         int64          `json:"user_id"`
+	OrderID        int64          `json:"order_id"`
+	Amount         string         `json:"amount"`
+	PaymentDate    time.Time      `json:"payment_date"`
+	Status         PaymentStatus  `json:"status"`
+	InvoiceID      sql.NullString `json:"invoice_id"`
+	RefundedAmount string         `json:"refunded_amount"`
+	Version        int32          `json:"version"`
+	DeletedAt      sql.NullTime   `json:"deleted_at"`
 }
 
 type Product struct {
-	ID            int64     `json:"id"`
-	Name          string    `json:"name"`
-	Description   string    `json:"description"`
-	Price         string    `json:"price"`
-	Category      string    `json:"category"`
-	StockQuantity int32     `json:"stock_quantity"`
-	AdditionDate  time.Time `json:"addition_date"`
+	ID            int64        `json:"id"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Price         string       `json:"price"`
+	StockQuantity int32        `json:"stock_quantity"`
+	AdditionDate  time.Time    `json:"addition_date"`
+	CategoryID    int64        `json:"category_id"`
+	Sku           string       `json:"sku"`
+	TaxClass      string       `json:"tax_class"`
+	WeightGrams   int32        `json:"weight_grams"`
+	Version       int32        `json:"version"`
+	DeletedAt     sql.NullTime `json:"deleted_at"`
+}
+
+type ProductImage struct {
+	ID          int64           `json:"id"`
+	ProductID   int64           `json:"product_id"`
+	StorageKey  string          `json:"storage_key"`
+	Url         string          `json:"url"`
+	Thumbnails  json.RawMessage `json:"thumbnails"`
+	ContentType string          `json:"content_type"`
+	Width       int32           `json:"width"`
+	Height      int32           `json:"height"`
+	SizeBytes   int64           `json:"size_bytes"`
+	Position    int32           `json:"position"`
+	IsPrimary   bool            `json:"is_primary"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type ProductImportJob struct {
+	ID          int64           `json:"id"`
+	Format      string          `json:"format"`
+	Status      ImportJobStatus `json:"status"`
+	DryRun      bool            `json:"dry_run"`
+	TotalRows   int32           `json:"total_rows"`
+	CreatedRows int32           `json:"created_rows"`
+	UpdatedRows int32           `json:"updated_rows"`
+	FailedRows  int32           `json:"failed_rows"`
+	Errors      json.RawMessage `json:"errors"`
+	LastError   sql.NullString  `json:"last_error"`
+	CreatedAt   time.Time       `json:"created_at"`
+	StartedAt   sql.NullTime    `json:"started_at"`
+	FinishedAt  sql.NullTime    `json:"finished_at"`
+}
+
+type ProductVariant struct {
+	ID            int64           `json:"id"`
+	ProductID     int64           `json:"product_id"`
+	Sku           string          `json:"sku"`
+	Attributes    json.RawMessage `json:"attributes"`
+	Price         sql.NullString  `json:"price"`
+	StockQuantity int32           `json:"stock_quantity"`
+	Position      int32           `json:"position"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type Promotion struct {
+	ID             int64          `json:"id"`
+	Name           string         `json:"name"`
+	Code           sql.NullString `json:"code"`
+	Type           PromotionType  `json:"type"`
+	Value          string         `json:"value"`
+	BuyQuantity    int32          `json:"buy_quantity"`
+	GetQuantity    int32          `json:"get_quantity"`
+	ProductID      sql.NullInt64  `json:"product_id"`
+	CategoryID     sql.NullInt64  `json:"category_id"`
+	MinOrderAmount sql.NullString `json:"min_order_amount"`
+	StartsAt       sql.NullTime   `json:"starts_at"`
+	EndsAt         sql.NullTime   `json:"ends_at"`
+	UsageLimit     sql.NullInt32  `json:"usage_limit"`
+	PerUserLimit   sql.NullInt32  `json:"per_user_limit"`
+	TimesUsed      int32          `json:"times_used"`
+	Active         bool           `json:"active"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+type PromotionRedemption struct {
+	ID          int64     `json:"id"`
+	PromotionID int64     `json:"promotion_id"`
+	OrderID     int64     `json:"order_id"`
+	UserID      int64     `json:"user_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Return struct {
+	ID           int64         `json:"id"`
+	OrderID      int64         `json:"order_id"`
+	Status       ReturnStatus  `json:"status"`
+	Reason       string        `json:"reason"`
+	Note         string        `json:"note"`
+	RefundAmount string        `json:"refund_amount"`
+	PaymentID    sql.NullInt64 `json:"payment_id"`
+	ReceivedAt   sql.NullTime  `json:"received_at"`
+	RefundedAt   sql.NullTime  `json:"refunded_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+type ReturnItem struct {
+	ID          int64  `json:"id"`
+	ReturnID    int64  `json:"return_id"`
+	OrderItemID int64  `json:"order_item_id"`
+	Quantity    int32  `json:"quantity"`
+	Amount      string `json:"amount"`
+}
+
+type Shipment struct {
+	ID               int64          `json:"id"`
+	OrderID          int64          `json:"order_id"`
+	ShippingMethodID sql.NullInt64  `json:"shipping_method_id"`
+	Carrier          string         `json:"carrier"`
+	TrackingNumber   sql.NullString `json:"tracking_number"`
+	Status           ShipmentStatus `json:"status"`
+	ShippedAt        sql.NullTime   `json:"shipped_at"`
+	DeliveredAt      sql.NullTime   `json:"delivered_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+type ShippingMethod struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	Carrier   string           `json:"carrier"`
+	RateType  ShippingRateType `json:"rate_type"`
+	Price     string           `json:"price"`
+	FreeOver  sql.NullString   `json:"free_over"`
+	Active    bool             `json:"active"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+type ShippingRate struct {
+	ID             int64  `json:"id"`
+	MethodID       int64  `json:"method_id"`
+	MaxWeightGrams int32  `json:"max_weight_grams"`
+	Price          string `json:"price"`
+}
+
+type TaxRate struct {
+	ID        int64     `json:"id"`
+	TaxClass  string    `json:"tax_class"`
+	Country   string    `json:"country"`
+	Rate      string    `json:"rate"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type User struct {
-	ID               int64     `json:"id"`
-	FullName         string    `json:"full_name"`
-	Email            string    `json:"email"`
-	Address          string    `json:"address"`
-	RegistrationDate time.Time `json:"registration_date"`
-	Role             string    `json:"role"`
+	ID               int64        `json:"id"`
+	FullName         string       `json:"full_name"`
+	Email            string       `json:"email"`
+	Address          string       `json:"address"`
+	RegistrationDate time.Time    `json:"registration_date"`
+	Role             string       `json:"role"`
+	PasswordHash     string       `json:"-"`
+	EmailVerifiedAt  sql.NullTime `json:"email_verified_at"`
+	Locale           string       `json:"locale"`
+	Version          int32        `json:"version"`
+	DeletedAt        sql.NullTime `json:"deleted_at"`
+}
+
+type UserAddress struct {
+	ID         int64          `json:"id"`
+	UserID     int64          `json:"user_id"`
+	Type       AddressType    `json:"type"`
+	FullName   string         `json:"full_name"`
+	Phone      sql.NullString `json:"phone"`
+	Line1      string         `json:"line1"`
+	Line2      sql.NullString `json:"line2"`
+	City       string         `json:"city"`
+	Region     sql.NullString `json:"region"`
+	PostalCode sql.NullString `json:"postal_code"`
+	Country    string         `json:"country"`
+	IsDefault  bool           `json:"is_default"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	SubscriptionID int64                 `json:"subscription_id"`
+	EventType      string                `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int32                 `json:"attempts"`
+	ResponseStatus sql.NullInt32         `json:"response_status"`
+	ResponseBody   sql.NullString        `json:"response_body"`
+	LastError      sql.NullString        `json:"last_error"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	DeliveredAt    sql.NullTime          `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"-"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -7,21 +7,31 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createOrder = `-- name: CreateOrder :one
-INSERT INTO orders (user_id, total_amount, order_date) 
-VALUES ($1, $2, NOW()) 
-RETURNING id, user_id, total_amount, order_date, status
+INSERT INTO orders (user_id, total_amount, prices_include_tax, destination_country, shipping_method_id, order_date) 
+VALUES ($1, $2, $3, $4, $5, NOW()) 
+RETURNING id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at
 `
 
 type CreateOrderParams struct {
-	UserID      int64  `json:"user_id"`
-	TotalAmount string `json:"total_amount"`
+	UserID             int64         `json:"user_id"`
+	TotalAmount        string        `json:"total_amount"`
+	PricesIncludeTax   bool          `json:"prices_include_tax"`
+	DestinationCountry string        `json:"destination_country"`
+	ShippingMethodID   sql.NullInt64 `json:"shipping_method_id"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
-	row := q.db.QueryRowContext(ctx, createOrder, arg.UserID, arg.TotalAmount)
+	row := q.db.QueryRowContext(ctx, createOrder,
+		arg.UserID,
+		arg.TotalAmount,
+		arg.PricesIncludeTax,
+		arg.DestinationCountry,
+		arg.ShippingMethodID,
+	)
 	var i Order
 	err := row.Scan(
 		&i.ID,
@@ -29,21 +39,45 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.TotalAmount,
 		&i.OrderDate,
 		&i.Status,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.PricesIncludeTax,
+		&i.DestinationCountry,
+		&i.ShippingMethodID,
+		&i.ShippingAmount,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteOrder = `-- name: DeleteOrder :exec
-DELETE FROM orders WHERE id = $1
+const deleteOrder = `-- name: DeleteOrder :one
+UPDATE orders SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at
 `
 
-func (q *Queries) DeleteOrder(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteOrder, id)
-	return err
+func (q *Queries) DeleteOrder(ctx context.Context, id int64) (Order, error) {
+	row := q.db.QueryRowContext(ctx, deleteOrder, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TotalAmount,
+		&i.OrderDate,
+		&i.Status,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.PricesIncludeTax,
+		&i.DestinationCountry,
+		&i.ShippingMethodID,
+		&i.ShippingAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
 const getOrder = `-- name: GetOrder :one
-SELECT id, user_id, total_amount, order_date, status FROM orders WHERE id = $1 LIMIT 1
+SELECT id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at FROM orders WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetOrder(ctx context.Context, id int64) (Order, error) {
@@ -55,12 +89,45 @@ func (q *Queries) GetOrder(ctx context.Context, id int64) (Order, error) {
 		&i.TotalAmount,
 		&i.OrderDate,
 		&i.Status,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.PricesIncludeTax,
+		&i.DestinationCountry,
+		&i.ShippingMethodID,
+		&i.ShippingAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at FROM orders WHERE id = $1 LIMIT 1 FOR UPDATE
+`
+
+func (q *Queries) GetOrderForUpdate(ctx context.Context, id int64) (Order, error) {
+	row := q.db.QueryRowContext(ctx, getOrderForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TotalAmount,
+		&i.OrderDate,
+		&i.Status,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.PricesIncludeTax,
+		&i.DestinationCountry,
+		&i.ShippingMethodID,
+		&i.ShippingAmount,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const listOrders = `-- name: ListOrders :many
-SELECT id, user_id, total_amount, order_date, status FROM orders ORDER BY order_date ASC
+SELECT id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at FROM orders WHERE deleted_at IS NULL ORDER BY order_date ASC
 `
 
 func (q *Queries) ListOrders(ctx context.Context) ([]Order, error) {
@@ -78,6 +145,14 @@ func (q *Queries) ListOrders(ctx context.Context) ([]Order, error) {
 			&i.TotalAmount,
 			&i.OrderDate,
 			&i.Status,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.PricesIncludeTax,
+			&i.DestinationCountry,
+			&i.ShippingMethodID,
+			&i.ShippingAmount,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -92,8 +167,55 @@ func (q *Queries) ListOrders(ctx context.Context) ([]Order, error) {
 	return items, nil
 }
 
+const purgeDeletedOrders = `-- name: PurgeDeletedOrders :execrows
+-- Orders with payments are kept until those are purged; items and notifications go with the order
+WITH expired AS (
+    SELECT o.id FROM orders o
+    WHERE o.deleted_at < $1
+        AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.order_id = o.id)
+), items_deleted AS (
+    DELETE FROM order_items WHERE order_id IN (SELECT id FROM expired)
+), notifications_deleted AS (
+    DELETE FROM notifications WHERE order_id IN (SELECT id FROM expired)
+)
+DELETE FROM orders WHERE id IN (SELECT id FROM expired)
+`
+
+func (q *Queries) PurgeDeletedOrders(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedOrders, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreOrder = `-- name: RestoreOrder :one
+UPDATE orders SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at
+`
+
+func (q *Queries) RestoreOrder(ctx context.Context, id int64) (Order, error) {
+	row := q.db.QueryRowContext(ctx, restoreOrder, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TotalAmount,
+		&i.OrderDate,
+		&i.Status,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.PricesIncludeTax,
+		&i.DestinationCountry,
+		&i.ShippingMethodID,
+		&i.ShippingAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const searchOrdersByStatus = `-- name: SearchOrdersByStatus :many
-SELECT id, user_id, total_amount, order_date, status FROM orders WHERE status = $1 ORDER BY order_date ASC
+SELECT id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at FROM orders WHERE status = $1 AND deleted_at IS NULL ORDER BY order_date ASC
 `
 
 func (q *Queries) SearchOrdersByStatus(ctx context.Context, status OrderStatus) ([]Order, error) {
@@ -111,6 +233,14 @@ func (q *Queries) SearchOrdersByStatus(ctx context.Context, status OrderStatus)
 			&i.TotalAmount,
 			&i.OrderDate,
 			&i.Status,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.PricesIncludeTax,
+			&i.DestinationCountry,
+			&i.ShippingMethodID,
+			&i.ShippingAmount,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -126,7 +256,7 @@ func (q *Queries) SearchOrdersByStatus(ctx context.Context, status OrderStatus)
 }
 
 const searchOrdersByUser = `-- name: SearchOrdersByUser :many
-SELECT id, user_id, total_amount, order_date, status FROM orders WHERE user_id = $1 ORDER BY order_date ASC
+SELECT id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at FROM orders WHERE user_id = $1 AND deleted_at IS NULL ORDER BY order_date ASC
 `
 
 func (q *Queries) SearchOrdersByUser(ctx context.Context, userID int64) ([]Order, error) {
@@ -144,6 +274,14 @@ func (q *Queries) SearchOrdersByUser(ctx context.Context, userID int64) ([]Order
 			&i.TotalAmount,
 			&i.OrderDate,
 			&i.Status,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.PricesIncludeTax,
+			&i.DestinationCountry,
+			&i.ShippingMethodID,
+			&i.ShippingAmount,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -158,13 +296,88 @@ func (q *Queries) SearchOrdersByUser(ctx context.Context, userID int64) ([]Order
 	return items, nil
 }
 
+const setOrderStatus = `-- name: SetOrderStatus :one
+UPDATE orders SET status = $2 WHERE id = $1 RETURNING id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at
+`
+
+type SetOrderStatusParams struct {
+	ID     int64       `json:"id"`
+	Status OrderStatus `json:"status"`
+}
+
+func (q *Queries) SetOrderStatus(ctx context.Context, arg SetOrderStatusParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, setOrderStatus, arg.ID, arg.Status)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TotalAmount,
+		&i.OrderDate,
+		&i.Status,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.PricesIncludeTax,
+		&i.DestinationCountry,
+		&i.ShippingMethodID,
+		&i.ShippingAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const setOrderTotals = `-- name: SetOrderTotals :one
+UPDATE orders SET
+    net_amount = $2,
+    tax_amount = $3,
+    shipping_amount = $4,
+    total_amount = $5
+WHERE id = $1
+RETURNING id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at
+`
+
+type SetOrderTotalsParams struct {
+	ID             int64  `json:"id"`
+	NetAmount      string `json:"net_amount"`
+	TaxAmount      string `json:"tax_amount"`
+	ShippingAmount string `json:"shipping_amount"`
+	TotalAmount    string `json:"total_amount"`
+}
+
+func (q *Queries) SetOrderTotals(ctx context.Context, arg SetOrderTotalsParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, setOrderTotals,
+		arg.ID,
+		arg.NetAmount,
+		arg.TaxAmount,
+		arg.ShippingAmount,
+		arg.TotalAmount,
+	)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TotalAmount,
+		&i.OrderDate,
+		&i.Status,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.PricesIncludeTax,
+		&i.DestinationCountry,
+		&i.ShippingMethodID,
+		&i.ShippingAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const updateOrder = `-- name: UpdateOrder :one
 UPDATE orders SET 
     user_id = $2,
     total_amount = $3,
     status = $4
-WHERE id = $1 
-RETURNING id, user_id, total_amount, order_date, status
+WHERE id = $1 AND version = $5
+RETURNING id, user_id, total_amount, order_date, status, net_amount, tax_amount, prices_include_tax, destination_country, shipping_method_id, shipping_amount, version, deleted_at
 `
 
 type UpdateOrderParams struct {
@@ -172,6 +385,7 @@ type UpdateOrderParams struct {
 	UserID      int64       `json:"user_id"`
 	TotalAmount string      `json:"total_amount"`
 	Status      OrderStatus `json:"status"`
+	Version     int32       `json:"version"`
 }
 
 func (q *Queries) UpdateOrder(ctx context.Context, arg UpdateOrderParams) (Order, error) {
@@ -180,6 +394,7 @@ func (q *Queries) UpdateOrder(ctx context.Context, arg UpdateOrderParams) (Order
 		arg.UserID,
 		arg.TotalAmount,
 		arg.Status,
+		arg.Version,
 	)
 	var i Order
 	err := row.Scan(
@@ -188,6 +403,14 @@ func (q *Queries) UpdateOrder(ctx context.Context, arg UpdateOrderParams) (Order
 		&i.TotalAmount,
 		&i.OrderDate,
 		&i.Status,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.PricesIncludeTax,
+		&i.DestinationCountry,
+		&i.ShippingMethodID,
+		&i.ShippingAmount,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -7,19 +7,27 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createOrderItem = `-- name: CreateOrderItem :one
-INSERT INTO order_items (order_id, product_id, quantity, price) 
-VALUES ($1, $2, $3, $4) 
-RETURNING id, order_id, product_id, quantity, price
+INSERT INTO order_items (order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+RETURNING id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount
 `
 
 type CreateOrderItemParams struct {
-	OrderID   int64  `json:"order_id"`
-	ProductID int64  `json:"product_id"`
-	Quantity  int32  `json:"quantity"`
-	Price     string `json:"price"`
+	OrderID     int64         `json:"order_id"`
+	ProductID   int64         `json:"product_id"`
+	Quantity    int32         `json:"quantity"`
+	Price       string        `json:"price"`
+	VariantID   sql.NullInt64 `json:"variant_id"`
+	TaxRate     string        `json:"tax_rate"`
+	NetAmount   string        `json:"net_amount"`
+	TaxAmount   string        `json:"tax_amount"`
+	GrossAmount string        `json:"gross_amount"`
 }
 
 func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error) {
@@ -28,6 +36,11 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		arg.ProductID,
 		arg.Quantity,
 		arg.Price,
+		arg.VariantID,
+		arg.TaxRate,
+		arg.NetAmount,
+		arg.TaxAmount,
+		arg.GrossAmount,
 	)
 	var i OrderItem
 	err := row.Scan(
@@ -36,6 +49,11 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		&i.ProductID,
 		&i.Quantity,
 		&i.Price,
+		&i.VariantID,
+		&i.TaxRate,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.GrossAmount,
 	)
 	return i, err
 }
@@ -50,7 +68,7 @@ func (q *Queries) DeleteOrderItem(ctx context.Context, id int64) error {
 }
 
 const getOrderItem = `-- name: GetOrderItem :one
-SELECT id, order_id, product_id, quantity, price FROM order_items WHERE id = $1 LIMIT 1
+SELECT id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount FROM order_items WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetOrderItem(ctx context.Context, id int64) (OrderItem, error) {
@@ -62,12 +80,17 @@ func (q *Queries) GetOrderItem(ctx context.Context, id int64) (OrderItem, error)
 		&i.ProductID,
 		&i.Quantity,
 		&i.Price,
+		&i.VariantID,
+		&i.TaxRate,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.GrossAmount,
 	)
 	return i, err
 }
 
 const listOrderItems = `-- name: ListOrderItems :many
-SELECT id, order_id, product_id, quantity, price FROM order_items ORDER BY id ASC
+SELECT id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount FROM order_items ORDER BY id ASC
 `
 
 func (q *Queries) ListOrderItems(ctx context.Context) ([]OrderItem, error) {
@@ -85,6 +108,11 @@ func (q *Queries) ListOrderItems(ctx context.Context) ([]OrderItem, error) {
 			&i.ProductID,
 			&i.Quantity,
 			&i.Price,
+			&i.VariantID,
+			&i.TaxRate,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.GrossAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -100,7 +128,7 @@ func (q *Queries) ListOrderItems(ctx context.Context) ([]OrderItem, error) {
 }
 
 const listOrderItemsByOrder = `-- name: ListOrderItemsByOrder :many
-SELECT id, order_id, product_id, quantity, price FROM order_items WHERE order_id = $1 ORDER BY id ASC
+SELECT id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount FROM order_items WHERE order_id = $1 ORDER BY id ASC
 `
 
 func (q *Queries) ListOrderItemsByOrder(ctx context.Context, orderID int64) ([]OrderItem, error) {
@@ -118,6 +146,49 @@ func (q *Queries) ListOrderItemsByOrder(ctx context.Context, orderID int64) ([]O
 			&i.ProductID,
 			&i.Quantity,
 			&i.Price,
+			&i.VariantID,
+			&i.TaxRate,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.GrossAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderItemsByOrders = `-- name: ListOrderItemsByOrders :many
+SELECT id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount FROM order_items WHERE order_id = ANY($1::bigint[]) ORDER BY order_id ASC, id ASC
+`
+
+func (q *Queries) ListOrderItemsByOrders(ctx context.Context, orderIds []int64) ([]OrderItem, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderItemsByOrders, pq.Array(orderIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderItem{}
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.Price,
+			&i.VariantID,
+			&i.TaxRate,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.GrossAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -133,7 +204,7 @@ func (q *Queries) ListOrderItemsByOrder(ctx context.Context, orderID int64) ([]O
 }
 
 const listOrderItemsByProduct = `-- name: ListOrderItemsByProduct :many
-SELECT id, order_id, product_id, quantity, price FROM order_items WHERE product_id = $1 ORDER BY id ASC
+SELECT id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount FROM order_items WHERE product_id = $1 ORDER BY id ASC
 `
 
 func (q *Queries) ListOrderItemsByProduct(ctx context.Context, productID int64) ([]OrderItem, error) {
@@ -151,6 +222,105 @@ func (q *Queries) ListOrderItemsByProduct(ctx context.Context, productID int64)
 			&i.ProductID,
 			&i.Quantity,
 			&i.Price,
+			&i.VariantID,
+			&i.TaxRate,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.GrossAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setOrderItemAmounts = `-- name: SetOrderItemAmounts :one
+UPDATE order_items SET
+    quantity = $2,
+    price = $3,
+    tax_rate = $4,
+    net_amount = $5,
+    tax_amount = $6,
+    gross_amount = $7
+WHERE id = $1
+RETURNING id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount
+`
+
+type SetOrderItemAmountsParams struct {
+	ID          int64  `json:"id"`
+	Quantity    int32  `json:"quantity"`
+	Price       string `json:"price"`
+	TaxRate     string `json:"tax_rate"`
+	NetAmount   string `json:"net_amount"`
+	TaxAmount   string `json:"tax_amount"`
+	GrossAmount string `json:"gross_amount"`
+}
+
+func (q *Queries) SetOrderItemAmounts(ctx context.Context, arg SetOrderItemAmountsParams) (OrderItem, error) {
+	row := q.db.QueryRowContext(ctx, setOrderItemAmounts,
+		arg.ID,
+		arg.Quantity,
+		arg.Price,
+		arg.TaxRate,
+		arg.NetAmount,
+		arg.TaxAmount,
+		arg.GrossAmount,
+	)
+	var i OrderItem
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.ProductID,
+		&i.Quantity,
+		&i.Price,
+		&i.VariantID,
+		&i.TaxRate,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.GrossAmount,
+	)
+	return i, err
+}
+
+const summarizeOrderItemTaxes = `-- name: SummarizeOrderItemTaxes :many
+SELECT tax_rate,
+    sum(net_amount)::numeric AS net_amount,
+    sum(tax_amount)::numeric AS tax_amount,
+    sum(gross_amount)::numeric AS gross_amount
+FROM order_items
+WHERE order_id = $1
+GROUP BY tax_rate
+ORDER BY tax_rate DESC
+`
+
+type SummarizeOrderItemTaxesRow struct {
+	TaxRate     string `json:"tax_rate"`
+	NetAmount   string `json:"net_amount"`
+	TaxAmount   string `json:"tax_amount"`
+	GrossAmount string `json:"gross_amount"`
+}
+
+func (q *Queries) SummarizeOrderItemTaxes(ctx context.Context, orderID int64) ([]SummarizeOrderItemTaxesRow, error) {
+	rows, err := q.db.QueryContext(ctx, summarizeOrderItemTaxes, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SummarizeOrderItemTaxesRow{}
+	for rows.Next() {
+		var i SummarizeOrderItemTaxesRow
+		if err := rows.Scan(
+			&i.TaxRate,
+			&i.NetAmount,
+			&i.TaxAmount,
+			&i.GrossAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -172,7 +342,7 @@ UPDATE order_items SET
     quantity = $4,
     price = $5
 WHERE id = $1 
-RETURNING id, order_id, product_id, quantity, price
+RETURNING id, order_id, product_id, quantity, price, variant_id, tax_rate, net_amount, tax_amount, gross_amount
 `
 
 type UpdateOrderItemParams struct {
@@ -198,6 +368,11 @@ func (q *Queries) UpdateOrderItem(ctx context.Context, arg UpdateOrderItemParams
 		&i.ProductID,
 		&i.Quantity,
 		&i.Price,
+		&i.VariantID,
+		&i.TaxRate,
+		&i.NetAmount,
+		&i.TaxAmount,
+		&i.GrossAmount,
 	)
 	return i, err
 }
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort field")
+)
+
+// PageParams selects one keyset page of a list.
+// Sort names a whitelisted field, prefixed with "-" for descending order; Cursor is the NextCursor of the previous page.
+type PageParams struct {
+	Limit  int32  `json:"limit"`
+	Cursor string `json:"cursor"`
+	Sort   string `json:"sort"`
+}
+
+// Page is a single page of a list together with the cursor of the following page and the number of matching rows
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
+}
+
+// cursor is the position after the last row of a page; it is bound to the sort it was issued for
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (c cursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	return
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// sortField is a column a list may be ordered by; cast is the SQL type the cursor value is compared as
+type sortField[T any] struct {
+	column string
+	cast   string
+	value  func(T) string
+}
+
+// listQuery describes how to page through a table with whitelisted sorts
+type listQuery[T any] struct {
+	table       string
+	columns     string
+	sorts       map[string]sortField[T]
+	defaultSort string
+	id          func(T) int64
+	scan        func(scanner) (T, error)
+}
+
+// filters accumulates WHERE conditions; each condition has a single %s placeholder for its argument
+type filters struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (f *filters) add(condition string, arg interface{}) {
+	f.args = append(f.args, arg)
+	f.conditions = append(f.conditions, fmt.Sprintf(condition, fmt.Sprintf("$%d", len(f.args))))
+}
+
+func (f *filters) where() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(f.conditions, " AND ")
+}
+
+func (l listQuery[T]) list(ctx context.Context, db DBTX, arg PageParams, f filters) (dst Page[T], err error) {
+	sort := arg.Sort
+	if sort == "" {
+		sort = l.defaultSort
+	}
+	field, ok := l.sorts[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return dst, fmt.Errorf("%w: %s", ErrInvalidSort, strings.TrimPrefix(sort, "-"))
+	}
+	desc := strings.HasPrefix(sort, "-")
+
+	limit := arg.Limit
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	// The total ignores the cursor so that every page reports the same number
+	if err = db.QueryRowContext(ctx, "SELECT count(*) FROM "+l.table+f.where(), f.args...).Scan(&dst.Total); err != nil {
+		return
+	}
+
+	page := filters{
+		conditions: append([]string{}, f.conditions...),
+		args:       append([]interface{}{}, f.args...),
+	}
+	if arg.Cursor != "" {
+		c, err := decodeCursor(arg.Cursor)
+		if err != nil {
+			return dst, err
+		}
+		if c.Sort != sort {
+			return dst, fmt.Errorf("%w: issued for sort %q", ErrInvalidCursor, c.Sort)
+		}
+
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		page.args = append(page.args, c.Value, c.ID)
+		page.conditions = append(page.conditions, fmt.Sprintf("(%s, id) %s ($%d::%s, $%d)",
+			field.column, op, len(page.args)-1, field.cast, len(page.args)))
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	page.args = append(page.args, limit+1)
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s %s, id %s LIMIT $%d",
+		l.columns, l.table, page.where(), field.column, direction, direction, len(page.args))
+
+	rows, err := db.QueryContext(ctx, query, page.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	dst.Items = []T{}
+	for rows.Next() {
+		i, err := l.scan(rows)
+		if err != nil {
+			return dst, err
+		}
+		dst.Items = append(dst.Items, i)
+	}
+	if err = rows.Close(); err != nil {
+		return
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	// One extra row was requested to learn whether another page follows
+	if len(dst.Items) > int(limit) {
+		dst.Items = dst.Items[:limit]
+		last := dst.Items[limit-1]
+		dst.NextCursor = encodeCursor(cursor{
+			Sort:  sort,
+			Value: field.value(last),
+			ID:    l.id(last),
+		})
+	}
+
+	return
+}
@@ -7,19 +7,67 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
 )
 
+const addPaymentRefund = `-- name: AddPaymentRefund :one
+-- A payment becomes refunded once its whole amount has been returned
+UPDATE payments SET
+    refunded_amount = refunded_amount + $1::numeric,
+    status = CASE WHEN refunded_amount + $1::numeric >= amount THEN 'refunded'::payment_status ELSE status END
+WHERE id = $2
+RETURNING id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at
+`
+
+type AddPaymentRefundParams struct {
+	Amount string `json:"amount"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) AddPaymentRefund(ctx context.Context, arg AddPaymentRefundParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, addPaymentRefund, arg.Amount, arg.ID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrderID,
+		&i.Amount,
+		&i.PaymentDate,
+		&i.Status,
+		&i.InvoiceID,
+		&i.RefundedAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const countPaidPayments = `-- name: CountPaidPayments :one
+-- Payments that took money from the customer, including ones refunded since
+SELECT count(*) FROM payments WHERE order_id = $1 AND status <> 'unsuccessful'
+`
+
+func (q *Queries) CountPaidPayments(ctx context.Context, orderID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPaidPayments, orderID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPayment = `-- name: CreatePayment :one
-INSERT INTO payments (user_id, order_id, amount, payment_date, status) 
-VALUES ($1, $2, $3, NOW(), $4) 
-RETURNING id, user_id, order_id, amount, payment_date, status
+INSERT INTO payments (user_id, order_id, amount, payment_date, status, invoice_id) 
+VALUES ($1, $2, $3, NOW(), $4, $5) 
+RETURNING id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at
 `
 
 type CreatePaymentParams struct {
-	UserID  int64         `json:"user_id"`
-	OrderID int64         `json:"order_id"`
-	Amount  string        `json:"amount"`
-	Status  PaymentStatus `json:"status"`
+	UserID    int64          `json:"user_id"`
+	OrderID   int64          `json:"order_id"`
+	Amount    string         `json:"amount"`
+	Status    PaymentStatus  `json:"status"`
+	InvoiceID sql.NullString `json:"invoice_id"`
 }
 
 func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
@@ -28,6 +76,7 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		arg.OrderID,
 		arg.Amount,
 		arg.Status,
+		arg.InvoiceID,
 	)
 	var i Payment
 	err := row.Scan(
@@ -37,21 +86,38 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		&i.Amount,
 		&i.PaymentDate,
 		&i.Status,
+		&i.InvoiceID,
+		&i.RefundedAmount,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deletePayment = `-- name: DeletePayment :exec
-DELETE FROM payments WHERE id = $1
+const deletePayment = `-- name: DeletePayment :one
+UPDATE payments SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at
 `
 
-func (q *Queries) DeletePayment(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deletePayment, id)
-	return err
+func (q *Queries) DeletePayment(ctx context.Context, id int64) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, deletePayment, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrderID,
+		&i.Amount,
+		&i.PaymentDate,
+		&i.Status,
+		&i.InvoiceID,
+		&i.RefundedAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
 const getPayment = `-- name: GetPayment :one
-SELECT id, user_id, order_id, amount, payment_date, status FROM payments WHERE id = $1 LIMIT 1
+SELECT id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at FROM payments WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetPayment(ctx context.Context, id int64) (Payment, error) {
@@ -64,12 +130,42 @@ func (q *Queries) GetPayment(ctx context.Context, id int64) (Payment, error) {
 		&i.Amount,
 		&i.PaymentDate,
 		&i.Status,
+		&i.InvoiceID,
+		&i.RefundedAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getRefundablePayment = `-- name: GetRefundablePayment :one
+-- The latest successful ePay payment of an order that has not been refunded in full
+SELECT id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at FROM payments
+WHERE order_id = $1 AND status = 'successful' AND invoice_id IS NOT NULL AND refunded_amount < amount
+ORDER BY payment_date DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetRefundablePayment(ctx context.Context, orderID int64) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getRefundablePayment, orderID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrderID,
+		&i.Amount,
+		&i.PaymentDate,
+		&i.Status,
+		&i.InvoiceID,
+		&i.RefundedAmount,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const listPayments = `-- name: ListPayments :many
-SELECT id, user_id, order_id, amount, payment_date, status FROM payments ORDER BY payment_date ASC
+SELECT id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at FROM payments WHERE deleted_at IS NULL ORDER BY payment_date ASC
 `
 
 func (q *Queries) ListPayments(ctx context.Context) ([]Payment, error) {
@@ -88,6 +184,10 @@ func (q *Queries) ListPayments(ctx context.Context) ([]Payment, error) {
 			&i.Amount,
 			&i.PaymentDate,
 			&i.Status,
+			&i.InvoiceID,
+			&i.RefundedAmount,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -102,8 +202,80 @@ func (q *Queries) ListPayments(ctx context.Context) ([]Payment, error) {
 	return items, nil
 }
 
+const listPaymentsByOrders = `-- name: ListPaymentsByOrders :many
+SELECT id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at FROM payments WHERE order_id = ANY($1::bigint[]) AND deleted_at IS NULL ORDER BY order_id ASC, payment_date ASC
+`
+
+func (q *Queries) ListPaymentsByOrders(ctx context.Context, orderIds []int64) ([]Payment, error) {
+	rows, err := q.db.QueryContext(ctx, listPaymentsByOrders, pq.Array(orderIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payment{}
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrderID,
+			&i.Amount,
+			&i.PaymentDate,
+			&i.Status,
+			&i.InvoiceID,
+			&i.RefundedAmount,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedPayments = `-- name: PurgeDeletedPayments :execrows
+DELETE FROM payments WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedPayments(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedPayments, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restorePayment = `-- name: RestorePayment :one
+UPDATE payments SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at
+`
+
+func (q *Queries) RestorePayment(ctx context.Context, id int64) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, restorePayment, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrderID,
+		&i.Amount,
+		&i.PaymentDate,
+		&i.Status,
+		&i.InvoiceID,
+		&i.RefundedAmount,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const searchPaymentsByOrder = `-- name: SearchPaymentsByOrder :many
-SELECT id, user_id, order_id, amount, payment_date, status FROM payments WHERE order_id = $1 ORDER BY payment_date ASC
+SELECT id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at FROM payments WHERE order_id = $1 AND deleted_at IS NULL ORDER BY payment_date ASC
 `
 
 func (q *Queries) SearchPaymentsByOrder(ctx context.Context, orderID int64) ([]Payment, error) {
@@ -122,6 +294,10 @@ func (q *Queries) SearchPaymentsByOrder(ctx context.Context, orderID int64) ([]P
 			&i.Amount,
 			&i.PaymentDate,
 			&i.Status,
+			&i.InvoiceID,
+			&i.RefundedAmount,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -137,7 +313,7 @@ func (q *Queries) SearchPaymentsByOrder(ctx context.Context, orderID int64) ([]P
 }
 
 const searchPaymentsByStatus = `-- name: SearchPaymentsByStatus :many
-SELECT id, user_id, order_id, amount, payment_date, status FROM payments WHERE status = $1 ORDER BY payment_date ASC
+SELECT id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at FROM payments WHERE status = $1 AND deleted_at IS NULL ORDER BY payment_date ASC
 `
 
 func (q *Queries) SearchPaymentsByStatus(ctx context.Context, status PaymentStatus) ([]Payment, error) {
@@ -156,6 +332,10 @@ func (q *Queries) SearchPaymentsByStatus(ctx context.Context, status PaymentStat
 			&i.Amount,
 			&i.PaymentDate,
 			&i.Status,
+			&i.InvoiceID,
+			&i.RefundedAmount,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -171,7 +351,7 @@ func (q *Queries) SearchPaymentsByStatus(ctx context.Context, status PaymentStat
 }
 
 const searchPaymentsByUser = `-- name: SearchPaymentsByUser :many
-SELECT id, user_id, order_id, amount, payment_date, status FROM payments WHERE user_id = $1 ORDER BY payment_date ASC
+SELECT id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at FROM payments WHERE user_id = $1 AND deleted_at IS NULL ORDER BY payment_date ASC
 `
 
 func (q *Queries) SearchPaymentsByUser(ctx context.Context, userID int64) ([]Payment, error) {
@@ -190,6 +370,10 @@ func (q *Queries) SearchPaymentsByUser(ctx context.Context, userID int64) ([]Pay
 			&i.Amount,
 			&i.PaymentDate,
 			&i.Status,
+			&i.InvoiceID,
+			&i.RefundedAmount,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -210,8 +394,8 @@ UPDATE payments SET
     order_id = $3,
     amount = $4,
     status = $5
-WHERE id = $1 
-RETURNING id, user_id, order_id, amount, payment_date, status
+WHERE id = $1 AND version = $6
+RETURNING id, user_id, order_id, amount, payment_date, status, invoice_id, refunded_amount, version, deleted_at
 `
 
 type UpdatePaymentParams struct {
@@ -220,6 +404,7 @@ type UpdatePaymentParams struct {
 	OrderID int64         `json:"order_id"`
 	Amount  string        `json:"amount"`
 	Status  PaymentStatus `json:"status"`
+	Version int32         `json:"version"`
 }
 
 func (q *Queries) UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (Payment, error) {
@@ -229,6 +414,7 @@ func (q *Queries) UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (P
 		arg.OrderID,
 		arg.Amount,
 		arg.Status,
+		arg.Version,
 	)
 	var i Payment
 	err := row.Scan(
@@ -238,6 +424,10 @@ func (q *Queries) UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (P
 		&i.Amount,
 		&i.PaymentDate,
 		&i.Status,
+		&i.InvoiceID,
+		&i.RefundedAmount,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -8,29 +8,38 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 const createProduct = `-- name: CreateProduct :one
-INSERT INTO products (name, description, price, category, stock_quantity, addition_date) 
-VALUES ($1, $2, $3, $4, $5, NOW()) 
-RETURNING id, name, description, price, category, stock_quantity, addition_date
+INSERT INTO products (sku, name, description, price, category_id, stock_quantity, tax_class, weight_grams, addition_date) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW()) 
+RETURNING id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at
 `
 
 type CreateProductParams struct {
+	Sku           string `json:"sku"`
 	Name          string `json:"name"`
 	Description   string `json:"description"`
 	Price         string `json:"price"`
-	Category      string `json:"category"`
+	CategoryID    int64  `json:"category_id"`
 	StockQuantity int32  `json:"stock_quantity"`
+	TaxClass      string `json:"tax_class"`
+	WeightGrams   int32  `json:"weight_grams"`
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
 	row := q.db.QueryRowContext(ctx, createProduct,
+		arg.Sku,
 		arg.Name,
 		arg.Description,
 		arg.Price,
-		arg.Category,
+		arg.CategoryID,
 		arg.StockQuantity,
+		arg.TaxClass,
+		arg.WeightGrams,
 	)
 	var i Product
 	err := row.Scan(
@@ -38,24 +47,44 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.Name,
 		&i.Description,
 		&i.Price,
-		&i.Category,
 		&i.StockQuantity,
 		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteProduct = `-- name: DeleteProduct :exec
-DELETE FROM products WHERE id = $1
+const deleteProduct = `-- name: DeleteProduct :one
+UPDATE products SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at
 `
 
-func (q *Queries) DeleteProduct(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteProduct, id)
-	return err
+func (q *Queries) DeleteProduct(ctx context.Context, id int64) (Product, error) {
+	row := q.db.QueryRowContext(ctx, deleteProduct, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
 const getProduct = `-- name: GetProduct :one
-SELECT id, name, description, price, category, stock_quantity, addition_date FROM products WHERE id = $1 LIMIT 1
+SELECT id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at FROM products WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetProduct(ctx context.Context, id int64) (Product, error) {
@@ -66,15 +95,44 @@ func (q *Queries) GetProduct(ctx context.Context, id int64) (Product, error) {
 		&i.Name,
 		&i.Description,
 		&i.Price,
-		&i.Category,
 		&i.StockQuantity,
 		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getProductBySku = `-- name: GetProductBySku :one
+SELECT id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at FROM products WHERE sku = $1 LIMIT 1
+`
+
+func (q *Queries) GetProductBySku(ctx context.Context, sku string) (Product, error) {
+	row := q.db.QueryRowContext(ctx, getProductBySku, sku)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const listProducts = `-- name: ListProducts :many
-SELECT id, name, description, price, category, stock_quantity, addition_date FROM products ORDER BY addition_date ASC
+SELECT id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at FROM products WHERE deleted_at IS NULL ORDER BY addition_date ASC
 `
 
 func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
@@ -91,9 +149,487 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 			&i.Name,
 			&i.Description,
 			&i.Price,
+			&i.StockQuantity,
+			&i.AdditionDate,
+			&i.CategoryID,
+			&i.Sku,
+			&i.TaxClass,
+			&i.WeightGrams,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByIDs = `-- name: ListProductsByIDs :many
+-- Deleted products are included so that the orders they are on can still show them
+SELECT id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at FROM products WHERE id = ANY($1::bigint[]) ORDER BY id ASC
+`
+
+func (q *Queries) ListProductsByIDs(ctx context.Context, ids []int64) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Product{}
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.StockQuantity,
+			&i.AdditionDate,
+			&i.CategoryID,
+			&i.Sku,
+			&i.TaxClass,
+			&i.WeightGrams,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsForExport = `-- name: ListProductsForExport :many
+SELECT p.id, p.name, p.description, p.price, p.stock_quantity, p.addition_date, p.category_id, p.sku, p.tax_class, p.weight_grams, p.version, p.deleted_at, c.slug AS category
+FROM products p
+JOIN categories c ON c.id = p.category_id
+WHERE p.id > $1::bigint AND p.deleted_at IS NULL
+ORDER BY p.id ASC
+LIMIT $2::int
+`
+
+type ListProductsForExportRow struct {
+	ID            int64        `json:"id"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Price         string       `json:"price"`
+	StockQuantity int32        `json:"stock_quantity"`
+	AdditionDate  time.Time    `json:"addition_date"`
+	CategoryID    int64        `json:"category_id"`
+	Sku           string       `json:"sku"`
+	TaxClass      string       `json:"tax_class"`
+	WeightGrams   int32        `json:"weight_grams"`
+	Version       int32        `json:"version"`
+	DeletedAt     sql.NullTime `json:"deleted_at"`
+	Category      string       `json:"category"`
+}
+
+type ListProductsForExportParams struct {
+	AfterID   int64 `json:"after_id"`
+	PageLimit int32 `json:"page_limit"`
+}
+
+func (q *Queries) ListProductsForExport(ctx context.Context, arg ListProductsForExportParams) ([]ListProductsForExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsForExport, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListProductsForExportRow{}
+	for rows.Next() {
+		var i ListProductsForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.StockQuantity,
+			&i.AdditionDate,
+			&i.CategoryID,
+			&i.Sku,
+			&i.TaxClass,
+			&i.WeightGrams,
+			&i.Version,
+			&i.DeletedAt,
+			&i.Category,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPurgeableProductImages = `-- name: ListPurgeableProductImages :many
+SELECT i.id, i.product_id, i.storage_key, i.url, i.thumbnails, i.content_type, i.width, i.height, i.size_bytes, i.position, i.is_primary, i.created_at FROM product_images i
+JOIN products p ON p.id = i.product_id
+WHERE p.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = p.id)
+`
+
+func (q *Queries) ListPurgeableProductImages(ctx context.Context, deletedAt sql.NullTime) ([]ProductImage, error) {
+	rows, err := q.db.QueryContext(ctx, listPurgeableProductImages, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProductImage{}
+	for rows.Next() {
+		var i ProductImage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.StorageKey,
+			&i.Url,
+			&i.Thumbnails,
+			&i.ContentType,
+			&i.Width,
+			&i.Height,
+			&i.SizeBytes,
+			&i.Position,
+			&i.IsPrimary,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedProducts = `-- name: PurgeDeletedProducts :execrows
+-- Products on orders are kept for the invoices; variants, images and promotions are removed by the cascade
+DELETE FROM products p
+WHERE p.deleted_at < $1
+    AND NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = p.id)
+`
+
+func (q *Queries) PurgeDeletedProducts(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedProducts, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reserveProductStock = `-- name: ReserveProductStock :one
+UPDATE products
+SET stock_quantity = stock_quantity - $1::int
+WHERE id = $2 AND stock_quantity >= $1::int
+RETURNING id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at
+`
+
+type ReserveProductStockParams struct {
+	Quantity int32 `json:"quantity"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) ReserveProductStock(ctx context.Context, arg ReserveProductStockParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, reserveProductStock, arg.Quantity, arg.ID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const restockProduct = `-- name: RestockProduct :one
+UPDATE products
+SET stock_quantity = stock_quantity + $1::int
+WHERE id = $2
+RETURNING id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at
+`
+
+type RestockProductParams struct {
+	Quantity int32 `json:"quantity"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) RestockProduct(ctx context.Context, arg RestockProductParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, restockProduct, arg.Quantity, arg.ID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const restoreProduct = `-- name: RestoreProduct :one
+UPDATE products SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at
+`
+
+func (q *Queries) RestoreProduct(ctx context.Context, id int64) (Product, error) {
+	row := q.db.QueryRowContext(ctx, restoreProduct, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const searchProductCategoryFacets = `-- name: SearchProductCategoryFacets :many
+SELECT p.category_id, c.name AS category, count(*)::bigint AS count
+FROM products p
+JOIN categories c ON c.id = p.category_id
+WHERE (
+        setweight(to_tsvector('russian', p.name), 'A') || setweight(to_tsvector('russian', p.description), 'B') ||
+        setweight(to_tsvector('english', p.name), 'A') || setweight(to_tsvector('english', p.description), 'B')
+        @@ (websearch_to_tsquery('russian', $1::text) || websearch_to_tsquery('english', $1::text))
+        OR p.name % $1::text
+    )
+    AND p.deleted_at IS NULL
+    AND ($2::numeric IS NULL OR p.price >= $2::numeric)
+    AND ($3::numeric IS NULL OR p.price <= $3::numeric)
+    AND (NOT $4::boolean OR p.stock_quantity > 0)
+GROUP BY p.category_id, c.name
+ORDER BY count DESC, c.name ASC
+`
+
+type SearchProductCategoryFacetsRow struct {
+	CategoryID int64  `json:"category_id"`
+	Category   string `json:"category"`
+	Count      int64  `json:"count"`
+}
+
+type SearchProductCategoryFacetsParams struct {
+	Query    string         `json:"query"`
+	MinPrice sql.NullString `json:"min_price"`
+	MaxPrice sql.NullString `json:"max_price"`
+	InStock  bool           `json:"in_stock"`
+}
+
+func (q *Queries) SearchProductCategoryFacets(ctx context.Context, arg SearchProductCategoryFacetsParams) ([]SearchProductCategoryFacetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchProductCategoryFacets,
+		arg.Query,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchProductCategoryFacetsRow{}
+	for rows.Next() {
+		var i SearchProductCategoryFacetsRow
+		if err := rows.Scan(
+			&i.CategoryID,
 			&i.Category,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchProductPriceFacets = `-- name: SearchProductPriceFacets :many
+SELECT width_bucket(p.price, $1::numeric[])::int AS bucket, count(*)::bigint AS count
+FROM products p
+WHERE (
+        setweight(to_tsvector('russian', p.name), 'A') || setweight(to_tsvector('russian', p.description), 'B') ||
+        setweight(to_tsvector('english', p.name), 'A') || setweight(to_tsvector('english', p.description), 'B')
+        @@ (websearch_to_tsquery('russian', $2::text) || websearch_to_tsquery('english', $2::text))
+        OR p.name % $2::text
+    )
+    AND p.deleted_at IS NULL
+    AND ($3::bigint[] IS NULL OR p.category_id = ANY($3::bigint[]))
+    AND (NOT $4::boolean OR p.stock_quantity > 0)
+GROUP BY bucket
+ORDER BY bucket ASC
+`
+
+type SearchProductPriceFacetsRow struct {
+	Bucket int32 `json:"bucket"`
+	Count  int64 `json:"count"`
+}
+
+type SearchProductPriceFacetsParams struct {
+	Bounds      []string `json:"bounds"`
+	Query       string   `json:"query"`
+	CategoryIds []int64  `json:"category_ids"`
+	InStock     bool     `json:"in_stock"`
+}
+
+func (q *Queries) SearchProductPriceFacets(ctx context.Context, arg SearchProductPriceFacetsParams) ([]SearchProductPriceFacetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchProductPriceFacets,
+		pq.Array(arg.Bounds),
+		arg.Query,
+		pq.Array(arg.CategoryIds),
+		arg.InStock,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchProductPriceFacetsRow{}
+	for rows.Next() {
+		var i SearchProductPriceFacetsRow
+		if err := rows.Scan(
+			&i.Bucket,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchProducts = `-- name: SearchProducts :many
+SELECT p.id, p.name, p.description, p.price, p.stock_quantity, p.addition_date, p.category_id, p.sku, p.tax_class, p.weight_grams, p.version, p.deleted_at,
+    (ts_rank(
+        setweight(to_tsvector('russian', p.name), 'A') || setweight(to_tsvector('russian', p.description), 'B') ||
+        setweight(to_tsvector('english', p.name), 'A') || setweight(to_tsvector('english', p.description), 'B'),
+        websearch_to_tsquery('russian', $1::text) || websearch_to_tsquery('english', $1::text)
+    ) + similarity(p.name, $1::text))::real AS rank,
+    count(*) OVER ()::bigint AS total
+FROM products p
+WHERE (
+        setweight(to_tsvector('russian', p.name), 'A') || setweight(to_tsvector('russian', p.description), 'B') ||
+        setweight(to_tsvector('english', p.name), 'A') || setweight(to_tsvector('english', p.description), 'B')
+        @@ (websearch_to_tsquery('russian', $1::text) || websearch_to_tsquery('english', $1::text))
+        OR p.name % $1::text
+    )
+    AND p.deleted_at IS NULL
+    AND ($2::bigint[] IS NULL OR p.category_id = ANY($2::bigint[]))
+    AND ($3::numeric IS NULL OR p.price >= $3::numeric)
+    AND ($4::numeric IS NULL OR p.price <= $4::numeric)
+    AND (NOT $5::boolean OR p.stock_quantity > 0)
+ORDER BY rank DESC, p.id ASC
+LIMIT $6::int OFFSET $7::int
+`
+
+type SearchProductsRow struct {
+	ID            int64        `json:"id"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Price         string       `json:"price"`
+	StockQuantity int32        `json:"stock_quantity"`
+	AdditionDate  time.Time    `json:"addition_date"`
+	CategoryID    int64        `json:"category_id"`
+	Sku           string       `json:"sku"`
+	TaxClass      string       `json:"tax_class"`
+	WeightGrams   int32        `json:"weight_grams"`
+	Version       int32        `json:"version"`
+	DeletedAt     sql.NullTime `json:"deleted_at"`
+	Rank          float32      `json:"rank"`
+	Total         int64        `json:"total"`
+}
+
+type SearchProductsParams struct {
+	Query       string         `json:"query"`
+	CategoryIds []int64        `json:"category_ids"`
+	MinPrice    sql.NullString `json:"min_price"`
+	MaxPrice    sql.NullString `json:"max_price"`
+	InStock     bool           `json:"in_stock"`
+	PageLimit   int32          `json:"page_limit"`
+	PageOffset  int32          `json:"page_offset"`
+}
+
+func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchProducts,
+		arg.Query,
+		pq.Array(arg.CategoryIds),
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchProductsRow{}
+	for rows.Next() {
+		var i SearchProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
 			&i.StockQuantity,
 			&i.AdditionDate,
+			&i.CategoryID,
+			&i.Sku,
+			&i.TaxClass,
+			&i.WeightGrams,
+			&i.Version,
+			&i.DeletedAt,
+			&i.Rank,
+			&i.Total,
 		); err != nil {
 			return nil, err
 		}
@@ -109,11 +645,14 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 }
 
 const searchProductsByCategory = `-- name: SearchProductsByCategory :many
-SELECT id, name, description, price, category, stock_quantity, addition_date FROM products WHERE category = $1 ORDER BY addition_date ASC
+SELECT p.id, p.name, p.description, p.price, p.stock_quantity, p.addition_date, p.category_id, p.sku, p.tax_class, p.weight_grams, p.version, p.deleted_at FROM products p 
+JOIN categories c ON c.id = p.category_id 
+WHERE c.name = $1 AND p.deleted_at IS NULL
+ORDER BY p.addition_date ASC
 `
 
-func (q *Queries) SearchProductsByCategory(ctx context.Context, category string) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, searchProductsByCategory, category)
+func (q *Queries) SearchProductsByCategory(ctx context.Context, name string) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, searchProductsByCategory, name)
 	if err != nil {
 		return nil, err
 	}
@@ -126,9 +665,14 @@ func (q *Queries) SearchProductsByCategory(ctx context.Context, category string)
 			&i.Name,
 			&i.Description,
 			&i.Price,
-			&i.Category,
 			&i.StockQuantity,
 			&i.AdditionDate,
+			&i.CategoryID,
+			&i.Sku,
+			&i.TaxClass,
+			&i.WeightGrams,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -144,7 +688,7 @@ func (q *Queries) SearchProductsByCategory(ctx context.Context, category string)
 }
 
 const searchProductsByName = `-- name: SearchProductsByName :many
-SELECT id, name, description, price, category, stock_quantity, addition_date FROM products WHERE name ILIKE '%' || $1 || '%' ORDER BY addition_date ASC
+SELECT id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at FROM products WHERE name ILIKE '%' || $1 || '%' AND deleted_at IS NULL ORDER BY addition_date ASC
 `
 
 func (q *Queries) SearchProductsByName(ctx context.Context, dollar_1 sql.NullString) ([]Product, error) {
@@ -161,9 +705,14 @@ func (q *Queries) SearchProductsByName(ctx context.Context, dollar_1 sql.NullStr
 			&i.Name,
 			&i.Description,
 			&i.Price,
-			&i.Category,
 			&i.StockQuantity,
 			&i.AdditionDate,
+			&i.CategoryID,
+			&i.Sku,
+			&i.TaxClass,
+			&i.WeightGrams,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -180,32 +729,43 @@ func (q *Queries) SearchProductsByName(ctx context.Context, dollar_1 sql.NullStr
 
 const updateProduct = `-- name: UpdateProduct :one
 UPDATE products SET 
-    name = $2,
-    description = $3,
-    price = $4,
-    category = $5,
-    stock_quantity = $6
-WHERE id = $1 
-RETURNING id, name, description, price, category, stock_quantity, addition_date
+    sku = $2,
+    name = $3,
+    description = $4,
+    price = $5,
+    category_id = $6,
+    stock_quantity = $7,
+    tax_class = $8,
+    weight_grams = $9
+WHERE id = $1 AND version = $10
+RETURNING id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at
 `
 
 type UpdateProductParams struct {
 	ID            int64  `json:"id"`
+	Sku           string `json:"sku"`
 	Name          string `json:"name"`
 	Description   string `json:"description"`
 	Price         string `json:"price"`
-	Category      string `json:"category"`
+	CategoryID    int64  `json:"category_id"`
 	StockQuantity int32  `json:"stock_quantity"`
+	TaxClass      string `json:"tax_class"`
+	WeightGrams   int32  `json:"weight_grams"`
+	Version       int32  `json:"version"`
 }
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
 	row := q.db.QueryRowContext(ctx, updateProduct,
 		arg.ID,
+		arg.Sku,
 		arg.Name,
 		arg.Description,
 		arg.Price,
-		arg.Category,
+		arg.CategoryID,
 		arg.StockQuantity,
+		arg.TaxClass,
+		arg.WeightGrams,
+		arg.Version,
 	)
 	var i Product
 	err := row.Scan(
@@ -213,9 +773,14 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.Name,
 		&i.Description,
 		&i.Price,
-		&i.Category,
 		&i.StockQuantity,
 		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -224,7 +789,7 @@ const updateProductStock = `-- name: UpdateProductStock :one
 UPDATE products
 SET stock_quantity = stock_quantity - $1
 WHERE id = $2
-RETURNING id, name, description, price, category, stock_quantity, addition_date
+RETURNING id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at
 `
 
 type UpdateProductStockParams struct {
@@ -240,9 +805,100 @@ func (q *Queries) UpdateProductStock(ctx context.Context, arg UpdateProductStock
 		&i.Name,
 		&i.Description,
 		&i.Price,
-		&i.Category,
 		&i.StockQuantity,
 		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const upsertProductBySku = `-- name: UpsertProductBySku :one
+-- Fields passed as NULL keep their value on an existing product and take the column default on a new one.
+-- A deleted product stays deleted unless restore is set.
+INSERT INTO products (sku, name, description, price, category_id, stock_quantity, tax_class, weight_grams, addition_date)
+VALUES (
+    $1,
+    $2::varchar,
+    coalesce($3::text, ''),
+    $4::numeric,
+    $5::bigint,
+    coalesce($6::int, 0),
+    coalesce($7::varchar, 'standard'),
+    coalesce($8::int, 0),
+    NOW()
+)
+ON CONFLICT (sku) DO UPDATE SET
+    name = coalesce($2::varchar, products.name),
+    description = coalesce($3::text, products.description),
+    price = coalesce($4::numeric, products.price),
+    category_id = coalesce($5::bigint, products.category_id),
+    stock_quantity = coalesce($6::int, products.stock_quantity),
+    tax_class = coalesce($7::varchar, products.tax_class),
+    weight_grams = coalesce($8::int, products.weight_grams),
+    deleted_at = CASE WHEN $9::boolean THEN NULL ELSE products.deleted_at END
+RETURNING id, name, description, price, stock_quantity, addition_date, category_id, sku, tax_class, weight_grams, version, deleted_at, (xmax = 0)::boolean AS inserted
+`
+
+type UpsertProductBySkuRow struct {
+	ID            int64        `json:"id"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Price         string       `json:"price"`
+	StockQuantity int32        `json:"stock_quantity"`
+	AdditionDate  time.Time    `json:"addition_date"`
+	CategoryID    int64        `json:"category_id"`
+	Sku           string       `json:"sku"`
+	TaxClass      string       `json:"tax_class"`
+	WeightGrams   int32        `json:"weight_grams"`
+	Version       int32        `json:"version"`
+	DeletedAt     sql.NullTime `json:"deleted_at"`
+	Inserted      bool         `json:"inserted"`
+}
+
+type UpsertProductBySkuParams struct {
+	Sku           string         `json:"sku"`
+	Name          sql.NullString `json:"name"`
+	Description   sql.NullString `json:"description"`
+	Price         sql.NullString `json:"price"`
+	CategoryID    sql.NullInt64  `json:"category_id"`
+	StockQuantity sql.NullInt32  `json:"stock_quantity"`
+	TaxClass      sql.NullString `json:"tax_class"`
+	WeightGrams   sql.NullInt32  `json:"weight_grams"`
+	Restore       bool           `json:"restore"`
+}
+
+func (q *Queries) UpsertProductBySku(ctx context.Context, arg UpsertProductBySkuParams) (UpsertProductBySkuRow, error) {
+	row := q.db.QueryRowContext(ctx, upsertProductBySku,
+		arg.Sku,
+		arg.Name,
+		arg.Description,
+		arg.Price,
+		arg.CategoryID,
+		arg.StockQuantity,
+		arg.TaxClass,
+		arg.WeightGrams,
+		arg.Restore,
+	)
+	var i UpsertProductBySkuRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.AdditionDate,
+		&i.CategoryID,
+		&i.Sku,
+		&i.TaxClass,
+		&i.WeightGrams,
+		&i.Version,
+		&i.DeletedAt,
+		&i.Inserted,
 	)
 	return i, err
 }
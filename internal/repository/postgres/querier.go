@@ -7,46 +7,206 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddCartItem(ctx context.Context, arg AddCartItemParams) (CartItem, error)
+	AddPaymentRefund(ctx context.Context, arg AddPaymentRefundParams) (Payment, error)
+	ClaimReturnRefund(ctx context.Context, arg ClaimReturnRefundParams) (Return, error)
+	ClaimDueNotifications(ctx context.Context, arg ClaimDueNotificationsParams) ([]Notification, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error)
+	ClearCart(ctx context.Context, userID int64) error
+	ClearDefaultUserAddress(ctx context.Context, arg ClearDefaultUserAddressParams) error
+	ClearPrimaryProductImage(ctx context.Context, productID int64) error
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	CompleteReturnRefund(ctx context.Context, arg CompleteReturnRefundParams) (Return, error)
+	CountPaidPayments(ctx context.Context, orderID int64) (int64, error)
+	CountProductImagesByProduct(ctx context.Context, productID int64) (int64, error)
+	CountProductVariantsByProduct(ctx context.Context, productID int64) (int64, error)
+	CountProductsByCategory(ctx context.Context, categoryID int64) (int64, error)
+	CountPromotionRedemptionsByUser(ctx context.Context, arg CountPromotionRedemptionsByUserParams) (int64, error)
+	CountUndeliveredShipments(ctx context.Context, orderID int64) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreateOrderAddress(ctx context.Context, arg CreateOrderAddressParams) (OrderAddress, error)
+	CreateOrderAdjustment(ctx context.Context, arg CreateOrderAdjustmentParams) (OrderAdjustment, error)
 	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
 	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
+	CreateProductImage(ctx context.Context, arg CreateProductImageParams) (ProductImage, error)
+	CreateProductImportJob(ctx context.Context, arg CreateProductImportJobParams) (ProductImportJob, error)
+	CreateProductVariant(ctx context.Context, arg CreateProductVariantParams) (ProductVariant, error)
+	CreatePromotion(ctx context.Context, arg CreatePromotionParams) (Promotion, error)
+	CreatePromotionRedemption(ctx context.Context, arg CreatePromotionRedemptionParams) error
+	CreateReturn(ctx context.Context, arg CreateReturnParams) (Return, error)
+	CreateReturnItem(ctx context.Context, arg CreateReturnItemParams) (ReturnItem, error)
+	CreateShipment(ctx context.Context, arg CreateShipmentParams) (Shipment, error)
+	CreateShippingMethod(ctx context.Context, arg CreateShippingMethodParams) (ShippingMethod, error)
+	CreateShippingRate(ctx context.Context, arg CreateShippingRateParams) (ShippingRate, error)
+	CreateTaxRate(ctx context.Context, arg CreateTaxRateParams) (TaxRate, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteOrder(ctx context.Context, id int64) error
+	CreateUserAddress(ctx context.Context, arg CreateUserAddressParams) (UserAddress, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DeleteCartItem(ctx context.Context, arg DeleteCartItemParams) (int64, error)
+	DeleteCategory(ctx context.Context, id int64) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, createdAt time.Time) (int64, error)
+	DeleteIdempotencyKey(ctx context.Context, id int64) error
+	DeleteOrder(ctx context.Context, id int64) (Order, error)
+	DeleteOrderAdjustmentsByOrder(ctx context.Context, orderID int64) error
 	DeleteOrderItem(ctx context.Context, id int64) error
-	DeletePayment(ctx context.Context, id int64) error
-	DeleteProduct(ctx context.Context, id int64) error
-	DeleteUser(ctx context.Context, id int64) error
+	DeletePayment(ctx context.Context, id int64) (Payment, error)
+	DeleteProduct(ctx context.Context, id int64) (Product, error)
+	DeleteProductImage(ctx context.Context, arg DeleteProductImageParams) error
+	DeleteProductVariant(ctx context.Context, arg DeleteProductVariantParams) error
+	DeletePromotion(ctx context.Context, id int64) error
+	DeletePromotionRedemptionsByOrder(ctx context.Context, orderID int64) error
+	DeleteReturn(ctx context.Context, id int64) (Return, error)
+	DeleteShipment(ctx context.Context, id int64) error
+	DeleteShippingMethod(ctx context.Context, id int64) error
+	DeleteShippingRatesByMethod(ctx context.Context, methodID int64) error
+	DeleteTaxRate(ctx context.Context, id int64) error
+	DeleteUser(ctx context.Context, id int64) (User, error)
+	DeleteUserAddress(ctx context.Context, arg DeleteUserAddressParams) (UserAddress, error)
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	EnsureDefaultUserAddress(ctx context.Context, arg EnsureDefaultUserAddressParams) error
+	FinishProductImportJob(ctx context.Context, arg FinishProductImportJobParams) (ProductImportJob, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetAuditEvent(ctx context.Context, id int64) (AuditEvent, error)
+	GetCategory(ctx context.Context, id int64) (Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (Category, error)
+	GetDefaultUserAddress(ctx context.Context, arg GetDefaultUserAddressParams) (UserAddress, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetNotification(ctx context.Context, id int64) (Notification, error)
 	GetOrder(ctx context.Context, id int64) (Order, error)
+	GetOrderForUpdate(ctx context.Context, id int64) (Order, error)
 	GetOrderItem(ctx context.Context, id int64) (OrderItem, error)
 	GetPayment(ctx context.Context, id int64) (Payment, error)
 	GetProduct(ctx context.Context, id int64) (Product, error)
+	GetProductBySku(ctx context.Context, sku string) (Product, error)
+	GetProductImage(ctx context.Context, arg GetProductImageParams) (ProductImage, error)
+	GetProductImportJob(ctx context.Context, id int64) (ProductImportJob, error)
+	GetProductVariant(ctx context.Context, id int64) (ProductVariant, error)
+	GetProductVariantBySku(ctx context.Context, sku string) (ProductVariant, error)
+	GetPromotion(ctx context.Context, id int64) (Promotion, error)
+	GetPromotionByCode(ctx context.Context, code sql.NullString) (Promotion, error)
+	GetRefundablePayment(ctx context.Context, orderID int64) (Payment, error)
+	GetReturn(ctx context.Context, id int64) (Return, error)
+	GetReturnForUpdate(ctx context.Context, id int64) (Return, error)
+	GetShipment(ctx context.Context, id int64) (Shipment, error)
+	GetShippingMethod(ctx context.Context, id int64) (ShippingMethod, error)
+	GetShippingRateForWeight(ctx context.Context, arg GetShippingRateForWeightParams) (ShippingRate, error)
+	GetTaxRate(ctx context.Context, id int64) (TaxRate, error)
+	GetTaxRateByClass(ctx context.Context, arg GetTaxRateByClassParams) (TaxRate, error)
 	GetUser(ctx context.Context, id int64) (User, error)
+	GetUserAddress(ctx context.Context, arg GetUserAddressParams) (UserAddress, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	ListAPIKeysByUser(ctx context.Context, userID int64) ([]ApiKey, error)
+	ListActiveAutomaticPromotions(ctx context.Context, now time.Time) ([]Promotion, error)
+	ListActiveWebhookSubscriptionsByEvent(ctx context.Context, eventType string) ([]WebhookSubscription, error)
+	ListCartItems(ctx context.Context, userID int64) ([]CartItem, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+	ListCategoryChildren(ctx context.Context, parentID sql.NullInt64) ([]Category, error)
+	ListCategoryDescendantIDs(ctx context.Context, id int64) ([]int64, error)
+	ListNotificationsByUser(ctx context.Context, userID int64) ([]Notification, error)
+	ListOrderAddresses(ctx context.Context, orderID int64) ([]OrderAddress, error)
+	ListOrderAdjustmentsByOrder(ctx context.Context, orderID int64) ([]OrderAdjustment, error)
 	ListOrderItems(ctx context.Context) ([]OrderItem, error)
 	ListOrderItemsByOrder(ctx context.Context, orderID int64) ([]OrderItem, error)
+	ListOrderItemsByOrders(ctx context.Context, orderIds []int64) ([]OrderItem, error)
 	ListOrderItemsByProduct(ctx context.Context, productID int64) ([]OrderItem, error)
 	ListOrders(ctx context.Context) ([]Order, error)
 	ListPayments(ctx context.Context) ([]Payment, error)
+	ListPaymentsByOrders(ctx context.Context, orderIds []int64) ([]Payment, error)
+	ListProductImagesByProduct(ctx context.Context, productID int64) ([]ProductImage, error)
+	ListProductVariantsByProduct(ctx context.Context, productID int64) ([]ProductVariant, error)
 	ListProducts(ctx context.Context) ([]Product, error)
+	ListProductsByIDs(ctx context.Context, ids []int64) ([]Product, error)
+	ListProductsForExport(ctx context.Context, arg ListProductsForExportParams) ([]ListProductsForExportRow, error)
+	ListPromotions(ctx context.Context) ([]Promotion, error)
+	ListPurgeableProductImages(ctx context.Context, deletedAt sql.NullTime) ([]ProductImage, error)
+	ListReturnItems(ctx context.Context, returnID int64) ([]ReturnItem, error)
+	ListReturns(ctx context.Context) ([]Return, error)
+	ListReturnsByOrder(ctx context.Context, orderID int64) ([]Return, error)
+	ListReturnsByStatus(ctx context.Context, status ReturnStatus) ([]Return, error)
+	ListShipmentsByOrder(ctx context.Context, orderID int64) ([]Shipment, error)
+	ListShippingMethods(ctx context.Context) ([]ShippingMethod, error)
+	ListShippingRatesByMethod(ctx context.Context, methodID int64) ([]ShippingRate, error)
+	ListTaxRates(ctx context.Context) ([]TaxRate, error)
+	ListUserAddresses(ctx context.Context, userID int64) ([]UserAddress, error)
 	ListUsers(ctx context.Context) ([]User, error)
+	ListUsersByIDs(ctx context.Context, ids []int64) ([]User, error)
+	ListWebhookDeliveriesBySubscription(ctx context.Context, subscriptionID int64) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	MarkNotificationFailed(ctx context.Context, arg MarkNotificationFailedParams) error
+	MarkNotificationSent(ctx context.Context, id int64) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error
+	MarkWebhookDeliverySucceeded(ctx context.Context, arg MarkWebhookDeliverySucceededParams) error
+	PurgeDeletedOrders(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	PurgeDeletedPayments(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	PurgeDeletedProducts(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	RedeemPromotion(ctx context.Context, id int64) (Promotion, error)
+	ReleaseOrderPromotions(ctx context.Context, orderID int64) error
+	ReleaseReturnRefund(ctx context.Context, id int64) (Return, error)
+	RequeueWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	ReserveProductStock(ctx context.Context, arg ReserveProductStockParams) (Product, error)
+	ReserveProductVariantStock(ctx context.Context, arg ReserveProductVariantStockParams) (ProductVariant, error)
+	RestockProduct(ctx context.Context, arg RestockProductParams) (Product, error)
+	RestockProductVariant(ctx context.Context, arg RestockProductVariantParams) (ProductVariant, error)
+	RestoreOrder(ctx context.Context, id int64) (Order, error)
+	RestorePayment(ctx context.Context, id int64) (Payment, error)
+	RestoreProduct(ctx context.Context, id int64) (Product, error)
+	RestoreUser(ctx context.Context, id int64) (User, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (ApiKey, error)
 	SearchOrdersByStatus(ctx context.Context, status OrderStatus) ([]Order, error)
 	SearchOrdersByUser(ctx context.Context, userID int64) ([]Order, error)
 	SearchPaymentsByOrder(ctx context.Context, orderID int64) ([]Payment, error)
 	SearchPaymentsByStatus(ctx context.Context, status PaymentStatus) ([]Payment, error)
 	SearchPaymentsByUser(ctx context.Context, userID int64) ([]Payment, error)
-	SearchProductsByCategory(ctx context.Context, category string) ([]Product, error)
+	SearchProductCategoryFacets(ctx context.Context, arg SearchProductCategoryFacetsParams) ([]SearchProductCategoryFacetsRow, error)
+	SearchProductPriceFacets(ctx context.Context, arg SearchProductPriceFacetsParams) ([]SearchProductPriceFacetsRow, error)
+	SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error)
+	SearchProductsByCategory(ctx context.Context, name string) ([]Product, error)
 	SearchProductsByName(ctx context.Context, dollar_1 sql.NullString) ([]Product, error)
 	SearchUsersByEmail(ctx context.Context, email string) ([]User, error)
 	SearchUsersByName(ctx context.Context, dollar_1 sql.NullString) ([]User, error)
+	SetDefaultUserAddress(ctx context.Context, id int64) (UserAddress, error)
+	SetOrderItemAmounts(ctx context.Context, arg SetOrderItemAmountsParams) (OrderItem, error)
+	SetOrderStatus(ctx context.Context, arg SetOrderStatusParams) (Order, error)
+	SetOrderTotals(ctx context.Context, arg SetOrderTotalsParams) (Order, error)
+	SetPrimaryProductImage(ctx context.Context, arg SetPrimaryProductImageParams) (ProductImage, error)
+	StartProductImportJob(ctx context.Context, id int64) error
+	SumReturnedQuantity(ctx context.Context, orderItemID int64) (int32, error)
+	SummarizeOrderItemTaxes(ctx context.Context, orderID int64) ([]SummarizeOrderItemTaxesRow, error)
+	TouchAPIKey(ctx context.Context, id int64) error
+	UpdateCartItemQuantity(ctx context.Context, arg UpdateCartItemQuantityParams) (CartItem, error)
+	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
 	UpdateOrder(ctx context.Context, arg UpdateOrderParams) (Order, error)
 	UpdateOrderItem(ctx context.Context, arg UpdateOrderItemParams) (OrderItem, error)
 	UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (Payment, error)
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
+	UpdateProductImagePosition(ctx context.Context, arg UpdateProductImagePositionParams) (ProductImage, error)
 	UpdateProductStock(ctx context.Context, arg UpdateProductStockParams) (Product, error)
+	UpdateProductVariant(ctx context.Context, arg UpdateProductVariantParams) (ProductVariant, error)
+	UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (Promotion, error)
+	UpdateReturn(ctx context.Context, arg UpdateReturnParams) (Return, error)
+	UpdateShipment(ctx context.Context, arg UpdateShipmentParams) (Shipment, error)
+	UpdateShippingMethod(ctx context.Context, arg UpdateShippingMethodParams) (ShippingMethod, error)
+	UpdateTaxRate(ctx context.Context, arg UpdateTaxRateParams) (TaxRate, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserAddress(ctx context.Context, arg UpdateUserAddressParams) (UserAddress, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (WebhookSubscription, error)
+	UpsertProductBySku(ctx context.Context, arg UpsertProductBySkuParams) (UpsertProductBySkuRow, error)
+	VerifyUserEmail(ctx context.Context, id int64) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
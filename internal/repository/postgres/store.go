@@ -27,7 +27,7 @@ func (s *Store) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
     }
     return &Tx{
         Tx:      tx,
-        Queries: s.Queries,
+        Queries: s.Queries.WithTx(tx),
     }, nil
 }
 
@@ -43,7 +43,7 @@ func (tx *Tx) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, er
 }
 
 // DeleteOrder deletes an order within the transaction.
-func (tx *Tx) DeleteOrder(ctx context.Context, id int64) error {
+func (tx *Tx) DeleteOrder(ctx context.Context, id int64) (Order, error) {
     return tx.Queries.DeleteOrder(ctx, id)
 }
 
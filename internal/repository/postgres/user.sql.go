@@ -8,19 +8,23 @@ package postgres
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (full_name, email, address, registration_date, role) 
-VALUES ($1, $2, $3, NOW(), $4) 
-RETURNING id, full_name, email, address, registration_date, role
+INSERT INTO users (full_name, email, address, registration_date, role, password_hash, locale) 
+VALUES ($1, $2, $3, NOW(), $4, $5, $6) 
+RETURNING id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at
 `
 
 type CreateUserParams struct {
-	FullName string `json:"full_name"`
-	Email    string `json:"email"`
-	Address  string `json:"address"`
-	Role     string `json:"role"`
+	FullName     string `json:"full_name"`
+	Email        string `json:"email"`
+	Address      string `json:"address"`
+	Role         string `json:"role"`
+	PasswordHash string `json:"-"`
+	Locale       string `json:"locale"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -29,6 +33,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Email,
 		arg.Address,
 		arg.Role,
+		arg.PasswordHash,
+		arg.Locale,
 	)
 	var i User
 	err := row.Scan(
@@ -38,21 +44,40 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Address,
 		&i.RegistrationDate,
 		&i.Role,
+		&i.PasswordHash,
+		&i.EmailVerifiedAt,
+		&i.Locale,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users WHERE id = $1
+const deleteUser = `-- name: DeleteUser :one
+UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteUser, id)
-	return err
+func (q *Queries) DeleteUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, deleteUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FullName,
+		&i.Email,
+		&i.Address,
+		&i.RegistrationDate,
+		&i.Role,
+		&i.PasswordHash,
+		&i.EmailVerifiedAt,
+		&i.Locale,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, full_name, email, address, registration_date, role FROM users WHERE id = $1 LIMIT 1
+SELECT id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at FROM users WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
@@ -65,12 +90,40 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.Address,
 		&i.RegistrationDate,
 		&i.Role,
+		&i.PasswordHash,
+		&i.EmailVerifiedAt,
+		&i.Locale,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at FROM users WHERE email = $1 AND deleted_at IS NULL LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FullName,
+		&i.Email,
+		&i.Address,
+		&i.RegistrationDate,
+		&i.Role,
+		&i.PasswordHash,
+		&i.EmailVerifiedAt,
+		&i.Locale,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, full_name, email, address, registration_date, role FROM users ORDER BY registration_date ASC
+SELECT id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at FROM users WHERE deleted_at IS NULL ORDER BY registration_date ASC
 `
 
 func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
@@ -89,6 +142,50 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.Address,
 			&i.RegistrationDate,
 			&i.Role,
+			&i.PasswordHash,
+			&i.EmailVerifiedAt,
+			&i.Locale,
+			&i.Version,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDs = `-- name: ListUsersByIDs :many
+SELECT id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at FROM users WHERE id = ANY($1::bigint[]) ORDER BY id ASC
+`
+
+func (q *Queries) ListUsersByIDs(ctx context.Context, ids []int64) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.Email,
+			&i.Address,
+			&i.RegistrationDate,
+			&i.Role,
+			&i.PasswordHash,
+			&i.EmailVerifiedAt,
+			&i.Locale,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -103,8 +200,54 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+-- Users who still have orders or payments are kept; their API keys and notifications go with them
+WITH expired AS (
+    SELECT u.id FROM users u
+    WHERE u.deleted_at < $1
+        AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id)
+        AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.user_id = u.id)
+), api_keys_deleted AS (
+    DELETE FROM api_keys WHERE user_id IN (SELECT id FROM expired)
+), notifications_deleted AS (
+    DELETE FROM notifications WHERE user_id IN (SELECT id FROM expired)
+)
+DELETE FROM users WHERE id IN (SELECT id FROM expired)
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedUsers, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FullName,
+		&i.Email,
+		&i.Address,
+		&i.RegistrationDate,
+		&i.Role,
+		&i.PasswordHash,
+		&i.EmailVerifiedAt,
+		&i.Locale,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const searchUsersByEmail = `-- name: SearchUsersByEmail :many
-SELECT id, full_name, email, address, registration_date, role FROM users WHERE email = $1 ORDER BY registration_date ASC
+SELECT id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at FROM users WHERE email = $1 AND deleted_at IS NULL ORDER BY registration_date ASC
 `
 
 func (q *Queries) SearchUsersByEmail(ctx context.Context, email string) ([]User, error) {
@@ -123,6 +266,11 @@ func (q *Queries) SearchUsersByEmail(ctx context.Context, email string) ([]User,
 			&i.Address,
 			&i.RegistrationDate,
 			&i.Role,
+			&i.PasswordHash,
+			&i.EmailVerifiedAt,
+			&i.Locale,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -138,7 +286,7 @@ func (q *Queries) SearchUsersByEmail(ctx context.Context, email string) ([]User,
 }
 
 const searchUsersByName = `-- name: SearchUsersByName :many
-SELECT id, full_name, email, address, registration_date, role FROM users WHERE full_name ILIKE '%' || $1 || '%' ORDER BY registration_date ASC
+SELECT id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at FROM users WHERE full_name ILIKE '%' || $1 || '%' AND deleted_at IS NULL ORDER BY registration_date ASC
 `
 
 func (q *Queries) SearchUsersByName(ctx context.Context, dollar_1 sql.NullString) ([]User, error) {
@@ -157,6 +305,11 @@ func (q *Queries) SearchUsersByName(ctx context.Context, dollar_1 sql.NullString
 			&i.Address,
 			&i.RegistrationDate,
 			&i.Role,
+			&i.PasswordHash,
+			&i.EmailVerifiedAt,
+			&i.Locale,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -176,9 +329,10 @@ UPDATE users SET
     full_name = $2,
     email = $3,
     address = $4,
-    role = $5
-WHERE id = $1 
-RETURNING id, full_name, email, address, registration_date, role
+    role = $5,
+    email_verified_at = CASE WHEN email = $3 THEN email_verified_at ELSE NULL END
+WHERE id = $1 AND version = $6
+RETURNING id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at
 `
 
 type UpdateUserParams struct {
@@ -187,6 +341,7 @@ type UpdateUserParams struct {
 	Email    string `json:"email"`
 	Address  string `json:"address"`
 	Role     string `json:"role"`
+	Version  int32  `json:"version"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -196,7 +351,45 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Email,
 		arg.Address,
 		arg.Role,
+		arg.Version,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FullName,
+		&i.Email,
+		&i.Address,
+		&i.RegistrationDate,
+		&i.Role,
+		&i.PasswordHash,
+		&i.EmailVerifiedAt,
+		&i.Locale,
+		&i.Version,
+		&i.DeletedAt,
 	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2 WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           int64  `json:"id"`
+	PasswordHash string `json:"-"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users SET email_verified_at = NOW() WHERE id = $1 RETURNING id, full_name, email, address, registration_date, role, password_hash, email_verified_at, locale, version, deleted_at
+`
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, verifyUserEmail, id)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -205,6 +398,11 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Address,
 		&i.RegistrationDate,
 		&i.Role,
+		&i.PasswordHash,
+		&i.EmailVerifiedAt,
+		&i.Locale,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -1,96 +1,118 @@
 package repository
 
-// import (
-// 	"ecommerce_management/internal/repository/memory"
-// 	"ecommerce_management/internal/repository/postgres"
-// 	"ecommerce_management/pkg/store"
-// )
-
-// // Configuration is an alias for a function that will take in a pointer to a Repository and modify it
-// type Configuration func(r *Repository) error
-
-// // Repository is an implementation of the Repository
-// type Repository struct {
-// 	// mongo    store.Mongo
-// 	postgres store.SQLX
-
-// 	Entity postgres.Querier
-// }
-
-// // New takes a variable amount of Configuration functions and returns a new Repository
-// // Each Configuration will be called in the order they are passed in
-// func New(configs ...Configuration) (s *Repository, err error) {
-// 	// Create the repository
-// 	s = &Repository{}
-
-// 	// Apply all Configurations passed in
-// 	for _, cfg := range configs {
-// 		// Pass the repository into the configuration function
-// 		if err = cfg(s); err != nil {
-// 			return
-// 		}
-// 	}
-
-// 	return
-// }
-
-// // Close closes the repository and prevents new queries from starting.
-// // Close then waits for all queries that have started processing on the server to finish.
-// func (r *Repository) Close() {
-// 	if r.postgres.Client != nil {
-// 		r.postgres.Client.Close()
-// 	}
-
-// 	// if r.mongo.Client != nil {
-// 	// 	r.mongo.Client.Disconnect(nil)
-// 	// }
-// }
-
-// // WithMemoryStore applies a memory store to the Repository
-// func WithMemoryStore() Configuration {
-// 	return func(s *Repository) (err error) {
-// 		// Create the memory store, if we needed parameters, such as connection strings they could be inputted here
-// 		s.Author = memory.NewAuthorRepository()
-// 		s.Book = memory.NewBookRepository()
-// 		s.Member = memory.NewMemberRepository()
-
-// 		return
-// 	}
-// }
-
-// // // WithMongoStore applies a mongo store to the Repository
-// // func WithMongoStore(uri, name string) Configuration {
-// // 	return func(s *Repository) (err error) {
-// // 		// Create the mongo store, if we needed parameters, such as connection strings they could be inputted here
-// // 		s.mongo, err = store.NewMongo(uri)
-// // 		if err != nil {
-// // 			return
-// // 		}
-// // 		database := s.mongo.Client.Database(name)
-
-// // 		s.Author = mongo.NewAuthorRepository(database)
-// // 		s.Book = mongo.NewBookRepository(database)
-// // 		s.Member = mongo.NewMemberRepository(database)
-
-// // 		return
-// // 	}
-// // }
-
-// // WithPostgresStore applies a postgres store to the Repository
-// func WithPostgresStore(dataSourceName string) Configuration {
-// 	return func(s *Repository) (err error) {
-// 		// Create the postgres store, if we needed parameters, such as connection strings they could be inputted here
-// 		s.postgres, err = store.NewSQL(dataSourceName)
-// 		if err != nil {
-// 			return
-// 		}
-
-// 		if err = store.Migrate(dataSourceName); err != nil {
-// 			return
-// 		}
-
-// 		s.Entity = postgres.NewAuthorRepository(s.postgres.Client)
-
-// 		return
-// 	}
-// }
+import (
+	"context"
+	"database/sql"
+
+	"ecommerce_management/internal/repository/memory"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// DriverMemory selects the in-memory store through DB_DRIVER; any other value selects Postgres
+const DriverMemory = "memory"
+
+// Querier is the set of queries every store serves
+type Querier interface {
+	postgres.Querier
+	ListUsersPage(ctx context.Context, arg postgres.ListUsersPageParams) (postgres.Page[postgres.User], error)
+	ListProductsPage(ctx context.Context, arg postgres.ListProductsPageParams) (postgres.Page[postgres.Product], error)
+	ListOrdersPage(ctx context.Context, arg postgres.ListOrdersPageParams) (postgres.Page[postgres.Order], error)
+	ListPaymentsPage(ctx context.Context, arg postgres.ListPaymentsPageParams) (postgres.Page[postgres.Payment], error)
+	ListOrderItemsPage(ctx context.Context, arg postgres.ListOrderItemsPageParams) (postgres.Page[postgres.OrderItem], error)
+	ListAuditEventsPage(ctx context.Context, arg postgres.ListAuditEventsPageParams) (postgres.Page[postgres.AuditEvent], error)
+}
+
+// Tx is a Querier running inside a transaction
+type Tx interface {
+	Querier
+	Commit() error
+	Rollback() error
+}
+
+// Store is a Querier that can start transactions
+type Store interface {
+	Querier
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
+}
+
+var (
+	_ Store = postgresStore{}
+	_ Store = memoryStore{}
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Repository and modify it
+type Configuration func(r *Repository) error
+
+// Repository is the Store handlers and services read and write through
+type Repository struct {
+	Store
+
+	db *sql.DB
+}
+
+// New takes a variable amount of Configuration functions and returns a new Repository
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (r *Repository, err error) {
+	// Create the repository
+	r = &Repository{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the repository into the configuration function
+		if err = cfg(r); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Close closes the database of the repository, if it has one
+func (r *Repository) Close() {
+	if r.db != nil {
+		r.db.Close()
+	}
+}
+
+// WithMemoryStore applies a store keeping every table in memory; its data is lost on restart
+func WithMemoryStore() Configuration {
+	return func(r *Repository) error {
+		r.Store = memoryStore{memory.NewStore()}
+		return nil
+	}
+}
+
+// WithPostgresStore applies a store running the queries against a migrated Postgres database
+func WithPostgresStore(db *sql.DB) Configuration {
+	return func(r *Repository) error {
+		r.Store = postgresStore{postgres.NewStore(db)}
+		r.db = db
+		return nil
+	}
+}
+
+// postgresStore and memoryStore return their own transaction types from BeginTx as a Tx
+
+type postgresStore struct {
+	*postgres.Store
+}
+
+func (s postgresStore) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := s.Store.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+type memoryStore struct {
+	*memory.Store
+}
+
+func (s memoryStore) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := s.Store.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/pkg/log"
+)
+
+// ErrNotFound is returned when an audit event does not exist
+var ErrNotFound = errors.New("audit event not found")
+
+// Record stores an event for a change to an entity. before and after are the entity as the API returns
+// it before and after the change, nil when it did not exist; only the fields that differ are kept.
+// Failures are logged rather than returned because the change itself has already been made.
+func (s *Service) Record(ctx context.Context, action, entityType string, entityID int64, before, after any) {
+	ctx = context.WithoutCancel(ctx)
+	logger := log.LoggerFromContext(ctx).Named("Record").With(
+		zap.String("action", action),
+		zap.String("entity_type", entityType),
+		zap.Int64("entity_id", entityID))
+
+	from, to, err := diff(before, after)
+	if err != nil {
+		logger.Error("failed to diff entity", zap.Error(err))
+		return
+	}
+
+	req, _ := ctx.Value(requestKey{}).(request)
+	if req.ActorType == "" {
+		req.ActorType = ActorAnonymous
+	}
+
+	err = s.repository.CreateAuditEvent(ctx, postgres.CreateAuditEventParams{
+		ActorType:  req.ActorType,
+		ActorID:    req.ActorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     from,
+		After:      to,
+		RequestID:  req.RequestID,
+		Ip:         req.IP,
+	})
+	if err != nil {
+		logger.Error("failed to store audit event", zap.Error(err))
+	}
+}
+
+// List returns a page of events, newest first unless the page sorts otherwise
+func (s *Service) List(ctx context.Context, arg postgres.ListAuditEventsPageParams) (postgres.Page[postgres.AuditEvent], error) {
+	return s.repository.ListAuditEventsPage(ctx, arg)
+}
+
+// Get returns an event by ID
+func (s *Service) Get(ctx context.Context, id int64) (postgres.AuditEvent, error) {
+	event, err := s.repository.GetAuditEvent(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: audit event ID %d", ErrNotFound, id)
+	}
+	return event, err
+}
+
+// diff returns the JSON fields of before and after whose values differ
+func diff(before, after any) (from, to json.RawMessage, err error) {
+	b, err := fields(before)
+	if err != nil {
+		return
+	}
+	a, err := fields(after)
+	if err != nil {
+		return
+	}
+
+	for key, value := range b {
+		if other, ok := a[key]; ok && reflect.DeepEqual(value, other) {
+			delete(b, key)
+			delete(a, key)
+		}
+	}
+
+	if from, err = json.Marshal(b); err != nil {
+		return
+	}
+	to, err = json.Marshal(a)
+	return
+}
+
+// fields decodes the JSON object of v; nil has no fields
+func fields(v any) (dst map[string]any, err error) {
+	dst = map[string]any{}
+	if v == nil {
+		return
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(raw, &dst)
+	return
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// requestIDHeader is the metadata key gRPC clients and proxies pass the request ID in,
+// like the X-Request-Id header of HTTP requests
+const requestIDHeader = "x-request-id"
+
+// UnaryInterceptor remembers the actor, request ID and peer IP of a gRPC call for the events it records,
+// as Middleware does for HTTP requests. It must run after authentication.
+func (s *Service) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = context.WithValue(ctx, requestKey{}, describeCall(ctx))
+		return handler(ctx, req)
+	}
+}
+
+// describeCall identifies the actor, request ID and peer IP of a gRPC call
+func describeCall(ctx context.Context) (req request) {
+	req.ActorType, req.ActorID = actor(ctx)
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			req.RequestID = values[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		req.IP = host
+	}
+
+	return
+}
@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/oauth"
+
+	"ecommerce_management/internal/service/auth"
+)
+
+// Actor types
+const (
+	ActorAPIKey    = "api_key"
+	ActorUser      = "user"
+	ActorAnonymous = "anonymous"
+)
+
+type requestKey struct{}
+
+// request describes who sent a request and from where
+type request struct {
+	ActorType string
+	ActorID   string
+	RequestID string
+	IP        string
+}
+
+// Middleware remembers the actor, request ID and client IP of a request for the events it records.
+// It must run after authentication.
+func (s *Service) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), requestKey{}, describe(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// describe identifies the actor, request ID and client IP of an HTTP request
+func describe(r *http.Request) (req request) {
+	req.ActorType, req.ActorID = actor(r.Context())
+	req.RequestID = middleware.GetReqID(r.Context())
+
+	// RemoteAddr has already been replaced by the client address from X-Forwarded-For or X-Real-IP
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	req.IP = host
+
+	return
+}
+
+// actor identifies API keys by their ID and users by the email they signed in with
+func actor(ctx context.Context) (actorType, actorID string) {
+	actorType = ActorAnonymous
+	if credential, _ := ctx.Value(oauth.CredentialContext).(string); credential != "" {
+		actorType, actorID = ActorUser, credential
+	}
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		actorType, actorID = ActorAPIKey, strconv.FormatInt(p.APIKeyID, 10)
+	}
+
+	return
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Actions recorded for an entity
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// Entity types recorded
+const (
+	EntityUser    = "user"
+	EntityProduct = "product"
+	EntityOrder   = "order"
+	EntityPayment = "payment"
+)
+
+// Actions lists every action, Entities every entity type that is recorded
+var (
+	Actions  = []string{ActionCreate, ActionUpdate, ActionDelete, ActionRestore}
+	Entities = []string{EntityUser, EntityProduct, EntityOrder, EntityPayment}
+)
+
+// Repository is the subset of queries the Service needs to store and read events
+type Repository interface {
+	CreateAuditEvent(ctx context.Context, arg postgres.CreateAuditEventParams) error
+	ListAuditEventsPage(ctx context.Context, arg postgres.ListAuditEventsPageParams) (postgres.Page[postgres.AuditEvent], error)
+	GetAuditEvent(ctx context.Context, id int64) (postgres.AuditEvent, error)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service keeps a log of who created, changed or deleted users, products, orders and payments
+type Service struct {
+	repository Repository
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithRepository applies a given repository to the Service
+func WithRepository(repository Repository) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
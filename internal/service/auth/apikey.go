@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Permissions that can be granted to an API key
+const (
+	PermissionUsersRead       = "users:read"
+	PermissionUsersWrite      = "users:write"
+	PermissionProductsRead    = "products:read"
+	PermissionProductsWrite   = "products:write"
+	PermissionOrdersRead      = "orders:read"
+	PermissionOrdersWrite     = "orders:write"
+	PermissionPaymentsRead    = "payments:read"
+	PermissionPaymentsWrite   = "payments:write"
+	PermissionWebhooksRead    = "webhooks:read"
+	PermissionWebhooksWrite   = "webhooks:write"
+	PermissionPromotionsRead  = "promotions:read"
+	PermissionPromotionsWrite = "promotions:write"
+	PermissionAuditRead       = "audit:read"
+)
+
+// Permissions lists every permission an API key may be scoped to
+var Permissions = []string{
+	PermissionUsersRead,
+	PermissionUsersWrite,
+	PermissionProductsRead,
+	PermissionProductsWrite,
+	PermissionOrdersRead,
+	PermissionOrdersWrite,
+	PermissionPaymentsRead,
+	PermissionPaymentsWrite,
+	PermissionWebhooksRead,
+	PermissionWebhooksWrite,
+	PermissionPromotionsRead,
+	PermissionPromotionsWrite,
+	PermissionAuditRead,
+}
+
+// RoleAdmin is the role of users whose API keys may manage the API keys of other users
+const RoleAdmin = "admin"
+
+const (
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 24
+)
+
+var (
+	ErrInvalidAPIKey     = errors.New("invalid api key")
+	ErrRevokedAPIKey     = errors.New("api key has been revoked")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrForbiddenAPIKey   = errors.New("api key may not manage this api key")
+)
+
+// APIKeyRepository is the subset of queries the Service needs to authenticate API keys
+type APIKeyRepository interface {
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (postgres.ApiKey, error)
+	TouchAPIKey(ctx context.Context, id int64) error
+}
+
+// APIKey is a freshly generated key; Secret is only known at creation time
+type APIKey struct {
+	Prefix     string
+	Secret     string
+	SecretHash string
+}
+
+// Token returns the value clients send as "Authorization: ApiKey <token>"
+func (k APIKey) Token() string {
+	return k.Prefix + "." + k.Secret
+}
+
+// GenerateAPIKey creates a random key prefix and secret together with the hash to be stored
+func GenerateAPIKey() (key APIKey, err error) {
+	prefix := make([]byte, apiKeyPrefixBytes)
+	if _, err = rand.Read(prefix); err != nil {
+		return
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return
+	}
+
+	key.Prefix = hex.EncodeToString(prefix)
+	key.Secret = hex.EncodeToString(secret)
+	key.SecretHash = hashAPIKeySecret(key.Secret)
+
+	return
+}
+
+// ValidatePermissions checks that every requested permission is known
+func ValidatePermissions(permissions []string) error {
+	for _, p := range permissions {
+		if !slices.Contains(Permissions, p) {
+			return fmt.Errorf("%w: %s", ErrUnknownPermission, p)
+		}
+	}
+	return nil
+}
+
+// CheckKeyOwner checks that a request made with the API key p may manage the API keys of userID.
+// role is the role of the user p belongs to; only keys of admins may manage the keys of other users.
+func CheckKeyOwner(p Principal, role string, userID int64) error {
+	if p.UserID != userID && role != RoleAdmin {
+		return fmt.Errorf("%w: keys of user ID %d belong to another user", ErrForbiddenAPIKey, userID)
+	}
+	return nil
+}
+
+// CheckGrant checks that a request made with the API key p may create a key of userID with permissions.
+// On top of CheckKeyOwner, a key can only grant the permissions it has itself.
+func CheckGrant(p Principal, role string, userID int64, permissions []string) error {
+	if err := CheckKeyOwner(p, role, userID); err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if !p.Can(permission) {
+			return fmt.Errorf("%w: %s is not granted to the key making the request", ErrForbiddenAPIKey, permission)
+		}
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves a "<prefix>.<secret>" token to its stored key and records its usage
+func (s *Service) AuthenticateAPIKey(ctx context.Context, token string) (key postgres.ApiKey, err error) {
+	if s.apiKeys == nil {
+		err = ErrInvalidAPIKey
+		return
+	}
+
+	prefix, secret, found := strings.Cut(token, ".")
+	if !found || prefix == "" || secret == "" {
+		err = ErrInvalidAPIKey
+		return
+	}
+
+	key, err = s.apiKeys.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrInvalidAPIKey
+		}
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(hashAPIKeySecret(secret))) != 1 {
+		err = ErrInvalidAPIKey
+		return
+	}
+
+	if key.RevokedAt.Valid {
+		err = ErrRevokedAPIKey
+		return
+	}
+
+	err = s.apiKeys.TouchAPIKey(ctx, key.ID)
+
+	return
+}
+
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -7,13 +7,18 @@ import (
 	"github.com/go-chi/oauth"
 )
 
-// ValidateUser validates username and password returning an error if the user credentials are wrong
-func (*Service) ValidateUser(username, password, scope string, r *http.Request) error {
-	if username == "user01" && password == "12345" {
-		return nil
+// ValidateUser validates username (the user's email) and password returning an error if the user credentials are wrong
+func (s *Service) ValidateUser(username, password, scope string, r *http.Request) error {
+	if s.users == nil {
+		return errors.New("wrong user")
+	}
+
+	user, err := s.users.GetUserByEmail(r.Context(), username)
+	if err != nil || !CheckPassword(user.PasswordHash, password) {
+		return errors.New("wrong user")
 	}
 
-	return errors.New("wrong user")
+	return nil
 }
 
 // ValidateClient validates clientID and secret returning an error if the client credentials are wrong
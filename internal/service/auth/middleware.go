@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/oauth"
+
+	"ecommerce_management/pkg/server/response"
+)
+
+var (
+	ErrInvalidBearerToken = errors.New("invalid or expired bearer token")
+	ErrUnsupportedScheme  = errors.New("unsupported authorization scheme")
+	ErrUnauthenticated    = errors.New("an API key or bearer token is required")
+)
+
+type principalKey struct{}
+
+// Principal describes the API key a request was authenticated with
+type Principal struct {
+	UserID      int64
+	APIKeyID    int64
+	Permissions []string
+}
+
+// Can reports whether the principal has been granted the given permission
+func (p Principal) Can(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+// ContextWithPrincipal adds principal to context
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the API key principal from context, if any
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Authenticate inspects the Authorization header and authenticates the request.
+// "ApiKey <prefix>.<secret>" is checked against stored API keys and "Bearer <token>"
+// is handed over to the OAuth bearer middleware. Requests without credentials pass through.
+func (s *Service) Authenticate(next http.Handler) http.Handler {
+	var bearer http.Handler
+	if s.bearer != nil {
+		bearer = s.bearer.Authorize(next)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		scheme, credentials, _ := strings.Cut(header, " ")
+		switch {
+		case strings.EqualFold(scheme, "ApiKey"):
+			key, err := s.AuthenticateAPIKey(r.Context(), strings.TrimSpace(credentials))
+			if err != nil {
+				if errors.Is(err, ErrInvalidAPIKey) || errors.Is(err, ErrRevokedAPIKey) {
+					response.Unauthorized(w, r, err)
+				} else {
+					response.InternalServerError(w, r, err)
+				}
+				return
+			}
+
+			ctx := ContextWithPrincipal(r.Context(), Principal{
+				UserID:      key.UserID,
+				APIKeyID:    key.ID,
+				Permissions: key.Permissions,
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+		case strings.EqualFold(scheme, "Bearer") && bearer != nil:
+			bearer.ServeHTTP(w, r)
+
+		default:
+			response.Unauthorized(w, r, ErrUnsupportedScheme)
+		}
+	})
+}
+
+// AuthenticateCredentials authenticates the value of an Authorization header received outside of HTTP,
+// such as gRPC metadata, and returns ctx carrying what the HTTP middleware would add to the request.
+// Empty credentials are accepted as they are by Authenticate.
+func (s *Service) AuthenticateCredentials(ctx context.Context, credentials string) (context.Context, error) {
+	if credentials == "" {
+		return ctx, nil
+	}
+
+	scheme, value, _ := strings.Cut(credentials, " ")
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.EqualFold(scheme, "ApiKey"):
+		key, err := s.AuthenticateAPIKey(ctx, value)
+		if err != nil {
+			return ctx, err
+		}
+
+		return ContextWithPrincipal(ctx, Principal{
+			UserID:      key.UserID,
+			APIKeyID:    key.ID,
+			Permissions: key.Permissions,
+		}), nil
+
+	case strings.EqualFold(scheme, "Bearer") && s.bearerToken != nil:
+		token, err := s.bearerToken.DecryptToken(value)
+		if err != nil || time.Now().UTC().After(token.CreationDate.Add(token.ExpiresIn)) {
+			return ctx, ErrInvalidBearerToken
+		}
+
+		ctx = context.WithValue(ctx, oauth.CredentialContext, token.Credential)
+		ctx = context.WithValue(ctx, oauth.ClaimsContext, token.Claims)
+		ctx = context.WithValue(ctx, oauth.ScopeContext, token.Scope)
+		ctx = context.WithValue(ctx, oauth.TokenTypeContext, token.TokenType)
+		ctx = context.WithValue(ctx, oauth.AccessTokenContext, value)
+		return ctx, nil
+
+	default:
+		return ctx, ErrUnsupportedScheme
+	}
+}
+
+// Authenticated reports whether the request was made with an API key or a bearer token
+func Authenticated(ctx context.Context) bool {
+	if _, ok := PrincipalFromContext(ctx); ok {
+		return true
+	}
+	credential, _ := ctx.Value(oauth.CredentialContext).(string)
+	return credential != ""
+}
+
+// RequireAuthentication rejects requests made without an API key or a bearer token
+func RequireAuthentication(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Authenticated(r.Context()) {
+			response.Unauthorized(w, r, ErrUnauthenticated)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Permitted reports whether the request may use permission. Like RequirePermission,
+// only requests made with an API key are restricted.
+func Permitted(ctx context.Context, permission string) bool {
+	p, ok := PrincipalFromContext(ctx)
+	return !ok || p.Can(permission)
+}
+
+// RequirePermission restricts requests made with an API key to keys scoped to resource.
+// Safe methods need "<resource>:read", everything else "<resource>:write".
+func RequirePermission(resource string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			permission := resource + ":write"
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				permission = resource + ":read"
+			}
+
+			if !p.Can(permission) {
+				response.Forbidden(w, r, errors.New("api key lacks permission "+permission))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	repo, err := repository.New(repository.WithMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(WithAPIKeyRepository(repo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := repo.CreateUser(ctx, postgres.CreateUserParams{
+		FullName: "Jane Doe",
+		Email:    "jane@example.com",
+		Role:     "customer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.CreateAPIKey(ctx, postgres.CreateAPIKeyParams{
+		UserID:      user.ID,
+		Name:        "ci",
+		Prefix:      key.Prefix,
+		SecretHash:  key.SecretHash,
+		Permissions: []string{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := s.Authenticate(RequireAuthentication(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	serve := func(authorization string) int {
+		r := httptest.NewRequest(http.MethodGet, "/users/1/api-keys", nil)
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := serve(""); code != http.StatusUnauthorized {
+		t.Errorf("anonymous request got %d, want 401", code)
+	}
+	if code := serve("ApiKey " + key.Token()); code != http.StatusNoContent {
+		t.Errorf("request with a valid key got %d, want 204", code)
+	}
+
+	if _, err = repo.DeleteUser(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.AuthenticateAPIKey(ctx, key.Token()); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("key of a deleted user authenticated: %v", err)
+	}
+	if code := serve("ApiKey " + key.Token()); code != http.StatusUnauthorized {
+		t.Errorf("request with the key of a deleted user got %d, want 401", code)
+	}
+}
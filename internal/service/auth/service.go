@@ -1,10 +1,29 @@
 package auth
 
+import (
+	"context"
+
+	"github.com/go-chi/oauth"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
 // Configuration is an alias for a function that will take in a pointer to a Service and modify it
 type Configuration func(s *Service) error
 
 // Service is an implementation of the Service
-type Service struct{}
+type Service struct {
+	apiKeys     APIKeyRepository
+	users       UserRepository
+	bearer      *oauth.BearerAuthentication
+	bearerToken *oauth.TokenProvider
+	tokenSecret []byte
+}
+
+// UserRepository is the subset of queries the Service needs to validate user logins
+type UserRepository interface {
+	GetUserByEmail(ctx context.Context, email string) (postgres.User, error)
+}
 
 // New takes a variable amount of Configuration functions and returns a new Service
 // Each Configuration will be called in the order they are passed in
@@ -21,3 +40,36 @@ func New(configs ...Configuration) (s *Service, err error) {
 	}
 	return
 }
+
+// WithAPIKeyRepository applies a given API key repository to the Service
+func WithAPIKeyRepository(apiKeys APIKeyRepository) Configuration {
+	return func(s *Service) error {
+		s.apiKeys = apiKeys
+		return nil
+	}
+}
+
+// WithBearerAuthentication enables validation of OAuth bearer tokens signed with the given secret
+func WithBearerAuthentication(secretKey string) Configuration {
+	return func(s *Service) error {
+		s.bearer = oauth.NewBearerAuthentication(secretKey, nil)
+		s.bearerToken = oauth.NewTokenProvider(oauth.NewSHA256RC4TokenSecurityProvider([]byte(secretKey)))
+		return nil
+	}
+}
+
+// WithUserRepository applies a given user repository to the Service
+func WithUserRepository(users UserRepository) Configuration {
+	return func(s *Service) error {
+		s.users = users
+		return nil
+	}
+}
+
+// WithTokenSecret sets the key used to sign one-time tokens
+func WithTokenSecret(secret string) Configuration {
+	return func(s *Service) error {
+		s.tokenSecret = []byte(secret)
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Purposes of signed one-time tokens
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposePasswordReset     = "password_reset"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// TokenClaims is the payload carried by a signed one-time token.
+// State binds the token to the user's current state (e.g. password hash),
+// so the token stops being valid once it has been used.
+type TokenClaims struct {
+	Purpose   string `json:"p"`
+	UserID    int64  `json:"u"`
+	State     string `json:"s"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// Fingerprint derives a short, non-reversible state value for TokenClaims
+func Fingerprint(values ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(values, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// IssueToken signs a one-time token for the given purpose and user state
+func (s *Service) IssueToken(purpose string, userID int64, state string, ttl time.Duration) (string, error) {
+	if len(s.tokenSecret) == 0 {
+		return "", errors.New("token secret is not configured")
+	}
+
+	payload, err := json.Marshal(TokenClaims{
+		Purpose:   purpose,
+		UserID:    userID,
+		State:     state,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.sign(encoded), nil
+}
+
+// ParseToken verifies the signature, purpose and expiry of a token and returns its claims.
+// Callers must still compare claims.State with the user's current fingerprint.
+func (s *Service) ParseToken(token, purpose string) (claims TokenClaims, err error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || len(s.tokenSecret) == 0 {
+		err = ErrInvalidToken
+		return
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		err = ErrInvalidToken
+		return
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		err = ErrInvalidToken
+		return
+	}
+
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		err = ErrInvalidToken
+		return
+	}
+
+	if claims.Purpose != purpose || time.Now().Unix() > claims.ExpiresAt {
+		err = ErrInvalidToken
+		return
+	}
+
+	return
+}
+
+func (s *Service) sign(value string) string {
+	mac := hmac.New(sha256.New, s.tokenSecret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
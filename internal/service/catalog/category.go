@@ -0,0 +1,136 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"ecommerce_management/internal/domain/category"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrInvalidCategory  = errors.New("invalid category")
+)
+
+// ListCategories returns every category
+func (s *Service) ListCategories(ctx context.Context) ([]postgres.Category, error) {
+	return s.repository.ListCategories(ctx)
+}
+
+// GetCategory returns a category by ID
+func (s *Service) GetCategory(ctx context.Context, id int64) (postgres.Category, error) {
+	data, err := s.repository.GetCategory(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: category ID %d", ErrCategoryNotFound, id)
+	}
+	return data, err
+}
+
+// CreateCategory adds a category; its slug is derived from its name unless given
+func (s *Service) CreateCategory(ctx context.Context, req category.Request) (data postgres.Category, err error) {
+	if err = s.validateCategory(ctx, 0, &req); err != nil {
+		return
+	}
+
+	return s.repository.CreateCategory(ctx, postgres.CreateCategoryParams{
+		ParentID: nullInt64(req.ParentID),
+		Name:     req.Name,
+		Slug:     req.Slug,
+		Position: req.Position,
+	})
+}
+
+// UpdateCategory replaces a category; moving it under one of its own descendants is an error wrapping ErrInvalidCategory
+func (s *Service) UpdateCategory(ctx context.Context, id int64, req category.Request) (data postgres.Category, err error) {
+	if err = s.validateCategory(ctx, id, &req); err != nil {
+		return
+	}
+
+	data, err = s.repository.UpdateCategory(ctx, postgres.UpdateCategoryParams{
+		ID:       id,
+		ParentID: nullInt64(req.ParentID),
+		Name:     req.Name,
+		Slug:     req.Slug,
+		Position: req.Position,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: category ID %d", ErrCategoryNotFound, id)
+	}
+	return
+}
+
+// DeleteCategory removes a category; only empty categories without subcategories can be deleted
+func (s *Service) DeleteCategory(ctx context.Context, id int64) error {
+	children, err := s.repository.ListCategoryChildren(ctx, sql.NullInt64{Int64: id, Valid: true})
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return fmt.Errorf("%w: category has subcategories", ErrInvalidCategory)
+	}
+
+	products, err := s.repository.CountProductsByCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+	if products > 0 {
+		return fmt.Errorf("%w: category has %d products", ErrInvalidCategory, products)
+	}
+
+	return s.repository.DeleteCategory(ctx, id)
+}
+
+// validateCategory fills in the slug and checks that it is free and that the parent exists without creating a cycle.
+// Problems with the request are errors wrapping ErrInvalidCategory.
+func (s *Service) validateCategory(ctx context.Context, id int64, req *category.Request) error {
+	if req.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCategory)
+	}
+
+	if req.Slug == "" {
+		req.Slug = category.Slugify(req.Name)
+	}
+	if req.Slug == "" || req.Slug != category.Slugify(req.Slug) {
+		return fmt.Errorf("%w: invalid slug: %q", ErrInvalidCategory, req.Slug)
+	}
+
+	existing, err := s.repository.GetCategoryBySlug(ctx, req.Slug)
+	if err == nil && existing.ID != id {
+		return fmt.Errorf("%w: slug %q is already used", ErrInvalidCategory, req.Slug)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	// Walk up from the new parent; reaching the category itself means it would become its own ancestor
+	for parentID := req.ParentID; parentID != nil; {
+		if *parentID == id {
+			return fmt.Errorf("%w: category cannot be moved under itself", ErrInvalidCategory)
+		}
+
+		parent, err := s.repository.GetCategory(ctx, *parentID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: parent category %d not found", ErrInvalidCategory, *parentID)
+			}
+			return err
+		}
+
+		parentID = nil
+		if parent.ParentID.Valid {
+			parentID = &parent.ParentID.Int64
+		}
+	}
+
+	return nil
+}
+
+func nullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
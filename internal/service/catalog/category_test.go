@@ -0,0 +1,176 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"ecommerce_management/internal/domain/category"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/media"
+)
+
+// fakeImages keeps the keys of stored images instead of their files
+type fakeImages struct {
+	mu     sync.Mutex
+	stored map[string]bool
+	count  int
+}
+
+func (f *fakeImages) StoreImage(ctx context.Context, prefix string, body io.Reader) (media.Image, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count++
+	key := fmt.Sprintf("%s/%d", prefix, f.count)
+	f.stored[key] = true
+
+	return media.Image{Key: key, URL: "/media/" + key, ContentType: "image/png", Width: 1, Height: 1}, nil
+}
+
+func (f *fakeImages) DeleteImage(ctx context.Context, img media.Image) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.stored, img.Key)
+}
+
+func newService(t *testing.T) (*Service, repository.Store, *fakeImages) {
+	t.Helper()
+
+	repo, err := repository.New(repository.WithMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images := &fakeImages{stored: map[string]bool{}}
+	s, err := New(WithRepository(repo), WithImages(images))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, repo, images
+}
+
+func TestCategoryCycles(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newService(t)
+
+	root, err := s.CreateCategory(ctx, category.Request{Name: "Home & Garden"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.Slug != category.Slugify("Home & Garden") {
+		t.Errorf("slug %q was not derived from the name", root.Slug)
+	}
+
+	child, err := s.CreateCategory(ctx, category.Request{Name: "Kitchen", ParentID: &root.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	grandchild, err := s.CreateCategory(ctx, category.Request{Name: "Mugs", ParentID: &child.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missing := grandchild.ID + 100
+	tests := []struct {
+		name string
+		id   int64
+		req  category.Request
+		want error
+	}{
+		{"under itself", root.ID, category.Request{Name: "Home & Garden", ParentID: &root.ID}, ErrInvalidCategory},
+		{"under a descendant", root.ID, category.Request{Name: "Home & Garden", ParentID: &grandchild.ID}, ErrInvalidCategory},
+		{"under a missing parent", child.ID, category.Request{Name: "Kitchen", ParentID: &missing}, ErrInvalidCategory},
+		{"taken slug", child.ID, category.Request{Name: "Kitchen", Slug: grandchild.Slug}, ErrInvalidCategory},
+		{"invalid slug", child.ID, category.Request{Name: "Kitchen", Slug: "Not A Slug"}, ErrInvalidCategory},
+		{"missing category", missing, category.Request{Name: "Garden"}, ErrCategoryNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.UpdateCategory(ctx, tt.id, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Moving a subtree to the top is fine
+	if _, err = s.UpdateCategory(ctx, child.ID, category.Request{Name: "Kitchen"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.DeleteCategory(ctx, child.ID); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("got error %v deleting a category with subcategories, want %v", err, ErrInvalidCategory)
+	}
+	if err = s.DeleteCategory(ctx, grandchild.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.DeleteCategory(ctx, child.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImages(t *testing.T) {
+	ctx := context.Background()
+	s, store, images := newService(t)
+
+	kitchen, err := s.CreateCategory(ctx, category.Request{Name: "Kitchen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := store.CreateProduct(ctx, postgres.CreateProductParams{
+		Sku:        "SKU-1",
+		Name:       "Mug",
+		Price:      "10.00",
+		CategoryID: kitchen.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.AddImage(ctx, product.ID+100, bytes.NewReader(nil), 0, false); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got error %v adding an image to a missing product, want %v", err, ErrNotFound)
+	}
+
+	first, err := s.AddImage(ctx, product.ID, bytes.NewReader(nil), 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.IsPrimary {
+		t.Error("the first image of a product should be primary")
+	}
+
+	second, err := s.AddImage(ctx, product.ID, bytes.NewReader(nil), 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.IsPrimary {
+		t.Error("a later image should only become primary when asked")
+	}
+
+	unset := false
+	if _, err = s.UpdateImage(ctx, product.ID, first.ID, nil, &unset); !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("got error %v unsetting the primary image, want %v", err, ErrInvalidImage)
+	}
+
+	// Deleting the primary image hands the flag to the next one and removes its files
+	if err = s.DeleteImage(ctx, product.ID, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if second, err = store.GetProductImage(ctx, postgres.GetProductImageParams{ID: second.ID, ProductID: product.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if !second.IsPrimary {
+		t.Error("the remaining image should have become primary")
+	}
+	if images.stored[first.StorageKey] || !images.stored[second.StorageKey] {
+		t.Errorf("unexpected stored images: %v", images.stored)
+	}
+
+	if err = s.DeleteImage(ctx, product.ID, first.ID); !errors.Is(err, ErrImageNotFound) {
+		t.Errorf("got error %v deleting a deleted image, want %v", err, ErrImageNotFound)
+	}
+}
@@ -0,0 +1,94 @@
+package catalog
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"ecommerce_management/internal/domain/product"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Export writes the whole catalogue to w in batches, flushing after each one so that large catalogues stream.
+// The output can be imported again unchanged.
+func (s *Service) Export(ctx context.Context, format string, w io.Writer) error {
+	var (
+		write func(row product.ImportRow) error
+		flush func() error
+	)
+
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		write = func(row product.ImportRow) error {
+			return writer.Write([]string{
+				row.Sku,
+				row.Name,
+				row.Description,
+				row.Price.String(),
+				strconv.Itoa(int(row.StockQuantity)),
+				strconv.FormatInt(row.CategoryID, 10),
+				row.Category,
+				row.TaxClass,
+				strconv.Itoa(int(row.WeightGrams)),
+			})
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		write = func(row product.ImportRow) error {
+			return encoder.Encode(row)
+		}
+		flush = func() error { return nil }
+	default:
+		return ErrUnknownFormat
+	}
+
+	var afterID int64
+	for {
+		products, err := s.repository.ListProductsForExport(ctx, postgres.ListProductsForExportParams{
+			AfterID:   afterID,
+			PageLimit: s.exportBatch,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, p := range products {
+			err = write(product.ImportRow{
+				Sku:           p.Sku,
+				Name:          p.Name,
+				Description:   p.Description,
+				Price:         json.Number(p.Price),
+				StockQuantity: p.StockQuantity,
+				CategoryID:    p.CategoryID,
+				Category:      p.Category,
+				TaxClass:      p.TaxClass,
+				WeightGrams:   p.WeightGrams,
+			})
+			if err != nil {
+				return err
+			}
+			afterID = p.ID
+		}
+
+		if err = flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+
+		if len(products) < int(s.exportBatch) {
+			return nil
+		}
+	}
+}
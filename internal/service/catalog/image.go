@@ -0,0 +1,215 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/media"
+	"ecommerce_management/internal/service/webhook"
+)
+
+var (
+	ErrImageNotFound = errors.New("product image not found")
+	ErrInvalidImage  = errors.New("invalid product image")
+)
+
+// ListImages returns the images of a product
+func (s *Service) ListImages(ctx context.Context, productID int64) ([]postgres.ProductImage, error) {
+	return s.repository.ListProductImagesByProduct(ctx, productID)
+}
+
+// AddImage stores an uploaded image of a product with its thumbnails and records it.
+// The image becomes primary when asked or when the product has no images yet.
+func (s *Service) AddImage(ctx context.Context, productID int64, body io.Reader, position int32, primary bool) (image postgres.ProductImage, err error) {
+	if s.images == nil {
+		return image, errors.New("catalog service has no image store")
+	}
+
+	if _, err = s.Get(ctx, productID, false); err != nil {
+		return
+	}
+
+	stored, err := s.images.StoreImage(ctx, fmt.Sprintf("products/%d", productID), body)
+	if err != nil {
+		return
+	}
+
+	if image, err = s.createImage(ctx, productID, stored, position, primary); err != nil {
+		s.images.DeleteImage(context.WithoutCancel(ctx), stored)
+		return
+	}
+
+	s.publish(ctx, webhook.EventProductUpdated, image)
+
+	return
+}
+
+// UpdateImage moves an image of a product to position and makes it primary; a nil field keeps its value.
+// The primary image cannot be unset, only replaced by making another image primary.
+func (s *Service) UpdateImage(ctx context.Context, productID, imageID int64, position *int32, primary *bool) (image postgres.ProductImage, err error) {
+	if primary != nil && !*primary {
+		return image, fmt.Errorf("%w: make another image primary instead", ErrInvalidImage)
+	}
+
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	image, err = tx.GetProductImage(ctx, postgres.GetProductImageParams{
+		ID:        imageID,
+		ProductID: productID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: image ID %d of product ID %d", ErrImageNotFound, imageID, productID)
+		}
+		return
+	}
+
+	if position != nil {
+		image, err = tx.UpdateProductImagePosition(ctx, postgres.UpdateProductImagePositionParams{
+			ID:        imageID,
+			ProductID: productID,
+			Position:  *position,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	if primary != nil && !image.IsPrimary {
+		if image, err = setPrimaryImage(ctx, tx, productID, imageID); err != nil {
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	s.publish(ctx, webhook.EventProductUpdated, image)
+
+	return
+}
+
+// DeleteImage removes an image of a product along with its stored files.
+// When the primary image is deleted the next one in order becomes primary.
+func (s *Service) DeleteImage(ctx context.Context, productID, imageID int64) error {
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	image, err := tx.GetProductImage(ctx, postgres.GetProductImageParams{
+		ID:        imageID,
+		ProductID: productID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: image ID %d of product ID %d", ErrImageNotFound, imageID, productID)
+		}
+		return err
+	}
+
+	err = tx.DeleteProductImage(ctx, postgres.DeleteProductImageParams{
+		ID:        imageID,
+		ProductID: productID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if image.IsPrimary {
+		remaining, err := tx.ListProductImagesByProduct(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if len(remaining) > 0 {
+			if _, err = setPrimaryImage(ctx, tx, productID, remaining[0].ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	if s.images != nil {
+		s.images.DeleteImage(context.WithoutCancel(ctx), storedImage(image))
+	}
+
+	return nil
+}
+
+// createImage records a stored image, making it primary when asked or when the product has no images yet
+func (s *Service) createImage(ctx context.Context, productID int64, stored media.Image, position int32, primary bool) (image postgres.ProductImage, err error) {
+	thumbnails, err := json.Marshal(stored.Thumbnails)
+	if err != nil {
+		return
+	}
+
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	count, err := tx.CountProductImagesByProduct(ctx, productID)
+	if err != nil {
+		return
+	}
+
+	image, err = tx.CreateProductImage(ctx, postgres.CreateProductImageParams{
+		ProductID:   productID,
+		StorageKey:  stored.Key,
+		Url:         stored.URL,
+		Thumbnails:  thumbnails,
+		ContentType: stored.ContentType,
+		Width:       int32(stored.Width),
+		Height:      int32(stored.Height),
+		SizeBytes:   stored.Size,
+		Position:    position,
+	})
+	if err != nil {
+		return
+	}
+
+	if primary || count == 0 {
+		if image, err = setPrimaryImage(ctx, tx, productID, image.ID); err != nil {
+			return
+		}
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// setPrimaryImage moves the primary flag of a product to the given image
+func setPrimaryImage(ctx context.Context, tx repository.Tx, productID, imageID int64) (postgres.ProductImage, error) {
+	if err := tx.ClearPrimaryProductImage(ctx, productID); err != nil {
+		return postgres.ProductImage{}, err
+	}
+
+	return tx.SetPrimaryProductImage(ctx, postgres.SetPrimaryProductImageParams{
+		ID:        imageID,
+		ProductID: productID,
+	})
+}
+
+// storedImage restores where the files of an image live in the blob store
+func storedImage(src postgres.ProductImage) media.Image {
+	dst := media.Image{Key: src.StorageKey}
+	json.Unmarshal(src.Thumbnails, &dst.Thumbnails)
+
+	return dst
+}
@@ -0,0 +1,442 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/domain/product"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/tax"
+	"ecommerce_management/internal/service/webhook"
+	"ecommerce_management/pkg/log"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format, use csv or jsonl")
+	ErrInvalidFile   = errors.New("invalid file")
+	ErrJobNotFound   = errors.New("import job not found")
+)
+
+// columns are the CSV headers written by the exporter and understood by the importer, in export order
+var columns = []string{"sku", "name", "description", "price", "stock_quantity", "category_id", "category", "tax_class", "weight_grams"}
+
+// restoreColumn asks the importer to bring back a deleted product; it is never exported
+const restoreColumn = "restore"
+
+// priceFormat matches the prices that fit the numeric(10,2) price column
+var priceFormat = regexp.MustCompile(`^[0-9]{1,8}(\.[0-9]{1,2})?$`)
+
+// fields are the columns or keys given for a row. Those left out keep their value on an existing product
+// and are required or take their default on a new one.
+type fields map[string]bool
+
+// Import upserts every valid row of body by SKU and reports the rows that were rejected.
+// A dry run only validates the rows. The error wraps ErrInvalidFile when the file itself cannot be read.
+func (s *Service) Import(ctx context.Context, format string, body io.Reader, dryRun bool) (report product.ImportReport, err error) {
+	report = product.ImportReport{DryRun: dryRun, Errors: []product.RowError{}}
+	categories := make(map[string]int64)
+
+	var failed error
+	err = readRows(format, body, func(line int, row product.ImportRow, given fields, rowErr error) error {
+		report.Total++
+		if rowErr != nil {
+			report.AddError(line, row.Sku, rowErr)
+			return nil
+		}
+
+		created, err := s.importRow(ctx, row, given, categories, dryRun)
+		if err != nil {
+			var invalid invalidRowError
+			if !errors.As(err, &invalid) {
+				failed = fmt.Errorf("line %d: %w", line, err)
+				return failed
+			}
+			report.AddError(line, row.Sku, err)
+			return nil
+		}
+
+		if created {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		return nil
+	})
+	if err != nil && failed == nil && !errors.Is(err, ErrUnknownFormat) {
+		err = fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	return
+}
+
+// ImportAsync records an import job and runs it in the background; poll the job for the report
+func (s *Service) ImportAsync(ctx context.Context, format string, data []byte, dryRun bool) (job postgres.ProductImportJob, err error) {
+	if format != FormatCSV && format != FormatJSONL {
+		return job, ErrUnknownFormat
+	}
+
+	job, err = s.repository.CreateProductImportJob(ctx, postgres.CreateProductImportJobParams{
+		Format: format,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go s.runJob(ctx, job, data)
+
+	return
+}
+
+// GetImportJob returns an asynchronous import by ID
+func (s *Service) GetImportJob(ctx context.Context, id int64) (postgres.ProductImportJob, error) {
+	job, err := s.repository.GetProductImportJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: import job ID %d", ErrJobNotFound, id)
+	}
+	return job, err
+}
+
+func (s *Service) runJob(ctx context.Context, job postgres.ProductImportJob, data []byte) {
+	logger := log.LoggerFromContext(ctx).Named("runJob").With(zap.Int64("job_id", job.ID))
+
+	if err := s.repository.StartProductImportJob(ctx, job.ID); err != nil {
+		logger.Error("failed to start import job", zap.Error(err))
+		return
+	}
+
+	report, err := s.Import(ctx, job.Format, bytes.NewReader(data), job.DryRun)
+
+	params := postgres.FinishProductImportJobParams{
+		ID:          job.ID,
+		Status:      postgres.ImportJobStatusCompleted,
+		TotalRows:   int32(report.Total),
+		CreatedRows: int32(report.Created),
+		UpdatedRows: int32(report.Updated),
+		FailedRows:  int32(report.Failed),
+	}
+	if err != nil {
+		params.Status = postgres.ImportJobStatusFailed
+		params.LastError = sql.NullString{String: err.Error(), Valid: true}
+	}
+	if params.Errors, err = json.Marshal(report.Errors); err != nil {
+		params.Errors = json.RawMessage("[]")
+	}
+
+	if _, err = s.repository.FinishProductImportJob(ctx, params); err != nil {
+		logger.Error("failed to finish import job", zap.Error(err))
+	}
+}
+
+// invalidRowError marks a row that was rejected without stopping the import
+type invalidRowError struct {
+	error
+}
+
+func invalidRow(format string, args ...any) error {
+	return invalidRowError{fmt.Errorf(format, args...)}
+}
+
+// importRow validates a row and upserts it, reporting whether the product was created.
+// categories caches resolved category slugs and IDs for the duration of an import.
+func (s *Service) importRow(ctx context.Context, row product.ImportRow, given fields, categories map[string]int64, dryRun bool) (created bool, err error) {
+	row.Sku = strings.TrimSpace(row.Sku)
+	if row.Sku == "" {
+		return false, invalidRow("sku is required")
+	}
+
+	existing, err := s.repository.GetProductBySku(ctx, row.Sku)
+	found := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	if found && existing.DeletedAt.Valid && !row.Restore {
+		return false, invalidRow("product %s is deleted, set restore to bring it back", row.Sku)
+	}
+
+	params := postgres.UpsertProductBySkuParams{Sku: row.Sku, Restore: row.Restore}
+	if given["name"] {
+		if row.Name = strings.TrimSpace(row.Name); row.Name == "" {
+			return false, invalidRow("name cannot be empty")
+		}
+		params.Name = sql.NullString{String: row.Name, Valid: true}
+	} else if !found {
+		return false, invalidRow("name is required")
+	}
+	if given["description"] {
+		params.Description = sql.NullString{String: row.Description, Valid: true}
+	}
+	if given["price"] {
+		if !priceFormat.MatchString(row.Price.String()) {
+			return false, invalidRow("invalid price: %q, expected at most 8 digits and 2 decimals", row.Price)
+		}
+		params.Price = sql.NullString{String: row.Price.String(), Valid: true}
+	} else if !found {
+		return false, invalidRow("price is required")
+	}
+	if given["stock_quantity"] {
+		if row.StockQuantity < 0 {
+			return false, invalidRow("stock_quantity cannot be negative")
+		}
+		params.StockQuantity = sql.NullInt32{Int32: row.StockQuantity, Valid: true}
+	}
+	if given["weight_grams"] {
+		if row.WeightGrams < 0 {
+			return false, invalidRow("weight_grams cannot be negative")
+		}
+		params.WeightGrams = sql.NullInt32{Int32: row.WeightGrams, Valid: true}
+	}
+	if given["tax_class"] {
+		params.TaxClass = sql.NullString{String: tax.NormalizeClass(row.TaxClass), Valid: true}
+	}
+	if given["category_id"] || given["category"] {
+		categoryID, err := s.resolveCategory(ctx, row, categories)
+		if err != nil {
+			return false, err
+		}
+		params.CategoryID = sql.NullInt64{Int64: categoryID, Valid: true}
+	} else if !found {
+		return false, invalidRow("category_id or category is required")
+	}
+
+	if dryRun {
+		return !found, nil
+	}
+
+	saved, err := s.repository.UpsertProductBySku(ctx, params)
+	if err != nil {
+		return
+	}
+
+	// The audit event shows what the import changed on the product the row replaces
+	var previous any
+	if found {
+		previous = existing
+	}
+
+	data := upsertedProduct(saved)
+	action, event := audit.ActionUpdate, webhook.EventProductUpdated
+	if saved.Inserted {
+		action, event = audit.ActionCreate, webhook.EventProductCreated
+	}
+	s.record(ctx, action, data.ID, previous, data)
+	s.publish(ctx, event, saved)
+
+	return saved.Inserted, nil
+}
+
+// upsertedProduct drops the inserted flag of an upserted row, leaving the product as the API returns it
+func upsertedProduct(src postgres.UpsertProductBySkuRow) postgres.Product {
+	return postgres.Product{
+		ID:            src.ID,
+		Name:          src.Name,
+		Description:   src.Description,
+		Price:         src.Price,
+		StockQuantity: src.StockQuantity,
+		AdditionDate:  src.AdditionDate,
+		CategoryID:    src.CategoryID,
+		Sku:           src.Sku,
+		TaxClass:      src.TaxClass,
+		WeightGrams:   src.WeightGrams,
+		Version:       src.Version,
+		DeletedAt:     src.DeletedAt,
+	}
+}
+
+func (s *Service) resolveCategory(ctx context.Context, row product.ImportRow, categories map[string]int64) (int64, error) {
+	key := "slug:" + row.Category
+	if row.CategoryID != 0 {
+		key = "id:" + strconv.FormatInt(row.CategoryID, 10)
+	} else if row.Category == "" {
+		return 0, invalidRow("category_id or category is required")
+	}
+
+	if id, ok := categories[key]; ok {
+		return id, nil
+	}
+
+	var (
+		category postgres.Category
+		err      error
+	)
+	if row.CategoryID != 0 {
+		category, err = s.repository.GetCategory(ctx, row.CategoryID)
+	} else {
+		category, err = s.repository.GetCategoryBySlug(ctx, row.Category)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		if row.CategoryID != 0 {
+			return 0, invalidRow("category %d not found", row.CategoryID)
+		}
+		return 0, invalidRow("category %q not found", row.Category)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	categories[key] = category.ID
+	return category.ID, nil
+}
+
+// readRows calls fn for every row of body with the fields it gives; a row that cannot be decoded is passed with its error
+func readRows(format string, body io.Reader, fn func(line int, row product.ImportRow, given fields, err error) error) error {
+	switch format {
+	case FormatCSV:
+		return readCSV(body, fn)
+	case FormatJSONL:
+		return readJSONL(body, fn)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+func readCSV(body io.Reader, fn func(line int, row product.ImportRow, given fields, err error) error) error {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("file is empty")
+		}
+		return err
+	}
+
+	index := make(map[string]int, len(header))
+	given := make(fields, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(columns, name) && name != restoreColumn {
+			return fmt.Errorf("unknown column %q, expected %s or %s", name, strings.Join(columns, ", "), restoreColumn)
+		}
+		index[name] = i
+		given[name] = true
+	}
+	if _, ok := index["sku"]; !ok {
+		return errors.New("sku column is required")
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+
+		row, rowErr := parseRecord(index, record)
+		if err != nil {
+			rowErr = fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+		}
+		if err = fn(line, row, given, rowErr); err != nil {
+			return err
+		}
+	}
+}
+
+func parseRecord(index map[string]int, record []string) (row product.ImportRow, err error) {
+	field := func(name string) string {
+		if i, ok := index[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	row = product.ImportRow{
+		Sku:         field("sku"),
+		Name:        field("name"),
+		Description: field("description"),
+		Price:       json.Number(field("price")),
+		Category:    field("category"),
+		TaxClass:    field("tax_class"),
+	}
+
+	if value := field("stock_quantity"); value != "" {
+		quantity, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return row, fmt.Errorf("invalid stock_quantity: %q", value)
+		}
+		row.StockQuantity = int32(quantity)
+	}
+
+	if value := field("weight_grams"); value != "" {
+		weight, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return row, fmt.Errorf("invalid weight_grams: %q", value)
+		}
+		row.WeightGrams = int32(weight)
+	}
+
+	if value := field("category_id"); value != "" {
+		if row.CategoryID, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return row, fmt.Errorf("invalid category_id: %q", value)
+		}
+	}
+
+	if value := field(restoreColumn); value != "" {
+		if row.Restore, err = strconv.ParseBool(value); err != nil {
+			return row, fmt.Errorf("invalid %s: %q", restoreColumn, value)
+		}
+	}
+
+	return
+}
+
+func readJSONL(body io.Reader, fn func(line int, row product.ImportRow, given fields, err error) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var (
+			row  product.ImportRow
+			keys map[string]json.RawMessage
+		)
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&row)
+		if err == nil {
+			err = json.Unmarshal(data, &keys)
+		}
+		if err != nil {
+			err = fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		// A key set to null is treated as left out
+		given := make(fields, len(keys))
+		for key, value := range keys {
+			given[key] = string(value) != "null"
+		}
+		if err = fn(line, row, given, err); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
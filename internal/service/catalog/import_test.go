@@ -0,0 +1,147 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"ecommerce_management/internal/domain/category"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+)
+
+// event is a change recorded through fakeRecorder
+type event struct {
+	action        string
+	id            int64
+	before, after any
+}
+
+// fakeRecorder keeps the audit events of a test in memory
+type fakeRecorder struct {
+	mu     sync.Mutex
+	events []event
+}
+
+func (f *fakeRecorder) Record(ctx context.Context, action, entityType string, entityID int64, before, after any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event{action: action, id: entityID, before: before, after: after})
+}
+
+func TestImportRecordsChanges(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newService(t)
+	recorder := &fakeRecorder{}
+	s.audit = recorder
+
+	if _, err := s.CreateCategory(ctx, category.Request{Name: "Kitchen"}); err != nil {
+		t.Fatal(err)
+	}
+
+	file := "sku,name,price,stock_quantity,category\nMUG-1,Mug,10.00,5,kitchen\n"
+	if _, err := s.Import(ctx, FormatCSV, strings.NewReader(file), true); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.events) != 0 {
+		t.Errorf("a dry run recorded %d events", len(recorder.events))
+	}
+
+	report, err := s.Import(ctx, FormatCSV, strings.NewReader(file), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	file = "sku,name,price,stock_quantity,category\nMUG-1,Mug,12.50,5,kitchen\n"
+	if report, err = s.Import(ctx, FormatCSV, strings.NewReader(file), false); err != nil {
+		t.Fatal(err)
+	}
+	if report.Updated != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if len(recorder.events) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(recorder.events))
+	}
+
+	created, updated := recorder.events[0], recorder.events[1]
+	if created.action != audit.ActionCreate || created.before != nil {
+		t.Errorf("unexpected create event: %+v", created)
+	}
+	before, _ := updated.before.(postgres.Product)
+	after, _ := updated.after.(postgres.Product)
+	if updated.action != audit.ActionUpdate || updated.id != created.id || before.Price != "10.00" || after.Price != "12.50" {
+		t.Errorf("unexpected update event: %+v", updated)
+	}
+}
+
+func TestImportKeepsOmittedFields(t *testing.T) {
+	ctx := context.Background()
+	s, store, _ := newService(t)
+
+	if _, err := s.CreateCategory(ctx, category.Request{Name: "Kitchen"}); err != nil {
+		t.Fatal(err)
+	}
+
+	file := "sku,name,description,price,stock_quantity,category,tax_class,weight_grams\nMUG-1,Mug,Blue mug,10.00,5,kitchen,reduced,300\n"
+	if _, err := s.Import(ctx, FormatCSV, strings.NewReader(file), false); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		file    string
+		invalid bool
+	}{
+		{"new product without a price", FormatCSV, "sku,name,category\nMUG-2,Cup,kitchen\n", true},
+		{"price out of range", FormatCSV, "sku,price\nMUG-1,1e12\n", true},
+		{"too many decimals", FormatCSV, "sku,price\nMUG-1,1.005\n", true},
+		{"price only", FormatCSV, "sku,price\nMUG-1,12.50\n", false},
+		{"name only", FormatJSONL, `{"sku": "MUG-1", "name": "Large mug", "description": null}` + "\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := s.Import(ctx, tt.format, strings.NewReader(tt.file), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (report.Failed == 1) != tt.invalid {
+				t.Errorf("unexpected report: %+v", report)
+			}
+		})
+	}
+
+	got, err := store.GetProductBySku(ctx, "MUG-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Large mug" || got.Price != "12.50" || got.Description != "Blue mug" || got.StockQuantity != 5 || got.TaxClass != "reduced" || got.WeightGrams != 300 {
+		t.Errorf("fields left out of the import changed: %+v", got)
+	}
+
+	if _, err = store.DeleteProduct(ctx, got.ID); err != nil {
+		t.Fatal(err)
+	}
+	report, err := s.Import(ctx, FormatCSV, strings.NewReader("sku,stock_quantity\nMUG-1,7\n"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Failed != 1 {
+		t.Errorf("a deleted product was imported without restore: %+v", report)
+	}
+
+	if _, err = s.Import(ctx, FormatCSV, strings.NewReader("sku,stock_quantity,restore\nMUG-1,7,true\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = store.GetProductBySku(ctx, "MUG-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got.DeletedAt.Valid || got.StockQuantity != 7 {
+		t.Errorf("the product was not restored: %+v", got)
+	}
+}
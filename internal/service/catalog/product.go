@@ -0,0 +1,209 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ecommerce_management/internal/domain/product"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/tax"
+	"ecommerce_management/internal/service/webhook"
+)
+
+// List returns a page of products
+func (s *Service) List(ctx context.Context, arg postgres.ListProductsPageParams) (postgres.Page[postgres.Product], error) {
+	return s.repository.ListProductsPage(ctx, arg)
+}
+
+// Get returns a product; deleted products are only returned when includeDeleted is set
+func (s *Service) Get(ctx context.Context, id int64, includeDeleted bool) (data postgres.Product, err error) {
+	data, err = s.repository.GetProduct(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: product ID %d", ErrNotFound, id)
+		}
+		return
+	}
+
+	if data.DeletedAt.Valid && !includeDeleted {
+		return data, fmt.Errorf("%w: product ID %d has been deleted", ErrNotFound, id)
+	}
+	return
+}
+
+// Create adds a product to the catalogue; its SKU must be unused and its category must exist
+func (s *Service) Create(ctx context.Context, arg postgres.CreateProductParams) (data postgres.Product, err error) {
+	arg.Sku = strings.TrimSpace(arg.Sku)
+	arg.TaxClass = tax.NormalizeClass(arg.TaxClass)
+	if err = s.validate(ctx, 0, arg.Sku, arg.CategoryID, arg.WeightGrams); err != nil {
+		return
+	}
+
+	if data, err = s.repository.CreateProduct(ctx, arg); err != nil {
+		return
+	}
+
+	s.record(ctx, audit.ActionCreate, data.ID, nil, data)
+	s.publish(ctx, webhook.EventProductCreated, data)
+
+	return
+}
+
+// Update replaces the fields of previous, as read with Get, by those of arg.
+// It fails with ErrVersionChanged when the product has been changed since it was read.
+func (s *Service) Update(ctx context.Context, previous postgres.Product, arg postgres.UpdateProductParams) (data postgres.Product, err error) {
+	arg.ID = previous.ID
+	arg.Version = previous.Version
+
+	arg.Sku = strings.TrimSpace(arg.Sku)
+	arg.TaxClass = tax.NormalizeClass(arg.TaxClass)
+	if err = s.validate(ctx, arg.ID, arg.Sku, arg.CategoryID, arg.WeightGrams); err != nil {
+		return
+	}
+
+	data, err = s.repository.UpdateProduct(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: product ID %d", ErrVersionChanged, arg.ID)
+		}
+		return
+	}
+
+	s.record(ctx, audit.ActionUpdate, data.ID, previous, data)
+	s.publish(ctx, webhook.EventProductUpdated, data)
+
+	return
+}
+
+// Delete hides a product, keeping it to be restored until the retention period has passed.
+// Its images stay with it for restoring; the retention job removes their files.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	data, err := s.repository.DeleteProduct(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: product ID %d", ErrNotFound, id)
+		}
+		return err
+	}
+
+	s.record(ctx, audit.ActionDelete, id, data, nil)
+	s.publish(ctx, webhook.EventProductDeleted, map[string]int64{"id": id})
+
+	return nil
+}
+
+// Restore brings back a deleted product; restoring a product that is not deleted changes nothing
+func (s *Service) Restore(ctx context.Context, id int64) (data postgres.Product, err error) {
+	previous, err := s.Get(ctx, id, true)
+	if err != nil || !previous.DeletedAt.Valid {
+		return previous, err
+	}
+
+	data, err = s.repository.RestoreProduct(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Restored or purged since it was read
+			err = fmt.Errorf("%w: product ID %d", ErrConflict, id)
+		}
+		return
+	}
+
+	s.record(ctx, audit.ActionRestore, id, previous, data)
+	s.publish(ctx, webhook.EventProductRestored, data)
+
+	return
+}
+
+// Search ranks the products matching the query of arg and computes the category and price facets.
+// A valid categoryID limits the results to the category and its subcategories. The total number of
+// matches is returned alongside, as ranked results are paginated by offset.
+func (s *Service) Search(ctx context.Context, arg postgres.SearchProductsParams, categoryID sql.NullInt64) (dst product.SearchResponse, total int64, err error) {
+	if categoryID.Valid {
+		if arg.CategoryIds, err = s.repository.ListCategoryDescendantIDs(ctx, categoryID.Int64); err != nil {
+			return
+		}
+	}
+
+	items, err := s.repository.SearchProducts(ctx, arg)
+	if err != nil {
+		return
+	}
+
+	// Each facet ignores its own filter so that clients can offer the alternatives
+	categories, err := s.repository.SearchProductCategoryFacets(ctx, postgres.SearchProductCategoryFacetsParams{
+		Query:    arg.Query,
+		MinPrice: arg.MinPrice,
+		MaxPrice: arg.MaxPrice,
+		InStock:  arg.InStock,
+	})
+	if err != nil {
+		return
+	}
+
+	prices, err := s.repository.SearchProductPriceFacets(ctx, postgres.SearchProductPriceFacetsParams{
+		Bounds:      product.PriceBuckets,
+		Query:       arg.Query,
+		CategoryIds: arg.CategoryIds,
+		InStock:     arg.InStock,
+	})
+	if err != nil {
+		return
+	}
+
+	if len(items) > 0 {
+		total = items[0].Total
+	}
+
+	return product.SearchResponse{
+		Items: items,
+		Facets: product.Facets{
+			Categories: product.ParseCategoryFacets(categories),
+			Prices:     product.ParsePriceFacets(product.PriceBuckets, prices),
+		},
+	}, total, nil
+}
+
+// SearchByName returns the products whose name contains name
+func (s *Service) SearchByName(ctx context.Context, name string) ([]postgres.Product, error) {
+	return s.repository.SearchProductsByName(ctx, sql.NullString{String: name, Valid: true})
+}
+
+// SearchByCategory returns the products of the category with the given name
+func (s *Service) SearchByCategory(ctx context.Context, category string) ([]postgres.Product, error) {
+	return s.repository.SearchProductsByCategory(ctx, category)
+}
+
+// validate checks a product before it is stored: the SKU must be set and not belong to another product,
+// the category must exist and the weight cannot be negative
+func (s *Service) validate(ctx context.Context, id int64, sku string, categoryID int64, weightGrams int32) error {
+	if weightGrams < 0 {
+		return fmt.Errorf("%w: weight_grams cannot be negative", ErrInvalidProduct)
+	}
+	if sku == "" {
+		return fmt.Errorf("%w: sku is required", ErrInvalidProduct)
+	}
+
+	existing, err := s.repository.GetProductBySku(ctx, sku)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err == nil && existing.ID != id {
+		if existing.DeletedAt.Valid {
+			return fmt.Errorf("%w: sku %q belongs to deleted product ID %d; restore it instead", ErrInvalidProduct, sku, existing.ID)
+		}
+		return fmt.Errorf("%w: sku %q is already used", ErrInvalidProduct, sku)
+	}
+
+	if _, err = s.repository.GetCategory(ctx, categoryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: category %d not found", ErrInvalidProduct, categoryID)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/media"
+)
+
+const (
+	defaultExportBatch = 500
+)
+
+var (
+	ErrNotFound       = errors.New("product not found")
+	ErrVersionChanged = errors.New("product has changed since it was read")
+	ErrConflict       = errors.New("product was changed by another request")
+	ErrInvalidProduct = errors.New("invalid product")
+)
+
+// Repository is the subset of queries the Service needs to keep, search, import and export products with their
+// categories and variants; images are changed inside transactions it begins
+type Repository interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (repository.Tx, error)
+	CreateCategory(ctx context.Context, arg postgres.CreateCategoryParams) (postgres.Category, error)
+	UpdateCategory(ctx context.Context, arg postgres.UpdateCategoryParams) (postgres.Category, error)
+	DeleteCategory(ctx context.Context, id int64) error
+	GetCategory(ctx context.Context, id int64) (postgres.Category, error)
+	ListCategories(ctx context.Context) ([]postgres.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (postgres.Category, error)
+	ListCategoryChildren(ctx context.Context, parentID sql.NullInt64) ([]postgres.Category, error)
+	ListCategoryDescendantIDs(ctx context.Context, id int64) ([]int64, error)
+	CountProductsByCategory(ctx context.Context, categoryID int64) (int64, error)
+	GetProduct(ctx context.Context, id int64) (postgres.Product, error)
+	GetProductBySku(ctx context.Context, sku string) (postgres.Product, error)
+	CreateProduct(ctx context.Context, arg postgres.CreateProductParams) (postgres.Product, error)
+	UpdateProduct(ctx context.Context, arg postgres.UpdateProductParams) (postgres.Product, error)
+	DeleteProduct(ctx context.Context, id int64) (postgres.Product, error)
+	RestoreProduct(ctx context.Context, id int64) (postgres.Product, error)
+	ListProductsPage(ctx context.Context, arg postgres.ListProductsPageParams) (postgres.Page[postgres.Product], error)
+	SearchProducts(ctx context.Context, arg postgres.SearchProductsParams) ([]postgres.SearchProductsRow, error)
+	SearchProductCategoryFacets(ctx context.Context, arg postgres.SearchProductCategoryFacetsParams) ([]postgres.SearchProductCategoryFacetsRow, error)
+	SearchProductPriceFacets(ctx context.Context, arg postgres.SearchProductPriceFacetsParams) ([]postgres.SearchProductPriceFacetsRow, error)
+	SearchProductsByName(ctx context.Context, name sql.NullString) ([]postgres.Product, error)
+	SearchProductsByCategory(ctx context.Context, name string) ([]postgres.Product, error)
+	UpsertProductBySku(ctx context.Context, arg postgres.UpsertProductBySkuParams) (postgres.UpsertProductBySkuRow, error)
+	ListProductsForExport(ctx context.Context, arg postgres.ListProductsForExportParams) ([]postgres.ListProductsForExportRow, error)
+	CreateProductImportJob(ctx context.Context, arg postgres.CreateProductImportJobParams) (postgres.ProductImportJob, error)
+	StartProductImportJob(ctx context.Context, id int64) error
+	FinishProductImportJob(ctx context.Context, arg postgres.FinishProductImportJobParams) (postgres.ProductImportJob, error)
+	GetProductImportJob(ctx context.Context, id int64) (postgres.ProductImportJob, error)
+	ListProductImagesByProduct(ctx context.Context, productID int64) ([]postgres.ProductImage, error)
+	ListProductVariantsByProduct(ctx context.Context, productID int64) ([]postgres.ProductVariant, error)
+	GetProductVariantBySku(ctx context.Context, sku string) (postgres.ProductVariant, error)
+	CreateProductVariant(ctx context.Context, arg postgres.CreateProductVariantParams) (postgres.ProductVariant, error)
+	UpdateProductVariant(ctx context.Context, arg postgres.UpdateProductVariantParams) (postgres.ProductVariant, error)
+	DeleteProductVariant(ctx context.Context, arg postgres.DeleteProductVariantParams) error
+}
+
+// ImageStore keeps the files of uploaded images and their thumbnails; *media.Service implements it
+type ImageStore interface {
+	StoreImage(ctx context.Context, prefix string, body io.Reader) (media.Image, error)
+	DeleteImage(ctx context.Context, img media.Image)
+}
+
+// Recorder records changes to products in the audit log; *audit.Service implements it
+type Recorder interface {
+	Record(ctx context.Context, action, entityType string, entityID int64, before, after any)
+}
+
+// Publisher publishes product events to webhook subscribers; *webhook.Service implements it
+type Publisher interface {
+	Publish(ctx context.Context, event string, data any)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service keeps and searches the products of the catalogue with their categories and images, imports them from CSV or JSON lines files
+// and exports them in the same formats
+type Service struct {
+	repository  Repository
+	images      ImageStore
+	audit       Recorder
+	webhooks    Publisher
+	exportBatch int32
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		exportBatch: defaultExportBatch,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+
+	if s.repository == nil {
+		return nil, errors.New("catalog service requires a repository")
+	}
+	return
+}
+
+// WithRepository applies a given repository to the Service
+func WithRepository(repository Repository) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithImages applies the store uploaded product images are kept in
+func WithImages(images ImageStore) Configuration {
+	return func(s *Service) error {
+		s.images = images
+		return nil
+	}
+}
+
+// WithAudit records every change to a product made through the Service in the audit log
+func WithAudit(audit Recorder) Configuration {
+	return func(s *Service) error {
+		s.audit = audit
+		return nil
+	}
+}
+
+// WithWebhooks publishes product events for every change and imported row
+func WithWebhooks(webhooks Publisher) Configuration {
+	return func(s *Service) error {
+		s.webhooks = webhooks
+		return nil
+	}
+}
+
+func (s *Service) record(ctx context.Context, action string, id int64, before, after any) {
+	if s.audit != nil {
+		s.audit.Record(ctx, action, audit.EntityProduct, id, before, after)
+	}
+}
+
+func (s *Service) publish(ctx context.Context, event string, data any) {
+	if s.webhooks != nil {
+		s.webhooks.Publish(ctx, event, data)
+	}
+}
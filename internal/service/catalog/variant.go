@@ -0,0 +1,136 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"ecommerce_management/internal/domain/product"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/webhook"
+)
+
+var (
+	ErrVariantNotFound = errors.New("product variant not found")
+	ErrInvalidVariant  = errors.New("invalid product variant")
+)
+
+// ListVariants returns the variants of a product
+func (s *Service) ListVariants(ctx context.Context, productID int64) ([]postgres.ProductVariant, error) {
+	return s.repository.ListProductVariantsByProduct(ctx, productID)
+}
+
+// CreateVariant adds a variant to a product; orders must then name the variant and its stock is used instead of the product's
+func (s *Service) CreateVariant(ctx context.Context, productID int64, req product.VariantRequest) (variant postgres.ProductVariant, err error) {
+	attributes, err := s.validateVariant(ctx, 0, &req)
+	if err != nil {
+		return
+	}
+
+	if _, err = s.repository.GetProduct(ctx, productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: product ID %d", ErrNotFound, productID)
+		}
+		return
+	}
+
+	variant, err = s.repository.CreateProductVariant(ctx, postgres.CreateProductVariantParams{
+		ProductID:     productID,
+		Sku:           req.Sku,
+		Attributes:    attributes,
+		Price:         nullString(req.Price),
+		StockQuantity: req.StockQuantity,
+		Position:      req.Position,
+	})
+	if err != nil {
+		return
+	}
+
+	s.publish(ctx, webhook.EventProductUpdated, variant)
+	return
+}
+
+// UpdateVariant replaces a variant of a product
+func (s *Service) UpdateVariant(ctx context.Context, productID, variantID int64, req product.VariantRequest) (variant postgres.ProductVariant, err error) {
+	attributes, err := s.validateVariant(ctx, variantID, &req)
+	if err != nil {
+		return
+	}
+
+	variant, err = s.repository.UpdateProductVariant(ctx, postgres.UpdateProductVariantParams{
+		ID:            variantID,
+		ProductID:     productID,
+		Sku:           req.Sku,
+		Attributes:    attributes,
+		Price:         nullString(req.Price),
+		StockQuantity: req.StockQuantity,
+		Position:      req.Position,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: variant ID %d of product ID %d", ErrVariantNotFound, variantID, productID)
+		}
+		return
+	}
+
+	s.publish(ctx, webhook.EventProductUpdated, variant)
+	return
+}
+
+// DeleteVariant removes a variant of a product; variants that have been ordered are kept
+func (s *Service) DeleteVariant(ctx context.Context, productID, variantID int64) error {
+	err := s.repository.DeleteProductVariant(ctx, postgres.DeleteProductVariantParams{
+		ID:        variantID,
+		ProductID: productID,
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("%w: variant has been ordered and cannot be deleted", ErrInvalidVariant)
+	}
+	return err
+}
+
+// validateVariant checks the SKU is set and unused and the price is numeric, and encodes the attributes.
+// Problems with the request are errors wrapping ErrInvalidVariant.
+func (s *Service) validateVariant(ctx context.Context, id int64, req *product.VariantRequest) (json.RawMessage, error) {
+	req.Sku = strings.TrimSpace(req.Sku)
+	if req.Sku == "" {
+		return nil, fmt.Errorf("%w: sku is required", ErrInvalidVariant)
+	}
+
+	if req.StockQuantity < 0 {
+		return nil, fmt.Errorf("%w: stock_quantity cannot be negative", ErrInvalidVariant)
+	}
+
+	if req.Price != nil {
+		if _, err := strconv.ParseFloat(*req.Price, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid price: %s", ErrInvalidVariant, *req.Price)
+		}
+	}
+
+	existing, err := s.repository.GetProductVariantBySku(ctx, req.Sku)
+	if err == nil && existing.ID != id {
+		return nil, fmt.Errorf("%w: sku %q is already used", ErrInvalidVariant, req.Sku)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if req.Attributes == nil {
+		req.Attributes = map[string]string{}
+	}
+	return json.Marshal(req.Attributes)
+}
+
+func nullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
@@ -0,0 +1,207 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/oauth"
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/pkg/log"
+	"ecommerce_management/pkg/server/response"
+)
+
+const (
+	// Header is the request header carrying the client's key
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed from an earlier request
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+var (
+	ErrInvalidKey   = errors.New("invalid Idempotency-Key")
+	ErrKeyReused    = errors.New("Idempotency-Key has already been used for a different request")
+	ErrKeyInProcess = errors.New("a request with this Idempotency-Key is still being processed")
+)
+
+// Middleware honours the Idempotency-Key header of POST, PUT, PATCH and DELETE requests.
+// The first request with a key runs and its response is stored. Retries with the same key and an identical
+// request get the stored response back; reusing the key for a different request, or while the first one
+// is still running, is answered with 409 Conflict. Server errors and 401 or 403 responses are not stored, so such
+// requests can be retried once fixed. Bodies larger than the configured limit are rejected before anything is claimed.
+// Keys are scoped to the API key or user sending them and must run after authentication.
+func (s *Service) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if key == "" || !mutating(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxKeyLength {
+			response.BadRequest(w, r, fmt.Errorf("%w: longer than %d characters", ErrInvalidKey, maxKeyLength), nil)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				err = fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit)
+			}
+			response.BadRequest(w, r, err, nil)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		owner := owner(r)
+		fingerprint := fingerprint(r, body)
+
+		claimed, err := s.repository.ClaimIdempotencyKey(r.Context(), postgres.ClaimIdempotencyKeyParams{
+			Owner:         owner,
+			Key:           key,
+			Fingerprint:   fingerprint,
+			ExpiresBefore: s.now().Add(-s.ttl),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			s.replay(w, r, owner, key, fingerprint)
+			return
+		}
+		if err != nil {
+			response.InternalServerError(w, r, err)
+			return
+		}
+
+		// Release the key unless a response worth replaying was stored, including when the handler panics
+		completed := false
+		defer func() {
+			if !completed {
+				s.release(r.Context(), claimed.ID)
+			}
+		}()
+
+		var recorded bytes.Buffer
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(&recorded)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if !storable(status) {
+			return
+		}
+
+		err = s.repository.CompleteIdempotencyKey(context.WithoutCancel(r.Context()), postgres.CompleteIdempotencyKeyParams{
+			ID:           claimed.ID,
+			StatusCode:   sql.NullInt32{Int32: int32(status), Valid: true},
+			ContentType:  ww.Header().Get("Content-Type"),
+			ResponseBody: recorded.Bytes(),
+		})
+		if err != nil {
+			logger := log.LoggerFromContext(r.Context()).Named("idempotency")
+			logger.Error("failed to store response", zap.Error(err), zap.Int64("id", claimed.ID))
+			return
+		}
+		completed = true
+	})
+}
+
+// replay answers a request whose key is already held with the stored response, or a conflict
+func (s *Service) replay(w http.ResponseWriter, r *http.Request, owner, key, fingerprint string) {
+	stored, err := s.repository.GetIdempotencyKey(r.Context(), postgres.GetIdempotencyKeyParams{
+		Owner: owner,
+		Key:   key,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Released by a failed request in the meantime; the client may simply retry
+			response.Conflict(w, r, ErrKeyInProcess)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	switch {
+	case stored.Fingerprint != fingerprint:
+		response.Conflict(w, r, ErrKeyReused)
+	case !stored.StatusCode.Valid:
+		response.Conflict(w, r, ErrKeyInProcess)
+	default:
+		if stored.ContentType != "" {
+			w.Header().Set("Content-Type", stored.ContentType)
+		}
+		w.Header().Set(ReplayedHeader, "true")
+		w.WriteHeader(int(stored.StatusCode.Int32))
+		w.Write(stored.ResponseBody)
+	}
+}
+
+// release forgets a claimed key so that the request can be retried
+func (s *Service) release(ctx context.Context, id int64) {
+	if err := s.repository.DeleteIdempotencyKey(context.WithoutCancel(ctx), id); err != nil {
+		logger := log.LoggerFromContext(ctx).Named("idempotency")
+		logger.Error("failed to release key", zap.Error(err), zap.Int64("id", id))
+	}
+}
+
+// mutating reports whether requests with method change state
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// storable reports whether a response with status is replayed to retries. Server errors and rejected
+// credentials or permissions depend on state outside the request, so the client may retry them unchanged.
+func storable(status int) bool {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return false
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return false
+	}
+	return true
+}
+
+// owner names who sent a request: the API key, the user of a bearer token or, without credentials, the client address
+func owner(r *http.Request) string {
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		return fmt.Sprintf("api_key:%d", p.APIKeyID)
+	}
+	if credential, ok := r.Context().Value(oauth.CredentialContext).(string); ok && credential != "" {
+		return "user:" + credential
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// fingerprint identifies a request by its method, path, query and body
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.RequestURI())
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
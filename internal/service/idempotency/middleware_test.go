@@ -0,0 +1,59 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ecommerce_management/internal/repository"
+)
+
+func TestMiddleware(t *testing.T) {
+	repo, err := repository.New(repository.WithMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(WithRepository(repo), WithMaxBody(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := http.StatusForbidden
+	calls := 0
+	handler := s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+	serve := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		r.Header.Set(Header, "key")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve("{}"); w.Code != http.StatusForbidden {
+		t.Fatalf("first request got %d, want 403", w.Code)
+	}
+
+	status = http.StatusCreated
+	w := serve("{}")
+	if w.Code != http.StatusCreated || w.Header().Get(ReplayedHeader) != "" {
+		t.Errorf("retry after 403 got %d replayed=%q, want a fresh 201", w.Code, w.Header().Get(ReplayedHeader))
+	}
+	w = serve("{}")
+	if w.Code != http.StatusCreated || w.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("retry after 201 got %d replayed=%q, want the stored 201", w.Code, w.Header().Get(ReplayedHeader))
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+
+	if w = serve(strings.Repeat("x", 17)); w.Code != http.StatusBadRequest {
+		t.Errorf("oversized body got %d, want 400", w.Code)
+	}
+	if calls != 2 {
+		t.Errorf("handler ran for an oversized body")
+	}
+}
@@ -0,0 +1,93 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+const (
+	defaultTTL         = 24 * time.Hour
+	defaultPurgePeriod = time.Hour
+	// defaultMaxBody matches the largest body a handler accepts, an async product import
+	defaultMaxBody = 100 << 20
+)
+
+// Repository is the subset of queries the Service needs to claim keys and store responses
+type Repository interface {
+	ClaimIdempotencyKey(ctx context.Context, arg postgres.ClaimIdempotencyKeyParams) (postgres.IdempotencyKey, error)
+	GetIdempotencyKey(ctx context.Context, arg postgres.GetIdempotencyKeyParams) (postgres.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, arg postgres.CompleteIdempotencyKeyParams) error
+	DeleteIdempotencyKey(ctx context.Context, id int64) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, createdAt time.Time) (int64, error)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service makes retried mutating requests safe by replaying the response of the first request sent with the same Idempotency-Key
+type Service struct {
+	repository  Repository
+	ttl         time.Duration
+	purgePeriod time.Duration
+	maxBody     int64
+	now         func() time.Time
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		ttl:         defaultTTL,
+		purgePeriod: defaultPurgePeriod,
+		maxBody:     defaultMaxBody,
+		now:         time.Now,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithRepository applies a given repository to the Service
+func WithRepository(repository Repository) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithTTL sets how long a key is remembered; a key older than ttl may be reused for any request
+func WithTTL(ttl time.Duration) Configuration {
+	return func(s *Service) error {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+		return nil
+	}
+}
+
+// WithMaxBody limits the size of the request bodies buffered to fingerprint requests sent with a key
+func WithMaxBody(size int64) Configuration {
+	return func(s *Service) error {
+		if size > 0 {
+			s.maxBody = size
+		}
+		return nil
+	}
+}
+
+// WithClock replaces the clock used to expire keys
+func WithClock(now func() time.Time) Configuration {
+	return func(s *Service) error {
+		s.now = now
+		return nil
+	}
+}
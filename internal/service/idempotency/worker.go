@@ -0,0 +1,35 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/pkg/log"
+)
+
+// Start deletes expired keys until ctx is cancelled
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.purgePeriod)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.purgeExpired(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Service) purgeExpired(ctx context.Context) {
+	logger := log.LoggerFromContext(ctx).Named("purgeExpired")
+
+	if _, err := s.repository.DeleteExpiredIdempotencyKeys(ctx, s.now().Add(-s.ttl)); err != nil {
+		logger.Error("failed to delete expired idempotency keys", zap.Error(err))
+	}
+}
@@ -0,0 +1,215 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/pkg/log"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image format, use JPEG, PNG or GIF")
+	ErrImageTooLarge    = errors.New("image is too large")
+)
+
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// Image is a stored original together with its thumbnails
+type Image struct {
+	Key         string
+	URL         string
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+	Thumbnails  map[string]Thumbnail
+}
+
+// Thumbnail is a resized copy of an image
+type Thumbnail struct {
+	Key    string `json:"key"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// StoreImage validates body as an image, stores it under prefix with its thumbnails and returns where they live
+func (s *Service) StoreImage(ctx context.Context, prefix string, body io.Reader) (dst Image, err error) {
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return
+	}
+	if int64(len(data)) > s.maxSize {
+		return dst, ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return dst, ErrUnsupportedImage
+	}
+
+	// A small file can declare huge dimensions, so they are checked before any pixel is decoded
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return dst, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return dst, fmt.Errorf("%w: image has no pixels", ErrUnsupportedImage)
+	}
+	if int64(config.Width)*int64(config.Height) > s.maxPixels {
+		return dst, fmt.Errorf("%w: %dx%d pixels, at most %d are accepted", ErrImageTooLarge, config.Width, config.Height, s.maxPixels)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return dst, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	// Converted once so that every thumbnail is filtered from the same raw pixels
+	src := toNRGBA(decoded)
+
+	id := make([]byte, 12)
+	if _, err = rand.Read(id); err != nil {
+		return
+	}
+	dir := path.Join(prefix, hex.EncodeToString(id))
+
+	dst = Image{
+		Key:         path.Join(dir, "original."+ext),
+		ContentType: contentType,
+		Width:       src.Bounds().Dx(),
+		Height:      src.Bounds().Dy(),
+		Size:        int64(len(data)),
+		Thumbnails:  make(map[string]Thumbnail, len(s.sizes)),
+	}
+	dst.URL = s.store.URL(dst.Key)
+
+	if err = s.store.Put(ctx, dst.Key, bytes.NewReader(data), dst.Size, contentType); err != nil {
+		return
+	}
+
+	// Thumbnails keep PNG for sources that may be transparent and use JPEG otherwise
+	thumbType, thumbExt := "image/jpeg", "jpg"
+	if contentType != "image/jpeg" {
+		thumbType, thumbExt = "image/png", "png"
+	}
+
+	for _, size := range s.sizes {
+		thumb := resize(src, size.MaxSide)
+
+		var buf bytes.Buffer
+		if thumbType == "image/png" {
+			err = png.Encode(&buf, thumb)
+		} else {
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85})
+		}
+		if err != nil {
+			s.DeleteImage(ctx, dst)
+			return
+		}
+
+		key := path.Join(dir, size.Name+"."+thumbExt)
+		if err = s.store.Put(ctx, key, &buf, int64(buf.Len()), thumbType); err != nil {
+			s.DeleteImage(ctx, dst)
+			return
+		}
+
+		dst.Thumbnails[size.Name] = Thumbnail{
+			Key:    key,
+			URL:    s.store.URL(key),
+			Width:  thumb.Bounds().Dx(),
+			Height: thumb.Bounds().Dy(),
+		}
+	}
+
+	return
+}
+
+// DeleteImage removes an original and its thumbnails; failures are logged so that a missing object never blocks deletion
+func (s *Service) DeleteImage(ctx context.Context, img Image) {
+	logger := log.LoggerFromContext(ctx).Named("DeleteImage")
+
+	keys := []string{img.Key}
+	for _, thumb := range img.Thumbnails {
+		keys = append(keys, thumb.Key)
+	}
+
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Warn("failed to delete blob", zap.Error(err), zap.String("key", key))
+		}
+	}
+}
+
+// toNRGBA converts src to non-premultiplied RGBA pixels starting at the origin
+func toNRGBA(src image.Image) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// resize scales src down to fit in a maxSide square using a box filter; smaller images are returned as they are
+func resize(src *image.NRGBA, maxSide int) *image.NRGBA {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+
+	dw, dh := sw, sh
+	if sw > maxSide || sh > maxSide {
+		if sw >= sh {
+			dw, dh = maxSide, max(1, sh*maxSide/sw)
+		} else {
+			dw, dh = max(1, sw*maxSide/sh), maxSide
+		}
+	}
+	if dw == sw && dh == sh {
+		return src
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				i := sy*src.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+
+			o := y*dst.Stride + x*4
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(b / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
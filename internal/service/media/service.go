@@ -0,0 +1,95 @@
+package media
+
+import (
+	"errors"
+
+	"ecommerce_management/internal/provider/blob"
+)
+
+const (
+	defaultMaxSize   = 10 << 20
+	defaultMaxPixels = 25_000_000
+)
+
+// Size is a thumbnail generated for every uploaded image; MaxSide bounds both dimensions
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+var defaultSizes = []Size{
+	{Name: "small", MaxSide: 160},
+	{Name: "medium", MaxSide: 480},
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service validates uploaded images, generates thumbnails and keeps everything in a blob store
+type Service struct {
+	store     blob.Store
+	sizes     []Size
+	maxSize   int64
+	maxPixels int64
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		sizes:     defaultSizes,
+		maxSize:   defaultMaxSize,
+		maxPixels: defaultMaxPixels,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+
+	if s.store == nil {
+		return nil, errors.New("media service requires a blob store")
+	}
+	return
+}
+
+// WithStore applies a given blob store to the Service
+func WithStore(store blob.Store) Configuration {
+	return func(s *Service) error {
+		s.store = store
+		return nil
+	}
+}
+
+// WithSizes replaces the thumbnail sizes generated on upload
+func WithSizes(sizes ...Size) Configuration {
+	return func(s *Service) error {
+		s.sizes = sizes
+		return nil
+	}
+}
+
+// WithMaxSize limits the size in bytes of an uploaded image
+func WithMaxSize(maxSize int64) Configuration {
+	return func(s *Service) error {
+		s.maxSize = maxSize
+		return nil
+	}
+}
+
+// WithMaxPixels limits the width times the height of an uploaded image, which bounds the memory needed to decode it
+func WithMaxPixels(maxPixels int64) Configuration {
+	return func(s *Service) error {
+		s.maxPixels = maxPixels
+		return nil
+	}
+}
+
+// MaxSize returns the largest accepted upload in bytes
+func (s *Service) MaxSize() int64 {
+	return s.maxSize
+}
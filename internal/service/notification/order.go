@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/pkg/log"
+)
+
+// Event identifies a transactional email; its value names the templates used to render it
+type Event string
+
+const (
+	EventOrderCreated   Event = "order_created"
+	EventOrderPaid      Event = "order_paid"
+	EventOrderShipped   Event = "order_shipped"
+	EventOrderDelivered Event = "order_delivered"
+	EventOrderRefunded  Event = "order_refunded"
+)
+
+// OrderData is passed to order and payment templates
+type OrderData struct {
+	User  postgres.User
+	Order postgres.Order
+	Items []OrderItemData
+	// Shipments carry the tracking numbers shown in shipping emails
+	Shipments []postgres.Shipment
+}
+
+// OrderItemData is a single order line as shown in emails
+type OrderItemData struct {
+	Name     string
+	Quantity int32
+	Price    string
+}
+
+// NotifyOrder renders the email for event about the given order, records it and sends it.
+// It runs in the background; failures are logged and retried by the worker.
+func (s *Service) NotifyOrder(ctx context.Context, event Event, orderID int64) {
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		logger := log.LoggerFromContext(ctx).Named("NotifyOrder")
+
+		notification, err := s.createOrderNotification(ctx, event, orderID)
+		if err != nil {
+			logger.Error("failed to create notification", zap.Error(err), zap.String("event", string(event)), zap.Int64("order_id", orderID))
+			return
+		}
+
+		s.deliver(ctx, notification)
+	}()
+}
+
+func (s *Service) createOrderNotification(ctx context.Context, event Event, orderID int64) (dst postgres.Notification, err error) {
+	order, err := s.repository.GetOrder(ctx, orderID)
+	if err != nil {
+		return
+	}
+
+	user, err := s.repository.GetUser(ctx, order.UserID)
+	if err != nil {
+		return
+	}
+
+	items, err := s.repository.ListOrderItemsByOrder(ctx, orderID)
+	if err != nil {
+		return
+	}
+
+	data := OrderData{
+		User:  user,
+		Order: order,
+		Items: make([]OrderItemData, 0, len(items)),
+	}
+	for _, item := range items {
+		product, err := s.repository.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return dst, err
+		}
+		name := product.Name
+		if item.VariantID.Valid {
+			variant, err := s.repository.GetProductVariant(ctx, item.VariantID.Int64)
+			if err != nil {
+				return dst, err
+			}
+			name += " (" + variant.Sku + ")"
+		}
+		data.Items = append(data.Items, OrderItemData{
+			Name:     name,
+			Quantity: item.Quantity,
+			Price:    item.Price,
+		})
+	}
+
+	if data.Shipments, err = s.repository.ListShipmentsByOrder(ctx, orderID); err != nil {
+		return
+	}
+
+	email, err := s.render(event, user.Locale, data)
+	if err != nil {
+		return
+	}
+
+	return s.repository.CreateNotification(ctx, postgres.CreateNotificationParams{
+		UserID:    user.ID,
+		OrderID:   sql.NullInt64{Int64: order.ID, Valid: true},
+		Event:     string(event),
+		Recipient: user.Email,
+		Locale:    email.Locale,
+		Subject:   email.Subject,
+		BodyText:  email.Text,
+		BodyHtml:  email.HTML,
+		// The first attempt is made right away; the worker only picks the notification up if it fails
+		NextAttemptAt: time.Now().Add(s.retryDelay),
+	})
+}
@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"ecommerce_management/internal/provider/mail"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+const (
+	defaultLocale      = "ru"
+	defaultMaxAttempts = 5
+	defaultRetryDelay  = time.Minute
+	defaultPollPeriod  = 30 * time.Second
+	defaultBatchSize   = 50
+	defaultLease       = 10 * time.Minute
+)
+
+// Repository is the subset of queries the Service needs to render and record notifications
+type Repository interface {
+	GetUser(ctx context.Context, id int64) (postgres.User, error)
+	GetOrder(ctx context.Context, id int64) (postgres.Order, error)
+	GetProduct(ctx context.Context, id int64) (postgres.Product, error)
+	GetProductVariant(ctx context.Context, id int64) (postgres.ProductVariant, error)
+	ListOrderItemsByOrder(ctx context.Context, orderID int64) ([]postgres.OrderItem, error)
+	ListShipmentsByOrder(ctx context.Context, orderID int64) ([]postgres.Shipment, error)
+	CreateNotification(ctx context.Context, arg postgres.CreateNotificationParams) (postgres.Notification, error)
+	ClaimDueNotifications(ctx context.Context, arg postgres.ClaimDueNotificationsParams) ([]postgres.Notification, error)
+	MarkNotificationSent(ctx context.Context, id int64) error
+	MarkNotificationFailed(ctx context.Context, arg postgres.MarkNotificationFailedParams) error
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service renders transactional emails, records them and delivers them with retries
+type Service struct {
+	repository  Repository
+	mailer      mail.Sender
+	locale      string
+	maxAttempts int
+	retryDelay  time.Duration
+	pollPeriod  time.Duration
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		locale:      defaultLocale,
+		maxAttempts: defaultMaxAttempts,
+		retryDelay:  defaultRetryDelay,
+		pollPeriod:  defaultPollPeriod,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithRepository applies a given repository to the Service
+func WithRepository(repository Repository) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithMailer applies a given mail sender to the Service
+func WithMailer(mailer mail.Sender) Configuration {
+	return func(s *Service) error {
+		s.mailer = mailer
+		return nil
+	}
+}
+
+// WithRetryPolicy sets how many delivery attempts are made and the initial delay between them
+func WithRetryPolicy(maxAttempts int, retryDelay time.Duration) Configuration {
+	return func(s *Service) error {
+		s.maxAttempts = maxAttempts
+		s.retryDelay = retryDelay
+		return nil
+	}
+}
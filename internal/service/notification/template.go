@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"io/fs"
+	"text/template"
+)
+
+//go:embed template
+var templates embed.FS
+
+var (
+	ErrUnknownEvent = errors.New("unknown notification event")
+)
+
+// rendered is a localized email ready to be recorded and sent
+type rendered struct {
+	Locale  string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// render executes the text and HTML templates of event in locale, falling back to the default locale
+func (s *Service) render(event Event, locale string, data any) (dst rendered, err error) {
+	if !hasTemplate(locale, event) {
+		locale = s.locale
+	}
+	if !hasTemplate(locale, event) {
+		err = ErrUnknownEvent
+		return
+	}
+	dst.Locale = locale
+
+	name := string(event)
+
+	text, err := template.ParseFS(templates, "template/"+locale+"/"+name+".txt")
+	if err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return
+	}
+	dst.Subject = buf.String()
+
+	buf.Reset()
+	if err = text.ExecuteTemplate(&buf, name+".txt", data); err != nil {
+		return
+	}
+	dst.Text = buf.String()
+
+	html, err := htmltemplate.ParseFS(templates, "template/layout.html", "template/"+locale+"/"+name+".html")
+	if err != nil {
+		return
+	}
+
+	buf.Reset()
+	if err = html.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return
+	}
+	dst.HTML = buf.String()
+
+	return
+}
+
+func hasTemplate(locale string, event Event) bool {
+	_, err := fs.Stat(templates, "template/"+locale+"/"+string(event)+".txt")
+	return err == nil
+}
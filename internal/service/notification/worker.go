@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/provider/mail"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/pkg/log"
+)
+
+// Start retries pending notifications until ctx is cancelled.
+// Each due notification is claimed by one worker, so several instances can run it side by side.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.pollPeriod)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.retryDue(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Service) retryDue(ctx context.Context) {
+	logger := log.LoggerFromContext(ctx).Named("retryDue")
+
+	notifications, err := s.repository.ClaimDueNotifications(ctx, postgres.ClaimDueNotificationsParams{
+		NextAttemptAt: time.Now().Add(defaultLease),
+		Limit:         defaultBatchSize,
+	})
+	if err != nil {
+		logger.Error("failed to claim due notifications", zap.Error(err))
+		return
+	}
+
+	for _, notification := range notifications {
+		s.deliver(ctx, notification)
+	}
+}
+
+// deliver sends a recorded notification and stores the outcome, scheduling a retry with exponential backoff on failure
+func (s *Service) deliver(ctx context.Context, notification postgres.Notification) {
+	logger := log.LoggerFromContext(ctx).Named("deliver")
+
+	err := s.mailer.Send(ctx, mail.Message{
+		To:      []string{notification.Recipient},
+		Subject: notification.Subject,
+		Text:    notification.BodyText,
+		HTML:    notification.BodyHtml,
+	})
+	if err == nil {
+		if err = s.repository.MarkNotificationSent(ctx, notification.ID); err != nil {
+			logger.Error("failed to mark notification as sent", zap.Error(err), zap.Int64("id", notification.ID))
+		}
+		return
+	}
+
+	logger.Warn("failed to send notification", zap.Error(err), zap.Int64("id", notification.ID), zap.Int32("attempts", notification.Attempts+1))
+
+	status := postgres.NotificationStatusPending
+	if int(notification.Attempts)+1 >= s.maxAttempts {
+		status = postgres.NotificationStatusFailed
+	}
+
+	err = s.repository.MarkNotificationFailed(ctx, postgres.MarkNotificationFailedParams{
+		ID:            notification.ID,
+		Status:        status,
+		LastError:     sql.NullString{String: err.Error(), Valid: true},
+		NextAttemptAt: time.Now().Add(s.retryDelay << notification.Attempts),
+	})
+	if err != nil {
+		logger.Error("failed to mark notification as failed", zap.Error(err), zap.Int64("id", notification.ID))
+	}
+}
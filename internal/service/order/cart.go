@@ -0,0 +1,129 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	domain "ecommerce_management/internal/domain/order"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Cart returns the items in the cart of a user
+func (s *Service) Cart(ctx context.Context, userID int64) ([]postgres.CartItem, error) {
+	return s.repository.ListCartItems(ctx, userID)
+}
+
+// AddToCart puts an item in the cart of a user; adding a product already in the cart increases its quantity.
+// Stock is only reserved at checkout.
+func (s *Service) AddToCart(ctx context.Context, userID int64, item domain.OrderItem) (data postgres.CartItem, err error) {
+	if item.Quantity <= 0 {
+		return data, fmt.Errorf("%w: invalid quantity for product ID %d", ErrInvalidItem, item.ProductID)
+	}
+
+	if err = s.checkCartItem(ctx, item); err != nil {
+		return
+	}
+
+	return s.repository.AddCartItem(ctx, postgres.AddCartItemParams{
+		UserID:    userID,
+		ProductID: item.ProductID,
+		VariantID: nullInt64(item.VariantID),
+		Quantity:  item.Quantity,
+	})
+}
+
+// UpdateCartItem changes the quantity of an item in the cart of a user
+func (s *Service) UpdateCartItem(ctx context.Context, userID, id int64, quantity int32) (data postgres.CartItem, err error) {
+	if quantity <= 0 {
+		return data, fmt.Errorf("%w: invalid quantity for cart item ID %d", ErrInvalidItem, id)
+	}
+
+	data, err = s.repository.UpdateCartItemQuantity(ctx, postgres.UpdateCartItemQuantityParams{
+		ID:       id,
+		UserID:   userID,
+		Quantity: quantity,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: cart item ID %d", ErrNotFound, id)
+	}
+	return
+}
+
+// RemoveFromCart takes an item out of the cart of a user
+func (s *Service) RemoveFromCart(ctx context.Context, userID, id int64) error {
+	removed, err := s.repository.DeleteCartItem(ctx, postgres.DeleteCartItemParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("%w: cart item ID %d", ErrNotFound, id)
+	}
+	return nil
+}
+
+// ClearCart empties the cart of a user
+func (s *Service) ClearCart(ctx context.Context, userID int64) error {
+	return s.repository.ClearCart(ctx, userID)
+}
+
+// Checkout places an order for the items in the cart of req.UserID, ignoring req.Items, and empties the cart.
+// The cart is emptied in the transaction of the order, so it is kept when the order cannot be placed.
+func (s *Service) Checkout(ctx context.Context, req domain.CreateOrderRequest) (postgres.Order, error) {
+	return s.place(ctx, req, func(ctx context.Context, tx repository.Tx) ([]domain.OrderItem, error) {
+		cart, err := tx.ListCartItems(ctx, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(cart) == 0 {
+			return nil, fmt.Errorf("%w: the cart of user ID %d is empty", ErrInvalidItem, req.UserID)
+		}
+
+		items := make([]domain.OrderItem, 0, len(cart))
+		for _, item := range cart {
+			next := domain.OrderItem{ProductID: item.ProductID, Quantity: item.Quantity}
+			if item.VariantID.Valid {
+				next.VariantID = &item.VariantID.Int64
+			}
+			items = append(items, next)
+		}
+
+		return items, tx.ClearCart(ctx, req.UserID)
+	})
+}
+
+// checkCartItem verifies that an item can be ordered as it is requested, without reserving its stock
+func (s *Service) checkCartItem(ctx context.Context, item domain.OrderItem) error {
+	product, err := s.repository.GetProduct(ctx, item.ProductID)
+	if err == nil && product.DeletedAt.Valid {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: product ID %d not found", ErrInvalidItem, item.ProductID)
+		}
+		return err
+	}
+
+	if item.VariantID == nil {
+		variants, err := s.repository.CountProductVariantsByProduct(ctx, item.ProductID)
+		if err != nil {
+			return err
+		}
+		if variants > 0 {
+			return fmt.Errorf("%w: product ID %d requires a variant_id", ErrInvalidItem, item.ProductID)
+		}
+		return nil
+	}
+
+	variant, err := s.repository.GetProductVariant(ctx, *item.VariantID)
+	if err != nil || variant.ProductID != item.ProductID {
+		if err == nil || errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: variant ID %d not found for product ID %d", ErrInvalidItem, *item.VariantID, item.ProductID)
+		}
+		return err
+	}
+	return nil
+}
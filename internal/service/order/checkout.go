@@ -0,0 +1,294 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	domain "ecommerce_management/internal/domain/order"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/shipping"
+	"ecommerce_management/internal/service/webhook"
+)
+
+// Create places an order: it reserves the stock of every item, prices the items with the coupon,
+// automatic promotions, shipping method and tax of the destination, and copies the checkout addresses.
+// Nothing is stored when any item cannot be ordered.
+func (s *Service) Create(ctx context.Context, req domain.CreateOrderRequest) (postgres.Order, error) {
+	return s.place(ctx, req, func(ctx context.Context, tx repository.Tx) ([]domain.OrderItem, error) {
+		return req.Items, nil
+	})
+}
+
+// place creates the order of req with the items returned by items, which runs inside the transaction of the order
+func (s *Service) place(ctx context.Context, req domain.CreateOrderRequest, items func(ctx context.Context, tx repository.Tx) ([]domain.OrderItem, error)) (data postgres.Order, err error) {
+	shippingAddress, billingAddress, err := s.checkoutAddresses(ctx, req)
+	if err != nil {
+		return
+	}
+
+	// Tax follows the country of the shipping address unless the request names one
+	destination := req.Country
+	if destination == "" && shippingAddress != nil {
+		destination = shippingAddress.Country
+	}
+
+	country, err := s.taxes.Destination(destination)
+	if err != nil {
+		return
+	}
+
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // re-throw panic after Rollback
+		} else if err != nil {
+			tx.Rollback() // err is non-nil; don't change it
+		}
+	}()
+
+	// Resolve the shipping method first so that the order records it
+	var method *postgres.ShippingMethod
+	if req.ShippingMethodID != nil {
+		stored, err := tx.GetShippingMethod(ctx, *req.ShippingMethodID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w: shipping method ID %d not found", shipping.ErrNotAvailable, *req.ShippingMethodID)
+			}
+			return data, err
+		}
+		method = &stored
+	}
+
+	// Create the order first with a placeholder total amount ("0.00")
+	data, err = tx.CreateOrder(ctx, postgres.CreateOrderParams{
+		UserID:             req.UserID,
+		TotalAmount:        "0.00", // Dummy value, will be updated later
+		PricesIncludeTax:   s.taxes.PricesIncludeTax(),
+		DestinationCountry: country,
+		ShippingMethodID:   nullInt64(req.ShippingMethodID),
+	})
+	if err != nil {
+		return
+	}
+
+	// Keep a copy of the addresses so that later edits of the user's addresses do not change the order
+	if err = snapshotAddresses(ctx, tx, data.ID, shippingAddress, billingAddress); err != nil {
+		return
+	}
+
+	ordered, err := items(ctx, tx)
+	if err != nil {
+		return
+	}
+
+	lines := make([]line, 0, len(ordered))
+	for _, item := range ordered {
+		// Resolve the unit price and reserve stock on the variant, or on the product when it has none
+		var next line
+		if next, err = reserveLine(ctx, tx, data.ID, item); err != nil {
+			return
+		}
+		lines = append(lines, next)
+	}
+
+	// Price the items, storing each with its tax and the order with its final amounts
+	if data, err = s.settle(ctx, tx, data, method, req.CouponCode, lines); err != nil {
+		return
+	}
+
+	// Commit before reading back so that notifications and webhooks see the order
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	// Re-fetch the updated order to return the correct total amount
+	if data, err = s.repository.GetOrder(ctx, data.ID); err != nil {
+		return
+	}
+
+	s.record(ctx, audit.ActionCreate, data.ID, nil, data)
+	s.notify(ctx, notification.EventOrderCreated, data.ID)
+	s.publish(ctx, webhook.EventOrderCreated, data)
+
+	return
+}
+
+// checkoutAddresses resolves the addresses an order is placed with, defaulting to the user's default addresses.
+// The billing address falls back to the shipping address; either is nil when the user has none.
+func (s *Service) checkoutAddresses(ctx context.Context, req domain.CreateOrderRequest) (shippingAddress, billingAddress *postgres.UserAddress, err error) {
+	if shippingAddress, err = s.checkoutAddress(ctx, req.UserID, req.ShippingAddressID, postgres.AddressTypeShipping); err != nil {
+		return
+	}
+	if shippingAddress != nil && shippingAddress.Type != postgres.AddressTypeShipping {
+		err = fmt.Errorf("%w: address ID %d is not a shipping address", ErrInvalidAddress, shippingAddress.ID)
+		return
+	}
+
+	if billingAddress, err = s.checkoutAddress(ctx, req.UserID, req.BillingAddressID, postgres.AddressTypeBilling); err != nil {
+		return
+	}
+	if billingAddress == nil {
+		billingAddress = shippingAddress
+	}
+
+	if req.ShippingMethodID != nil && shippingAddress == nil {
+		err = fmt.Errorf("%w: a shipping address is required with a shipping method", ErrInvalidAddress)
+	}
+
+	return
+}
+
+// checkoutAddress returns the address of the user with the given ID, or the user's default of the type when id is nil
+func (s *Service) checkoutAddress(ctx context.Context, userID int64, id *int64, addressType postgres.AddressType) (*postgres.UserAddress, error) {
+	var data postgres.UserAddress
+	var err error
+	if id != nil {
+		data, err = s.repository.GetUserAddress(ctx, postgres.GetUserAddressParams{ID: *id, UserID: userID})
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: address ID %d not found for user ID %d", ErrInvalidAddress, *id, userID)
+		}
+	} else {
+		data, err = s.repository.GetDefaultUserAddress(ctx, postgres.GetDefaultUserAddressParams{UserID: userID, Type: addressType})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// snapshotAddresses copies the checkout addresses onto an order
+func snapshotAddresses(ctx context.Context, tx repository.Tx, orderID int64, shippingAddress, billingAddress *postgres.UserAddress) error {
+	snapshots := []struct {
+		addressType postgres.AddressType
+		address     *postgres.UserAddress
+	}{
+		{postgres.AddressTypeShipping, shippingAddress},
+		{postgres.AddressTypeBilling, billingAddress},
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.address == nil {
+			continue
+		}
+
+		_, err := tx.CreateOrderAddress(ctx, postgres.CreateOrderAddressParams{
+			OrderID:   orderID,
+			Type:      snapshot.addressType,
+			AddressID: snapshot.address.ID,
+		})
+		if err != nil {
+			// The address was deleted since it was resolved
+			if errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w: address ID %d not found", ErrInvalidAddress, snapshot.address.ID)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reserveLine reserves the stock of a requested item and resolves its unit price
+func reserveLine(ctx context.Context, tx repository.Tx, orderID int64, item domain.OrderItem) (line, error) {
+	if item.Quantity <= 0 {
+		return line{}, fmt.Errorf("%w: invalid quantity for product ID %d", ErrInvalidItem, item.ProductID)
+	}
+
+	product, unitPrice, variantID, err := reserve(ctx, tx, item)
+	if err != nil {
+		return line{}, err
+	}
+
+	price, err := strconv.ParseFloat(unitPrice, 64)
+	if err != nil {
+		return line{}, fmt.Errorf("invalid product price format: %v", err)
+	}
+
+	return line{
+		item: postgres.OrderItem{
+			OrderID:   orderID,
+			ProductID: item.ProductID,
+			VariantID: variantID,
+			Quantity:  item.Quantity,
+		},
+		product:   product,
+		unitPrice: price,
+	}, nil
+}
+
+// reserve decrements stock for an order item and returns the product with the unit price.
+// Products with variants must be ordered by variant, whose price overrides the product price when set.
+func reserve(ctx context.Context, tx repository.Tx, item domain.OrderItem) (product postgres.Product, price string, variantID sql.NullInt64, err error) {
+	product, err = tx.GetProduct(ctx, item.ProductID)
+	if err == nil && product.DeletedAt.Valid {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: product ID %d not found", ErrInvalidItem, item.ProductID)
+		}
+		return
+	}
+
+	if item.VariantID == nil {
+		variants, err := tx.CountProductVariantsByProduct(ctx, item.ProductID)
+		if err != nil {
+			return product, "", variantID, err
+		}
+		if variants > 0 {
+			return product, "", variantID, fmt.Errorf("%w: product ID %d requires a variant_id", ErrInvalidItem, item.ProductID)
+		}
+
+		_, err = tx.ReserveProductStock(ctx, postgres.ReserveProductStockParams{
+			Quantity: item.Quantity,
+			ID:       item.ProductID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w for product ID %d", ErrInsufficientStock, item.ProductID)
+		}
+		return product, product.Price, variantID, err
+	}
+
+	variant, err := tx.GetProductVariant(ctx, *item.VariantID)
+	if err != nil || variant.ProductID != item.ProductID {
+		if err == nil || errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: variant ID %d not found for product ID %d", ErrInvalidItem, *item.VariantID, item.ProductID)
+		}
+		return
+	}
+
+	price = variant.Price.String
+	if !variant.Price.Valid {
+		price = product.Price
+	}
+
+	_, err = tx.ReserveProductVariantStock(ctx, postgres.ReserveProductVariantStockParams{
+		Quantity: item.Quantity,
+		ID:       variant.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w for variant %s", ErrInsufficientStock, variant.Sku)
+	}
+	return product, price, sql.NullInt64{Int64: variant.ID, Valid: true}, err
+}
+
+func nullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
@@ -0,0 +1,208 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	domain "ecommerce_management/internal/domain/order"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/webhook"
+)
+
+// AddItem reserves the stock of a new item of an order and prices the order again.
+// Only new and processing orders without a payment can be changed; others fail with ErrClosed.
+func (s *Service) AddItem(ctx context.Context, id int64, item domain.OrderItem) (postgres.Order, error) {
+	return s.changeItems(ctx, id, func(tx repository.Tx, data postgres.Order, lines []line) ([]line, error) {
+		next, err := reserveLine(ctx, tx, data.ID, item)
+		if err != nil {
+			return nil, err
+		}
+		return append(lines, next), nil
+	})
+}
+
+// UpdateItem changes the quantity of an order item, taking or returning the difference in stock,
+// and prices the order again; the item keeps the unit price it was ordered at
+func (s *Service) UpdateItem(ctx context.Context, id, itemID int64, quantity int32) (postgres.Order, error) {
+	if quantity <= 0 {
+		return postgres.Order{}, fmt.Errorf("%w: quantity must be positive; delete the item to remove it", ErrInvalidItem)
+	}
+
+	return s.changeItems(ctx, id, func(tx repository.Tx, data postgres.Order, lines []line) ([]line, error) {
+		i := findLine(lines, itemID)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: order item ID %d in order ID %d", ErrNotFound, itemID, data.ID)
+		}
+
+		if err := adjustStock(ctx, tx, lines[i].item, quantity-lines[i].item.Quantity); err != nil {
+			return nil, err
+		}
+		lines[i].item.Quantity = quantity
+
+		return lines, nil
+	})
+}
+
+// DeleteItem removes an item from an order, releasing its stock, and prices the order again.
+// The last item cannot be removed; the order is deleted instead.
+func (s *Service) DeleteItem(ctx context.Context, id, itemID int64) (postgres.Order, error) {
+	return s.changeItems(ctx, id, func(tx repository.Tx, data postgres.Order, lines []line) ([]line, error) {
+		i := findLine(lines, itemID)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: order item ID %d in order ID %d", ErrNotFound, itemID, data.ID)
+		}
+		if len(lines) == 1 {
+			return nil, fmt.Errorf("%w: an order needs at least one item; delete the order instead", ErrInvalidItem)
+		}
+
+		if err := adjustStock(ctx, tx, lines[i].item, -lines[i].item.Quantity); err != nil {
+			return nil, err
+		}
+		if err := tx.DeleteOrderItem(ctx, itemID); err != nil {
+			return nil, err
+		}
+
+		return append(lines[:i], lines[i+1:]...), nil
+	})
+}
+
+// changeItems locks an open order, lets change return its new lines and prices the order again
+func (s *Service) changeItems(ctx context.Context, id int64, change func(tx repository.Tx, data postgres.Order, lines []line) ([]line, error)) (data postgres.Order, err error) {
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	if data, err = openOrder(ctx, tx, id); err != nil {
+		return
+	}
+	previous := data
+
+	lines, err := storedLines(ctx, tx, data.ID)
+	if err != nil {
+		return
+	}
+
+	if lines, err = change(tx, data, lines); err != nil {
+		return
+	}
+
+	if data, err = s.reprice(ctx, tx, data, lines); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	s.record(ctx, audit.ActionUpdate, data.ID, previous, data)
+	s.publish(ctx, webhook.EventOrderUpdated, data)
+
+	return
+}
+
+// openOrder locks an order for changing its items, which is only allowed
+// while it is new or processing and has not been paid
+func openOrder(ctx context.Context, tx repository.Tx, id int64) (postgres.Order, error) {
+	data, err := tx.GetOrderForUpdate(ctx, id)
+	if err == nil && data.DeletedAt.Valid {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: order ID %d", ErrNotFound, id)
+		}
+		return data, err
+	}
+
+	if data.Status != postgres.OrderStatusNew && data.Status != postgres.OrderStatusProcessing {
+		return data, fmt.Errorf("%w: order ID %d is %s", ErrClosed, data.ID, data.Status)
+	}
+
+	paid, err := tx.CountPaidPayments(ctx, data.ID)
+	if err != nil {
+		return data, err
+	}
+	if paid > 0 {
+		return data, fmt.Errorf("%w: order ID %d has been paid", ErrClosed, data.ID)
+	}
+
+	return data, nil
+}
+
+// storedLines loads the items of an order for pricing it again; each keeps the unit price it was ordered at
+func storedLines(ctx context.Context, tx repository.Tx, orderID int64) ([]line, error) {
+	items, err := tx.ListOrderItemsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]line, 0, len(items))
+	for _, item := range items {
+		product, err := tx.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		price, err := strconv.ParseFloat(item.Price, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, line{
+			item:      item,
+			product:   product,
+			unitPrice: math.Round(price/float64(item.Quantity)*100) / 100,
+		})
+	}
+
+	return lines, nil
+}
+
+// findLine returns the index of the line of an order item, or -1
+func findLine(lines []line, itemID int64) int {
+	for i, line := range lines {
+		if line.item.ID == itemID {
+			return i
+		}
+	}
+	return -1
+}
+
+// adjustStock takes delta more units of an order item from stock, or puts them back when delta is negative
+func adjustStock(ctx context.Context, tx repository.Tx, item postgres.OrderItem, delta int32) (err error) {
+	switch {
+	case delta > 0 && item.VariantID.Valid:
+		_, err = tx.ReserveProductVariantStock(ctx, postgres.ReserveProductVariantStockParams{
+			Quantity: delta,
+			ID:       item.VariantID.Int64,
+		})
+	case delta > 0:
+		_, err = tx.ReserveProductStock(ctx, postgres.ReserveProductStockParams{
+			Quantity: delta,
+			ID:       item.ProductID,
+		})
+	case delta < 0 && item.VariantID.Valid:
+		_, err = tx.RestockProductVariant(ctx, postgres.RestockProductVariantParams{
+			Quantity: -delta,
+			ID:       item.VariantID.Int64,
+		})
+	case delta < 0:
+		_, err = tx.RestockProduct(ctx, postgres.RestockProductParams{
+			Quantity: -delta,
+			ID:       item.ProductID,
+		})
+	}
+
+	if delta > 0 && errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w for product ID %d", ErrInsufficientStock, item.ProductID)
+	}
+	return
+}
@@ -0,0 +1,192 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	domain "ecommerce_management/internal/domain/order"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/webhook"
+)
+
+// List returns a page of orders
+func (s *Service) List(ctx context.Context, arg postgres.ListOrdersPageParams) (postgres.Page[postgres.Order], error) {
+	return s.repository.ListOrdersPage(ctx, arg)
+}
+
+// Get returns an order; deleted orders are only returned when includeDeleted is set
+func (s *Service) Get(ctx context.Context, id int64, includeDeleted bool) (data postgres.Order, err error) {
+	data, err = s.repository.GetOrder(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: order ID %d", ErrNotFound, id)
+		}
+		return
+	}
+
+	if data.DeletedAt.Valid && !includeDeleted {
+		return data, fmt.Errorf("%w: order ID %d has been deleted", ErrNotFound, id)
+	}
+	return
+}
+
+// Update replaces the fields of previous, as read with Get, by those of arg.
+// It fails with ErrVersionChanged when the order has been changed since it was read.
+func (s *Service) Update(ctx context.Context, previous postgres.Order, arg postgres.UpdateOrderParams) (data postgres.Order, err error) {
+	arg.ID = previous.ID
+	arg.Version = previous.Version
+
+	data, err = s.repository.UpdateOrder(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: order ID %d", ErrVersionChanged, previous.ID)
+		}
+		return
+	}
+
+	if previous.Status != postgres.OrderStatusShipped && data.Status == postgres.OrderStatusShipped {
+		s.notify(ctx, notification.EventOrderShipped, data.ID)
+	}
+	if previous.Status != postgres.OrderStatusDelivered && data.Status == postgres.OrderStatusDelivered {
+		s.notify(ctx, notification.EventOrderDelivered, data.ID)
+	}
+	s.record(ctx, audit.ActionUpdate, data.ID, previous, data)
+	s.publish(ctx, webhook.EventOrderUpdated, data)
+
+	return
+}
+
+// Delete hides an order, keeping it to be restored until the retention period has passed
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	data, err := s.repository.DeleteOrder(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: order ID %d", ErrNotFound, id)
+		}
+		return err
+	}
+
+	s.record(ctx, audit.ActionDelete, id, data, nil)
+	s.publish(ctx, webhook.EventOrderDeleted, map[string]int64{"id": id})
+
+	return nil
+}
+
+// Restore brings back a deleted order; restoring an order that is not deleted changes nothing
+func (s *Service) Restore(ctx context.Context, id int64) (data postgres.Order, err error) {
+	previous, err := s.Get(ctx, id, true)
+	if err != nil || !previous.DeletedAt.Valid {
+		return previous, err
+	}
+
+	data, err = s.repository.RestoreOrder(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Restored or purged since it was read
+			err = fmt.Errorf("%w: order ID %d", ErrConflict, id)
+		}
+		return
+	}
+
+	s.record(ctx, audit.ActionRestore, id, previous, data)
+	s.publish(ctx, webhook.EventOrderRestored, data)
+
+	return
+}
+
+// SearchByUser returns every order of a user
+func (s *Service) SearchByUser(ctx context.Context, userID int64) ([]postgres.Order, error) {
+	return s.repository.SearchOrdersByUser(ctx, userID)
+}
+
+// SearchByStatus returns every order with the given status
+func (s *Service) SearchByStatus(ctx context.Context, status postgres.OrderStatus) ([]postgres.Order, error) {
+	return s.repository.SearchOrdersByStatus(ctx, status)
+}
+
+// ListItems returns a page of the items of orders
+func (s *Service) ListItems(ctx context.Context, arg postgres.ListOrderItemsPageParams) (postgres.Page[postgres.OrderItem], error) {
+	return s.repository.ListOrderItemsPage(ctx, arg)
+}
+
+// Adjustments returns the discounts, tax and other lines that explain the total of an order
+func (s *Service) Adjustments(ctx context.Context, id int64) ([]domain.Adjustment, error) {
+	adjustments, err := s.repository.ListOrderAdjustmentsByOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ParseAdjustments(adjustments), nil
+}
+
+// Addresses returns the shipping and billing addresses as they were when the order was placed
+func (s *Service) Addresses(ctx context.Context, id int64) ([]postgres.OrderAddress, error) {
+	return s.repository.ListOrderAddresses(ctx, id)
+}
+
+// Invoice lists the addresses of an order, every line with its tax, the totals per rate and the order totals
+func (s *Service) Invoice(ctx context.Context, id int64) (dst domain.Invoice, err error) {
+	data, err := s.Get(ctx, id, true)
+	if err != nil {
+		return
+	}
+
+	user, err := s.repository.GetUser(ctx, data.UserID)
+	if err != nil {
+		return
+	}
+
+	items, err := s.repository.ListOrderItemsByOrder(ctx, id)
+	if err != nil {
+		return
+	}
+
+	lines := make([]domain.InvoiceLine, 0, len(items))
+	for _, item := range items {
+		product, err := s.repository.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return dst, err
+		}
+
+		line := domain.InvoiceLine{
+			ProductID:   item.ProductID,
+			Sku:         product.Sku,
+			Name:        product.Name,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+			TaxRate:     item.TaxRate,
+			NetAmount:   item.NetAmount,
+			TaxAmount:   item.TaxAmount,
+			GrossAmount: item.GrossAmount,
+		}
+		if item.VariantID.Valid {
+			variant, err := s.repository.GetProductVariant(ctx, item.VariantID.Int64)
+			if err != nil {
+				return dst, err
+			}
+			line.VariantID = &variant.ID
+			line.Sku = variant.Sku
+		}
+		lines = append(lines, line)
+	}
+
+	adjustments, err := s.repository.ListOrderAdjustmentsByOrder(ctx, id)
+	if err != nil {
+		return
+	}
+
+	taxes, err := s.repository.SummarizeOrderItemTaxes(ctx, id)
+	if err != nil {
+		return
+	}
+
+	addresses, err := s.repository.ListOrderAddresses(ctx, id)
+	if err != nil {
+		return
+	}
+
+	return domain.ParseInvoice(data, user, addresses, lines, adjustments, taxes), nil
+}
@@ -0,0 +1,164 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/promotion"
+	"ecommerce_management/internal/service/shipping"
+	"ecommerce_management/internal/service/tax"
+)
+
+// line is an order item being priced; the item ID is zero until it is stored
+type line struct {
+	item      postgres.OrderItem
+	product   postgres.Product
+	unitPrice float64
+}
+
+// reprice prices an order again after its items changed. The promotion uses and adjustments of the
+// previous price are released first, and the coupon of the order is applied again.
+func (s *Service) reprice(ctx context.Context, tx repository.Tx, data postgres.Order, lines []line) (postgres.Order, error) {
+	adjustments, err := tx.ListOrderAdjustmentsByOrder(ctx, data.ID)
+	if err != nil {
+		return data, err
+	}
+
+	var code string
+	for _, adjustment := range adjustments {
+		if adjustment.Kind == promotion.KindDiscount && adjustment.Code.Valid {
+			code = adjustment.Code.String
+		}
+	}
+
+	if err = tx.ReleaseOrderPromotions(ctx, data.ID); err != nil {
+		return data, err
+	}
+	if err = tx.DeletePromotionRedemptionsByOrder(ctx, data.ID); err != nil {
+		return data, err
+	}
+	if err = tx.DeleteOrderAdjustmentsByOrder(ctx, data.ID); err != nil {
+		return data, err
+	}
+
+	var method *postgres.ShippingMethod
+	if data.ShippingMethodID.Valid {
+		stored, err := tx.GetShippingMethod(ctx, data.ShippingMethodID.Int64)
+		if err != nil {
+			return data, err
+		}
+		method = &stored
+	}
+
+	return s.settle(ctx, tx, data, method, code, lines)
+}
+
+// settle prices the lines of an order with its promotions, shipping and tax, records the adjustments,
+// stores every line with its tax and sets the order totals. Coupons that do not apply and shipping methods
+// that cannot carry the order fail with the errors of the promotion and shipping services.
+func (s *Service) settle(ctx context.Context, tx repository.Tx, data postgres.Order, method *postgres.ShippingMethod, code string, lines []line) (postgres.Order, error) {
+	promotionLines := make([]promotion.Line, 0, len(lines))
+	taxLines := make([]tax.Line, 0, len(lines))
+	var weight int64
+	for _, line := range lines {
+		amount := math.Round(line.unitPrice*float64(line.item.Quantity)*100) / 100
+		weight += int64(line.product.WeightGrams) * int64(line.item.Quantity)
+
+		promotionLines = append(promotionLines, promotion.Line{
+			ProductID:  line.item.ProductID,
+			CategoryID: line.product.CategoryID,
+			UnitPrice:  line.unitPrice,
+			Quantity:   line.item.Quantity,
+		})
+		taxLines = append(taxLines, tax.Line{
+			TaxClass: line.product.TaxClass,
+			Amount:   amount,
+		})
+	}
+
+	// Apply automatic promotions and the coupon code, recording each discount as an adjustment of the order.
+	// Shipping is quoted on the discounted subtotal, and a free shipping promotion waives it
+	priced, err := s.promotions.Price(ctx, tx, data.UserID, code, promotionLines)
+	if err != nil {
+		return data, err
+	}
+
+	var quote shipping.Quote
+	if method != nil {
+		if quote, err = s.shipping.Quote(ctx, tx, *method, priced.Total, weight); err != nil {
+			return data, err
+		}
+		if priced.FreeShipping {
+			priced.WaiveShipping(quote.Amount)
+		}
+	}
+
+	if _, err = s.promotions.Redeem(ctx, tx, data.ID, data.UserID, priced); err != nil {
+		return data, err
+	}
+
+	// Tax every line after its share of the discounts; tax on top of net prices is recorded as an adjustment
+	taxed, err := s.taxes.Recalculate(ctx, tx, data, taxLines, priced.Discount())
+	if err != nil {
+		return data, err
+	}
+	if _, err = s.taxes.Record(ctx, tx, data.ID, taxed); err != nil {
+		return data, err
+	}
+	if method != nil {
+		if _, err = s.shipping.Record(ctx, tx, data.ID, quote); err != nil {
+			return data, err
+		}
+	}
+
+	for i, line := range lines {
+		amounts := taxed.Lines[i]
+		price := fmt.Sprintf("%.2f", line.unitPrice*float64(line.item.Quantity))
+		rate := strconv.FormatFloat(amounts.Rate, 'f', 4, 64)
+
+		if line.item.ID == 0 {
+			_, err = tx.CreateOrderItem(ctx, postgres.CreateOrderItemParams{
+				OrderID:     data.ID,
+				ProductID:   line.item.ProductID,
+				Quantity:    line.item.Quantity,
+				Price:       price,
+				VariantID:   line.item.VariantID,
+				TaxRate:     rate,
+				NetAmount:   fmt.Sprintf("%.2f", amounts.Net),
+				TaxAmount:   fmt.Sprintf("%.2f", amounts.Tax),
+				GrossAmount: fmt.Sprintf("%.2f", amounts.Gross),
+			})
+		} else {
+			_, err = tx.SetOrderItemAmounts(ctx, postgres.SetOrderItemAmountsParams{
+				ID:          line.item.ID,
+				Quantity:    line.item.Quantity,
+				Price:       price,
+				TaxRate:     rate,
+				NetAmount:   fmt.Sprintf("%.2f", amounts.Net),
+				TaxAmount:   fmt.Sprintf("%.2f", amounts.Tax),
+				GrossAmount: fmt.Sprintf("%.2f", amounts.Gross),
+			})
+		}
+		if err != nil {
+			return data, err
+		}
+	}
+
+	// Shipping is not taxed, so it adds to both the net amount and the total
+	shippingAmount := quote.Amount
+	if priced.FreeShipping {
+		shippingAmount = 0
+	}
+
+	return tx.SetOrderTotals(ctx, postgres.SetOrderTotalsParams{
+		ID:             data.ID,
+		NetAmount:      fmt.Sprintf("%.2f", taxed.Net+shippingAmount),
+		TaxAmount:      fmt.Sprintf("%.2f", taxed.Tax),
+		ShippingAmount: fmt.Sprintf("%.2f", shippingAmount),
+		TotalAmount:    fmt.Sprintf("%.2f", taxed.Gross+shippingAmount),
+	})
+}
@@ -0,0 +1,136 @@
+package order
+
+import (
+	"context"
+	"errors"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/promotion"
+	"ecommerce_management/internal/service/shipping"
+	"ecommerce_management/internal/service/tax"
+)
+
+var (
+	ErrNotFound          = errors.New("order not found")
+	ErrVersionChanged    = errors.New("order has changed since it was read")
+	ErrConflict          = errors.New("order was changed by another request")
+	ErrClosed            = errors.New("order can no longer be changed")
+	ErrInvalidItem       = errors.New("invalid order item")
+	ErrInvalidAddress    = errors.New("invalid order address")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// Recorder records changes to orders in the audit log; *audit.Service implements it
+type Recorder interface {
+	Record(ctx context.Context, action, entityType string, entityID int64, before, after any)
+}
+
+// Publisher publishes order events to webhook subscribers; *webhook.Service implements it
+type Publisher interface {
+	Publish(ctx context.Context, event string, data any)
+}
+
+// Notifier emails customers about their orders; *notification.Service implements it
+type Notifier interface {
+	NotifyOrder(ctx context.Context, event notification.Event, orderID int64)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service places orders, changes their items and prices them with promotions, shipping and tax.
+// Stock is reserved and the order priced inside one transaction of the repository.
+type Service struct {
+	repository    repository.Store
+	promotions    *promotion.Service
+	taxes         *tax.Service
+	shipping      *shipping.Service
+	audit         Recorder
+	webhooks      Publisher
+	notifications Notifier
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+
+	switch {
+	case s.repository == nil:
+		return nil, errors.New("order service requires a repository")
+	case s.promotions == nil || s.taxes == nil || s.shipping == nil:
+		return nil, errors.New("order service requires the promotion, tax and shipping services")
+	}
+	return
+}
+
+// WithRepository applies the store orders are kept in
+func WithRepository(repository repository.Store) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithPricing applies the services that price orders
+func WithPricing(promotions *promotion.Service, taxes *tax.Service, shipping *shipping.Service) Configuration {
+	return func(s *Service) error {
+		s.promotions = promotions
+		s.taxes = taxes
+		s.shipping = shipping
+		return nil
+	}
+}
+
+// WithAudit records every change to an order in the audit log
+func WithAudit(audit Recorder) Configuration {
+	return func(s *Service) error {
+		s.audit = audit
+		return nil
+	}
+}
+
+// WithWebhooks publishes order events to webhook subscribers
+func WithWebhooks(webhooks Publisher) Configuration {
+	return func(s *Service) error {
+		s.webhooks = webhooks
+		return nil
+	}
+}
+
+// WithNotifications emails customers when their orders are placed, shipped and delivered
+func WithNotifications(notifications Notifier) Configuration {
+	return func(s *Service) error {
+		s.notifications = notifications
+		return nil
+	}
+}
+
+func (s *Service) record(ctx context.Context, action string, id int64, before, after any) {
+	if s.audit != nil {
+		s.audit.Record(ctx, action, audit.EntityOrder, id, before, after)
+	}
+}
+
+func (s *Service) publish(ctx context.Context, event string, data any) {
+	if s.webhooks != nil {
+		s.webhooks.Publish(ctx, event, data)
+	}
+}
+
+func (s *Service) notify(ctx context.Context, event notification.Event, id int64) {
+	if s.notifications != nil {
+		s.notifications.NotifyOrder(ctx, event, id)
+	}
+}
@@ -1 +1,110 @@
-package payment
\ No newline at end of file
+package payment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/webhook"
+)
+
+// List returns a page of payments
+func (s *Service) List(ctx context.Context, arg postgres.ListPaymentsPageParams) (postgres.Page[postgres.Payment], error) {
+	return s.repository.ListPaymentsPage(ctx, arg)
+}
+
+// Get returns a payment; deleted payments are only returned when includeDeleted is set
+func (s *Service) Get(ctx context.Context, id int64, includeDeleted bool) (data postgres.Payment, err error) {
+	data, err = s.repository.GetPayment(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: payment ID %d", ErrNotFound, id)
+		}
+		return
+	}
+
+	if data.DeletedAt.Valid && !includeDeleted {
+		return data, fmt.Errorf("%w: payment ID %d has been deleted", ErrNotFound, id)
+	}
+	return
+}
+
+// Update replaces the fields of previous, as read with Get, by those of arg.
+// It fails with ErrVersionChanged when the payment has been changed since it was read.
+func (s *Service) Update(ctx context.Context, previous postgres.Payment, arg postgres.UpdatePaymentParams) (data postgres.Payment, err error) {
+	arg.ID = previous.ID
+	arg.Version = previous.Version
+
+	data, err = s.repository.UpdatePayment(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: payment ID %d", ErrVersionChanged, previous.ID)
+		}
+		return
+	}
+
+	if previous.Status != postgres.PaymentStatusRefunded && data.Status == postgres.PaymentStatusRefunded {
+		s.notify(ctx, notification.EventOrderRefunded, data.OrderID)
+	}
+	s.record(ctx, audit.ActionUpdate, data.ID, previous, data)
+	s.publish(ctx, webhook.EventPaymentUpdated, data)
+
+	return
+}
+
+// Delete hides a payment, keeping it to be restored until the retention period has passed
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	data, err := s.repository.DeletePayment(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: payment ID %d", ErrNotFound, id)
+		}
+		return err
+	}
+
+	s.record(ctx, audit.ActionDelete, id, data, nil)
+	s.publish(ctx, webhook.EventPaymentDeleted, map[string]int64{"id": id})
+
+	return nil
+}
+
+// Restore brings back a deleted payment; restoring a payment that is not deleted changes nothing
+func (s *Service) Restore(ctx context.Context, id int64) (data postgres.Payment, err error) {
+	previous, err := s.Get(ctx, id, true)
+	if err != nil || !previous.DeletedAt.Valid {
+		return previous, err
+	}
+
+	data, err = s.repository.RestorePayment(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Restored or purged since it was read
+			err = fmt.Errorf("%w: payment ID %d", ErrConflict, id)
+		}
+		return
+	}
+
+	s.record(ctx, audit.ActionRestore, id, previous, data)
+	s.publish(ctx, webhook.EventPaymentRestored, data)
+
+	return
+}
+
+// SearchByUser returns every payment of a user
+func (s *Service) SearchByUser(ctx context.Context, userID int64) ([]postgres.Payment, error) {
+	return s.repository.SearchPaymentsByUser(ctx, userID)
+}
+
+// SearchByOrder returns every payment of an order
+func (s *Service) SearchByOrder(ctx context.Context, orderID int64) ([]postgres.Payment, error) {
+	return s.repository.SearchPaymentsByOrder(ctx, orderID)
+}
+
+// SearchByStatus returns every payment with the given status
+func (s *Service) SearchByStatus(ctx context.Context, status postgres.PaymentStatus) ([]postgres.Payment, error) {
+	return s.repository.SearchPaymentsByStatus(ctx, status)
+}
@@ -1 +1,140 @@
 package payment
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/exp/rand"
+
+	domain "ecommerce_management/internal/domain/payment"
+	"ecommerce_management/internal/provider/epay"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/webhook"
+	"ecommerce_management/pkg/log"
+)
+
+// Create charges the card of the request for the total of an order through an ePay invoice.
+// The payment is recorded as unsuccessful before the card is charged and marked successful once ePay accepts it.
+func (s *Service) Create(ctx context.Context, req domain.CreatePaymentParams) (data postgres.Payment, err error) {
+	logger := log.LoggerFromContext(ctx).Named("Create").With(zap.Int64("order_id", req.OrderID))
+
+	order, err := s.repository.GetOrder(ctx, req.OrderID)
+	if err == nil && order.DeletedAt.Valid {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: order ID %d", ErrOrderNotFound, req.OrderID)
+		}
+		return
+	}
+
+	amount, err := strconv.ParseInt(order.TotalAmount, 10, 64)
+	if err != nil {
+		return data, ErrInvalidAmount
+	}
+
+	user, err := s.repository.GetUser(ctx, order.UserID)
+	if err != nil {
+		return
+	}
+
+	// The invoice ID is kept so that returns can find the transaction to refund
+	invoiceID := generateInvoiceID()
+
+	data, err = s.repository.CreatePayment(ctx, postgres.CreatePaymentParams{
+		UserID:    order.UserID,
+		OrderID:   req.OrderID,
+		Amount:    order.TotalAmount,
+		Status:    postgres.PaymentStatusUnsuccessful,
+		InvoiceID: sql.NullString{String: invoiceID, Valid: true},
+	})
+	if err != nil {
+		return
+	}
+
+	s.record(ctx, audit.ActionCreate, data.ID, nil, data)
+
+	token, err := s.gateway.GetPaymentToken(ctx, &epay.PaymentRequest{
+		Amount:    order.TotalAmount,
+		Currency:  "KZT",
+		InvoiceID: invoiceID,
+	})
+	if err != nil {
+		return
+	}
+
+	cryptogram, err := json.Marshal(epay.Cryptogram{
+		HPAN:       req.HPAN,
+		ExpDate:    req.ExpDate,
+		CVC:        req.CVC,
+		TerminalID: s.terminalID,
+	})
+	if err != nil {
+		logger.Error("failed to marshal cryptogram", zap.Error(err))
+		return
+	}
+
+	encrypted, err := epay.EncryptWithPublicKey(cryptogram, epay.PublicKeyPEM)
+	if err != nil {
+		logger.Error("failed to encrypt cryptogram", zap.Error(err))
+		return
+	}
+
+	invoice, err := s.gateway.CreateInvoice(ctx, token.AccessToken, epay.CreateInvoiceRequest{
+		Amount:      amount,
+		Currency:    "KZT",
+		Name:        user.FullName,
+		Cryptogram:  encrypted,
+		Email:       user.Email,
+		InvoiceID:   invoiceID,
+		Description: "Payment for Order " + fmt.Sprint(req.OrderID),
+		CardSave:    false,
+		PostLink:    "https://testmerchant/order/" + fmt.Sprint(req.OrderID),
+	})
+	if err != nil {
+		logger.Error("failed to create invoice", zap.Error(err))
+		return
+	}
+
+	// Update the payment status based on the response from the invoice creation
+	status := postgres.PaymentStatusUnsuccessful
+	if invoice.Success {
+		status = postgres.PaymentStatusSuccessful
+	}
+
+	created := data
+	data, err = s.repository.UpdatePayment(ctx, postgres.UpdatePaymentParams{
+		ID:      data.ID,
+		UserID:  data.UserID,
+		OrderID: data.OrderID,
+		Amount:  data.Amount,
+		Status:  status,
+		Version: data.Version,
+	})
+	if err != nil {
+		logger.Error("failed to update payment", zap.Error(err))
+		return
+	}
+
+	s.record(ctx, audit.ActionUpdate, data.ID, created, data)
+	if data.Status == postgres.PaymentStatusSuccessful {
+		s.notify(ctx, notification.EventOrderPaid, data.OrderID)
+	}
+	s.publish(ctx, webhook.EventPaymentCreated, data)
+
+	return
+}
+
+func generateInvoiceID() string {
+	rand.Seed(uint64(time.Now().UnixNano())) // Convert int64 to uint64
+	return fmt.Sprintf("%012d", rand.Int63n(1e12))
+}
@@ -1,15 +1,72 @@
 package payment
 
 import (
+	"context"
+	"errors"
+
 	"ecommerce_management/internal/provider/currency"
+	"ecommerce_management/internal/provider/epay"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/notification"
+)
+
+var (
+	ErrNotFound       = errors.New("payment not found")
+	ErrOrderNotFound  = errors.New("order not found")
+	ErrVersionChanged = errors.New("payment has changed since it was read")
+	ErrConflict       = errors.New("payment was changed by another request")
+	ErrInvalidAmount  = errors.New("invalid amount format")
 )
 
+// Repository is the subset of queries the Service needs to take and keep payments
+type Repository interface {
+	GetOrder(ctx context.Context, id int64) (postgres.Order, error)
+	GetUser(ctx context.Context, id int64) (postgres.User, error)
+	CreatePayment(ctx context.Context, arg postgres.CreatePaymentParams) (postgres.Payment, error)
+	GetPayment(ctx context.Context, id int64) (postgres.Payment, error)
+	UpdatePayment(ctx context.Context, arg postgres.UpdatePaymentParams) (postgres.Payment, error)
+	DeletePayment(ctx context.Context, id int64) (postgres.Payment, error)
+	RestorePayment(ctx context.Context, id int64) (postgres.Payment, error)
+	ListPaymentsPage(ctx context.Context, arg postgres.ListPaymentsPageParams) (postgres.Page[postgres.Payment], error)
+	SearchPaymentsByUser(ctx context.Context, userID int64) ([]postgres.Payment, error)
+	SearchPaymentsByOrder(ctx context.Context, orderID int64) ([]postgres.Payment, error)
+	SearchPaymentsByStatus(ctx context.Context, status postgres.PaymentStatus) ([]postgres.Payment, error)
+}
+
+// Gateway is the payment provider cards are charged through; *epay.Client implements it
+type Gateway interface {
+	GetPaymentToken(ctx context.Context, src *epay.PaymentRequest) (epay.TokenResponse, error)
+	CreateInvoice(ctx context.Context, token string, req epay.CreateInvoiceRequest) (epay.CreateInvoiceResponse, error)
+}
+
+// Recorder records changes to payments in the audit log; *audit.Service implements it
+type Recorder interface {
+	Record(ctx context.Context, action, entityType string, entityID int64, before, after any)
+}
+
+// Publisher publishes payment events to webhook subscribers; *webhook.Service implements it
+type Publisher interface {
+	Publish(ctx context.Context, event string, data any)
+}
+
+// Notifier emails customers about their orders; *notification.Service implements it
+type Notifier interface {
+	NotifyOrder(ctx context.Context, event notification.Event, orderID int64)
+}
+
 // Configuration is an alias for a function that will take in a pointer to a Service and modify it
 type Configuration func(s *Service) error
 
 // Service is an implementation of the Service
 type Service struct {
 	currencyClient *currency.Client
+	repository     Repository
+	gateway        Gateway
+	terminalID     string
+	audit          Recorder
+	webhooks       Publisher
+	notifications  Notifier
 }
 
 // New takes a variable amount of Configuration functions and returns a new Service
@@ -37,3 +94,62 @@ func WithCurrencyClient(currencyClient *currency.Client) Configuration {
 		return nil
 	}
 }
+
+// WithRepository applies the store payments are kept in
+func WithRepository(repository Repository) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithGateway applies the payment provider and the terminal cards are charged on
+func WithGateway(gateway Gateway, terminalID string) Configuration {
+	return func(s *Service) error {
+		s.gateway = gateway
+		s.terminalID = terminalID
+		return nil
+	}
+}
+
+// WithAudit records every change to a payment in the audit log
+func WithAudit(audit Recorder) Configuration {
+	return func(s *Service) error {
+		s.audit = audit
+		return nil
+	}
+}
+
+// WithWebhooks publishes payment events to webhook subscribers
+func WithWebhooks(webhooks Publisher) Configuration {
+	return func(s *Service) error {
+		s.webhooks = webhooks
+		return nil
+	}
+}
+
+// WithNotifications emails customers when their orders are paid and refunded
+func WithNotifications(notifications Notifier) Configuration {
+	return func(s *Service) error {
+		s.notifications = notifications
+		return nil
+	}
+}
+
+func (s *Service) record(ctx context.Context, action string, id int64, before, after any) {
+	if s.audit != nil {
+		s.audit.Record(ctx, action, audit.EntityPayment, id, before, after)
+	}
+}
+
+func (s *Service) publish(ctx context.Context, event string, data any) {
+	if s.webhooks != nil {
+		s.webhooks.Publish(ctx, event, data)
+	}
+}
+
+func (s *Service) notify(ctx context.Context, event notification.Event, orderID int64) {
+	if s.notifications != nil {
+		s.notifications.NotifyOrder(ctx, event, orderID)
+	}
+}
@@ -0,0 +1,327 @@
+package promotion
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// KindDiscount is the order adjustment kind recorded for applied promotions
+const KindDiscount = "discount"
+
+var (
+	ErrCouponNotFound = errors.New("coupon code not found")
+	ErrNotApplicable  = errors.New("promotion does not apply")
+)
+
+// Line is an order item as seen by the promotion rules
+type Line struct {
+	ProductID  int64
+	CategoryID int64
+	UnitPrice  float64
+	Quantity   int32
+}
+
+// Adjustment is a discount granted by a promotion; Amount is negative
+type Adjustment struct {
+	PromotionID int64
+	Code        string
+	Description string
+	Amount      float64
+	// FreeShipping marks a waiver of the shipping cost rather than a discount on the items
+	FreeShipping bool
+}
+
+// Result is the price of an order after promotions
+type Result struct {
+	Subtotal     float64
+	Adjustments  []Adjustment
+	FreeShipping bool
+	Total        float64
+}
+
+// NormalizeCode returns the canonical form coupon codes are stored and looked up in
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Price applies every active automatic promotion and the optional coupon code to lines.
+// Automatic promotions that do not apply are skipped; a coupon that does not apply is an error wrapping ErrNotApplicable.
+// Discounts stack in the order they were created, the coupon last, and never exceed the subtotal.
+func (s *Service) Price(ctx context.Context, repository Repository, userID int64, code string, lines []Line) (result Result, err error) {
+	for _, line := range lines {
+		result.Subtotal += line.UnitPrice * float64(line.Quantity)
+	}
+	result.Subtotal = round(result.Subtotal)
+
+	now := s.now()
+	promotions, err := repository.ListActiveAutomaticPromotions(ctx, now)
+	if err != nil {
+		return
+	}
+
+	if code = NormalizeCode(code); code != "" {
+		coupon, err := repository.GetPromotionByCode(ctx, sql.NullString{String: code, Valid: true})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w: %s", ErrCouponNotFound, code)
+			}
+			return result, err
+		}
+		promotions = append(promotions, coupon)
+	}
+
+	remaining := result.Subtotal
+	for _, promotion := range promotions {
+		amount, err := s.discount(ctx, repository, promotion, userID, result.Subtotal, lines, now)
+		if err != nil {
+			if errors.Is(err, ErrNotApplicable) && !promotion.Code.Valid {
+				continue
+			}
+			return result, err
+		}
+
+		amount = round(min(amount, remaining))
+		remaining -= amount
+
+		if promotion.Type == postgres.PromotionTypeFreeShipping {
+			result.FreeShipping = true
+		} else if amount <= 0 {
+			if promotion.Code.Valid {
+				return result, fmt.Errorf("%w: coupon %s gives no discount on this order", ErrNotApplicable, promotion.Code.String)
+			}
+			continue
+		}
+
+		adjustment := Adjustment{
+			PromotionID:  promotion.ID,
+			Code:         promotion.Code.String,
+			Description:  promotion.Name,
+			FreeShipping: promotion.Type == postgres.PromotionTypeFreeShipping,
+		}
+		if amount > 0 {
+			adjustment.Amount = -amount
+		}
+		result.Adjustments = append(result.Adjustments, adjustment)
+	}
+
+	result.Total = round(remaining)
+	return
+}
+
+// Discount returns the positive amount taken off the items, leaving out shipping waivers
+func (r Result) Discount() (amount float64) {
+	for _, adjustment := range r.Adjustments {
+		if !adjustment.FreeShipping {
+			amount -= adjustment.Amount
+		}
+	}
+	return round(amount)
+}
+
+// WaiveShipping sets the amount of the first free shipping adjustment to cancel the shipping cost.
+// Total is left alone, as it only covers the items.
+func (r *Result) WaiveShipping(cost float64) {
+	for i, adjustment := range r.Adjustments {
+		if adjustment.FreeShipping {
+			if cost > 0 {
+				r.Adjustments[i].Amount = -round(cost)
+			}
+			return
+		}
+	}
+}
+
+// Redeem counts the usage of every applied promotion and records its adjustment on the order.
+// The global and per-user limits are checked again as the usage is counted; a promotion that has reached one
+// in the meantime fails with an error wrapping ErrNotApplicable.
+func (s *Service) Redeem(ctx context.Context, repository Repository, orderID, userID int64, result Result) (adjustments []postgres.OrderAdjustment, err error) {
+	adjustments = make([]postgres.OrderAdjustment, 0, len(result.Adjustments))
+	for _, adjustment := range result.Adjustments {
+		// The conditional update keeps the global limit exact under concurrent orders. It also locks the promotion
+		// until the order commits, so the count below sees the redemptions of every other order of the user.
+		var promotion postgres.Promotion
+		if promotion, err = repository.RedeemPromotion(ctx, adjustment.PromotionID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w: %s has reached its usage limit", ErrNotApplicable, adjustment.Description)
+			}
+			return
+		}
+
+		if promotion.PerUserLimit.Valid {
+			var used int64
+			used, err = repository.CountPromotionRedemptionsByUser(ctx, postgres.CountPromotionRedemptionsByUserParams{
+				PromotionID: promotion.ID,
+				UserID:      userID,
+			})
+			if err != nil {
+				return
+			}
+			if used >= int64(promotion.PerUserLimit.Int32) {
+				err = fmt.Errorf("%w: %s has already been used the maximum number of times", ErrNotApplicable, adjustment.Description)
+				return
+			}
+		}
+
+		err = repository.CreatePromotionRedemption(ctx, postgres.CreatePromotionRedemptionParams{
+			PromotionID: adjustment.PromotionID,
+			OrderID:     orderID,
+			UserID:      userID,
+		})
+		if err != nil {
+			return
+		}
+
+		var created postgres.OrderAdjustment
+		created, err = repository.CreateOrderAdjustment(ctx, postgres.CreateOrderAdjustmentParams{
+			OrderID:     orderID,
+			PromotionID: sql.NullInt64{Int64: adjustment.PromotionID, Valid: true},
+			Kind:        KindDiscount,
+			Code:        sql.NullString{String: adjustment.Code, Valid: adjustment.Code != ""},
+			Description: adjustment.Description,
+			Amount:      fmt.Sprintf("%.2f", adjustment.Amount),
+		})
+		if err != nil {
+			return
+		}
+		adjustments = append(adjustments, created)
+	}
+
+	return
+}
+
+// discount checks that a promotion may be used for this order and returns the amount it takes off
+func (s *Service) discount(ctx context.Context, repository Repository, promotion postgres.Promotion, userID int64, subtotal float64, lines []Line, now time.Time) (float64, error) {
+	name := promotion.Name
+	if promotion.Code.Valid {
+		name = promotion.Code.String
+	}
+
+	switch {
+	case !promotion.Active:
+		return 0, fmt.Errorf("%w: %s is not active", ErrNotApplicable, name)
+	case promotion.StartsAt.Valid && now.Before(promotion.StartsAt.Time):
+		return 0, fmt.Errorf("%w: %s has not started yet", ErrNotApplicable, name)
+	case promotion.EndsAt.Valid && !now.Before(promotion.EndsAt.Time):
+		return 0, fmt.Errorf("%w: %s has expired", ErrNotApplicable, name)
+	case promotion.UsageLimit.Valid && promotion.TimesUsed >= promotion.UsageLimit.Int32:
+		return 0, fmt.Errorf("%w: %s has reached its usage limit", ErrNotApplicable, name)
+	}
+
+	if promotion.MinOrderAmount.Valid {
+		minimum, err := strconv.ParseFloat(promotion.MinOrderAmount.String, 64)
+		if err != nil {
+			return 0, err
+		}
+		if subtotal < minimum {
+			return 0, fmt.Errorf("%w: %s requires an order of at least %s", ErrNotApplicable, name, promotion.MinOrderAmount.String)
+		}
+	}
+
+	if promotion.PerUserLimit.Valid {
+		used, err := repository.CountPromotionRedemptionsByUser(ctx, postgres.CountPromotionRedemptionsByUserParams{
+			PromotionID: promotion.ID,
+			UserID:      userID,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if used >= int64(promotion.PerUserLimit.Int32) {
+			return 0, fmt.Errorf("%w: %s has already been used the maximum number of times", ErrNotApplicable, name)
+		}
+	}
+
+	matching, err := s.matchingLines(ctx, repository, promotion, lines)
+	if err != nil {
+		return 0, err
+	}
+
+	var eligible float64
+	for _, line := range matching {
+		eligible += line.UnitPrice * float64(line.Quantity)
+	}
+
+	value, err := strconv.ParseFloat(promotion.Value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	switch promotion.Type {
+	case postgres.PromotionTypePercentage:
+		return eligible * value / 100, nil
+	case postgres.PromotionTypeFixed:
+		return min(value, eligible), nil
+	case postgres.PromotionTypeBuyXGetY:
+		return cheapestUnits(matching, promotion.BuyQuantity, promotion.GetQuantity), nil
+	default:
+		return 0, nil
+	}
+}
+
+// matchingLines returns the lines a promotion is limited to; a category includes its subcategories
+func (s *Service) matchingLines(ctx context.Context, repository Repository, promotion postgres.Promotion, lines []Line) ([]Line, error) {
+	var categories []int64
+	if promotion.CategoryID.Valid {
+		var err error
+		if categories, err = repository.ListCategoryDescendantIDs(ctx, promotion.CategoryID.Int64); err != nil {
+			return nil, err
+		}
+	}
+
+	matching := make([]Line, 0, len(lines))
+	for _, line := range lines {
+		if promotion.ProductID.Valid && line.ProductID != promotion.ProductID.Int64 {
+			continue
+		}
+		if promotion.CategoryID.Valid && !slices.Contains(categories, line.CategoryID) {
+			continue
+		}
+		matching = append(matching, line)
+	}
+
+	return matching, nil
+}
+
+// cheapestUnits returns the price of the units given away: for every buy+get units, the get cheapest are free
+func cheapestUnits(lines []Line, buy, get int32) float64 {
+	if buy <= 0 || get <= 0 {
+		return 0
+	}
+
+	var units int64
+	for _, line := range lines {
+		units += int64(line.Quantity)
+	}
+	free := units / int64(buy+get) * int64(get)
+
+	sorted := slices.Clone(lines)
+	slices.SortFunc(sorted, func(a, b Line) int {
+		return cmp.Compare(a.UnitPrice, b.UnitPrice)
+	})
+
+	var amount float64
+	for _, line := range sorted {
+		if free <= 0 {
+			break
+		}
+		n := min(free, int64(line.Quantity))
+		amount += line.UnitPrice * float64(n)
+		free -= n
+	}
+
+	return amount
+}
+
+// round rounds an amount to cents
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,205 @@
+package promotion
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "ecommerce_management/internal/domain/promotion"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+var (
+	ErrNotFound         = errors.New("promotion not found")
+	ErrInvalidPromotion = errors.New("invalid promotion")
+)
+
+// List returns every promotion
+func (s *Service) List(ctx context.Context) ([]postgres.Promotion, error) {
+	return s.repository.ListPromotions(ctx)
+}
+
+// Get returns a promotion
+func (s *Service) Get(ctx context.Context, id int64) (data postgres.Promotion, err error) {
+	data, err = s.repository.GetPromotion(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: promotion ID %d", ErrNotFound, id)
+	}
+	return
+}
+
+// Create adds a promotion. One with a code is a coupon applied when ordering; one without applies automatically.
+func (s *Service) Create(ctx context.Context, req domain.Request) (data postgres.Promotion, err error) {
+	if err = s.validate(ctx, 0, &req); err != nil {
+		return
+	}
+
+	return s.repository.CreatePromotion(ctx, postgres.CreatePromotionParams{
+		Name:           req.Name,
+		Code:           nullString(req.Code),
+		Type:           req.Type,
+		Value:          req.Value,
+		BuyQuantity:    req.BuyQuantity,
+		GetQuantity:    req.GetQuantity,
+		ProductID:      nullInt64(req.ProductID),
+		CategoryID:     nullInt64(req.CategoryID),
+		MinOrderAmount: nullString(req.MinOrderAmount),
+		StartsAt:       nullTime(req.StartsAt),
+		EndsAt:         nullTime(req.EndsAt),
+		UsageLimit:     nullInt32(req.UsageLimit),
+		PerUserLimit:   nullInt32(req.PerUserLimit),
+		Active:         req.Active == nil || *req.Active,
+	})
+}
+
+// Update replaces a promotion. The usage count is kept, so lowering the usage limit below it stops further use.
+func (s *Service) Update(ctx context.Context, id int64, req domain.Request) (data postgres.Promotion, err error) {
+	if err = s.validate(ctx, id, &req); err != nil {
+		return
+	}
+
+	data, err = s.repository.UpdatePromotion(ctx, postgres.UpdatePromotionParams{
+		ID:             id,
+		Name:           req.Name,
+		Code:           nullString(req.Code),
+		Type:           req.Type,
+		Value:          req.Value,
+		BuyQuantity:    req.BuyQuantity,
+		GetQuantity:    req.GetQuantity,
+		ProductID:      nullInt64(req.ProductID),
+		CategoryID:     nullInt64(req.CategoryID),
+		MinOrderAmount: nullString(req.MinOrderAmount),
+		StartsAt:       nullTime(req.StartsAt),
+		EndsAt:         nullTime(req.EndsAt),
+		UsageLimit:     nullInt32(req.UsageLimit),
+		PerUserLimit:   nullInt32(req.PerUserLimit),
+		Active:         req.Active == nil || *req.Active,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: promotion ID %d", ErrNotFound, id)
+	}
+	return
+}
+
+// Delete removes a promotion; discounts already applied to orders keep their code and description
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.repository.DeletePromotion(ctx, id)
+}
+
+// validate normalises the code and checks the rule makes sense for its type
+func (s *Service) validate(ctx context.Context, id int64, req *domain.Request) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+
+	if !req.Type.Valid() {
+		return fmt.Errorf("%w: invalid type: %s", ErrInvalidPromotion, req.Type)
+	}
+
+	if req.Value == "" {
+		req.Value = "0"
+	}
+	value, err := strconv.ParseFloat(req.Value, 64)
+	if err != nil || value < 0 {
+		return fmt.Errorf("%w: invalid value: %s", ErrInvalidPromotion, req.Value)
+	}
+
+	switch req.Type {
+	case postgres.PromotionTypePercentage:
+		if value <= 0 || value > 100 {
+			return fmt.Errorf("%w: value must be a percentage between 0 and 100", ErrInvalidPromotion)
+		}
+	case postgres.PromotionTypeFixed:
+		if value <= 0 {
+			return fmt.Errorf("%w: value must be a positive amount", ErrInvalidPromotion)
+		}
+	case postgres.PromotionTypeBuyXGetY:
+		if req.BuyQuantity <= 0 || req.GetQuantity <= 0 {
+			return fmt.Errorf("%w: buy_quantity and get_quantity must be positive", ErrInvalidPromotion)
+		}
+	}
+
+	if req.MinOrderAmount != nil {
+		if _, err := strconv.ParseFloat(*req.MinOrderAmount, 64); err != nil {
+			return fmt.Errorf("%w: invalid min_order_amount: %s", ErrInvalidPromotion, *req.MinOrderAmount)
+		}
+	}
+
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPromotion)
+	}
+
+	if (req.UsageLimit != nil && *req.UsageLimit <= 0) || (req.PerUserLimit != nil && *req.PerUserLimit <= 0) {
+		return fmt.Errorf("%w: usage limits must be positive", ErrInvalidPromotion)
+	}
+
+	if req.ProductID != nil {
+		if _, err := s.repository.GetProduct(ctx, *req.ProductID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: product %d not found", ErrInvalidPromotion, *req.ProductID)
+			}
+			return err
+		}
+	}
+
+	if req.CategoryID != nil {
+		if _, err := s.repository.GetCategory(ctx, *req.CategoryID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: category %d not found", ErrInvalidPromotion, *req.CategoryID)
+			}
+			return err
+		}
+	}
+
+	if req.Code != nil {
+		code := NormalizeCode(*req.Code)
+		if code == "" {
+			req.Code = nil
+			return nil
+		}
+		req.Code = &code
+
+		existing, err := s.repository.GetPromotionByCode(ctx, sql.NullString{String: code, Valid: true})
+		if err == nil && existing.ID != id {
+			return fmt.Errorf("%w: code %q is already used", ErrInvalidPromotion, code)
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func nullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func nullInt64(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
+
+func nullInt32(value *int32) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *value, Valid: true}
+}
+
+func nullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *value, Valid: true}
+}
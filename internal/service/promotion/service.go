@@ -0,0 +1,70 @@
+package promotion
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// Repository is the subset of queries the Service needs to price and redeem promotions.
+// It is passed to every call so that redemptions happen inside the transaction that creates the order.
+type Repository interface {
+	GetPromotionByCode(ctx context.Context, code sql.NullString) (postgres.Promotion, error)
+	ListActiveAutomaticPromotions(ctx context.Context, now time.Time) ([]postgres.Promotion, error)
+	ListCategoryDescendantIDs(ctx context.Context, id int64) ([]int64, error)
+	CountPromotionRedemptionsByUser(ctx context.Context, arg postgres.CountPromotionRedemptionsByUserParams) (int64, error)
+	RedeemPromotion(ctx context.Context, id int64) (postgres.Promotion, error)
+	CreatePromotionRedemption(ctx context.Context, arg postgres.CreatePromotionRedemptionParams) error
+	CreateOrderAdjustment(ctx context.Context, arg postgres.CreateOrderAdjustmentParams) (postgres.OrderAdjustment, error)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service keeps promotions and applies coupon codes and automatic promotions to orders
+type Service struct {
+	repository repository.Store
+	now        func() time.Time
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		now: time.Now,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+
+	if s.repository == nil {
+		return nil, errors.New("promotion service requires a repository")
+	}
+	return
+}
+
+// WithRepository applies the store promotions are kept in
+func WithRepository(repository repository.Store) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithClock replaces the clock used to check validity windows
+func WithClock(now func() time.Time) Configuration {
+	return func(s *Service) error {
+		s.now = now
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/media"
+)
+
+const (
+	defaultRetention   = 30 * 24 * time.Hour
+	defaultPurgePeriod = time.Hour
+)
+
+// Repository is the subset of queries the Service needs to purge soft-deleted records
+type Repository interface {
+	PurgeDeletedPayments(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	PurgeDeletedOrders(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	ListPurgeableProductImages(ctx context.Context, deletedAt sql.NullTime) ([]postgres.ProductImage, error)
+	PurgeDeletedProducts(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+}
+
+// ImageRemover deletes the stored files of an image
+type ImageRemover interface {
+	DeleteImage(ctx context.Context, img media.Image)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service permanently removes records that have been soft deleted for longer than the retention period
+type Service struct {
+	repository  Repository
+	images      ImageRemover
+	retention   time.Duration
+	purgePeriod time.Duration
+	now         func() time.Time
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		retention:   defaultRetention,
+		purgePeriod: defaultPurgePeriod,
+		now:         time.Now,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithRepository applies a given repository to the Service
+func WithRepository(repository Repository) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithImageRemover applies the service deleting image files of purged products
+func WithImageRemover(images ImageRemover) Configuration {
+	return func(s *Service) error {
+		s.images = images
+		return nil
+	}
+}
+
+// WithRetention sets how long deleted records can still be restored before they are purged
+func WithRetention(retention time.Duration) Configuration {
+	return func(s *Service) error {
+		if retention > 0 {
+			s.retention = retention
+		}
+		return nil
+	}
+}
+
+// WithClock replaces the clock used to find expired records
+func WithClock(now func() time.Time) Configuration {
+	return func(s *Service) error {
+		s.now = now
+		return nil
+	}
+}
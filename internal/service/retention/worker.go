@@ -0,0 +1,80 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/service/media"
+	"ecommerce_management/pkg/log"
+)
+
+// Start purges expired deleted records until ctx is cancelled
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.purgePeriod)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.purge(ctx)
+			}
+		}
+	}()
+}
+
+// purge removes payments before orders and orders before users, so that records
+// freed by an earlier step are purged in the same run. Records still referenced
+// by kept history are left in place.
+func (s *Service) purge(ctx context.Context) {
+	logger := log.LoggerFromContext(ctx).Named("purge")
+
+	before := sql.NullTime{Time: s.now().Add(-s.retention), Valid: true}
+
+	steps := []struct {
+		name  string
+		purge func(context.Context, sql.NullTime) (int64, error)
+	}{
+		{"payments", s.repository.PurgeDeletedPayments},
+		{"orders", s.repository.PurgeDeletedOrders},
+		{"users", s.repository.PurgeDeletedUsers},
+		{"products", s.purgeProducts},
+	}
+	for _, step := range steps {
+		purged, err := step.purge(ctx, before)
+		if err != nil {
+			logger.Error("failed to purge deleted records", zap.String("table", step.name), zap.Error(err))
+			continue
+		}
+		if purged > 0 {
+			logger.Info("purged deleted records", zap.String("table", step.name), zap.Int64("count", purged))
+		}
+	}
+}
+
+// purgeProducts deletes expired products and then the files of their images,
+// which the database removes along with the products
+func (s *Service) purgeProducts(ctx context.Context, before sql.NullTime) (int64, error) {
+	images, err := s.repository.ListPurgeableProductImages(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := s.repository.PurgeDeletedProducts(ctx, before)
+	if err != nil || s.images == nil {
+		return purged, err
+	}
+
+	for _, image := range images {
+		img := media.Image{Key: image.StorageKey}
+		json.Unmarshal(image.Thumbnails, &img.Thumbnails)
+		s.images.DeleteImage(ctx, img)
+	}
+	return purged, nil
+}
@@ -0,0 +1,159 @@
+package returns
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/webhook"
+	"ecommerce_management/pkg/log"
+)
+
+var (
+	// ErrNotRefundable is returned when a payment cannot give back the amount of a return
+	ErrNotRefundable = errors.New("payment cannot be refunded")
+	// ErrRefundInProgress is returned when another request is refunding a return
+	ErrRefundInProgress = errors.New("return is being refunded")
+)
+
+// refund gives back the amount of a received return through the latest payment of its order and marks it refunded.
+// The return is refunding while the payment provider is called, and only the request that moved it there calls the
+// provider, so a return is refunded at most once however many requests ask for it. An error of the provider means
+// that nothing was refunded and puts the return back to received. A refund that was made but could not be recorded
+// leaves the return refunding, to be reconciled with the provider by hand.
+func (s *Service) refund(ctx context.Context, data postgres.Return) (postgres.Return, error) {
+	// Carry on when the client goes away, so that a refund that was made is recorded
+	ctx = context.WithoutCancel(ctx)
+	logger := log.LoggerFromContext(ctx).Named("refund")
+
+	payment, err := s.repository.GetRefundablePayment(ctx, data.OrderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: order ID %d has no payment left to refund", ErrNotRefundable, data.OrderID)
+		}
+		return data, err
+	}
+
+	amount, err := strconv.ParseFloat(data.RefundAmount, 64)
+	if err != nil {
+		return data, err
+	}
+
+	claimed, err := s.repository.ClaimReturnRefund(ctx, postgres.ClaimReturnRefundParams{
+		ID:        data.ID,
+		PaymentID: sql.NullInt64{Int64: payment.ID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: return ID %d", ErrRefundInProgress, data.ID)
+		}
+		return data, err
+	}
+
+	if amount > 0 {
+		if err = s.Refund(ctx, payment, amount); err != nil {
+			if _, released := s.repository.ReleaseReturnRefund(ctx, data.ID); released != nil {
+				logger.Error("failed to release refund of return", zap.Error(released), zap.Int64("return_id", data.ID))
+			}
+			return data, err
+		}
+	}
+
+	updated, done, err := s.completeRefund(ctx, claimed, payment)
+	if err != nil {
+		logger.Error("return was refunded but the refund was not recorded", zap.Error(err),
+			zap.Int64("return_id", data.ID), zap.Int64("payment_id", payment.ID), zap.String("amount", data.RefundAmount))
+		return claimed, err
+	}
+
+	s.record(ctx, audit.ActionUpdate, updated.ID, payment, updated)
+	if updated.Status == postgres.PaymentStatusRefunded {
+		s.notify(ctx, notification.EventOrderRefunded, updated.OrderID)
+	}
+	s.publish(ctx, webhook.EventPaymentUpdated, updated)
+
+	return done, nil
+}
+
+// completeRefund adds the amount of a refunding return to the refunds of its payment and marks the return refunded
+func (s *Service) completeRefund(ctx context.Context, data postgres.Return, payment postgres.Payment) (updated postgres.Payment, done postgres.Return, err error) {
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	updated, err = tx.AddPaymentRefund(ctx, postgres.AddPaymentRefundParams{
+		ID:     payment.ID,
+		Amount: data.RefundAmount,
+	})
+	if err != nil {
+		return
+	}
+
+	done, err = tx.CompleteReturnRefund(ctx, postgres.CompleteReturnRefundParams{
+		ID:         data.ID,
+		RefundedAt: sql.NullTime{Time: s.now(), Valid: true},
+	})
+	if err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// Refund returns amount of payment through the payment provider.
+// A payment that is only authorized is cancelled, which releases the whole amount, so it must be returned in full;
+// a charged payment is refunded in part. The caller records the refund on the payment.
+func (s *Service) Refund(ctx context.Context, payment postgres.Payment, amount float64) error {
+	if s.refunder == nil {
+		return fmt.Errorf("%w: no payment provider is configured", ErrNotRefundable)
+	}
+	if !payment.InvoiceID.Valid {
+		return fmt.Errorf("%w: payment ID %d has no invoice", ErrNotRefundable, payment.ID)
+	}
+
+	paid, err := strconv.ParseFloat(payment.Amount, 64)
+	if err != nil {
+		return err
+	}
+	refunded, err := strconv.ParseFloat(payment.RefundedAmount, 64)
+	if err != nil {
+		return err
+	}
+	remaining := round(paid - refunded)
+	if amount > remaining {
+		return fmt.Errorf("%w: only %.2f of payment ID %d is left to refund", ErrNotRefundable, remaining, payment.ID)
+	}
+
+	token, err := s.refunder.GetPaymentToken(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	status, err := s.refunder.GetStatus(ctx, token.AccessToken, payment.InvoiceID.String)
+	if err != nil {
+		return err
+	}
+
+	transaction := status.Transaction
+	switch transaction.StatusName {
+	case "AUTH":
+		if amount < remaining {
+			return fmt.Errorf("%w: payment ID %d is only authorized; charge it before refunding part of it", ErrNotRefundable, payment.ID)
+		}
+		return s.refunder.Cancel(ctx, token.AccessToken, transaction.ID)
+	case "CHARGE", "REFUND":
+		return s.refunder.Refund(ctx, token.AccessToken, transaction.ID, fmt.Sprintf("%.2f", amount))
+	default:
+		return fmt.Errorf("%w: transaction of payment ID %d is %s", ErrNotRefundable, payment.ID, transaction.StatusName)
+	}
+}
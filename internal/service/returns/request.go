@@ -0,0 +1,371 @@
+package returns
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	domain "ecommerce_management/internal/domain/returns"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/webhook"
+	"ecommerce_management/pkg/log"
+)
+
+var (
+	ErrNotFound          = errors.New("return not found")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrInvalidReturn     = errors.New("invalid return")
+	ErrInvalidTransition = errors.New("invalid return status change")
+)
+
+// Line is a quantity of an order item asked to be returned
+type Line struct {
+	OrderItemID int64
+	Quantity    int32
+}
+
+// Item is a checked return line with the amount refunded for it
+type Item struct {
+	OrderItem postgres.OrderItem
+	Quantity  int32
+	Amount    float64
+}
+
+// returnable lists the order statuses whose goods have left the warehouse and may come back
+var returnable = map[postgres.OrderStatus]bool{
+	postgres.OrderStatusShipped:   true,
+	postgres.OrderStatusDelivered: true,
+	postgres.OrderStatusCompleted: true,
+}
+
+// Plan checks that lines may be returned from order and prices them.
+// A line is refunded its share of the item's gross amount, so discounts and tax are returned in proportion;
+// shipping is not refunded. Units already in a return that was not rejected cannot be returned again.
+// Problems with the request are errors wrapping ErrInvalidReturn.
+func (s *Service) Plan(ctx context.Context, repository Repository, order postgres.Order, lines []Line) (items []Item, total float64, err error) {
+	if !returnable[order.Status] {
+		return nil, 0, fmt.Errorf("%w: %s order cannot be returned", ErrInvalidReturn, order.Status)
+	}
+	if len(lines) == 0 {
+		return nil, 0, fmt.Errorf("%w: items are required", ErrInvalidReturn)
+	}
+
+	seen := make(map[int64]bool, len(lines))
+	items = make([]Item, 0, len(lines))
+	for _, line := range lines {
+		if seen[line.OrderItemID] {
+			return nil, 0, fmt.Errorf("%w: order item ID %d is listed twice", ErrInvalidReturn, line.OrderItemID)
+		}
+		seen[line.OrderItemID] = true
+
+		if line.Quantity <= 0 {
+			return nil, 0, fmt.Errorf("%w: invalid quantity for order item ID %d", ErrInvalidReturn, line.OrderItemID)
+		}
+
+		item, err := repository.GetOrderItem(ctx, line.OrderItemID)
+		if err != nil || item.OrderID != order.ID {
+			if err == nil || errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w: order item ID %d not found in order ID %d", ErrInvalidReturn, line.OrderItemID, order.ID)
+			}
+			return nil, 0, err
+		}
+
+		returned, err := repository.SumReturnedQuantity(ctx, item.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if returned+line.Quantity > item.Quantity {
+			return nil, 0, fmt.Errorf("%w: only %d of order item ID %d can still be returned", ErrInvalidReturn, item.Quantity-returned, item.ID)
+		}
+
+		gross, err := strconv.ParseFloat(item.GrossAmount, 64)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Priced as the difference of the shares before and after, so that returning every unit refunds the whole gross
+		amount := round(share(gross, returned+line.Quantity, item.Quantity) - share(gross, returned, item.Quantity))
+		total += amount
+		items = append(items, Item{
+			OrderItem: item,
+			Quantity:  line.Quantity,
+			Amount:    amount,
+		})
+	}
+
+	return items, round(total), nil
+}
+
+// Get returns a return with its items
+func (s *Service) Get(ctx context.Context, id int64) (data postgres.Return, items []postgres.ReturnItem, err error) {
+	data, err = s.repository.GetReturn(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: return ID %d", ErrNotFound, id)
+		}
+		return
+	}
+
+	items, err = s.repository.ListReturnItems(ctx, id)
+	return
+}
+
+// List returns the returns newest first, or with a status the oldest first so that the queue is worked in order
+func (s *Service) List(ctx context.Context, status postgres.ReturnStatus) ([]domain.Return, error) {
+	var src []postgres.Return
+	var err error
+	if status != "" {
+		src, err = s.repository.ListReturnsByStatus(ctx, status)
+	} else {
+		src, err = s.repository.ListReturns(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.parse(ctx, src)
+}
+
+// ListByOrder returns the returns of an order
+func (s *Service) ListByOrder(ctx context.Context, orderID int64) ([]domain.Return, error) {
+	src, err := s.repository.ListReturnsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.parse(ctx, src)
+}
+
+// parse loads the items of every return and converts them into their public representation
+func (s *Service) parse(ctx context.Context, src []postgres.Return) ([]domain.Return, error) {
+	dst := make([]domain.Return, 0, len(src))
+	for _, data := range src {
+		items, err := s.repository.ListReturnItems(ctx, data.ID)
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, domain.ParseReturn(data, items))
+	}
+
+	return dst, nil
+}
+
+// Create requests a return of lines from an order, priced as Plan prices them.
+// The order is locked while the lines are checked, so that two requests cannot return the same units.
+func (s *Service) Create(ctx context.Context, orderID int64, reason string, lines []Line) (data postgres.Return, items []postgres.ReturnItem, err error) {
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	order, err := tx.GetOrderForUpdate(ctx, orderID)
+	if err == nil && order.DeletedAt.Valid {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: order ID %d", ErrOrderNotFound, orderID)
+		}
+		return
+	}
+
+	planned, total, err := s.Plan(ctx, tx, order, lines)
+	if err != nil {
+		return
+	}
+
+	data, err = tx.CreateReturn(ctx, postgres.CreateReturnParams{
+		OrderID:      order.ID,
+		Reason:       strings.TrimSpace(reason),
+		RefundAmount: fmt.Sprintf("%.2f", total),
+	})
+	if err != nil {
+		return
+	}
+
+	items = make([]postgres.ReturnItem, 0, len(planned))
+	for _, item := range planned {
+		created, err := tx.CreateReturnItem(ctx, postgres.CreateReturnItemParams{
+			ReturnID:    data.ID,
+			OrderItemID: item.OrderItem.ID,
+			Quantity:    item.Quantity,
+			Amount:      fmt.Sprintf("%.2f", item.Amount),
+		})
+		if err != nil {
+			return data, nil, err
+		}
+		items = append(items, created)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	s.publish(ctx, webhook.EventReturnCreated, domain.ParseReturn(data, items))
+
+	return data, items, nil
+}
+
+// transitions lists the statuses each return status may move to
+var transitions = map[postgres.ReturnStatus][]postgres.ReturnStatus{
+	postgres.ReturnStatusRequested: {postgres.ReturnStatusApproved, postgres.ReturnStatusRejected},
+	postgres.ReturnStatusApproved:  {postgres.ReturnStatusReceived, postgres.ReturnStatusRejected},
+	postgres.ReturnStatusReceived:  {postgres.ReturnStatusRefunded},
+}
+
+// Transition moves a return to status, stamping when the goods were received and when the money was refunded.
+// A return is approved or rejected, then received, then refunded; any other change is an error wrapping ErrInvalidTransition.
+func (s *Service) Transition(data postgres.Return, status postgres.ReturnStatus) (postgres.Return, error) {
+	if status == data.Status {
+		return data, nil
+	}
+
+	allowed := false
+	for _, next := range transitions[data.Status] {
+		allowed = allowed || next == status
+	}
+	if !allowed {
+		return data, fmt.Errorf("%w: %s return cannot become %s", ErrInvalidTransition, data.Status, status)
+	}
+
+	now := s.now()
+	switch status {
+	case postgres.ReturnStatusReceived:
+		data.ReceivedAt = sql.NullTime{Time: now, Valid: true}
+	case postgres.ReturnStatusRefunded:
+		data.RefundedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	data.Status = status
+
+	return data, nil
+}
+
+// Update moves a return to status and replaces its note; an empty status keeps the status and a nil note keeps the note.
+// Receiving a return puts its goods back in stock and refunds it. A return only becomes refunded once the payment
+// provider has given the money back; a refund that fails leaves it received, to be retried by moving it to refunded.
+func (s *Service) Update(ctx context.Context, id int64, status postgres.ReturnStatus, note *string) (data postgres.Return, items []postgres.ReturnItem, err error) {
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	previous, err := tx.GetReturnForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: return ID %d", ErrNotFound, id)
+		}
+		return
+	}
+	if previous.Status == postgres.ReturnStatusRefunding && status != "" && status != previous.Status {
+		return previous, nil, fmt.Errorf("%w: return ID %d", ErrRefundInProgress, id)
+	}
+
+	data = previous
+	if status != "" {
+		next, err := s.Transition(previous, status)
+		if err != nil {
+			return previous, nil, err
+		}
+		if next.Status != postgres.ReturnStatusRefunded {
+			data = next
+		}
+	}
+	refund := status == postgres.ReturnStatusRefunded && previous.Status != postgres.ReturnStatusRefunded
+
+	if items, err = tx.ListReturnItems(ctx, id); err != nil {
+		return
+	}
+
+	received := data.Status == postgres.ReturnStatusReceived && previous.Status != postgres.ReturnStatusReceived
+	if received {
+		if err = s.Restock(ctx, tx, items); err != nil {
+			return
+		}
+	}
+
+	if note != nil {
+		data.Note = *note
+	}
+
+	data, err = tx.UpdateReturn(ctx, postgres.UpdateReturnParams{
+		ID:         data.ID,
+		Status:     data.Status,
+		Note:       data.Note,
+		PaymentID:  data.PaymentID,
+		ReceivedAt: data.ReceivedAt,
+		RefundedAt: data.RefundedAt,
+	})
+	if err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	switch {
+	case refund:
+		if data, err = s.refund(ctx, data); err != nil {
+			return
+		}
+	case received:
+		// The goods are back either way; a failed refund is retried by moving the return to refunded
+		refunded, err := s.refund(ctx, data)
+		if err != nil {
+			logger := log.LoggerFromContext(ctx).Named("refund")
+			logger.Error("failed to refund return", zap.Error(err), zap.Int64("return_id", data.ID))
+		} else {
+			data = refunded
+		}
+	}
+
+	s.publish(ctx, webhook.EventReturnUpdated, domain.ParseReturn(data, items))
+
+	return data, items, nil
+}
+
+// Restock puts the returned units back on the shelf of the variant or product they were ordered as.
+// Units of products or variants deleted since are skipped.
+func (s *Service) Restock(ctx context.Context, repository Repository, items []postgres.ReturnItem) error {
+	for _, data := range items {
+		item, err := repository.GetOrderItem(ctx, data.OrderItemID)
+		if err != nil {
+			return err
+		}
+
+		if item.VariantID.Valid {
+			_, err = repository.RestockProductVariant(ctx, postgres.RestockProductVariantParams{
+				Quantity: data.Quantity,
+				ID:       item.VariantID.Int64,
+			})
+		} else {
+			_, err = repository.RestockProduct(ctx, postgres.RestockProductParams{
+				Quantity: data.Quantity,
+				ID:       item.ProductID,
+			})
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// share returns the part of an item's gross amount that units of its quantity stand for, rounded to cents
+func share(gross float64, units, quantity int32) float64 {
+	return round(gross * float64(units) / float64(quantity))
+}
+
+// round rounds an amount to cents
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
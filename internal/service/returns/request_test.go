@@ -0,0 +1,248 @@
+package returns
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"ecommerce_management/internal/provider/epay"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// fakeRefunder is a payment provider whose charges are always captured; it counts the refunds asked of it
+type fakeRefunder struct {
+	refunds atomic.Int32
+	err     error
+}
+
+func (f *fakeRefunder) GetPaymentToken(ctx context.Context, src *epay.PaymentRequest) (epay.TokenResponse, error) {
+	return epay.TokenResponse{AccessToken: "token"}, nil
+}
+
+func (f *fakeRefunder) GetStatus(ctx context.Context, token string, invoiceID string) (dst epay.StatusResponse, err error) {
+	dst.Transaction.ID = "transaction-" + invoiceID
+	dst.Transaction.StatusName = "CHARGE"
+	return
+}
+
+func (f *fakeRefunder) Cancel(ctx context.Context, token, transactionID string) error {
+	return errors.New("unexpected cancel")
+}
+
+func (f *fakeRefunder) Refund(ctx context.Context, token, transactionID, amount string) error {
+	f.refunds.Add(1)
+	return f.err
+}
+
+// seed stores a shipped and paid order of two units of a product and returns it with its item
+func seed(t *testing.T, store repository.Store) (postgres.Order, postgres.OrderItem) {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := store.CreateUser(ctx, postgres.CreateUserParams{
+		FullName: "Jane Doe",
+		Email:    "jane@example.com",
+		Role:     "customer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category, err := store.CreateCategory(ctx, postgres.CreateCategoryParams{
+		Name: "Kitchen",
+		Slug: "kitchen",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	product, err := store.CreateProduct(ctx, postgres.CreateProductParams{
+		Sku:           "SKU-1",
+		Name:          "Mug",
+		Price:         "10.00",
+		CategoryID:    category.ID,
+		StockQuantity: 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := store.CreateOrder(ctx, postgres.CreateOrderParams{
+		UserID:      user.ID,
+		TotalAmount: "20.00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err = store.SetOrderStatus(ctx, postgres.SetOrderStatusParams{
+		ID:     order.ID,
+		Status: postgres.OrderStatusShipped,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := store.CreateOrderItem(ctx, postgres.CreateOrderItemParams{
+		OrderID:     order.ID,
+		ProductID:   product.ID,
+		Quantity:    2,
+		Price:       "10.00",
+		TaxRate:     "0",
+		NetAmount:   "20.00",
+		TaxAmount:   "0.00",
+		GrossAmount: "20.00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.CreatePayment(ctx, postgres.CreatePaymentParams{
+		UserID:    user.ID,
+		OrderID:   order.ID,
+		Amount:    "20.00",
+		Status:    postgres.PaymentStatusSuccessful,
+		InvoiceID: sql.NullString{String: "000001", Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return order, item
+}
+
+func newService(t *testing.T, refunder Refunder) (*Service, repository.Store) {
+	t.Helper()
+
+	repo, err := repository.New(repository.WithMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(WithRepository(repo), WithRefunder(refunder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, repo
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	s, store := newService(t, &fakeRefunder{})
+	order, item := seed(t, store)
+
+	data, items, err := s.Create(ctx, order.ID, " damaged ", []Line{{OrderItemID: item.ID, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.RefundAmount != "10.00" || data.Reason != "damaged" || data.Status != postgres.ReturnStatusRequested {
+		t.Errorf("unexpected return: %+v", data)
+	}
+	if len(items) != 1 || items[0].Amount != "10.00" {
+		t.Errorf("unexpected return items: %+v", items)
+	}
+
+	tests := []struct {
+		name    string
+		orderID int64
+		lines   []Line
+		want    error
+	}{
+		{"more units than are left", order.ID, []Line{{OrderItemID: item.ID, Quantity: 2}}, ErrInvalidReturn},
+		{"no items", order.ID, nil, ErrInvalidReturn},
+		{"item of another order", order.ID, []Line{{OrderItemID: item.ID + 100, Quantity: 1}}, ErrInvalidReturn},
+		{"unknown order", order.ID + 100, []Line{{OrderItemID: item.ID, Quantity: 1}}, ErrOrderNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := s.Create(ctx, tt.orderID, "", tt.lines); !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateRefundsOnce(t *testing.T) {
+	ctx := context.Background()
+	refunder := &fakeRefunder{}
+	s, store := newService(t, refunder)
+	order, item := seed(t, store)
+
+	data, _, err := s.Create(ctx, order.ID, "", []Line{{OrderItemID: item.ID, Quantity: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = s.Update(ctx, data.ID, postgres.ReturnStatusApproved, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Receiving refunds the return; the requests racing it must not refund it again
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(status postgres.ReturnStatus) {
+			defer wg.Done()
+			s.Update(ctx, data.ID, status, nil)
+		}([]postgres.ReturnStatus{postgres.ReturnStatusReceived, postgres.ReturnStatusRefunded}[i%2])
+	}
+	wg.Wait()
+
+	// A refund that failed while receiving is retried by moving the return to refunded
+	stored, err := store.GetReturn(ctx, data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != postgres.ReturnStatusRefunded {
+		if _, _, err = s.Update(ctx, data.ID, postgres.ReturnStatusRefunded, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := refunder.refunds.Load(); got != 1 {
+		t.Errorf("provider refunded %d times, want 1", got)
+	}
+
+	payments, err := store.SearchPaymentsByOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 1 || payments[0].RefundedAmount != "20.00" || payments[0].Status != postgres.PaymentStatusRefunded {
+		t.Errorf("unexpected payments: %+v", payments)
+	}
+}
+
+func TestUpdateReleasesFailedRefund(t *testing.T) {
+	ctx := context.Background()
+	refunder := &fakeRefunder{err: errors.New("provider is down")}
+	s, store := newService(t, refunder)
+	order, item := seed(t, store)
+
+	data, _, err := s.Create(ctx, order.ID, "", []Line{{OrderItemID: item.ID, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range []postgres.ReturnStatus{postgres.ReturnStatusApproved, postgres.ReturnStatusReceived} {
+		if _, _, err = s.Update(ctx, data.ID, status, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stored, err := store.GetReturn(ctx, data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != postgres.ReturnStatusReceived {
+		t.Errorf("return is %s after a failed refund, want received", stored.Status)
+	}
+
+	product, err := store.GetProduct(ctx, item.ProductID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.StockQuantity != 6 {
+		t.Errorf("stock is %d after receiving one unit, want 6", product.StockQuantity)
+	}
+}
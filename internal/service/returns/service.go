@@ -0,0 +1,146 @@
+package returns
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ecommerce_management/internal/provider/epay"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/notification"
+)
+
+// Repository is the subset of queries the Service needs to check and restock returns.
+// It is passed to every call so that the work happens inside the transaction of the request.
+type Repository interface {
+	GetOrderItem(ctx context.Context, id int64) (postgres.OrderItem, error)
+	SumReturnedQuantity(ctx context.Context, orderItemID int64) (int32, error)
+	RestockProduct(ctx context.Context, arg postgres.RestockProductParams) (postgres.Product, error)
+	RestockProductVariant(ctx context.Context, arg postgres.RestockProductVariantParams) (postgres.ProductVariant, error)
+}
+
+// Refunder is the payment provider a Service returns money through; *epay.Client implements it
+type Refunder interface {
+	GetPaymentToken(ctx context.Context, src *epay.PaymentRequest) (epay.TokenResponse, error)
+	GetStatus(ctx context.Context, token string, invoiceID string) (epay.StatusResponse, error)
+	Cancel(ctx context.Context, token, transactionID string) error
+	Refund(ctx context.Context, token, transactionID, amount string) error
+}
+
+// Recorder records refunds of payments in the audit log; *audit.Service implements it
+type Recorder interface {
+	Record(ctx context.Context, action, entityType string, entityID int64, before, after any)
+}
+
+// Publisher publishes return and payment events to webhook subscribers; *webhook.Service implements it
+type Publisher interface {
+	Publish(ctx context.Context, event string, data any)
+}
+
+// Notifier emails customers about refunds of their orders; *notification.Service implements it
+type Notifier interface {
+	NotifyOrder(ctx context.Context, event notification.Event, orderID int64)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service checks return requests, moves them through approval and receipt, and refunds the returned lines
+type Service struct {
+	repository    repository.Store
+	refunder      Refunder
+	audit         Recorder
+	webhooks      Publisher
+	notifications Notifier
+	now           func() time.Time
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		now: time.Now,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+
+	if s.repository == nil {
+		return nil, errors.New("returns service requires a repository")
+	}
+	return
+}
+
+// WithRepository applies the store returns are kept in
+func WithRepository(repository repository.Store) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithRefunder applies the payment provider refunds are made through
+func WithRefunder(refunder Refunder) Configuration {
+	return func(s *Service) error {
+		s.refunder = refunder
+		return nil
+	}
+}
+
+// WithClock replaces the clock used to stamp returns
+func WithClock(now func() time.Time) Configuration {
+	return func(s *Service) error {
+		s.now = now
+		return nil
+	}
+}
+
+// WithAudit records refunds of payments in the audit log
+func WithAudit(audit Recorder) Configuration {
+	return func(s *Service) error {
+		s.audit = audit
+		return nil
+	}
+}
+
+// WithWebhooks publishes return and payment events to webhook subscribers
+func WithWebhooks(webhooks Publisher) Configuration {
+	return func(s *Service) error {
+		s.webhooks = webhooks
+		return nil
+	}
+}
+
+// WithNotifications emails customers when their orders are refunded
+func WithNotifications(notifications Notifier) Configuration {
+	return func(s *Service) error {
+		s.notifications = notifications
+		return nil
+	}
+}
+
+func (s *Service) record(ctx context.Context, action string, id int64, before, after any) {
+	if s.audit != nil {
+		s.audit.Record(ctx, action, audit.EntityPayment, id, before, after)
+	}
+}
+
+func (s *Service) publish(ctx context.Context, event string, data any) {
+	if s.webhooks != nil {
+		s.webhooks.Publish(ctx, event, data)
+	}
+}
+
+func (s *Service) notify(ctx context.Context, event notification.Event, id int64) {
+	if s.notifications != nil {
+		s.notifications.NotifyOrder(ctx, event, id)
+	}
+}
@@ -0,0 +1,207 @@
+package shipping
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	domain "ecommerce_management/internal/domain/shipping"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+var (
+	ErrMethodNotFound = errors.New("shipping method not found")
+	ErrInvalidMethod  = errors.New("invalid shipping method")
+)
+
+// ListMethods returns every shipping method with its weight brackets
+func (s *Service) ListMethods(ctx context.Context) ([]domain.Method, error) {
+	methods, err := s.repository.ListShippingMethods(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := make([]domain.Method, 0, len(methods))
+	for _, method := range methods {
+		rates, err := s.repository.ListShippingRatesByMethod(ctx, method.ID)
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, domain.ParseMethod(method, rates))
+	}
+
+	return dst, nil
+}
+
+// GetMethod returns a shipping method with its weight brackets
+func (s *Service) GetMethod(ctx context.Context, id int64) (method postgres.ShippingMethod, rates []postgres.ShippingRate, err error) {
+	method, err = s.repository.GetShippingMethod(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: shipping method ID %d", ErrMethodNotFound, id)
+		}
+		return
+	}
+
+	rates, err = s.repository.ListShippingRatesByMethod(ctx, id)
+	return
+}
+
+// CreateMethod adds a shipping method with its weight brackets
+func (s *Service) CreateMethod(ctx context.Context, req domain.MethodRequest) (method postgres.ShippingMethod, rates []postgres.ShippingRate, err error) {
+	if err = validateMethod(&req); err != nil {
+		return
+	}
+
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	method, err = tx.CreateShippingMethod(ctx, postgres.CreateShippingMethodParams{
+		Name:     req.Name,
+		Carrier:  req.Carrier,
+		RateType: req.RateType,
+		Price:    req.Price,
+		FreeOver: nullString(req.FreeOver),
+		Active:   req.Active == nil || *req.Active,
+	})
+	if err != nil {
+		return
+	}
+
+	if rates, err = replaceRates(ctx, tx, method.ID, req.Rates); err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// UpdateMethod replaces a shipping method and its weight brackets; orders keep the cost they were placed with
+func (s *Service) UpdateMethod(ctx context.Context, id int64, req domain.MethodRequest) (method postgres.ShippingMethod, rates []postgres.ShippingRate, err error) {
+	if err = validateMethod(&req); err != nil {
+		return
+	}
+
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	method, err = tx.UpdateShippingMethod(ctx, postgres.UpdateShippingMethodParams{
+		ID:       id,
+		Name:     req.Name,
+		Carrier:  req.Carrier,
+		RateType: req.RateType,
+		Price:    req.Price,
+		FreeOver: nullString(req.FreeOver),
+		Active:   req.Active == nil || *req.Active,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: shipping method ID %d", ErrMethodNotFound, id)
+		}
+		return
+	}
+
+	if rates, err = replaceRates(ctx, tx, method.ID, req.Rates); err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// DeleteMethod removes a shipping method; orders and shipments that used it keep their cost and carrier
+func (s *Service) DeleteMethod(ctx context.Context, id int64) error {
+	return s.repository.DeleteShippingMethod(ctx, id)
+}
+
+// validateMethod checks the prices and that weight methods have distinct brackets.
+// Problems with the request are errors wrapping ErrInvalidMethod.
+func validateMethod(req *domain.MethodRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Carrier = strings.TrimSpace(req.Carrier)
+	if req.Name == "" || req.Carrier == "" {
+		return fmt.Errorf("%w: name and carrier are required", ErrInvalidMethod)
+	}
+
+	if !req.RateType.Valid() {
+		return fmt.Errorf("%w: invalid rate_type: %s", ErrInvalidMethod, req.RateType)
+	}
+
+	if req.Price == "" {
+		req.Price = "0"
+	}
+	if price, err := strconv.ParseFloat(req.Price, 64); err != nil || price < 0 {
+		return fmt.Errorf("%w: invalid price: %s", ErrInvalidMethod, req.Price)
+	}
+
+	if req.FreeOver != nil {
+		if value, err := strconv.ParseFloat(*req.FreeOver, 64); err != nil || value < 0 {
+			return fmt.Errorf("%w: invalid free_over: %s", ErrInvalidMethod, *req.FreeOver)
+		}
+	}
+
+	if req.RateType == postgres.ShippingRateTypeFlat {
+		if len(req.Rates) > 0 {
+			return fmt.Errorf("%w: rates only apply to weight methods", ErrInvalidMethod)
+		}
+		return nil
+	}
+
+	if len(req.Rates) == 0 {
+		return fmt.Errorf("%w: weight methods need at least one rate", ErrInvalidMethod)
+	}
+	weights := make([]int32, 0, len(req.Rates))
+	for _, rate := range req.Rates {
+		if rate.MaxWeightGrams <= 0 {
+			return fmt.Errorf("%w: max_weight_grams must be positive", ErrInvalidMethod)
+		}
+		if slices.Contains(weights, rate.MaxWeightGrams) {
+			return fmt.Errorf("%w: duplicate rate for %d g", ErrInvalidMethod, rate.MaxWeightGrams)
+		}
+		weights = append(weights, rate.MaxWeightGrams)
+
+		if price, err := strconv.ParseFloat(rate.Price, 64); err != nil || price < 0 {
+			return fmt.Errorf("%w: invalid rate price: %s", ErrInvalidMethod, rate.Price)
+		}
+	}
+
+	return nil
+}
+
+// replaceRates swaps the weight brackets of a method for the requested ones
+func replaceRates(ctx context.Context, tx repository.Tx, methodID int64, rates []domain.Rate) ([]postgres.ShippingRate, error) {
+	if err := tx.DeleteShippingRatesByMethod(ctx, methodID); err != nil {
+		return nil, err
+	}
+
+	for _, rate := range rates {
+		_, err := tx.CreateShippingRate(ctx, postgres.CreateShippingRateParams{
+			MethodID:       methodID,
+			MaxWeightGrams: rate.MaxWeightGrams,
+			Price:          rate.Price,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tx.ListShippingRatesByMethod(ctx, methodID)
+}
+
+func nullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
@@ -0,0 +1,85 @@
+package shipping
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// KindShipping is the order adjustment kind recorded for the shipping cost
+const KindShipping = "shipping"
+
+var ErrNotAvailable = errors.New("shipping method not available")
+
+// Quote is the cost of shipping an order with a method
+type Quote struct {
+	MethodID    int64
+	Description string
+	Amount      float64
+}
+
+// Quote prices an order of the given subtotal and weight.
+// A flat method costs its price; a weight method adds its price to the lightest bracket that fits the weight.
+// Either is free when the subtotal reaches free_over. An inactive method, or a weight no bracket fits, wraps ErrNotAvailable.
+func (s *Service) Quote(ctx context.Context, repository Repository, method postgres.ShippingMethod, subtotal float64, weightGrams int64) (quote Quote, err error) {
+	quote = Quote{MethodID: method.ID, Description: method.Name}
+
+	if !method.Active {
+		return quote, fmt.Errorf("%w: %s is not active", ErrNotAvailable, method.Name)
+	}
+
+	if method.FreeOver.Valid {
+		threshold, err := strconv.ParseFloat(method.FreeOver.String, 64)
+		if err != nil {
+			return quote, err
+		}
+		if subtotal >= threshold {
+			return quote, nil
+		}
+	}
+
+	if quote.Amount, err = strconv.ParseFloat(method.Price, 64); err != nil {
+		return
+	}
+
+	if method.RateType == postgres.ShippingRateTypeWeight {
+		if weightGrams > math.MaxInt32 {
+			return quote, fmt.Errorf("%w: %s does not ship orders of %d g", ErrNotAvailable, method.Name, weightGrams)
+		}
+
+		rate, err := repository.GetShippingRateForWeight(ctx, postgres.GetShippingRateForWeightParams{
+			MethodID:    method.ID,
+			WeightGrams: int32(weightGrams),
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w: %s does not ship orders of %d g", ErrNotAvailable, method.Name, weightGrams)
+			}
+			return quote, err
+		}
+
+		price, err := strconv.ParseFloat(rate.Price, 64)
+		if err != nil {
+			return quote, err
+		}
+		quote.Amount += price
+	}
+
+	quote.Amount = math.Round(quote.Amount*100) / 100
+	return
+}
+
+// Record adds the shipping cost to the order as an adjustment
+func (s *Service) Record(ctx context.Context, repository Repository, orderID int64, quote Quote) (postgres.OrderAdjustment, error) {
+	return repository.CreateOrderAdjustment(ctx, postgres.CreateOrderAdjustmentParams{
+		OrderID:     orderID,
+		Kind:        KindShipping,
+		Description: quote.Description,
+		Amount:      fmt.Sprintf("%.2f", quote.Amount),
+	})
+}
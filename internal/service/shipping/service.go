@@ -0,0 +1,105 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/notification"
+)
+
+// Repository is the subset of queries the Service needs to price shipping.
+// It is passed to every call so that the cost is recorded inside the transaction that creates the order.
+type Repository interface {
+	GetShippingRateForWeight(ctx context.Context, arg postgres.GetShippingRateForWeightParams) (postgres.ShippingRate, error)
+	CreateOrderAdjustment(ctx context.Context, arg postgres.CreateOrderAdjustmentParams) (postgres.OrderAdjustment, error)
+}
+
+// Publisher publishes shipment and order events to webhook subscribers; *webhook.Service implements it
+type Publisher interface {
+	Publish(ctx context.Context, event string, data any)
+}
+
+// Notifier emails customers when their orders ship and arrive; *notification.Service implements it
+type Notifier interface {
+	NotifyOrder(ctx context.Context, event notification.Event, orderID int64)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service keeps and prices shipping methods and tracks shipments through to delivery
+type Service struct {
+	repository    repository.Store
+	webhooks      Publisher
+	notifications Notifier
+	now           func() time.Time
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		now: time.Now,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+
+	if s.repository == nil {
+		return nil, errors.New("shipping service requires a repository")
+	}
+	return
+}
+
+// WithRepository applies the store shipping methods and shipments are kept in
+func WithRepository(repository repository.Store) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithWebhooks publishes shipment events and the order changes they cause to webhook subscribers
+func WithWebhooks(webhooks Publisher) Configuration {
+	return func(s *Service) error {
+		s.webhooks = webhooks
+		return nil
+	}
+}
+
+// WithNotifications emails customers when their orders ship and are delivered
+func WithNotifications(notifications Notifier) Configuration {
+	return func(s *Service) error {
+		s.notifications = notifications
+		return nil
+	}
+}
+
+// WithClock replaces the clock used to stamp shipments
+func WithClock(now func() time.Time) Configuration {
+	return func(s *Service) error {
+		s.now = now
+		return nil
+	}
+}
+
+func (s *Service) publish(ctx context.Context, event string, data any) {
+	if s.webhooks != nil {
+		s.webhooks.Publish(ctx, event, data)
+	}
+}
+
+func (s *Service) notify(ctx context.Context, event notification.Event, id int64) {
+	if s.notifications != nil {
+		s.notifications.NotifyOrder(ctx, event, id)
+	}
+}
@@ -0,0 +1,219 @@
+package shipping
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	domain "ecommerce_management/internal/domain/shipping"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/notification"
+	"ecommerce_management/internal/service/webhook"
+)
+
+var (
+	ErrShipmentNotFound  = errors.New("shipment not found")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrInvalidShipment   = errors.New("invalid shipment")
+	ErrInvalidTransition = errors.New("invalid shipment status change")
+)
+
+// shipmentSteps orders shipment statuses; a shipment only moves forward
+var shipmentSteps = map[postgres.ShipmentStatus]int{
+	postgres.ShipmentStatusPending:   0,
+	postgres.ShipmentStatusShipped:   1,
+	postgres.ShipmentStatusDelivered: 2,
+}
+
+// orderSteps orders the order statuses that shipments can move an order through
+var orderSteps = map[postgres.OrderStatus]int{
+	postgres.OrderStatusNew:        0,
+	postgres.OrderStatusProcessing: 1,
+	postgres.OrderStatusShipped:    2,
+	postgres.OrderStatusDelivered:  3,
+	postgres.OrderStatusCompleted:  4,
+}
+
+// Transition moves a shipment to status, stamping when it shipped and when it was delivered.
+// Skipping a step stamps both; going back is an error wrapping ErrInvalidTransition.
+func (s *Service) Transition(shipment postgres.Shipment, status postgres.ShipmentStatus) (postgres.Shipment, error) {
+	next, ok := shipmentSteps[status]
+	if !ok {
+		return shipment, fmt.Errorf("%w: unknown status %s", ErrInvalidTransition, status)
+	}
+	if next < shipmentSteps[shipment.Status] {
+		return shipment, fmt.Errorf("%w: %s shipment cannot become %s", ErrInvalidTransition, shipment.Status, status)
+	}
+
+	now := s.now()
+	if next >= shipmentSteps[postgres.ShipmentStatusShipped] && !shipment.ShippedAt.Valid {
+		shipment.ShippedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	if status == postgres.ShipmentStatusDelivered && !shipment.DeliveredAt.Valid {
+		shipment.DeliveredAt = sql.NullTime{Time: now, Valid: true}
+	}
+	shipment.Status = status
+
+	return shipment, nil
+}
+
+// OrderStatus returns the status an order reaches once a shipment has moved to status:
+// shipped as soon as one shipment leaves, delivered once none is outstanding. The order never moves back.
+func (s *Service) OrderStatus(current postgres.OrderStatus, status postgres.ShipmentStatus, undelivered int64) postgres.OrderStatus {
+	target := current
+	switch {
+	case status == postgres.ShipmentStatusDelivered && undelivered == 0:
+		target = postgres.OrderStatusDelivered
+	case status != postgres.ShipmentStatusPending:
+		target = postgres.OrderStatusShipped
+	}
+
+	if orderSteps[target] > orderSteps[current] {
+		return target
+	}
+	return current
+}
+
+// GetShipment returns a shipment by ID
+func (s *Service) GetShipment(ctx context.Context, id int64) (postgres.Shipment, error) {
+	shipment, err := s.repository.GetShipment(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: shipment ID %d", ErrShipmentNotFound, id)
+	}
+	return shipment, err
+}
+
+// ListShipments returns the shipments of an order
+func (s *Service) ListShipments(ctx context.Context, orderID int64) ([]postgres.Shipment, error) {
+	return s.repository.ListShipmentsByOrder(ctx, orderID)
+}
+
+// CreateShipment adds a shipment to an order. The carrier defaults to the one of the shipping method chosen at checkout.
+func (s *Service) CreateShipment(ctx context.Context, orderID int64, req domain.ShipmentRequest) (shipment postgres.Shipment, err error) {
+	order, err := s.repository.GetOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: order ID %d", ErrOrderNotFound, orderID)
+		}
+		return
+	}
+
+	carrier := strings.TrimSpace(req.Carrier)
+	if carrier == "" && order.ShippingMethodID.Valid {
+		method, err := s.repository.GetShippingMethod(ctx, order.ShippingMethodID.Int64)
+		if err != nil {
+			return shipment, err
+		}
+		carrier = method.Carrier
+	}
+	if carrier == "" {
+		return shipment, fmt.Errorf("%w: carrier is required", ErrInvalidShipment)
+	}
+
+	shipment, err = s.repository.CreateShipment(ctx, postgres.CreateShipmentParams{
+		OrderID:          order.ID,
+		ShippingMethodID: order.ShippingMethodID,
+		Carrier:          carrier,
+		TrackingNumber:   nullString(req.TrackingNumber),
+	})
+	if err != nil {
+		return
+	}
+
+	s.publish(ctx, webhook.EventShipmentCreated, shipment)
+
+	return
+}
+
+// UpdateShipment sets the carrier, tracking number and status of a shipment; empty fields keep their value.
+// The first shipment to ship marks the order shipped, and the order is delivered once all its shipments are.
+func (s *Service) UpdateShipment(ctx context.Context, id int64, req domain.ShipmentUpdateRequest) (shipment postgres.Shipment, err error) {
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	shipment, err = tx.GetShipment(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: shipment ID %d", ErrShipmentNotFound, id)
+		}
+		return
+	}
+
+	if carrier := strings.TrimSpace(req.Carrier); carrier != "" {
+		shipment.Carrier = carrier
+	}
+	if req.TrackingNumber != nil {
+		shipment.TrackingNumber = nullString(req.TrackingNumber)
+	}
+	if req.Status != "" {
+		if shipment, err = s.Transition(shipment, req.Status); err != nil {
+			return
+		}
+	}
+
+	shipment, err = tx.UpdateShipment(ctx, postgres.UpdateShipmentParams{
+		ID:             shipment.ID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status,
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+	})
+	if err != nil {
+		return
+	}
+
+	// Move the order forward once its shipments leave and arrive
+	previous, err := tx.GetOrderForUpdate(ctx, shipment.OrderID)
+	if err != nil {
+		return
+	}
+	undelivered, err := tx.CountUndeliveredShipments(ctx, shipment.OrderID)
+	if err != nil {
+		return
+	}
+
+	order := previous
+	if status := s.OrderStatus(previous.Status, shipment.Status, undelivered); status != previous.Status {
+		order, err = tx.SetOrderStatus(ctx, postgres.SetOrderStatusParams{
+			ID:     previous.ID,
+			Status: status,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return
+	}
+
+	s.publish(ctx, webhook.EventShipmentUpdated, shipment)
+	if order.Status != previous.Status {
+		switch order.Status {
+		case postgres.OrderStatusShipped:
+			s.notify(ctx, notification.EventOrderShipped, order.ID)
+		case postgres.OrderStatusDelivered:
+			s.notify(ctx, notification.EventOrderDelivered, order.ID)
+		}
+		s.publish(ctx, webhook.EventOrderUpdated, order)
+	}
+
+	return
+}
+
+// DeleteShipment removes a shipment; its order keeps its status
+func (s *Service) DeleteShipment(ctx context.Context, id int64) error {
+	if err := s.repository.DeleteShipment(ctx, id); err != nil {
+		return err
+	}
+
+	s.publish(ctx, webhook.EventShipmentDeleted, map[string]int64{"id": id})
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "ecommerce_management/internal/domain/shipping"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func newService(t *testing.T) (*Service, repository.Store) {
+	t.Helper()
+
+	repo, err := repository.New(repository.WithMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	s, err := New(WithRepository(repo), WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, repo
+}
+
+func TestMethods(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newService(t)
+
+	method, rates, err := s.CreateMethod(ctx, domain.MethodRequest{
+		Name:     " Standard ",
+		Carrier:  "DHL",
+		RateType: postgres.ShippingRateTypeWeight,
+		Price:    "1.00",
+		Rates: []domain.Rate{
+			{MaxWeightGrams: 1000, Price: "4.00"},
+			{MaxWeightGrams: 5000, Price: "8.00"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method.Name != "Standard" || !method.Active || len(rates) != 2 {
+		t.Errorf("unexpected method %+v with rates %+v", method, rates)
+	}
+
+	// Updating replaces the brackets
+	method, rates, err = s.UpdateMethod(ctx, method.ID, domain.MethodRequest{
+		Name:     "Standard",
+		Carrier:  "DHL",
+		RateType: postgres.ShippingRateTypeFlat,
+		Price:    "5.00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method.RateType != postgres.ShippingRateTypeFlat || len(rates) != 0 {
+		t.Errorf("unexpected method %+v with rates %+v", method, rates)
+	}
+
+	tests := []struct {
+		name string
+		id   int64
+		req  domain.MethodRequest
+		want error
+	}{
+		{"no carrier", method.ID, domain.MethodRequest{Name: "Standard", RateType: postgres.ShippingRateTypeFlat}, ErrInvalidMethod},
+		{"negative price", method.ID, domain.MethodRequest{Name: "Standard", Carrier: "DHL", RateType: postgres.ShippingRateTypeFlat, Price: "-1"}, ErrInvalidMethod},
+		{"weight without rates", method.ID, domain.MethodRequest{Name: "Standard", Carrier: "DHL", RateType: postgres.ShippingRateTypeWeight}, ErrInvalidMethod},
+		{"duplicate rates", method.ID, domain.MethodRequest{Name: "Standard", Carrier: "DHL", RateType: postgres.ShippingRateTypeWeight,
+			Rates: []domain.Rate{{MaxWeightGrams: 1000, Price: "1"}, {MaxWeightGrams: 1000, Price: "2"}}}, ErrInvalidMethod},
+		{"missing method", method.ID + 100, domain.MethodRequest{Name: "Standard", Carrier: "DHL", RateType: postgres.ShippingRateTypeFlat}, ErrMethodNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := s.UpdateMethod(ctx, tt.id, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestShipmentsMoveOrder(t *testing.T) {
+	ctx := context.Background()
+	s, store := newService(t)
+
+	user, err := store.CreateUser(ctx, postgres.CreateUserParams{
+		FullName: "Jane Doe",
+		Email:    "jane@example.com",
+		Role:     "customer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := store.CreateOrder(ctx, postgres.CreateOrderParams{
+		UserID:      user.ID,
+		TotalAmount: "20.00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.CreateShipment(ctx, order.ID, domain.ShipmentRequest{}); !errors.Is(err, ErrInvalidShipment) {
+		t.Errorf("got error %v creating a shipment without a carrier, want %v", err, ErrInvalidShipment)
+	}
+	if _, err = s.CreateShipment(ctx, order.ID+100, domain.ShipmentRequest{Carrier: "DHL"}); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("got error %v creating a shipment of a missing order, want %v", err, ErrOrderNotFound)
+	}
+
+	first, err := s.CreateShipment(ctx, order.ID, domain.ShipmentRequest{Carrier: "DHL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.CreateShipment(ctx, order.ID, domain.ShipmentRequest{Carrier: "UPS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		shipment int64
+		status   postgres.ShipmentStatus
+		want     postgres.OrderStatus
+	}{
+		{first.ID, postgres.ShipmentStatusShipped, postgres.OrderStatusShipped},
+		{first.ID, postgres.ShipmentStatusDelivered, postgres.OrderStatusShipped},
+		{second.ID, postgres.ShipmentStatusDelivered, postgres.OrderStatusDelivered},
+	}
+	for _, step := range steps {
+		shipment, err := s.UpdateShipment(ctx, step.shipment, domain.ShipmentUpdateRequest{Status: step.status})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !shipment.ShippedAt.Valid {
+			t.Errorf("shipment %d is %s without a shipping time", shipment.ID, shipment.Status)
+		}
+
+		order, err = store.GetOrder(ctx, order.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if order.Status != step.want {
+			t.Errorf("order is %s after shipment %d became %s, want %s", order.Status, step.shipment, step.status, step.want)
+		}
+	}
+
+	_, err = s.UpdateShipment(ctx, first.ID, domain.ShipmentUpdateRequest{Status: postgres.ShipmentStatusPending})
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("got error %v moving a delivered shipment back, want %v", err, ErrInvalidTransition)
+	}
+	if _, err = s.UpdateShipment(ctx, second.ID+100, domain.ShipmentUpdateRequest{}); !errors.Is(err, ErrShipmentNotFound) {
+		t.Errorf("got error %v updating a missing shipment, want %v", err, ErrShipmentNotFound)
+	}
+}
@@ -0,0 +1,159 @@
+package tax
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// KindTax is the order adjustment kind recorded for tax charged on top of net prices
+const KindTax = "tax"
+
+// Line is an order item as seen by the tax rules; Amount is the line price before discounts
+type Line struct {
+	TaxClass string
+	Amount   float64
+}
+
+// LineTax is the tax of a line after its share of the order discounts
+type LineTax struct {
+	Rate  float64
+	Name  string
+	Net   float64
+	Tax   float64
+	Gross float64
+}
+
+// Result is the tax of an order; Gross is what the customer pays
+type Result struct {
+	Country          string
+	PricesIncludeTax bool
+	Lines            []LineTax
+	Net              float64
+	Tax              float64
+	Gross            float64
+}
+
+// Calculate taxes every line at the rate of its tax class in the destination country, see Destination.
+// discount is the positive amount taken off the order by promotions; it is shared across the lines in proportion to their amount.
+// A class without a rate for the country is not taxed.
+func (s *Service) Calculate(ctx context.Context, repository Repository, country string, lines []Line, discount float64) (result Result, err error) {
+	return s.calculate(ctx, repository, country, s.pricesIncludeTax, lines, discount)
+}
+
+// Recalculate taxes the lines of an existing order as Calculate does, keeping the destination
+// and the prices_include_tax setting the order was placed with
+func (s *Service) Recalculate(ctx context.Context, repository Repository, order postgres.Order, lines []Line, discount float64) (result Result, err error) {
+	return s.calculate(ctx, repository, order.DestinationCountry, order.PricesIncludeTax, lines, discount)
+}
+
+func (s *Service) calculate(ctx context.Context, repository Repository, country string, pricesIncludeTax bool, lines []Line, discount float64) (result Result, err error) {
+	if result.Country, err = s.Destination(country); err != nil {
+		return
+	}
+	result.PricesIncludeTax = pricesIncludeTax
+
+	var subtotal float64
+	for _, line := range lines {
+		subtotal += line.Amount
+	}
+	discount = min(max(discount, 0), subtotal)
+
+	rates := make(map[string]postgres.TaxRate)
+	result.Lines = make([]LineTax, 0, len(lines))
+	remaining := discount
+	for i, line := range lines {
+		class := NormalizeClass(line.TaxClass)
+		rate, ok := rates[class]
+		if !ok {
+			rate, err = repository.GetTaxRateByClass(ctx, postgres.GetTaxRateByClassParams{
+				TaxClass: class,
+				Country:  result.Country,
+			})
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return
+			}
+			rates[class], err = rate, nil
+		}
+
+		value := 0.0
+		if rate.Rate != "" {
+			if value, err = strconv.ParseFloat(rate.Rate, 64); err != nil {
+				return
+			}
+		}
+
+		// The last line takes what is left of the discount so that rounding never loses a cent
+		share := remaining
+		if i < len(lines)-1 && subtotal > 0 {
+			share = min(round(discount*line.Amount/subtotal), remaining)
+		}
+		remaining -= share
+
+		tax := LineTax{Rate: value, Name: rate.Name}
+		if pricesIncludeTax {
+			tax.Gross = round(line.Amount - share)
+			tax.Tax = round(tax.Gross * value / (1 + value))
+			tax.Net = round(tax.Gross - tax.Tax)
+		} else {
+			tax.Net = round(line.Amount - share)
+			tax.Tax = round(tax.Net * value)
+			tax.Gross = round(tax.Net + tax.Tax)
+		}
+
+		result.Lines = append(result.Lines, tax)
+		result.Net += tax.Net
+		result.Tax += tax.Tax
+		result.Gross += tax.Gross
+	}
+
+	result.Net, result.Tax, result.Gross = round(result.Net), round(result.Tax), round(result.Gross)
+	return
+}
+
+// Record adds the tax to the order as one adjustment per rate when prices exclude it.
+// Tax included in the prices is already part of the items and is not recorded again.
+func (s *Service) Record(ctx context.Context, repository Repository, orderID int64, result Result) (adjustments []postgres.OrderAdjustment, err error) {
+	adjustments = []postgres.OrderAdjustment{}
+	if result.PricesIncludeTax {
+		return
+	}
+
+	var names []string
+	amounts := make(map[string]float64)
+	for _, line := range result.Lines {
+		if line.Tax == 0 {
+			continue
+		}
+		if _, ok := amounts[line.Name]; !ok {
+			names = append(names, line.Name)
+		}
+		amounts[line.Name] += line.Tax
+	}
+
+	for _, name := range names {
+		var created postgres.OrderAdjustment
+		created, err = repository.CreateOrderAdjustment(ctx, postgres.CreateOrderAdjustmentParams{
+			OrderID:     orderID,
+			Kind:        KindTax,
+			Description: name,
+			Amount:      fmt.Sprintf("%.2f", amounts[name]),
+		})
+		if err != nil {
+			return
+		}
+		adjustments = append(adjustments, created)
+	}
+
+	return
+}
+
+// round rounds an amount to cents
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,91 @@
+package tax
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+var (
+	ErrRateNotFound = errors.New("tax rate not found")
+	ErrInvalidRate  = errors.New("invalid tax rate")
+)
+
+// ListRates returns every configured tax rate
+func (s *Service) ListRates(ctx context.Context) ([]postgres.TaxRate, error) {
+	return s.repository.ListTaxRates(ctx)
+}
+
+// GetRate returns a tax rate
+func (s *Service) GetRate(ctx context.Context, id int64) (data postgres.TaxRate, err error) {
+	data, err = s.repository.GetTaxRate(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: tax rate ID %d", ErrRateNotFound, id)
+	}
+	return
+}
+
+// CreateRate adds the rate of a tax class in a country; no other rate may cover the same pair
+func (s *Service) CreateRate(ctx context.Context, arg postgres.CreateTaxRateParams) (data postgres.TaxRate, err error) {
+	if err = s.validateRate(ctx, 0, &arg.TaxClass, &arg.Country, arg.Rate, &arg.Name); err != nil {
+		return
+	}
+
+	return s.repository.CreateTaxRate(ctx, arg)
+}
+
+// UpdateRate replaces a tax rate; orders keep the rate they were placed with
+func (s *Service) UpdateRate(ctx context.Context, arg postgres.UpdateTaxRateParams) (data postgres.TaxRate, err error) {
+	if err = s.validateRate(ctx, arg.ID, &arg.TaxClass, &arg.Country, arg.Rate, &arg.Name); err != nil {
+		return
+	}
+
+	data, err = s.repository.UpdateTaxRate(ctx, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: tax rate ID %d", ErrRateNotFound, arg.ID)
+	}
+	return
+}
+
+// DeleteRate removes a tax rate, leaving its class untaxed in its country
+func (s *Service) DeleteRate(ctx context.Context, id int64) error {
+	return s.repository.DeleteTaxRate(ctx, id)
+}
+
+// validateRate normalises the class, defaulting it to the standard one, and the country and checks that no other
+// rate covers the same pair
+func (s *Service) validateRate(ctx context.Context, id int64, class, country *string, rate string, name *string) (err error) {
+	*class = NormalizeClass(*class)
+
+	if *country, err = NormalizeCountry(*country); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRate, err)
+	}
+
+	value, err := strconv.ParseFloat(rate, 64)
+	if err != nil || value < 0 || value >= 1 {
+		return fmt.Errorf("%w: rate %q, expected a fraction such as 0.12", ErrInvalidRate, rate)
+	}
+
+	*name = strings.TrimSpace(*name)
+	if *name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRate)
+	}
+
+	existing, err := s.repository.GetTaxRateByClass(ctx, postgres.GetTaxRateByClassParams{
+		TaxClass: *class,
+		Country:  *country,
+	})
+	if err == nil && existing.ID != id {
+		return fmt.Errorf("%w: a rate for %s in %s already exists", ErrInvalidRate, *class, *country)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package tax
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+// DefaultClass is the tax class of products that do not name one
+const DefaultClass = "standard"
+
+// Repository is the subset of queries the Service needs to tax orders.
+// It is passed to every call so that rates are read and recorded inside the transaction that creates the order.
+type Repository interface {
+	GetTaxRateByClass(ctx context.Context, arg postgres.GetTaxRateByClassParams) (postgres.TaxRate, error)
+	CreateOrderAdjustment(ctx context.Context, arg postgres.CreateOrderAdjustmentParams) (postgres.OrderAdjustment, error)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service keeps the rates configured per tax class and destination country and computes the tax of order lines from them
+type Service struct {
+	repository       repository.Store
+	pricesIncludeTax bool
+	defaultCountry   string
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		pricesIncludeTax: true,
+		defaultCountry:   "KZ",
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+
+	if s.repository == nil {
+		return nil, errors.New("tax service requires a repository")
+	}
+	return
+}
+
+// WithRepository applies the store tax rates are kept in
+func WithRepository(repository repository.Store) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithPricesIncludeTax sets whether catalogue prices are gross (tax included) or net (tax added on top)
+func WithPricesIncludeTax(include bool) Configuration {
+	return func(s *Service) error {
+		s.pricesIncludeTax = include
+		return nil
+	}
+}
+
+// WithDefaultCountry sets the destination assumed for orders that do not name one
+func WithDefaultCountry(country string) Configuration {
+	return func(s *Service) error {
+		if country == "" {
+			return nil
+		}
+		code, err := NormalizeCountry(country)
+		if err != nil {
+			return err
+		}
+		s.defaultCountry = code
+		return nil
+	}
+}
+
+// PricesIncludeTax reports whether catalogue prices are gross
+func (s *Service) PricesIncludeTax() bool {
+	return s.pricesIncludeTax
+}
+
+// Destination returns the normalised destination country of an order, or the default one when empty
+func (s *Service) Destination(country string) (string, error) {
+	if strings.TrimSpace(country) == "" {
+		return s.defaultCountry, nil
+	}
+	return NormalizeCountry(country)
+}
+
+// NormalizeClass returns the lower-case form tax classes are stored in, or DefaultClass when empty
+func NormalizeClass(class string) string {
+	if class = strings.ToLower(strings.TrimSpace(class)); class == "" {
+		return DefaultClass
+	}
+	return class
+}
+
+// ErrInvalidCountry is returned for a destination that is not an ISO 3166-1 alpha-2 code
+var ErrInvalidCountry = errors.New("country must be a two-letter ISO code")
+
+// NormalizeCountry returns the upper-case two-letter code rates are stored under
+func NormalizeCountry(country string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(country))
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return "", ErrInvalidCountry
+	}
+	return code, nil
+}
@@ -0,0 +1,156 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/provider/mail"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/pkg/log"
+)
+
+// RequestEmailVerification sends a new verification link to an unverified address.
+// Unknown addresses are ignored so that registered ones cannot be discovered.
+func (s *Service) RequestEmailVerification(ctx context.Context, email string) error {
+	data, err := s.repository.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if !data.EmailVerifiedAt.Valid {
+		s.sendVerificationEmail(ctx, data)
+	}
+	return nil
+}
+
+// ConfirmEmail marks the address a verification token was sent to as verified
+func (s *Service) ConfirmEmail(ctx context.Context, token string) (data postgres.User, err error) {
+	claims, err := s.tokens.ParseToken(token, auth.PurposeEmailVerification)
+	if err != nil {
+		return
+	}
+
+	if data, err = s.tokenUser(ctx, claims.UserID); err != nil {
+		return
+	}
+
+	// A token is bound to the address it was sent to and can be used only once
+	if data.EmailVerifiedAt.Valid || claims.State != auth.Fingerprint(data.Email) {
+		return data, auth.ErrInvalidToken
+	}
+
+	return s.repository.VerifyUserEmail(ctx, data.ID)
+}
+
+// RequestPasswordReset emails a password reset token.
+// Unknown addresses are ignored so that registered ones cannot be discovered.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	data, err := s.repository.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.tokens.IssueToken(auth.PurposePasswordReset, data.ID, auth.Fingerprint(data.PasswordHash), passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.link("/reset-password", token)
+	s.send(ctx, mail.Message{
+		To:      []string{data.Email},
+		Subject: "Reset your password",
+		Text: fmt.Sprintf("Hello %s,\n\nUse the token below to set a new password. It is valid for %s.\n\n%s\n\n%s\n\nIf you did not request a password reset, you can ignore this email.\n",
+			data.FullName, passwordResetTTL, token, link),
+	})
+
+	return nil
+}
+
+// ResetPassword sets the password of the user a reset token was issued to
+func (s *Service) ResetPassword(ctx context.Context, token, password string) error {
+	claims, err := s.tokens.ParseToken(token, auth.PurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	data, err := s.tokenUser(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Changing the password changes the fingerprint, so a token works only once
+	if claims.State != auth.Fingerprint(data.PasswordHash) {
+		return auth.ErrInvalidToken
+	}
+
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.UpdateUserPassword(ctx, postgres.UpdateUserPasswordParams{
+		ID:           data.ID,
+		PasswordHash: passwordHash,
+	})
+}
+
+// tokenUser returns the user a token was issued to; a token of a removed user is invalid
+func (s *Service) tokenUser(ctx context.Context, id int64) (data postgres.User, err error) {
+	data, err = s.repository.GetUser(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = auth.ErrInvalidToken
+	}
+	return
+}
+
+func (s *Service) sendVerificationEmail(ctx context.Context, data postgres.User) {
+	logger := log.LoggerFromContext(ctx).Named("sendVerificationEmail")
+
+	token, err := s.tokens.IssueToken(auth.PurposeEmailVerification, data.ID, auth.Fingerprint(data.Email), emailVerificationTTL)
+	if err != nil {
+		logger.Error("failed to issue verification token", zap.Error(err), zap.Int64("user_id", data.ID))
+		return
+	}
+
+	link := s.link("/verify-email", token)
+	s.send(ctx, mail.Message{
+		To:      []string{data.Email},
+		Subject: "Confirm your email address",
+		Text: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address with the token below. It is valid for %s.\n\n%s\n\n%s\n",
+			data.FullName, emailVerificationTTL, token, link),
+	})
+}
+
+// send delivers msg, logging instead of failing the request when the mail server is unavailable
+func (s *Service) send(ctx context.Context, msg mail.Message) {
+	logger := log.LoggerFromContext(ctx).Named("send")
+
+	if s.mailer == nil {
+		return
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logger.Error("failed to send email", zap.Error(err), zap.Strings("to", msg.To), zap.String("subject", msg.Subject))
+	}
+}
+
+// link builds a frontend URL carrying the token; empty when APP_URL is not configured
+func (s *Service) link(path, token string) string {
+	if s.appURL == "" {
+		return ""
+	}
+	return strings.TrimRight(s.appURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
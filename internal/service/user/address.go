@@ -0,0 +1,226 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ecommerce_management/internal/domain/address"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/tax"
+)
+
+var (
+	ErrAddressNotFound = errors.New("address not found")
+	ErrInvalidAddress  = errors.New("invalid address")
+)
+
+// ListAddresses returns the addresses of a user
+func (s *Service) ListAddresses(ctx context.Context, userID int64) ([]postgres.UserAddress, error) {
+	return s.repository.ListUserAddresses(ctx, userID)
+}
+
+// GetAddress returns an address of a user
+func (s *Service) GetAddress(ctx context.Context, userID, id int64) (data postgres.UserAddress, err error) {
+	data, err = s.repository.GetUserAddress(ctx, postgres.GetUserAddressParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: address ID %d of user ID %d", ErrAddressNotFound, id, userID)
+	}
+	return
+}
+
+// CreateAddress adds an address to a user. The first address of a type becomes its default,
+// and IsDefault moves the default to the new address.
+func (s *Service) CreateAddress(ctx context.Context, userID int64, req address.Request) (data postgres.UserAddress, err error) {
+	if err = normalizeAddress(&req); err != nil {
+		return
+	}
+
+	if _, err = s.repository.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: user ID %d", ErrNotFound, userID)
+		}
+		return
+	}
+
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	data, err = tx.CreateUserAddress(ctx, postgres.CreateUserAddressParams{
+		UserID:     userID,
+		Type:       req.Type,
+		FullName:   req.FullName,
+		Phone:      nullString(req.Phone),
+		Line1:      req.Line1,
+		Line2:      nullString(req.Line2),
+		City:       req.City,
+		Region:     nullString(req.Region),
+		PostalCode: nullString(req.PostalCode),
+		Country:    req.Country,
+	})
+	if err != nil {
+		return
+	}
+
+	if data, err = settleDefaultAddress(ctx, tx, data, req.IsDefault); err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// UpdateAddress replaces an address of a user; orders keep the copy they were placed with.
+// Changing the type of a default address makes another address the default of its old type.
+func (s *Service) UpdateAddress(ctx context.Context, userID, id int64, req address.Request) (data postgres.UserAddress, err error) {
+	if err = normalizeAddress(&req); err != nil {
+		return
+	}
+
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	current, err := tx.GetUserAddress(ctx, postgres.GetUserAddressParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: address ID %d of user ID %d", ErrAddressNotFound, id, userID)
+		}
+		return
+	}
+
+	data, err = tx.UpdateUserAddress(ctx, postgres.UpdateUserAddressParams{
+		ID:         id,
+		UserID:     userID,
+		Type:       req.Type,
+		FullName:   req.FullName,
+		Phone:      nullString(req.Phone),
+		Line1:      req.Line1,
+		Line2:      nullString(req.Line2),
+		City:       req.City,
+		Region:     nullString(req.Region),
+		PostalCode: nullString(req.PostalCode),
+		Country:    req.Country,
+	})
+	if err != nil {
+		return
+	}
+
+	if current.Type != data.Type {
+		err = tx.EnsureDefaultUserAddress(ctx, postgres.EnsureDefaultUserAddressParams{
+			UserID: current.UserID,
+			Type:   current.Type,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	if data, err = settleDefaultAddress(ctx, tx, data, req.IsDefault); err != nil {
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// DeleteAddress removes an address of a user; deleting a default address makes the oldest remaining address
+// of its type the default. Orders keep their copy.
+func (s *Service) DeleteAddress(ctx context.Context, userID, id int64) error {
+	tx, err := s.repository.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	data, err := tx.DeleteUserAddress(ctx, postgres.DeleteUserAddressParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: address ID %d of user ID %d", ErrAddressNotFound, id, userID)
+		}
+		return err
+	}
+
+	if data.IsDefault {
+		err = tx.EnsureDefaultUserAddress(ctx, postgres.EnsureDefaultUserAddressParams{
+			UserID: data.UserID,
+			Type:   data.Type,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// normalizeAddress checks the required fields of an address request and brings the country to its stored form.
+// Problems with the request are errors wrapping ErrInvalidAddress.
+func normalizeAddress(req *address.Request) (err error) {
+	switch {
+	case !req.Type.Valid():
+		return fmt.Errorf("%w: invalid address type %q", ErrInvalidAddress, req.Type)
+	case strings.TrimSpace(req.FullName) == "":
+		return fmt.Errorf("%w: full_name is required", ErrInvalidAddress)
+	case strings.TrimSpace(req.Line1) == "":
+		return fmt.Errorf("%w: line1 is required", ErrInvalidAddress)
+	case strings.TrimSpace(req.City) == "":
+		return fmt.Errorf("%w: city is required", ErrInvalidAddress)
+	}
+
+	if req.Country, err = tax.NormalizeCountry(req.Country); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	return nil
+}
+
+// settleDefaultAddress moves the default of the address type to data when asked,
+// and otherwise makes data the default when its type has none yet
+func settleDefaultAddress(ctx context.Context, tx repository.Tx, data postgres.UserAddress, makeDefault bool) (postgres.UserAddress, error) {
+	if makeDefault && !data.IsDefault {
+		err := tx.ClearDefaultUserAddress(ctx, postgres.ClearDefaultUserAddressParams{
+			UserID: data.UserID,
+			Type:   data.Type,
+		})
+		if err != nil {
+			return data, err
+		}
+		return tx.SetDefaultUserAddress(ctx, data.ID)
+	}
+
+	err := tx.EnsureDefaultUserAddress(ctx, postgres.EnsureDefaultUserAddressParams{
+		UserID: data.UserID,
+		Type:   data.Type,
+	})
+	if err != nil {
+		return data, err
+	}
+	return tx.GetUserAddress(ctx, postgres.GetUserAddressParams{
+		ID:     data.ID,
+		UserID: data.UserID,
+	})
+}
+
+func nullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
@@ -0,0 +1,138 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ecommerce_management/internal/domain/address"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+)
+
+func newService(t *testing.T) (*Service, repository.Store) {
+	t.Helper()
+
+	repo, err := repository.New(repository.WithMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := auth.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(WithRepository(repo), WithTokens(tokens))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, repo
+}
+
+func newAddress(city string, makeDefault bool) address.Request {
+	return address.Request{
+		Type:      postgres.AddressTypeShipping,
+		FullName:  "Jane Doe",
+		Line1:     "1 Main Street",
+		City:      city,
+		Country:   "de",
+		IsDefault: makeDefault,
+	}
+}
+
+func TestAddressDefaults(t *testing.T) {
+	ctx := context.Background()
+	s, store := newService(t)
+
+	user, err := store.CreateUser(ctx, postgres.CreateUserParams{
+		FullName: "Jane Doe",
+		Email:    "jane@example.com",
+		Role:     "customer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := s.CreateAddress(ctx, user.ID, newAddress("Berlin", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.IsDefault || first.Country != "DE" {
+		t.Errorf("first address should be the default with a normalized country: %+v", first)
+	}
+
+	second, err := s.CreateAddress(ctx, user.ID, newAddress("Munich", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.IsDefault {
+		t.Error("is_default should move the default to the new address")
+	}
+	if first, err = store.GetUserAddress(ctx, postgres.GetUserAddressParams{ID: first.ID, UserID: user.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if first.IsDefault {
+		t.Error("the previous default should have been cleared")
+	}
+
+	// Turning the default shipping address into a billing one hands the shipping default to the remaining address
+	billing := newAddress("Munich", false)
+	billing.Type = postgres.AddressTypeBilling
+	if second, err = s.UpdateAddress(ctx, user.ID, second.ID, billing); err != nil {
+		t.Fatal(err)
+	}
+	if !second.IsDefault || second.Type != postgres.AddressTypeBilling {
+		t.Errorf("the only billing address should be its default: %+v", second)
+	}
+	if first, err = store.GetUserAddress(ctx, postgres.GetUserAddressParams{ID: first.ID, UserID: user.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if !first.IsDefault {
+		t.Error("the remaining shipping address should have become the default")
+	}
+
+	if err = s.DeleteAddress(ctx, user.ID, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.DeleteAddress(ctx, user.ID, first.ID); !errors.Is(err, ErrAddressNotFound) {
+		t.Errorf("got error %v deleting a deleted address, want %v", err, ErrAddressNotFound)
+	}
+}
+
+func TestCreateAddressErrors(t *testing.T) {
+	ctx := context.Background()
+	s, store := newService(t)
+
+	user, err := store.CreateUser(ctx, postgres.CreateUserParams{
+		FullName: "Jane Doe",
+		Email:    "jane@example.com",
+		Role:     "customer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noCity := newAddress("", false)
+	badType := newAddress("Berlin", false)
+	badType.Type = "office"
+
+	tests := []struct {
+		name   string
+		userID int64
+		req    address.Request
+		want   error
+	}{
+		{"missing city", user.ID, noCity, ErrInvalidAddress},
+		{"unknown type", user.ID, badType, ErrInvalidAddress},
+		{"unknown user", user.ID + 100, newAddress("Berlin", false), ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.CreateAddress(ctx, tt.userID, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
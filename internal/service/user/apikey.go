@@ -0,0 +1,87 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ecommerce_management/internal/domain/apikey"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/auth"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+)
+
+// ListAPIKeys returns the API keys of a user, revoked ones included
+func (s *Service) ListAPIKeys(ctx context.Context, userID int64) ([]postgres.ApiKey, error) {
+	return s.repository.ListAPIKeysByUser(ctx, userID)
+}
+
+// CreateAPIKey issues an API key for a user and returns it with the token, which is not stored and cannot be shown again
+func (s *Service) CreateAPIKey(ctx context.Context, userID int64, req apikey.CreateAPIKeyRequest) (data postgres.ApiKey, token string, err error) {
+	if strings.TrimSpace(req.Name) == "" {
+		err = fmt.Errorf("%w: name is required", ErrInvalidAPIKey)
+		return
+	}
+	if err = auth.ValidatePermissions(req.Permissions); err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidAPIKey, err)
+		return
+	}
+
+	if _, err = s.repository.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: user ID %d", ErrNotFound, userID)
+		}
+		return
+	}
+
+	key, err := auth.GenerateAPIKey()
+	if err != nil {
+		return
+	}
+
+	permissions := req.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	data, err = s.repository.CreateAPIKey(ctx, postgres.CreateAPIKeyParams{
+		UserID:      userID,
+		Name:        req.Name,
+		Prefix:      key.Prefix,
+		SecretHash:  key.SecretHash,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return
+	}
+
+	return data, key.Token(), nil
+}
+
+// RevokeAPIKey revokes an API key of a user
+func (s *Service) RevokeAPIKey(ctx context.Context, userID, id int64) error {
+	_, err := s.repository.RevokeAPIKey(ctx, postgres.RevokeAPIKeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: api key ID %d of user ID %d", ErrAPIKeyNotFound, id, userID)
+	}
+	return err
+}
+
+// AuthorizeAPIKeys checks that the caller p may manage the API keys of userID and grant them permissions
+func (s *Service) AuthorizeAPIKeys(ctx context.Context, p auth.Principal, userID int64, permissions []string) error {
+	caller, err := s.repository.GetUser(ctx, p.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return auth.CheckGrant(p, caller.Role, userID, permissions)
+}
@@ -0,0 +1,144 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"ecommerce_management/internal/provider/mail"
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/auth"
+	"ecommerce_management/internal/service/kafka"
+)
+
+const (
+	emailVerificationTTL = 24 * time.Hour
+	passwordResetTTL     = time.Hour
+)
+
+var (
+	ErrNotFound       = errors.New("user not found")
+	ErrVersionChanged = errors.New("user has changed since it was read")
+	ErrConflict       = errors.New("user was changed by another request")
+)
+
+// Repository is the subset of queries the Service needs to keep users with their accounts, addresses and API keys;
+// addresses are changed inside transactions it begins
+type Repository interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (repository.Tx, error)
+	CreateUser(ctx context.Context, arg postgres.CreateUserParams) (postgres.User, error)
+	GetUser(ctx context.Context, id int64) (postgres.User, error)
+	GetUserByEmail(ctx context.Context, email string) (postgres.User, error)
+	UpdateUser(ctx context.Context, arg postgres.UpdateUserParams) (postgres.User, error)
+	UpdateUserPassword(ctx context.Context, arg postgres.UpdateUserPasswordParams) error
+	VerifyUserEmail(ctx context.Context, id int64) (postgres.User, error)
+	DeleteUser(ctx context.Context, id int64) (postgres.User, error)
+	RestoreUser(ctx context.Context, id int64) (postgres.User, error)
+	ListUsersPage(ctx context.Context, arg postgres.ListUsersPageParams) (postgres.Page[postgres.User], error)
+	SearchUsersByEmail(ctx context.Context, email string) ([]postgres.User, error)
+	SearchUsersByName(ctx context.Context, name sql.NullString) ([]postgres.User, error)
+	ListNotificationsByUser(ctx context.Context, userID int64) ([]postgres.Notification, error)
+	ListUserAddresses(ctx context.Context, userID int64) ([]postgres.UserAddress, error)
+	GetUserAddress(ctx context.Context, arg postgres.GetUserAddressParams) (postgres.UserAddress, error)
+	ListAPIKeysByUser(ctx context.Context, userID int64) ([]postgres.ApiKey, error)
+	CreateAPIKey(ctx context.Context, arg postgres.CreateAPIKeyParams) (postgres.ApiKey, error)
+	RevokeAPIKey(ctx context.Context, arg postgres.RevokeAPIKeyParams) (postgres.ApiKey, error)
+}
+
+// Recorder records changes to users in the audit log; *audit.Service implements it
+type Recorder interface {
+	Record(ctx context.Context, action, entityType string, entityID int64, before, after any)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service registers users, keeps their profiles and sends the emails that verify addresses and reset passwords
+type Service struct {
+	repository Repository
+	tokens     *auth.Service
+	mailer     mail.Sender
+	producer   kafka.KafkaService
+	audit      Recorder
+	appURL     string
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+
+	switch {
+	case s.repository == nil:
+		return nil, errors.New("user service requires a repository")
+	case s.tokens == nil:
+		return nil, errors.New("user service requires a token issuer")
+	}
+	return
+}
+
+// WithRepository applies the store users are kept in
+func WithRepository(repository Repository) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithTokens applies the issuer of the tokens sent in verification and password reset emails
+func WithTokens(tokens *auth.Service) Configuration {
+	return func(s *Service) error {
+		s.tokens = tokens
+		return nil
+	}
+}
+
+// WithMailer sends account emails through mailer; without one they are not sent
+func WithMailer(mailer mail.Sender) Configuration {
+	return func(s *Service) error {
+		s.mailer = mailer
+		return nil
+	}
+}
+
+// WithProducer publishes every registered user to the new-user Kafka topic
+func WithProducer(producer kafka.KafkaService) Configuration {
+	return func(s *Service) error {
+		s.producer = producer
+		return nil
+	}
+}
+
+// WithAudit records every change to a user in the audit log
+func WithAudit(audit Recorder) Configuration {
+	return func(s *Service) error {
+		s.audit = audit
+		return nil
+	}
+}
+
+// WithAppURL sets the frontend URL links in account emails point to
+func WithAppURL(appURL string) Configuration {
+	return func(s *Service) error {
+		s.appURL = appURL
+		return nil
+	}
+}
+
+func (s *Service) record(ctx context.Context, action string, id int64, before, after any) {
+	if s.audit != nil {
+		s.audit.Record(ctx, action, audit.EntityUser, id, before, after)
+	}
+}
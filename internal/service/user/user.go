@@ -0,0 +1,153 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	domain "ecommerce_management/internal/domain/user"
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/internal/service/audit"
+	"ecommerce_management/internal/service/auth"
+)
+
+// List returns a page of users
+func (s *Service) List(ctx context.Context, arg postgres.ListUsersPageParams) (postgres.Page[postgres.User], error) {
+	return s.repository.ListUsersPage(ctx, arg)
+}
+
+// Create registers a user, sends them a verification email and publishes them to the new-user topic.
+// The password is optional; users created without one set it via password reset.
+func (s *Service) Create(ctx context.Context, req domain.CreateUserRequest) (data postgres.User, err error) {
+	var passwordHash string
+	if req.Password != "" {
+		if passwordHash, err = auth.HashPassword(req.Password); err != nil {
+			return
+		}
+	}
+
+	if req.Locale == "" {
+		req.Locale = "ru"
+	}
+
+	data, err = s.repository.CreateUser(ctx, postgres.CreateUserParams{
+		FullName:     req.FullName,
+		Email:        req.Email,
+		Address:      req.Address,
+		Role:         req.Role,
+		PasswordHash: passwordHash,
+		Locale:       req.Locale,
+	})
+	if err != nil {
+		return
+	}
+
+	s.record(ctx, audit.ActionCreate, data.ID, nil, data)
+
+	s.sendVerificationEmail(ctx, data)
+
+	if s.producer != nil {
+		message, err := json.Marshal(data)
+		if err != nil {
+			return data, err
+		}
+		if err = s.producer.Producer(ctx, "new-user", message); err != nil {
+			return data, err
+		}
+	}
+
+	return
+}
+
+// Get returns a user; deleted users are only returned when includeDeleted is set
+func (s *Service) Get(ctx context.Context, id int64, includeDeleted bool) (data postgres.User, err error) {
+	data, err = s.repository.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: user ID %d", ErrNotFound, id)
+		}
+		return
+	}
+
+	if data.DeletedAt.Valid && !includeDeleted {
+		return data, fmt.Errorf("%w: user ID %d has been deleted", ErrNotFound, id)
+	}
+	return
+}
+
+// Update replaces the fields of previous, as read with Get, by those of arg.
+// A new email address is unverified until it is confirmed with the verification email sent to it.
+// It fails with ErrVersionChanged when the user has been changed since it was read.
+func (s *Service) Update(ctx context.Context, previous postgres.User, arg postgres.UpdateUserParams) (data postgres.User, err error) {
+	arg.ID = previous.ID
+	arg.Version = previous.Version
+
+	data, err = s.repository.UpdateUser(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: user ID %d", ErrVersionChanged, previous.ID)
+		}
+		return
+	}
+
+	s.record(ctx, audit.ActionUpdate, data.ID, previous, data)
+
+	if data.Email != previous.Email {
+		s.sendVerificationEmail(ctx, data)
+	}
+
+	return
+}
+
+// Delete hides a user, keeping them to be restored until the retention period has passed
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	data, err := s.repository.DeleteUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w: user ID %d", ErrNotFound, id)
+		}
+		return err
+	}
+
+	s.record(ctx, audit.ActionDelete, id, data, nil)
+
+	return nil
+}
+
+// Restore brings back a deleted user; restoring a user that is not deleted changes nothing
+func (s *Service) Restore(ctx context.Context, id int64) (data postgres.User, err error) {
+	previous, err := s.Get(ctx, id, true)
+	if err != nil || !previous.DeletedAt.Valid {
+		return previous, err
+	}
+
+	data, err = s.repository.RestoreUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Restored or purged since it was read
+			err = fmt.Errorf("%w: user ID %d", ErrConflict, id)
+		}
+		return
+	}
+
+	s.record(ctx, audit.ActionRestore, id, previous, data)
+
+	return
+}
+
+// SearchByEmail returns the users with the given email
+func (s *Service) SearchByEmail(ctx context.Context, email string) ([]postgres.User, error) {
+	return s.repository.SearchUsersByEmail(ctx, email)
+}
+
+// SearchByName returns the users whose full name contains name
+func (s *Service) SearchByName(ctx context.Context, name string) ([]postgres.User, error) {
+	return s.repository.SearchUsersByName(ctx, sql.NullString{String: name, Valid: true})
+}
+
+// Notifications returns the notifications sent to a user
+func (s *Service) Notifications(ctx context.Context, userID int64) ([]postgres.Notification, error) {
+	return s.repository.ListNotificationsByUser(ctx, userID)
+}
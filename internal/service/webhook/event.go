@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/pkg/log"
+)
+
+const (
+	EventOrderCreated    = "order.created"
+	EventOrderUpdated    = "order.updated"
+	EventOrderDeleted    = "order.deleted"
+	EventOrderRestored   = "order.restored"
+	EventPaymentCreated  = "payment.created"
+	EventPaymentUpdated  = "payment.updated"
+	EventPaymentDeleted  = "payment.deleted"
+	EventPaymentRestored = "payment.restored"
+	EventProductCreated  = "product.created"
+	EventProductUpdated  = "product.updated"
+	EventProductDeleted  = "product.deleted"
+	EventProductRestored = "product.restored"
+	EventShipmentCreated = "shipment.created"
+	EventShipmentUpdated = "shipment.updated"
+	EventShipmentDeleted = "shipment.deleted"
+	EventReturnCreated   = "return.created"
+	EventReturnUpdated   = "return.updated"
+)
+
+// Events lists every event type a subscription may listen to
+var Events = []string{
+	EventOrderCreated,
+	EventOrderUpdated,
+	EventOrderDeleted,
+	EventOrderRestored,
+	EventPaymentCreated,
+	EventPaymentUpdated,
+	EventPaymentDeleted,
+	EventPaymentRestored,
+	EventProductCreated,
+	EventProductUpdated,
+	EventProductDeleted,
+	EventProductRestored,
+	EventShipmentCreated,
+	EventShipmentUpdated,
+	EventShipmentDeleted,
+	EventReturnCreated,
+	EventReturnUpdated,
+}
+
+var ErrUnknownEvent = errors.New("unknown event type")
+
+// ValidateEvents checks that every event type is known
+func ValidateEvents(events []string) error {
+	for _, event := range events {
+		if !slices.Contains(Events, event) {
+			return fmt.Errorf("%w: %s", ErrUnknownEvent, event)
+		}
+	}
+	return nil
+}
+
+// Envelope is the JSON body posted to subscriber endpoints
+type Envelope struct {
+	Event      string          `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Publish records a delivery of event with data for every active subscription and attempts it.
+// It runs in the background; failures are logged and retried by the worker.
+func (s *Service) Publish(ctx context.Context, event string, data any) {
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		logger := log.LoggerFromContext(ctx).Named("Publish").With(zap.String("event", event))
+
+		payload, err := encode(event, data)
+		if err != nil {
+			logger.Error("failed to encode payload", zap.Error(err))
+			return
+		}
+
+		subscriptions, err := s.repository.ListActiveWebhookSubscriptionsByEvent(ctx, event)
+		if err != nil {
+			logger.Error("failed to list subscriptions", zap.Error(err))
+			return
+		}
+
+		for _, subscription := range subscriptions {
+			// The worker picks the delivery up only if the immediate attempt below does not finish it
+			delivery, err := s.repository.CreateWebhookDelivery(ctx, postgres.CreateWebhookDeliveryParams{
+				SubscriptionID: subscription.ID,
+				EventType:      event,
+				Payload:        payload,
+				NextAttemptAt:  time.Now().Add(s.retryDelay),
+			})
+			if err != nil {
+				logger.Error("failed to create delivery", zap.Error(err), zap.Int64("subscription_id", subscription.ID))
+				continue
+			}
+
+			s.deliver(ctx, subscription, delivery)
+		}
+	}()
+}
+
+// Replay queues a failed delivery of a subscription again with a fresh set of attempts. It is left to the worker to
+// attempt, so that the delivery is sent once rather than by both the request and the worker polling due deliveries.
+// Only failed deliveries are requeued; the error wraps ErrNotReplayable for any other.
+func (s *Service) Replay(ctx context.Context, subscriptionID, deliveryID int64) (data postgres.WebhookDelivery, err error) {
+	data, err = s.repository.GetWebhookDelivery(ctx, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && data.SubscriptionID != subscriptionID) {
+		return data, fmt.Errorf("%w: delivery ID %d", ErrDeliveryNotFound, deliveryID)
+	}
+	if err != nil {
+		return
+	}
+
+	// The requeue only matches failed deliveries, so a delivery another request is replaying is not queued twice
+	data, err = s.repository.RequeueWebhookDelivery(ctx, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: delivery ID %d", ErrNotReplayable, deliveryID)
+	}
+	return
+}
+
+func encode(event string, data any) (json.RawMessage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(Envelope{
+		Event:      event,
+		OccurredAt: time.Now().UTC(),
+		Data:       raw,
+	})
+}
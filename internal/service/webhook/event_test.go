@@ -0,0 +1,150 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ecommerce_management/internal/repository"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+func TestReplayDeliversOnce(t *testing.T) {
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	repo, err := repository.New(repository.WithMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(WithRepository(repo), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscription, err := repo.CreateWebhookSubscription(ctx, postgres.CreateWebhookSubscriptionParams{
+		Url:        server.URL,
+		EventTypes: []string{EventOrderCreated},
+		Secret:     "secret",
+		Active:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delivery, err := repo.CreateWebhookDelivery(ctx, postgres.CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventType:      EventOrderCreated,
+		Payload:        []byte(`{}`),
+		NextAttemptAt:  time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.MarkWebhookDeliveryFailed(ctx, postgres.MarkWebhookDeliveryFailedParams{
+		ID:            delivery.ID,
+		Status:        postgres.WebhookDeliveryStatusFailed,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if delivery, err = s.Replay(ctx, subscription.ID, delivery.ID); err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != postgres.WebhookDeliveryStatusPending {
+		t.Errorf("replayed delivery is %s, want pending", delivery.Status)
+	}
+	if _, err = s.Replay(ctx, subscription.ID, delivery.ID); err == nil {
+		t.Error("a pending delivery should not be replayed")
+	}
+
+	s.retryDue(ctx)
+	s.retryDue(ctx)
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("endpoint was called %d times, want 1", got)
+	}
+	if delivery, err = repo.GetWebhookDelivery(ctx, delivery.ID); err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != postgres.WebhookDeliveryStatusSucceeded {
+		t.Errorf("delivery is %s, want succeeded", delivery.Status)
+	}
+}
+
+func TestReplayRetriesAfterFailure(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo, err := repository.New(repository.WithMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const maxAttempts = 3
+	s, err := New(WithRepository(repo), WithHTTPClient(server.Client()), WithRetryPolicy(maxAttempts, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscription, err := repo.CreateWebhookSubscription(ctx, postgres.CreateWebhookSubscriptionParams{
+		Url:        server.URL,
+		EventTypes: []string{EventOrderCreated},
+		Secret:     "secret",
+		Active:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delivery, err := repo.CreateWebhookDelivery(ctx, postgres.CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventType:      EventOrderCreated,
+		Payload:        []byte(`{}`),
+		NextAttemptAt:  time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Use up every attempt
+	for i := 0; i < maxAttempts; i++ {
+		err = repo.MarkWebhookDeliveryFailed(ctx, postgres.MarkWebhookDeliveryFailedParams{
+			ID:            delivery.ID,
+			Status:        postgres.WebhookDeliveryStatusFailed,
+			LastError:     sql.NullString{String: "unexpected response status 500", Valid: true},
+			NextAttemptAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if delivery, err = s.Replay(ctx, subscription.ID, delivery.ID); err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Attempts != 0 || delivery.LastError.Valid {
+		t.Errorf("replay should start the delivery over: %+v", delivery)
+	}
+
+	s.retryDue(ctx)
+
+	if delivery, err = repo.GetWebhookDelivery(ctx, delivery.ID); err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != postgres.WebhookDeliveryStatusPending || delivery.Attempts != 1 {
+		t.Errorf("a replayed delivery that fails once should be retried, got %s after %d attempts", delivery.Status, delivery.Attempts)
+	}
+}
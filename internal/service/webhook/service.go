@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"ecommerce_management/internal/repository/postgres"
+)
+
+const (
+	defaultMaxAttempts = 8
+	defaultRetryDelay  = 30 * time.Second
+	defaultPollPeriod  = 15 * time.Second
+	defaultBatchSize   = 50
+	defaultTimeout     = 10 * time.Second
+	defaultLease       = 10 * time.Minute
+)
+
+// Repository is the subset of queries the Service needs to keep subscriptions and to fan out and record deliveries
+type Repository interface {
+	ListWebhookSubscriptions(ctx context.Context) ([]postgres.WebhookSubscription, error)
+	ListActiveWebhookSubscriptionsByEvent(ctx context.Context, eventType string) ([]postgres.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (postgres.WebhookSubscription, error)
+	CreateWebhookSubscription(ctx context.Context, arg postgres.CreateWebhookSubscriptionParams) (postgres.WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, arg postgres.UpdateWebhookSubscriptionParams) (postgres.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	GetWebhookDelivery(ctx context.Context, id int64) (postgres.WebhookDelivery, error)
+	ListWebhookDeliveriesBySubscription(ctx context.Context, subscriptionID int64) ([]postgres.WebhookDelivery, error)
+	CreateWebhookDelivery(ctx context.Context, arg postgres.CreateWebhookDeliveryParams) (postgres.WebhookDelivery, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg postgres.ClaimDueWebhookDeliveriesParams) ([]postgres.WebhookDelivery, error)
+	MarkWebhookDeliverySucceeded(ctx context.Context, arg postgres.MarkWebhookDeliverySucceededParams) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg postgres.MarkWebhookDeliveryFailedParams) error
+	RequeueWebhookDelivery(ctx context.Context, id int64) (postgres.WebhookDelivery, error)
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Service and modify it
+type Configuration func(s *Service) error
+
+// Service publishes domain events to subscribed endpoints and retries failed deliveries
+type Service struct {
+	repository  Repository
+	client      *http.Client
+	maxAttempts int
+	retryDelay  time.Duration
+	pollPeriod  time.Duration
+}
+
+// New takes a variable amount of Configuration functions and returns a new Service
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (s *Service, err error) {
+	// Insert the service
+	s = &Service{
+		client:      &http.Client{Timeout: defaultTimeout},
+		maxAttempts: defaultMaxAttempts,
+		retryDelay:  defaultRetryDelay,
+		pollPeriod:  defaultPollPeriod,
+	}
+
+	// Apply all Configurations passed in
+	for _, cfg := range configs {
+		// Pass the service into the configuration function
+		if err = cfg(s); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithRepository applies a given repository to the Service
+func WithRepository(repository Repository) Configuration {
+	return func(s *Service) error {
+		s.repository = repository
+		return nil
+	}
+}
+
+// WithHTTPClient applies a given HTTP client used to call subscriber endpoints
+func WithHTTPClient(client *http.Client) Configuration {
+	return func(s *Service) error {
+		s.client = client
+		return nil
+	}
+}
+
+// WithRetryPolicy sets how many delivery attempts are made and the initial delay between them
+func WithRetryPolicy(maxAttempts int, retryDelay time.Duration) Configuration {
+	return func(s *Service) error {
+		s.maxAttempts = maxAttempts
+		s.retryDelay = retryDelay
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+
+	domain "ecommerce_management/internal/domain/webhook"
+	"ecommerce_management/internal/repository/postgres"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrInvalidSubscription  = errors.New("invalid webhook subscription")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrNotReplayable        = errors.New("only failed deliveries can be replayed")
+)
+
+// ListSubscriptions returns every webhook subscription
+func (s *Service) ListSubscriptions(ctx context.Context) ([]postgres.WebhookSubscription, error) {
+	return s.repository.ListWebhookSubscriptions(ctx)
+}
+
+// GetSubscription returns a webhook subscription
+func (s *Service) GetSubscription(ctx context.Context, id int64) (data postgres.WebhookSubscription, err error) {
+	data, err = s.repository.GetWebhookSubscription(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: subscription ID %d", ErrSubscriptionNotFound, id)
+	}
+	return
+}
+
+// CreateSubscription subscribes an endpoint to events, generating its signing secret unless one is given
+func (s *Service) CreateSubscription(ctx context.Context, req domain.SubscriptionRequest) (data postgres.WebhookSubscription, err error) {
+	if err = validateSubscription(req); err != nil {
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = GenerateSecret(); err != nil {
+			return
+		}
+	}
+
+	return s.repository.CreateWebhookSubscription(ctx, postgres.CreateWebhookSubscriptionParams{
+		Url:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		Active:     req.Active == nil || *req.Active,
+	})
+}
+
+// UpdateSubscription replaces the endpoint and events of a subscription; its secret is kept
+func (s *Service) UpdateSubscription(ctx context.Context, id int64, req domain.SubscriptionRequest) (data postgres.WebhookSubscription, err error) {
+	if err = validateSubscription(req); err != nil {
+		return
+	}
+
+	data, err = s.repository.UpdateWebhookSubscription(ctx, postgres.UpdateWebhookSubscriptionParams{
+		ID:         id,
+		Url:        req.URL,
+		EventTypes: req.EventTypes,
+		Active:     req.Active == nil || *req.Active,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: subscription ID %d", ErrSubscriptionNotFound, id)
+	}
+	return
+}
+
+// DeleteSubscription removes a subscription together with its delivery log
+func (s *Service) DeleteSubscription(ctx context.Context, id int64) error {
+	return s.repository.DeleteWebhookSubscription(ctx, id)
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first
+func (s *Service) ListDeliveries(ctx context.Context, subscriptionID int64) ([]postgres.WebhookDelivery, error) {
+	return s.repository.ListWebhookDeliveriesBySubscription(ctx, subscriptionID)
+}
+
+func validateSubscription(req domain.SubscriptionRequest) error {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+
+	if len(req.EventTypes) == 0 {
+		return fmt.Errorf("%w: event_types is required", ErrInvalidSubscription)
+	}
+
+	return ValidateEvents(req.EventTypes)
+}
@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ecommerce_management/internal/repository/postgres"
+	"ecommerce_management/pkg/log"
+)
+
+// maxResponseBody caps how much of a subscriber response is kept in the delivery log
+const maxResponseBody = 4 << 10
+
+// Start retries pending deliveries until ctx is cancelled.
+// Each due delivery is claimed by one worker, so several instances can run it side by side.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.pollPeriod)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.retryDue(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Service) retryDue(ctx context.Context) {
+	logger := log.LoggerFromContext(ctx).Named("retryDue")
+
+	deliveries, err := s.repository.ClaimDueWebhookDeliveries(ctx, postgres.ClaimDueWebhookDeliveriesParams{
+		NextAttemptAt: time.Now().Add(defaultLease),
+		Limit:         defaultBatchSize,
+	})
+	if err != nil {
+		logger.Error("failed to claim due deliveries", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		// Stop starting deliveries on shutdown; those left are retried once their lease runs out
+		if ctx.Err() != nil {
+			return
+		}
+
+		subscription, err := s.repository.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+		if err != nil {
+			logger.Error("failed to get subscription", zap.Error(err), zap.Int64("id", delivery.ID))
+			continue
+		}
+
+		s.deliver(ctx, subscription, delivery)
+	}
+}
+
+// deliver posts a recorded delivery to its subscription and stores the outcome, scheduling a retry with exponential backoff on failure.
+// It runs to the end once started, bounded by the client timeout, so that a delivery that was sent is not left due and sent again.
+func (s *Service) deliver(ctx context.Context, subscription postgres.WebhookSubscription, delivery postgres.WebhookDelivery) {
+	ctx = context.WithoutCancel(ctx)
+	logger := log.LoggerFromContext(ctx).Named("deliver")
+
+	status, body, err := s.post(ctx, subscription, delivery)
+	responseStatus := sql.NullInt32{Int32: int32(status), Valid: status != 0}
+	responseBody := sql.NullString{String: body, Valid: status != 0}
+
+	if err == nil {
+		err = s.repository.MarkWebhookDeliverySucceeded(ctx, postgres.MarkWebhookDeliverySucceededParams{
+			ID:             delivery.ID,
+			ResponseStatus: responseStatus,
+			ResponseBody:   responseBody,
+		})
+		if err != nil {
+			logger.Error("failed to mark delivery as succeeded", zap.Error(err), zap.Int64("id", delivery.ID))
+		}
+		return
+	}
+
+	logger.Warn("failed to deliver webhook", zap.Error(err), zap.Int64("id", delivery.ID), zap.Int32("attempts", delivery.Attempts+1))
+
+	state := postgres.WebhookDeliveryStatusPending
+	if int(delivery.Attempts)+1 >= s.maxAttempts || !subscription.Active {
+		state = postgres.WebhookDeliveryStatusFailed
+	}
+
+	err = s.repository.MarkWebhookDeliveryFailed(ctx, postgres.MarkWebhookDeliveryFailedParams{
+		ID:             delivery.ID,
+		Status:         state,
+		ResponseStatus: responseStatus,
+		ResponseBody:   responseBody,
+		LastError:      sql.NullString{String: err.Error(), Valid: true},
+		NextAttemptAt:  time.Now().Add(s.retryDelay << delivery.Attempts),
+	})
+	if err != nil {
+		logger.Error("failed to mark delivery as failed", zap.Error(err), zap.Int64("id", delivery.ID))
+	}
+}
+
+// post sends the signed payload and returns the response status and a truncated body
+func (s *Service) post(ctx context.Context, subscription postgres.WebhookSubscription, delivery postgres.WebhookDelivery) (status int, body string, err error) {
+	if !subscription.Active {
+		return 0, "", errors.New("subscription is inactive")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, time.Now(), delivery.Payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+	status, body = res.StatusCode, string(raw)
+
+	if status < 200 || status > 299 {
+		err = fmt.Errorf("unexpected response status %d", status)
+	}
+	return
+}
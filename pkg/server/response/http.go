@@ -7,9 +7,16 @@ import (
 )
 
 type Object struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	Data    any    `json:"data,omitempty"`
+	Success    bool        `json:"success"`
+	Message    string      `json:"message,omitempty"`
+	Data       any         `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes where a paged list continues and how many items match in total
+type Pagination struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
 }
 
 func OK(w http.ResponseWriter, r *http.Request, data any) {
@@ -22,6 +29,27 @@ func OK(w http.ResponseWriter, r *http.Request, data any) {
 	render.JSON(w, r, v)
 }
 
+func Accepted(w http.ResponseWriter, r *http.Request, data any) {
+	render.Status(r, http.StatusAccepted)
+
+	v := Object{
+		Success: true,
+		Data:    data,
+	}
+	render.JSON(w, r, v)
+}
+
+func Page(w http.ResponseWriter, r *http.Request, data any, pagination Pagination) {
+	render.Status(r, http.StatusOK)
+
+	v := Object{
+		Success:    true,
+		Data:       data,
+		Pagination: &pagination,
+	}
+	render.JSON(w, r, v)
+}
+
 func BadRequest(w http.ResponseWriter, r *http.Request, err error, data any) {
 	render.Status(r, http.StatusBadRequest)
 
@@ -33,6 +61,26 @@ func BadRequest(w http.ResponseWriter, r *http.Request, err error, data any) {
 	render.JSON(w, r, v)
 }
 
+func Unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusUnauthorized)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
+func Forbidden(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusForbidden)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
 func NotFound(w http.ResponseWriter, r *http.Request, err error) {
 	render.Status(r, http.StatusNotFound)
 
@@ -43,6 +91,36 @@ func NotFound(w http.ResponseWriter, r *http.Request, err error) {
 	render.JSON(w, r, v)
 }
 
+func Conflict(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusConflict)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
+func PreconditionFailed(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusPreconditionFailed)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
+func PreconditionRequired(w http.ResponseWriter, r *http.Request, err error) {
+	render.Status(r, http.StatusPreconditionRequired)
+
+	v := Object{
+		Success: false,
+		Message: err.Error(),
+	}
+	render.JSON(w, r, v)
+}
+
 func InternalServerError(w http.ResponseWriter, r *http.Request, err error) {
 	render.Status(r, http.StatusInternalServerError)
 
@@ -71,13 +71,13 @@ func (s *Server) Stop(ctx context.Context) (err error) {
 	return
 }
 
-func WithGRPCServer(port string) Configuration {
+func WithGRPCServer(server *grpc.Server, port string) Configuration {
 	return func(s *Server) (err error) {
-		s.listener, err = net.Listen("tcp", fmt.Sprintf("localhost:%s", port))
+		s.listener, err = net.Listen("tcp", fmt.Sprintf(":%s", port))
 		if err != nil {
 			return
 		}
-		s.grpc = &grpc.Server{}
+		s.grpc = server
 
 		return
 	}